@@ -4,7 +4,9 @@ import (
 	"context"
 	"flag"
 	"log"
+	"log/slog"
 	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
 	"syscall"
@@ -12,6 +14,8 @@ import (
 
 	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/app"
 	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/config"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/tracing"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/pkg"
 )
 
 func main() {
@@ -24,6 +28,29 @@ func main() {
 		log.Printf("failed to open config file with path: %s", configPath)
 	}
 
+	logger, err := pkg.NewLogger(os.Stderr, cfg.LoggerLvl, cfg.LoggerFormat)
+	if err != nil {
+		log.Fatal("failed to make logger: ", err.Error())
+	}
+	slog.SetDefault(logger)
+
+	stopTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		log.Fatal("failed to set up tracing: ", err.Error())
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := stopTracing(ctx); err != nil {
+			slog.Error("failed to flush spans", "error", err)
+		}
+	}()
+
+	if flag.Arg(0) == "export" {
+		export(cfg, flag.Args()[1:])
+		return
+	}
+
 	doneCh := make(chan os.Signal, 1)
 	signal.Notify(doneCh, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
 	s := app.NewServer(&http.Server{
@@ -37,13 +64,62 @@ func main() {
 			log.Fatal("failed to start server")
 		}
 	}()
+	go func() {
+		if err := s.StartGRPC(); err != nil {
+			log.Fatal("failed to start grpc server: ", err.Error())
+		}
+	}()
+	go func() {
+		if err := s.StartMetrics(); err != nil {
+			log.Fatal("failed to start metrics server: ", err.Error())
+		}
+	}()
 
 	<-doneCh
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
+	if s.GRPCServer != nil {
+		s.GRPCServer.GracefulStop()
+	}
+	if s.MetricsServer != nil {
+		if err := s.MetricsServer.Shutdown(ctx); err != nil {
+			slog.Error("failed to stop metrics server", "error", err)
+		}
+	}
 	if err := s.Server.Shutdown(ctx); err != nil {
 		log.Fatal("failed to stop server", err.Error())
 		return
 	}
 }
+
+// export runs the export subcommand:
+//
+//	main [-ConfigPath path] export -o catalog.csv -format csv [-title t] [-actor a] [-filter f] [-order o]
+func export(cfg *config.Config, args []string) {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	output := flags.String("o", "", "Path to the export file")
+	format := flags.String("format", "json", "Format of the export: json, ndjson or csv")
+	title := flags.String("title", "", "Export movies with the title like this")
+	actor := flags.String("actor", "", "Export movies with an actor named like this")
+	sortBy := flags.String("filter", "", "Sort movies by title, rating or releaseDate")
+	order := flags.String("order", "", "Sort movies in ascending order")
+	flags.Parse(args)
+
+	if *output == "" {
+		log.Fatal("export: -o is required")
+	}
+
+	query := url.Values{}
+	query.Set("title", *title)
+	query.Set("actor", *actor)
+	query.Set("filter", *sortBy)
+	query.Set("order", *order)
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	if err := app.Export(ctx, cfg, *output, *format, query); err != nil {
+		log.Fatalf("export: %s", err)
+	}
+}
@@ -0,0 +1,8 @@
+// Package docs holds the OpenAPI spec of the API, the server serves it and
+// checks traffic against it.
+package docs
+
+import _ "embed"
+
+//go:embed swagger.yml
+var Swagger []byte
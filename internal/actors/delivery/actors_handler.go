@@ -2,13 +2,15 @@ package httpActors
 
 import (
 	"encoding/json"
-	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
 	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
-	"github.com/themilchenko/vk-tech_internship-problem_2024/pkg"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/etag"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/jsonpatch"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/problem"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/validate"
 )
 
 const (
@@ -25,22 +27,30 @@ func NewActorsUsecase(a domain.ActorsUsecase) ActorsHandler {
 	}
 }
 
+// actorVersion looks up the version an If-Match list is matched against.
+func (h ActorsHandler) actorVersion(r *http.Request, actorID uint64) func() (uint64, error) {
+	return func() (uint64, error) {
+		actor, err := h.actorsUsecase.GetActorByID(r.Context(), actorID)
+		return actor.Version, err
+	}
+}
+
 func (h ActorsHandler) CreateActor(w http.ResponseWriter, r *http.Request) {
 	var receivedActor httpModels.Actor
-	if err := json.NewDecoder(r.Body).Decode(&receivedActor); err != nil {
-		pkg.HandleError(w, err.Error(), http.StatusBadRequest)
+	if err := validate.DecodeJSON(r, &receivedActor); err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
-	actorID, err := h.actorsUsecase.CreateActor(receivedActor)
+	actorID, err := h.actorsUsecase.CreateActor(r.Context(), receivedActor)
 	if err != nil {
-		pkg.HandleError(w, err.Error(), http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
 
 	responseData, err := json.Marshal(httpModels.ID{ID: actorID})
 	if err != nil {
-		pkg.HandleError(w, err.Error(), http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -52,16 +62,18 @@ func (h ActorsHandler) CreateActor(w http.ResponseWriter, r *http.Request) {
 func (h ActorsHandler) DeleteActor(w http.ResponseWriter, r *http.Request) {
 	actorID, err := strconv.ParseUint(r.PathValue("id"), 10, 64)
 	if err != nil {
-		pkg.HandleError(w, err.Error(), http.StatusBadRequest)
+		problem.Write(w, r, domain.ErrBadRequest.Wrap(err))
 		return
 	}
 
-	if err := h.actorsUsecase.DeleteActorByID(actorID); err != nil {
-		if errors.Is(err, domain.ErrNotFound) {
-			pkg.HandleError(w, err.Error(), http.StatusNotFound)
-		} else {
-			pkg.HandleError(w, err.Error(), http.StatusInternalServerError)
-		}
+	version, err := etag.IfMatch(r, h.actorVersion(r, actorID))
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	if err := h.actorsUsecase.DeleteActorByID(r.Context(), actorID, version); err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -73,23 +85,25 @@ func (h ActorsHandler) DeleteActor(w http.ResponseWriter, r *http.Request) {
 func (h ActorsHandler) GetActor(w http.ResponseWriter, r *http.Request) {
 	actorID, err := strconv.ParseUint(r.PathValue("id"), 10, 64)
 	if err != nil {
-		pkg.HandleError(w, err.Error(), http.StatusBadRequest)
+		problem.Write(w, r, domain.ErrBadRequest.Wrap(err))
 		return
 	}
 
-	actor, err := h.actorsUsecase.GetActorByID(actorID)
+	actor, err := h.actorsUsecase.GetActorByID(r.Context(), actorID)
 	if err != nil {
-		if errors.Is(err, domain.ErrNotFound) {
-			pkg.HandleError(w, err.Error(), http.StatusNotFound)
-		} else {
-			pkg.HandleError(w, err.Error(), http.StatusInternalServerError)
-		}
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("ETag", etag.Format(actor.Version))
+	if !etag.NoneMatch(r, actor.Version) {
+		w.WriteHeader(http.StatusNotModified)
 		return
 	}
 
 	responseData, err := json.Marshal(actor)
 	if err != nil {
-		pkg.HandleError(w, err.Error(), http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -101,32 +115,72 @@ func (h ActorsHandler) GetActor(w http.ResponseWriter, r *http.Request) {
 func (h ActorsHandler) UpdateActor(w http.ResponseWriter, r *http.Request) {
 	actorID, err := strconv.ParseUint(r.PathValue("id"), 10, 64)
 	if err != nil {
-		pkg.HandleError(w, err.Error(), http.StatusBadRequest)
+		problem.Write(w, r, domain.ErrBadRequest.Wrap(err))
+		return
+	}
+
+	version, err := etag.IfMatch(r, h.actorVersion(r, actorID))
+	if err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
 	var receivedActor httpModels.Actor
-	if err := json.NewDecoder(r.Body).Decode(&receivedActor); err != nil {
-		pkg.HandleError(w, err.Error(), http.StatusBadRequest)
+	if err := validate.DecodeJSON(r, &receivedActor); err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
-	updatedActor, err := h.actorsUsecase.UpdateActor(receivedActor, actorID)
+	updatedActor, err := h.actorsUsecase.UpdateActor(r.Context(), receivedActor, actorID, version)
 	if err != nil {
-		if errors.Is(err, domain.ErrNotFound) {
-			pkg.HandleError(w, err.Error(), http.StatusNotFound)
-		} else {
-			pkg.HandleError(w, err.Error(), http.StatusInternalServerError)
-		}
+		problem.Write(w, r, err)
+		return
+	}
+
+	responseData, err := json.Marshal(updatedActor)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("ETag", etag.Format(updatedActor.Version))
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(responseData)
+}
+
+func (h ActorsHandler) PatchActor(w http.ResponseWriter, r *http.Request) {
+	actorID, err := strconv.ParseUint(r.PathValue("id"), 10, 64)
+	if err != nil {
+		problem.Write(w, r, domain.ErrBadRequest.Wrap(err))
+		return
+	}
+
+	version, err := etag.IfMatch(r, h.actorVersion(r, actorID))
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	patch, err := jsonpatch.FromRequest(r)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	updatedActor, err := h.actorsUsecase.PatchActor(r.Context(), actorID, version, patch)
+	if err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
 	responseData, err := json.Marshal(updatedActor)
 	if err != nil {
-		pkg.HandleError(w, err.Error(), http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
 
+	w.Header().Set("ETag", etag.Format(updatedActor.Version))
 	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
 	w.WriteHeader(http.StatusOK)
 	w.Write(responseData)
@@ -141,20 +195,20 @@ func (h ActorsHandler) GetActors(w http.ResponseWriter, r *http.Request) {
 		var err error
 		pageNum, err = strconv.ParseUint(r.URL.Query().Get("page"), 10, 64)
 		if err != nil {
-			pkg.HandleError(w, err.Error(), http.StatusBadRequest)
+			problem.Write(w, r, domain.ErrBadRequest.Wrap(err))
 			return
 		}
 	}
 
-	actors, err := h.actorsUsecase.GetActors(pageNum)
+	actors, err := h.actorsUsecase.GetActors(r.Context(), pageNum)
 	if err != nil {
-		pkg.HandleError(w, err.Error(), http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
 
 	responseData, err := json.Marshal(actors)
 	if err != nil {
-		pkg.HandleError(w, err.Error(), http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
 
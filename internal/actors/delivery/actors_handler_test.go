@@ -7,15 +7,32 @@ import (
 	"net/http/httptest"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/docs"
 	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
 	mockDomain "github.com/themilchenko/vk-tech_internship-problem_2024/internal/mocks/domain"
 	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/openapi"
 	"go.uber.org/mock/gomock"
 )
 
+var loadSpec = sync.OnceValues(func() (*openapi.Spec, error) {
+	return openapi.Load(docs.Swagger)
+})
+
+// strict checks the handlers behind mux against the spec, as the API is
+// checked in tests.
+func strict(t *testing.T, mux http.Handler) http.Handler {
+	spec, err := loadSpec()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return spec.Strict(mux)
+}
+
 func TestHandler_CreateActor(t *testing.T) {
 	type mockBehavior func(r *mockDomain.MockActorsUsecase, actor httpModels.Actor)
 
@@ -39,7 +56,7 @@ func TestHandler_CreateActor(t *testing.T) {
 			actorID: uint64(1),
 			mockBehavior: func(m *mockDomain.MockActorsUsecase, actor httpModels.Actor) {
 				m.EXPECT().
-					CreateActor(actor).
+					CreateActor(gomock.Any(), actor).
 					Return(uint64(1), nil)
 			},
 			expectedStatusCode:   http.StatusOK,
@@ -51,19 +68,44 @@ func TestHandler_CreateActor(t *testing.T) {
 			inputActor:           httpModels.Actor{},
 			mockBehavior:         func(m *mockDomain.MockActorsUsecase, actor httpModels.Actor) {},
 			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: `{"error":"invalid character 's' looking for beginning of value"}`,
+			expectedResponseBody: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"failed to unmarshal json: invalid character 's' looking for beginning of value","code":"invalid_json"}`,
 		},
 		{
-			name:       "Internal server error",
-			inputBody:  `{}`,
-			inputActor: httpModels.Actor{},
+			name:      "Internal server error",
+			inputBody: `{"name":"John","birthDate":"2000-01-01","gender":true}`,
+			inputActor: httpModels.Actor{
+				Name:      "John",
+				BirthDate: "2000-01-01",
+				Gender:    true,
+			},
 			mockBehavior: func(m *mockDomain.MockActorsUsecase, actor httpModels.Actor) {
 				m.EXPECT().
-					CreateActor(actor).
-					Return(uint64(0), errors.New("empty name"))
+					CreateActor(gomock.Any(), actor).
+					Return(uint64(0), errors.New("db is down"))
 			},
 			expectedStatusCode:   http.StatusInternalServerError,
-			expectedResponseBody: `{"error":"empty name"}`,
+			expectedResponseBody: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"server error","code":"internal_error"}`,
+		},
+		{
+			name:                 "Empty actor",
+			inputBody:            `{"name":"  ","birthDate":"3000-01-01"}`,
+			mockBehavior:         func(m *mockDomain.MockActorsUsecase, actor httpModels.Actor) {},
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"validation failed","code":"validation_failed","violations":[{"field":"name","rule":"required","message":"is required"},{"field":"birthDate","rule":"past","message":"must not be in the future"}]}`,
+		},
+		{
+			name:                 "Unknown field",
+			inputBody:            `{"name":"John","birthDate":"2000-01-01","age":24}`,
+			mockBehavior:         func(m *mockDomain.MockActorsUsecase, actor httpModels.Actor) {},
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"validation failed","code":"validation_failed","violations":[{"field":"age","rule":"unknown","message":"is not allowed"}]}`,
+		},
+		{
+			name:                 "Wrong type",
+			inputBody:            `{"name":"John","birthDate":"2000-01-01","gender":"male"}`,
+			mockBehavior:         func(m *mockDomain.MockActorsUsecase, actor httpModels.Actor) {},
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"validation failed","code":"validation_failed","violations":[{"field":"gender","rule":"type","message":"must be a boolean"}]}`,
 		},
 	}
 
@@ -88,7 +130,7 @@ func TestHandler_CreateActor(t *testing.T) {
 				bytes.NewBufferString(tt.inputBody),
 			)
 
-			mux.ServeHTTP(w, req)
+			strict(t, mux).ServeHTTP(w, req)
 
 			assert.Equal(t, tt.expectedStatusCode, w.Code)
 			assert.Equal(t, tt.expectedResponseBody, strings.Trim(w.Body.String(), "\n"))
@@ -102,6 +144,7 @@ func TestHandler_DeleteActor(t *testing.T) {
 	tests := []struct {
 		name                 string
 		actorID              string
+		ifMatch              string
 		mockBehavior         mockBehavior
 		expectedStatusCode   int
 		expectedResponseBody string
@@ -111,7 +154,7 @@ func TestHandler_DeleteActor(t *testing.T) {
 			actorID: "1",
 			mockBehavior: func(m *mockDomain.MockActorsUsecase, actorID uint64) {
 				m.EXPECT().
-					DeleteActorByID(actorID).
+					DeleteActorByID(gomock.Any(), actorID, uint64(0)).
 					Return(nil)
 			},
 			expectedStatusCode:   http.StatusOK,
@@ -122,29 +165,49 @@ func TestHandler_DeleteActor(t *testing.T) {
 			actorID: "1",
 			mockBehavior: func(m *mockDomain.MockActorsUsecase, actorID uint64) {
 				m.EXPECT().
-					DeleteActorByID(actorID).
+					DeleteActorByID(gomock.Any(), actorID, uint64(0)).
 					Return(errors.New("empty actor"))
 			},
 			expectedStatusCode:   http.StatusInternalServerError,
-			expectedResponseBody: `{"error":"empty actor"}`,
+			expectedResponseBody: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"server error","code":"internal_error"}`,
 		},
 		{
 			name:    "Status Not Found",
 			actorID: "1",
 			mockBehavior: func(m *mockDomain.MockActorsUsecase, actorID uint64) {
 				m.EXPECT().
-					DeleteActorByID(actorID).
+					DeleteActorByID(gomock.Any(), actorID, uint64(0)).
 					Return(domain.ErrNotFound)
 			},
 			expectedStatusCode:   http.StatusNotFound,
-			expectedResponseBody: `{"error":"failed to find item"}`,
+			expectedResponseBody: `{"type":"about:blank","title":"Not Found","status":404,"detail":"failed to find item","code":"not_found"}`,
+		},
+		{
+			name:    "Version mismatch",
+			actorID: "1",
+			ifMatch: `"2"`,
+			mockBehavior: func(m *mockDomain.MockActorsUsecase, actorID uint64) {
+				m.EXPECT().
+					DeleteActorByID(gomock.Any(), actorID, uint64(2)).
+					Return(domain.ErrVersionMismatch)
+			},
+			expectedStatusCode:   http.StatusPreconditionFailed,
+			expectedResponseBody: `{"type":"about:blank","title":"Precondition Failed","status":412,"detail":"item was changed since it was read","code":"version_mismatch"}`,
+		},
+		{
+			name:                 "Weak If-Match",
+			actorID:              "1",
+			ifMatch:              `W/"2"`,
+			mockBehavior:         func(m *mockDomain.MockActorsUsecase, actorID uint64) {},
+			expectedStatusCode:   http.StatusPreconditionFailed,
+			expectedResponseBody: `{"type":"about:blank","title":"Precondition Failed","status":412,"detail":"item was changed since it was read","code":"version_mismatch"}`,
 		},
 		{
 			name:                 "Bad request",
 			actorID:              "a",
 			mockBehavior:         func(m *mockDomain.MockActorsUsecase, actorID uint64) {},
 			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: `{"error":"strconv.ParseUint: parsing \"a\": invalid syntax"}`,
+			expectedResponseBody: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"bad request: strconv.ParseUint: parsing \"a\": invalid syntax","code":"bad_request"}`,
 		},
 	}
 
@@ -170,8 +233,11 @@ func TestHandler_DeleteActor(t *testing.T) {
 				"/actors/"+tt.actorID,
 				nil,
 			)
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
 
-			mux.ServeHTTP(w, req)
+			strict(t, mux).ServeHTTP(w, req)
 
 			assert.Equal(t, tt.expectedStatusCode, w.Code)
 			assert.Equal(t, tt.expectedResponseBody, strings.Trim(w.Body.String(), "\n"))
@@ -185,8 +251,10 @@ func TestHandler_GetActor(t *testing.T) {
 	tests := []struct {
 		name                 string
 		actorID              string
+		ifNoneMatch          string
 		mockBehavior         mockBehavior
 		expectedStatusCode   int
+		expectedETag         string
 		expectedResponseBody string
 	}{
 		{
@@ -194,45 +262,60 @@ func TestHandler_GetActor(t *testing.T) {
 			actorID: "1",
 			mockBehavior: func(m *mockDomain.MockActorsUsecase, actorID uint64) {
 				m.EXPECT().
-					GetActorByID(actorID).
+					GetActorByID(gomock.Any(), actorID).
 					Return(httpModels.ActorResponse{
 						ID:        1,
 						Name:      "John",
 						Gender:    true,
 						BirthDate: "2000-01-01",
+						Version:   3,
 					}, nil)
 			},
 			expectedStatusCode:   http.StatusOK,
+			expectedETag:         `"3"`,
 			expectedResponseBody: `{"id":1,"name":"John","gender":true,"birthDate":"2000-01-01"}`,
 		},
+		{
+			name:        "Not modified",
+			actorID:     "1",
+			ifNoneMatch: `"2", W/"3"`,
+			mockBehavior: func(m *mockDomain.MockActorsUsecase, actorID uint64) {
+				m.EXPECT().
+					GetActorByID(gomock.Any(), actorID).
+					Return(httpModels.ActorResponse{ID: 1, Version: 3}, nil)
+			},
+			expectedStatusCode:   http.StatusNotModified,
+			expectedETag:         `"3"`,
+			expectedResponseBody: ``,
+		},
 		{
 			name:                 "Bad request",
 			actorID:              "a",
 			mockBehavior:         func(m *mockDomain.MockActorsUsecase, actorID uint64) {},
 			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: `{"error":"strconv.ParseUint: parsing \"a\": invalid syntax"}`,
+			expectedResponseBody: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"bad request: strconv.ParseUint: parsing \"a\": invalid syntax","code":"bad_request"}`,
 		},
 		{
 			name:    "Error Not Found",
 			actorID: "1",
 			mockBehavior: func(m *mockDomain.MockActorsUsecase, actorID uint64) {
 				m.EXPECT().
-					GetActorByID(actorID).
+					GetActorByID(gomock.Any(), actorID).
 					Return(httpModels.ActorResponse{}, domain.ErrNotFound)
 			},
 			expectedStatusCode:   http.StatusNotFound,
-			expectedResponseBody: `{"error":"failed to find item"}`,
+			expectedResponseBody: `{"type":"about:blank","title":"Not Found","status":404,"detail":"failed to find item","code":"not_found"}`,
 		},
 		{
 			name:    "Error Not Found",
 			actorID: "1",
 			mockBehavior: func(m *mockDomain.MockActorsUsecase, actorID uint64) {
 				m.EXPECT().
-					GetActorByID(actorID).
+					GetActorByID(gomock.Any(), actorID).
 					Return(httpModels.ActorResponse{}, domain.ErrInternal)
 			},
 			expectedStatusCode:   http.StatusInternalServerError,
-			expectedResponseBody: `{"error":"server error"}`,
+			expectedResponseBody: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"server error","code":"internal_error"}`,
 		},
 	}
 
@@ -258,10 +341,14 @@ func TestHandler_GetActor(t *testing.T) {
 				"/actors/"+tt.actorID,
 				nil,
 			)
+			if tt.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
 
-			mux.ServeHTTP(w, req)
+			strict(t, mux).ServeHTTP(w, req)
 
 			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedETag, w.Header().Get("ETag"))
 			assert.Equal(t, tt.expectedResponseBody, strings.Trim(w.Body.String(), "\n"))
 		})
 	}
@@ -290,7 +377,7 @@ func TestHandler_UpdateActor(t *testing.T) {
 			},
 			mockBehavior: func(m *mockDomain.MockActorsUsecase, actor httpModels.Actor, actorID uint64) {
 				m.EXPECT().
-					UpdateActor(actor, actorID).
+					UpdateActor(gomock.Any(), actor, actorID, uint64(0)).
 					Return(httpModels.ActorResponse{
 						ID:        1,
 						Name:      "John",
@@ -308,7 +395,7 @@ func TestHandler_UpdateActor(t *testing.T) {
 			inputActor:           httpModels.Actor{},
 			mockBehavior:         func(m *mockDomain.MockActorsUsecase, actor httpModels.Actor, actorID uint64) {},
 			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: `{"error":"invalid character 's' looking for beginning of value"}`,
+			expectedResponseBody: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"failed to unmarshal json: invalid character 's' looking for beginning of value","code":"invalid_json"}`,
 		},
 		{
 			name:      "Error not found",
@@ -321,11 +408,11 @@ func TestHandler_UpdateActor(t *testing.T) {
 			},
 			mockBehavior: func(m *mockDomain.MockActorsUsecase, actor httpModels.Actor, actorID uint64) {
 				m.EXPECT().
-					UpdateActor(actor, actorID).
+					UpdateActor(gomock.Any(), actor, actorID, uint64(0)).
 					Return(httpModels.ActorResponse{}, domain.ErrNotFound)
 			},
 			expectedStatusCode:   http.StatusNotFound,
-			expectedResponseBody: `{"error":"failed to find item"}`,
+			expectedResponseBody: `{"type":"about:blank","title":"Not Found","status":404,"detail":"failed to find item","code":"not_found"}`,
 		},
 		{
 			name:                 "Bad Request",
@@ -334,7 +421,7 @@ func TestHandler_UpdateActor(t *testing.T) {
 			inputActor:           httpModels.Actor{},
 			mockBehavior:         func(m *mockDomain.MockActorsUsecase, actor httpModels.Actor, actorID uint64) {},
 			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: `{"error":"strconv.ParseUint: parsing \"a\": invalid syntax"}`,
+			expectedResponseBody: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"bad request: strconv.ParseUint: parsing \"a\": invalid syntax","code":"bad_request"}`,
 		},
 		{
 			name:      "Internal Error",
@@ -347,11 +434,11 @@ func TestHandler_UpdateActor(t *testing.T) {
 			},
 			mockBehavior: func(m *mockDomain.MockActorsUsecase, actor httpModels.Actor, actorID uint64) {
 				m.EXPECT().
-					UpdateActor(actor, actorID).
+					UpdateActor(gomock.Any(), actor, actorID, uint64(0)).
 					Return(httpModels.ActorResponse{}, domain.ErrInternal)
 			},
 			expectedStatusCode:   http.StatusInternalServerError,
-			expectedResponseBody: `{"error":"server error"}`,
+			expectedResponseBody: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"server error","code":"internal_error"}`,
 		},
 	}
 
@@ -378,7 +465,118 @@ func TestHandler_UpdateActor(t *testing.T) {
 				bytes.NewBufferString(tt.inputBody),
 			)
 
-			mux.ServeHTTP(w, req)
+			strict(t, mux).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, strings.Trim(w.Body.String(), "\n"))
+		})
+	}
+}
+
+func TestHandler_PatchActor(t *testing.T) {
+	type mockBehavior func(r *mockDomain.MockActorsUsecase, actorID uint64)
+
+	tests := []struct {
+		name                 string
+		actorID              string
+		contentType          string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "Merge patch",
+			actorID:     "1",
+			contentType: "application/merge-patch+json",
+			inputBody:   `{"gender":false}`,
+			mockBehavior: func(m *mockDomain.MockActorsUsecase, actorID uint64) {
+				m.EXPECT().
+					PatchActor(gomock.Any(), actorID, uint64(0), gomock.Any()).
+					Return(httpModels.ActorResponse{
+						ID:        1,
+						Name:      "John",
+						BirthDate: "2000-01-01",
+					}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"id":1,"name":"John","gender":false,"birthDate":"2000-01-01"}`,
+		},
+		{
+			name:        "JSON patch",
+			actorID:     "1",
+			contentType: "application/json-patch+json",
+			inputBody:   `[{"op":"replace","path":"/name","value":"Jack"}]`,
+			mockBehavior: func(m *mockDomain.MockActorsUsecase, actorID uint64) {
+				m.EXPECT().
+					PatchActor(gomock.Any(), actorID, uint64(0), gomock.Any()).
+					Return(httpModels.ActorResponse{
+						ID:        1,
+						Name:      "Jack",
+						BirthDate: "2000-01-01",
+					}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"id":1,"name":"Jack","gender":false,"birthDate":"2000-01-01"}`,
+		},
+		{
+			name:                 "Unsupported media type",
+			actorID:              "1",
+			contentType:          "application/json",
+			inputBody:            `{"gender":false}`,
+			mockBehavior:         func(m *mockDomain.MockActorsUsecase, actorID uint64) {},
+			expectedStatusCode:   http.StatusUnsupportedMediaType,
+			expectedResponseBody: `{"type":"about:blank","title":"Unsupported Media Type","status":415,"detail":"unsupported media type: use application/merge-patch+json or application/json-patch+json","code":"unsupported_media_type"}`,
+		},
+		{
+			name:                 "Unknown op",
+			actorID:              "1",
+			contentType:          "application/json-patch+json",
+			inputBody:            `[{"op":"rename","path":"/name"}]`,
+			mockBehavior:         func(m *mockDomain.MockActorsUsecase, actorID uint64) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid patch document: operation 0: unknown op \"rename\"","code":"invalid_patch"}`,
+		},
+		{
+			name:        "Not found",
+			actorID:     "1",
+			contentType: "application/merge-patch+json",
+			inputBody:   `{"gender":false}`,
+			mockBehavior: func(m *mockDomain.MockActorsUsecase, actorID uint64) {
+				m.EXPECT().
+					PatchActor(gomock.Any(), actorID, uint64(0), gomock.Any()).
+					Return(httpModels.ActorResponse{}, domain.ErrNotFound)
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"type":"about:blank","title":"Not Found","status":404,"detail":"failed to find item","code":"not_found"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cntx := gomock.NewController(t)
+			defer cntx.Finish()
+
+			mockActorsUsecase := mockDomain.NewMockActorsUsecase(cntx)
+
+			actorID, _ := strconv.ParseUint(tt.actorID, 10, 64)
+
+			tt.mockBehavior(mockActorsUsecase, actorID)
+
+			handler := NewActorsUsecase(mockActorsUsecase)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("PATCH /actors/{id}", handler.PatchActor)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(
+				http.MethodPatch,
+				"/actors/"+tt.actorID,
+				bytes.NewBufferString(tt.inputBody),
+			)
+			req.Header.Set("Content-Type", tt.contentType)
+
+			strict(t, mux).ServeHTTP(w, req)
 
 			assert.Equal(t, tt.expectedStatusCode, w.Code)
 			assert.Equal(t, tt.expectedResponseBody, strings.Trim(w.Body.String(), "\n"))
@@ -401,7 +599,7 @@ func TestHandler_GetActors(t *testing.T) {
 			pageNum: "",
 			mockBehavior: func(m *mockDomain.MockActorsUsecase, pageNum uint64) {
 				m.EXPECT().
-					GetActors(pageNum).
+					GetActors(gomock.Any(), pageNum).
 					Return([]httpModels.GetActorsResponse{
 						{
 							httpModels.ActorResponse{
@@ -422,18 +620,18 @@ func TestHandler_GetActors(t *testing.T) {
 			pageNum:              "a",
 			mockBehavior:         func(m *mockDomain.MockActorsUsecase, pageNum uint64) {},
 			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: `{"error":"strconv.ParseUint: parsing \"a\": invalid syntax"}`,
+			expectedResponseBody: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"bad request: strconv.ParseUint: parsing \"a\": invalid syntax","code":"bad_request"}`,
 		},
 		{
 			name:    "Internal server error",
 			pageNum: "1",
 			mockBehavior: func(m *mockDomain.MockActorsUsecase, pageNum uint64) {
 				m.EXPECT().
-					GetActors(pageNum).
+					GetActors(gomock.Any(), pageNum).
 					Return([]httpModels.GetActorsResponse{}, errors.New("empty actors"))
 			},
 			expectedStatusCode:   http.StatusInternalServerError,
-			expectedResponseBody: `{"error":"empty actors"}`,
+			expectedResponseBody: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"server error","code":"internal_error"}`,
 		},
 	}
 
@@ -472,7 +670,7 @@ func TestHandler_GetActors(t *testing.T) {
 				)
 			}
 
-			mux.ServeHTTP(w, req)
+			strict(t, mux).ServeHTTP(w, req)
 
 			assert.Equal(t, tt.expectedStatusCode, w.Code)
 			assert.Equal(t, tt.expectedResponseBody, strings.Trim(w.Body.String(), "\n"))
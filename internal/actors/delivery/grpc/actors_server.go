@@ -0,0 +1,132 @@
+package grpcActors
+
+import (
+	"context"
+
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/validate"
+	filmlibraryv1 "github.com/themilchenko/vk-tech_internship-problem_2024/pkg/api/filmlibrary/v1"
+)
+
+const (
+	defaultPage = 1
+)
+
+type ActorsServer struct {
+	filmlibraryv1.UnimplementedActorsServiceServer
+
+	actorsUsecase domain.ActorsUsecase
+}
+
+func NewActorsServer(a domain.ActorsUsecase) *ActorsServer {
+	return &ActorsServer{actorsUsecase: a}
+}
+
+func (s *ActorsServer) CreateActor(
+	ctx context.Context,
+	req *filmlibraryv1.CreateActorRequest,
+) (*filmlibraryv1.CreateActorResponse, error) {
+	actor := httpModels.Actor{
+		Name:      req.GetName(),
+		Gender:    req.GetGender(),
+		BirthDate: req.GetBirthDate(),
+	}
+	if err := validate.Check(actor); err != nil {
+		return nil, err
+	}
+
+	actorID, err := s.actorsUsecase.CreateActor(ctx, actor)
+	if err != nil {
+		return nil, err
+	}
+	return &filmlibraryv1.CreateActorResponse{Id: actorID}, nil
+}
+
+func (s *ActorsServer) GetActor(
+	ctx context.Context,
+	req *filmlibraryv1.GetActorRequest,
+) (*filmlibraryv1.GetActorResponse, error) {
+	actor, err := s.actorsUsecase.GetActorByID(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return &filmlibraryv1.GetActorResponse{Actor: ToActor(actor)}, nil
+}
+
+func (s *ActorsServer) ListActors(
+	ctx context.Context,
+	req *filmlibraryv1.ListActorsRequest,
+) (*filmlibraryv1.ListActorsResponse, error) {
+	pageNum := req.GetPage()
+	if pageNum == 0 {
+		pageNum = defaultPage
+	}
+
+	actors, err := s.actorsUsecase.GetActors(ctx, pageNum)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &filmlibraryv1.ListActorsResponse{
+		Actors: make([]*filmlibraryv1.ActorWithFilms, len(actors)),
+	}
+	for i, actor := range actors {
+		films := make([]*filmlibraryv1.Film, len(actor.ActedInFilms))
+		for j, film := range actor.ActedInFilms {
+			films[j] = &filmlibraryv1.Film{
+				Id:          film.ID,
+				Title:       film.Title,
+				Description: film.Description,
+				ReleaseDate: film.ReleaseDate,
+				Rating:      film.Rating,
+			}
+		}
+		response.Actors[i] = &filmlibraryv1.ActorWithFilms{
+			Actor: ToActor(actor.Actor),
+			Films: films,
+		}
+	}
+	return response, nil
+}
+
+func (s *ActorsServer) UpdateActor(
+	ctx context.Context,
+	req *filmlibraryv1.UpdateActorRequest,
+) (*filmlibraryv1.UpdateActorResponse, error) {
+	actor := httpModels.Actor{
+		Name:      req.GetName(),
+		Gender:    req.GetGender(),
+		BirthDate: req.GetBirthDate(),
+	}
+	if err := validate.Check(actor); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.actorsUsecase.UpdateActor(ctx, actor, req.GetId(), req.GetVersion())
+	if err != nil {
+		return nil, err
+	}
+	return &filmlibraryv1.UpdateActorResponse{Actor: ToActor(updated)}, nil
+}
+
+func (s *ActorsServer) DeleteActor(
+	ctx context.Context,
+	req *filmlibraryv1.DeleteActorRequest,
+) (*filmlibraryv1.DeleteActorResponse, error) {
+	if err := s.actorsUsecase.DeleteActorByID(ctx, req.GetId(), req.GetVersion()); err != nil {
+		return nil, err
+	}
+	return &filmlibraryv1.DeleteActorResponse{}, nil
+}
+
+// ToActor is an actor as a message, the movies service sends casts with it.
+func ToActor(actor httpModels.ActorResponse) *filmlibraryv1.Actor {
+	return &filmlibraryv1.Actor{
+		Id:        actor.ID,
+		Name:      actor.Name,
+		Gender:    actor.Gender,
+		BirthDate: actor.BirthDate,
+		Version:   actor.Version,
+	}
+}
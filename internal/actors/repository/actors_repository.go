@@ -1,9 +1,28 @@
 package actorsRepository
 
 import (
+	"context"
+	"log/slog"
+
+	auditRepository "github.com/themilchenko/vk-tech_internship-problem_2024/internal/audit/repository"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
 	gormModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/gorm"
+	moviesRepository "github.com/themilchenko/vk-tech_internship-problem_2024/internal/movies/repository"
+	revisionsRepository "github.com/themilchenko/vk-tech_internship-problem_2024/internal/revisions/repository"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/dbtx"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/metrics"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/pgerrors"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/sqlcomment"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/tracing"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	gormLogger "gorm.io/gorm/logger"
+)
+
+const (
+	actorEntity = "actor"
+	castEntity  = "cast"
 )
 
 type Postgres struct {
@@ -12,14 +31,31 @@ type Postgres struct {
 	pageSize uint64
 }
 
-func NewPostgres(url string, ps uint64) (*Postgres, error) {
-	db, err := gorm.Open(postgres.Open(url), &gorm.Config{})
+func NewPostgres(url string, ps uint64, logger gormLogger.Interface) (*Postgres, error) {
+	db, err := gorm.Open(postgres.Open(url), &gorm.Config{Logger: logger})
 	if err != nil {
 		return nil, err
 	}
+	if err = db.Use(pgerrors.Plugin{}); err != nil {
+		return nil, err
+	}
+	if err = db.Use(dbtx.Plugin{}); err != nil {
+		return nil, err
+	}
+	if err = db.Use(sqlcomment.Plugin{}); err != nil {
+		return nil, err
+	}
+	if err = db.Use(metrics.Plugin{DB: "actors"}); err != nil {
+		return nil, err
+	}
+	if err = db.Use(tracing.Plugin{DB: "actors"}); err != nil {
+		return nil, err
+	}
 
 	db.AutoMigrate(
 		gormModels.Actor{},
+		gormModels.AuditEntry{},
+		gormModels.Revision{},
 	)
 
 	return &Postgres{
@@ -28,49 +64,183 @@ func NewPostgres(url string, ps uint64) (*Postgres, error) {
 	}, nil
 }
 
-func (db Postgres) CreateActor(actor gormModels.Actor) (uint64, error) {
-	var recievedActor gormModels.Actor
-	if err := db.DB.Create(&actor).Scan(&recievedActor).Error; err != nil {
+func (db Postgres) CreateActor(ctx context.Context, actor gormModels.Actor) (uint64, error) {
+	if err := db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&actor).Error; err != nil {
+			return err
+		}
+		if err := auditRepository.Record(ctx, tx, actorEntity, actor.ID, nil, actor); err != nil {
+			return err
+		}
+		return revisionsRepository.Record(
+			ctx, tx, domain.ActorEntity, actor.ID, actor.ToHTTPModel(), false,
+		)
+	}); err != nil {
 		return 0, err
 	}
-	return recievedActor.ID, nil
+	return actor.ID, nil
 }
 
-func (db Postgres) UpdateActor(actor gormModels.Actor) (gormModels.Actor, error) {
+func (db Postgres) UpdateActor(
+	ctx context.Context,
+	actor gormModels.Actor,
+) (gormModels.Actor, error) {
+	return db.PatchActor(ctx, actor.ID, actor.Version, func(a *gormModels.Actor) error {
+		a.Name = actor.Name
+		a.Gender = actor.Gender
+		a.BirthDate = actor.BirthDate
+		return nil
+	})
+}
+
+// PatchActor locks the actor and saves what patch made of it, both in one
+// transaction. Every column is written, so zero values are saved too. A
+// non-zero version has to be the current one. The movies of the actor are
+// touched, as they show it in their cast.
+func (db Postgres) PatchActor(
+	ctx context.Context,
+	actorID, version uint64,
+	patch func(actor *gormModels.Actor) error,
+) (gormModels.Actor, error) {
 	var recievedActor gormModels.Actor
-	if err := db.DB.Model(&gormModels.Actor{ID: actor.ID}).
-		Updates(actor).
-		Scan(&recievedActor).
-		Error; err != nil {
+	if err := db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// The row lock keeps concurrent updates from racing for the
+		// same revision number.
+		var before gormModels.Actor
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&before, "id = ?", actorID).
+			Error; err != nil {
+			return err
+		}
+		if version != 0 && before.Version != version {
+			slog.DebugContext(ctx, "version mismatch", "actor_id", actorID, "expected", version, "current", before.Version)
+			return domain.ErrVersionMismatch
+		}
+
+		after := before
+		if err := patch(&after); err != nil {
+			return err
+		}
+		after.Version = before.Version + 1
+		if err := tx.Model(&gormModels.Actor{ID: actorID}).
+			Select("name", "gender", "birth_date", "version").
+			Updates(&after).
+			Error; err != nil {
+			return err
+		}
+
+		if err := tx.First(&recievedActor, "id = ?", actorID).Error; err != nil {
+			return err
+		}
+
+		// Movies show their cast, their ETags have to change with it.
+		var movieIDs []uint64
+		if err := tx.Model(&gormModels.ActorMovieRelation{}).
+			Where("actor_id = ?", actorID).
+			Pluck("movie_id", &movieIDs).
+			Error; err != nil {
+			return err
+		}
+		if err := moviesRepository.TouchMovies(tx, movieIDs...); err != nil {
+			return err
+		}
+
+		if err := auditRepository.Record(ctx, tx, actorEntity, actorID, before, recievedActor); err != nil {
+			return err
+		}
+		return revisionsRepository.Record(
+			ctx, tx, domain.ActorEntity, actorID, recievedActor.ToHTTPModel(), false,
+		)
+	}); err != nil {
 		return gormModels.Actor{}, err
 	}
 	return recievedActor, nil
 }
 
-func (db Postgres) DeleteActorByID(actorID uint64) error {
-	if err := db.DB.Unscoped().
-		Delete(&gormModels.Actor{}, "id = ?", actorID).
-		Error; err != nil {
-		return err
-	}
-	return nil
+func (db Postgres) DeleteActorByID(ctx context.Context, actorID, version uint64) error {
+	return db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var before gormModels.Actor
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&before, "id = ?", actorID).
+			Error; err != nil {
+			return err
+		}
+		if version != 0 && before.Version != version {
+			slog.DebugContext(ctx, "version mismatch", "actor_id", actorID, "expected", version, "current", before.Version)
+			return domain.ErrVersionMismatch
+		}
+
+		// The cast links go to the trash with the actor, they are recorded too.
+		var relations []gormModels.ActorMovieRelation
+		if err := tx.Where("actor_id = ?", actorID).Find(&relations).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&before).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("actor_id = ?", actorID).
+			Delete(&gormModels.ActorMovieRelation{}).
+			Error; err != nil {
+			return err
+		}
+
+		movieIDs := make([]uint64, 0, len(relations))
+		for _, relation := range relations {
+			if err := auditRepository.Record(
+				ctx, tx, castEntity, relation.MovieID, relation, nil,
+			); err != nil {
+				return err
+			}
+			movieIDs = append(movieIDs, relation.MovieID)
+		}
+		if err := moviesRepository.TouchMovies(tx, movieIDs...); err != nil {
+			return err
+		}
+		if err := auditRepository.Record(ctx, tx, actorEntity, actorID, before, nil); err != nil {
+			return err
+		}
+		return revisionsRepository.Record(ctx, tx, domain.ActorEntity, actorID, before.ToHTTPModel(), true)
+	})
 }
 
-func (db Postgres) GetActorByID(actorID uint64) (gormModels.Actor, error) {
+func (db Postgres) GetActorByID(ctx context.Context, actorID uint64) (gormModels.Actor, error) {
 	var recievedActor gormModels.Actor
-	if err := db.DB.First(&gormModels.Actor{ID: actorID}).
+	if err := db.DB.WithContext(ctx).First(&gormModels.Actor{ID: actorID}).
 		Scan(&recievedActor).
 		Error; err != nil {
-		return gormModels.Actor{}, nil
+		return gormModels.Actor{}, err
 	}
 	return recievedActor, nil
 }
 
-func (db Postgres) GetActorsFromMovie(movieID uint64) ([]gormModels.Actor, error) {
+// GetActorsByIDs returns the actors found among the ids, in no particular
+// order.
+func (db Postgres) GetActorsByIDs(
+	ctx context.Context,
+	actorIDs []uint64,
+) ([]gormModels.Actor, error) {
+	var recievedActors []gormModels.Actor
+	if len(actorIDs) == 0 {
+		return recievedActors, nil
+	}
+	if err := db.DB.WithContext(ctx).
+		Where("id IN ?", actorIDs).
+		Find(&recievedActors).
+		Error; err != nil {
+		return nil, err
+	}
+	return recievedActors, nil
+}
+
+func (db Postgres) GetActorsFromMovie(
+	ctx context.Context,
+	movieID uint64,
+) ([]gormModels.Actor, error) {
 	var recievedActors []gormModels.Actor
-	if err := db.DB.Model(&gormModels.ActorMovieRelation{}).
+	if err := db.DB.WithContext(ctx).Model(&gormModels.ActorMovieRelation{}).
 		Joins("JOIN actors ON actors.id=actor_movie_relations.actor_id").
-		Where("movie_id = ?", movieID).
+		Where("movie_id = ? AND actors.deleted_at IS NULL", movieID).
 		Select("actors.id, actors.name, actors.birth_date").
 		Find(&recievedActors).
 		Error; err != nil {
@@ -79,11 +249,11 @@ func (db Postgres) GetActorsFromMovie(movieID uint64) ([]gormModels.Actor, error
 	return recievedActors, nil
 }
 
-func (db Postgres) GetActors(pageNum uint64) ([]gormModels.Actor, error) {
+func (db Postgres) GetActors(ctx context.Context, pageNum uint64) ([]gormModels.Actor, error) {
 	offset := db.pageSize * (pageNum - 1)
 	var recievedActors []gormModels.Actor
 
-	if err := db.DB.Offset(int(offset)).
+	if err := db.DB.WithContext(ctx).Offset(int(offset)).
 		Limit(int(db.pageSize)).
 		Order("name").
 		Find(&recievedActors).
@@ -1,12 +1,16 @@
 package actorsUsecase
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"log/slog"
 	"time"
 
 	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
 	gormModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/gorm"
 	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/validate"
 	"gorm.io/gorm"
 )
 
@@ -22,59 +26,149 @@ func NewActorsUsecase(a domain.ActorsRepository, m domain.MoviesRepository) Acto
 	}
 }
 
-func (u ActorsUsecase) CreateActor(actor httpModels.Actor) (uint64, error) {
+// NewActor checks a new actor by the rules of its fields and converts it
+// for the repository. Every way of creating actors goes through it.
+func NewActor(actor httpModels.Actor) (gormModels.Actor, error) {
+	if violations := validate.Struct(actor); len(violations) > 0 {
+		return gormModels.Actor{}, domain.ValidationError{Violations: violations}
+	}
+
 	t, err := time.Parse(time.DateOnly, actor.BirthDate)
 	if err != nil {
-		return 0, err
+		return gormModels.Actor{}, domain.ErrValidation.Wrap(err)
 	}
 
-	actorID, err := u.actorsRepository.CreateActor(gormModels.Actor{
+	return gormModels.Actor{
 		Name:      actor.Name,
 		BirthDate: t,
 		Gender:    actor.Gender,
-	})
+	}, nil
+}
+
+func (u ActorsUsecase) CreateActor(ctx context.Context, actor httpModels.Actor) (uint64, error) {
+	gormActor, err := NewActor(actor)
 	if err != nil {
 		return 0, err
 	}
 
+	actorID, err := u.actorsRepository.CreateActor(ctx, gormActor)
+	if err != nil {
+		return 0, err
+	}
+
+	slog.InfoContext(ctx, "actor created", "actor_id", actorID)
 	return actorID, nil
 }
 
-func (u ActorsUsecase) GetActorByID(actorID uint64) (httpModels.ActorResponse, error) {
-	actor, err := u.actorsRepository.GetActorByID(actorID)
+func (u ActorsUsecase) GetActorByID(
+	ctx context.Context,
+	actorID uint64,
+) (httpModels.ActorResponse, error) {
+	actor, err := u.actorsRepository.GetActorByID(ctx, actorID)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return httpModels.ActorResponse{}, domain.ErrNotFound
+		}
 		return httpModels.ActorResponse{}, err
 	}
 	return actor.ToHTTPModel(), nil
 }
 
 func (u ActorsUsecase) UpdateActor(
+	ctx context.Context,
 	actor httpModels.Actor,
-	actorID uint64,
+	actorID, version uint64,
 ) (httpModels.ActorResponse, error) {
 	t, err := time.Parse(time.DateOnly, actor.BirthDate)
 	if err != nil {
-		return httpModels.ActorResponse{}, err
+		return httpModels.ActorResponse{}, domain.ErrValidation.Wrap(err)
 	}
 
-	updatedActor, err := u.actorsRepository.UpdateActor(gormModels.Actor{
+	updatedActor, err := u.actorsRepository.UpdateActor(ctx, gormModels.Actor{
 		ID:        actorID,
 		Name:      actor.Name,
 		Gender:    actor.Gender,
 		BirthDate: t,
+		Version:   version,
 	})
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return httpModels.ActorResponse{}, domain.ErrNotFound
+		}
 		return httpModels.ActorResponse{}, err
 	}
+	slog.InfoContext(ctx, "actor updated", "actor_id", actorID, "version", updatedActor.Version)
 	return updatedActor.ToHTTPModel(), nil
 }
 
-func (u ActorsUsecase) DeleteActorByID(actorID uint64) error {
-	return u.actorsRepository.DeleteActorByID(actorID)
+// PatchActor applies the patch to the JSON form of the actor. Members the
+// patch leaves out are kept, the result is checked like a PUT body.
+func (u ActorsUsecase) PatchActor(
+	ctx context.Context,
+	actorID, version uint64,
+	patch domain.Patch,
+) (httpModels.ActorResponse, error) {
+	updatedActor, err := u.actorsRepository.PatchActor(
+		ctx,
+		actorID,
+		version,
+		func(actor *gormModels.Actor) error {
+			doc, err := json.Marshal(httpModels.Actor{
+				Name:      actor.Name,
+				Gender:    actor.Gender,
+				BirthDate: actor.BirthDate.Format(time.DateOnly),
+			})
+			if err != nil {
+				return err
+			}
+			if doc, err = patch.Apply(doc); err != nil {
+				return err
+			}
+
+			var patched httpModels.Actor
+			if err = validate.Unmarshal(doc, &patched); err != nil {
+				return err
+			}
+			if err = validate.NotNull(doc, "gender"); err != nil {
+				return err
+			}
+
+			t, err := time.Parse(time.DateOnly, patched.BirthDate)
+			if err != nil {
+				return domain.ErrValidation.Wrap(err)
+			}
+			actor.Name = patched.Name
+			actor.Gender = patched.Gender
+			actor.BirthDate = t
+			return nil
+		},
+	)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return httpModels.ActorResponse{}, domain.ErrNotFound
+		}
+		return httpModels.ActorResponse{}, err
+	}
+	slog.InfoContext(ctx, "actor updated", "actor_id", actorID, "version", updatedActor.Version)
+	return updatedActor.ToHTTPModel(), nil
+}
+
+func (u ActorsUsecase) DeleteActorByID(ctx context.Context, actorID, version uint64) error {
+	if err := u.actorsRepository.DeleteActorByID(ctx, actorID, version); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.ErrNotFound
+		}
+		return err
+	}
+	slog.InfoContext(ctx, "actor deleted", "actor_id", actorID)
+	return nil
 }
 
-func (u ActorsUsecase) GetActors(pageNum uint64) ([]httpModels.GetActorsResponse, error) {
-	actors, err := u.actorsRepository.GetActors(pageNum)
+func (u ActorsUsecase) GetActors(
+	ctx context.Context,
+	pageNum uint64,
+) ([]httpModels.GetActorsResponse, error) {
+	actors, err := u.actorsRepository.GetActors(ctx, pageNum)
 	if err != nil {
 		return []httpModels.GetActorsResponse{}, err
 	}
@@ -82,7 +176,7 @@ func (u ActorsUsecase) GetActors(pageNum uint64) ([]httpModels.GetActorsResponse
 	responseActors := make([]httpModels.GetActorsResponse, len(actors))
 	for i, v := range actors {
 		responseActors[i].Actor = v.ToHTTPModel()
-		movies, err := u.moviesRepository.GetMoviesOfActor(v.ID)
+		movies, err := u.moviesRepository.GetMoviesOfActor(ctx, v.ID)
 		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 			return []httpModels.GetActorsResponse{}, err
 		}
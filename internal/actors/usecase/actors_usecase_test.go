@@ -1,13 +1,16 @@
 package actorsUsecase
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
 	mockDomain "github.com/themilchenko/vk-tech_internship-problem_2024/internal/mocks/domain"
 	gormModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/gorm"
 	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/jsonpatch"
 	"go.uber.org/mock/gomock"
 )
 
@@ -30,7 +33,7 @@ func TestUsecase_CreateActor(t *testing.T) {
 			},
 			mockBehaviorCreateActor: func(m *mockDomain.MockActorsRepository, actor gormModels.Actor) {
 				m.EXPECT().
-					CreateActor(actor).
+					CreateActor(gomock.Any(), actor).
 					Return(uint64(1), nil)
 			},
 			expectedActorID: uint64(1),
@@ -58,7 +61,7 @@ func TestUsecase_CreateActor(t *testing.T) {
 
 			tt.mockBehaviorCreateActor(mockRepo, actor)
 
-			actorID, err := u.CreateActor(tt.inputActor)
+			actorID, err := u.CreateActor(context.Background(), tt.inputActor)
 			assert.Equal(t, tt.expectedActorID, actorID)
 			assert.Equal(t, tt.expectedError, err)
 		})
@@ -80,7 +83,7 @@ func TestUsecase_GetActorByID(t *testing.T) {
 			inputActorID: uint64(1),
 			mockBehaviorGetActorByID: func(m *mockDomain.MockActorsRepository, actorID uint64) {
 				m.EXPECT().
-					GetActorByID(actorID).
+					GetActorByID(gomock.Any(), actorID).
 					Return(gormModels.Actor{
 						ID:        1,
 						Name:      "Name",
@@ -110,7 +113,7 @@ func TestUsecase_GetActorByID(t *testing.T) {
 
 			tt.mockBehaviorGetActorByID(mockRepo, tt.inputActorID)
 
-			actor, err := u.GetActorByID(tt.inputActorID)
+			actor, err := u.GetActorByID(context.Background(), tt.inputActorID)
 			assert.Equal(t, tt.expectedActorResponse, actor)
 			assert.Equal(t, tt.expectedError, err)
 		})
@@ -138,7 +141,7 @@ func TestUsecase_UpdateActor(t *testing.T) {
 			inputActorID: uint64(1),
 			mockBehaviorUpdateActor: func(m *mockDomain.MockActorsRepository, actor gormModels.Actor, actorID uint64) {
 				m.EXPECT().
-					UpdateActor(actor).
+					UpdateActor(gomock.Any(), actor).
 					Return(gormModels.Actor{
 						ID:        1,
 						Name:      "Name",
@@ -177,7 +180,87 @@ func TestUsecase_UpdateActor(t *testing.T) {
 
 			tt.mockBehaviorUpdateActor(mockRepo, actor, tt.inputActorID)
 
-			actorResponse, err := u.UpdateActor(tt.inputActor, tt.inputActorID)
+			actorResponse, err := u.UpdateActor(context.Background(), tt.inputActor, tt.inputActorID, 0)
+			assert.Equal(t, tt.expectedActorResponse, actorResponse)
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestUsecase_PatchActor(t *testing.T) {
+	stored := gormModels.Actor{
+		ID:        1,
+		Name:      "Name",
+		Gender:    true,
+		BirthDate: time.Date(2006, 1, 2, 0, 0, 0, 0, time.UTC),
+	}
+
+	mergePatch := func(doc string) domain.Patch {
+		p, err := jsonpatch.NewMergePatch([]byte(doc))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+
+	tests := []struct {
+		name                  string
+		patch                 domain.Patch
+		expectedActor         gormModels.Actor
+		expectedActorResponse httpModels.ActorResponse
+		expectedError         error
+	}{
+		{
+			name:  "False gender",
+			patch: mergePatch(`{"gender":false}`),
+			expectedActor: gormModels.Actor{
+				ID:        1,
+				Name:      "Name",
+				BirthDate: time.Date(2006, 1, 2, 0, 0, 0, 0, time.UTC),
+			},
+			expectedActorResponse: httpModels.ActorResponse{
+				ID:        1,
+				Name:      "Name",
+				BirthDate: "2006-01-02",
+			},
+		},
+		{
+			name:  "Null gender",
+			patch: mergePatch(`{"gender":null}`),
+			expectedError: domain.ValidationError{Violations: []domain.FieldError{{
+				Field:   "gender",
+				Rule:    "not_null",
+				Message: "can't be null",
+			}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mockDomain.NewMockActorsRepository(ctrl)
+			mockMovieRepo := mockDomain.NewMockMoviesRepository(ctrl)
+
+			u := NewActorsUsecase(mockRepo, mockMovieRepo)
+
+			mockRepo.EXPECT().
+				PatchActor(gomock.Any(), uint64(1), uint64(0), gomock.Any()).
+				DoAndReturn(func(
+					_ context.Context,
+					_, _ uint64,
+					patch func(*gormModels.Actor) error,
+				) (gormModels.Actor, error) {
+					actor := stored
+					if err := patch(&actor); err != nil {
+						return gormModels.Actor{}, err
+					}
+					assert.Equal(t, tt.expectedActor, actor)
+					return actor, nil
+				})
+
+			actorResponse, err := u.PatchActor(context.Background(), 1, 0, tt.patch)
 			assert.Equal(t, tt.expectedActorResponse, actorResponse)
 			assert.Equal(t, tt.expectedError, err)
 		})
@@ -201,7 +284,7 @@ func TestUsecase_GetActors(t *testing.T) {
 			inputPageNum: uint64(1),
 			mockBehaviorGetActors: func(m *mockDomain.MockActorsRepository, pageNum uint64) {
 				m.EXPECT().
-					GetActors(pageNum).
+					GetActors(gomock.Any(), pageNum).
 					Return([]gormModels.Actor{
 						{
 							ID:        1,
@@ -213,7 +296,7 @@ func TestUsecase_GetActors(t *testing.T) {
 			},
 			mockBehaviorGetMoviesByActor: func(m *mockDomain.MockMoviesRepository, actorID uint64) {
 				m.EXPECT().
-					GetMoviesOfActor(actorID).
+					GetMoviesOfActor(gomock.Any(), actorID).
 					Return([]gormModels.Movie{
 						{
 							ID:          1,
@@ -258,7 +341,7 @@ func TestUsecase_GetActors(t *testing.T) {
 			tt.mockBehaviorGetActors(mockRepo, tt.inputPageNum)
 			tt.mockBehaviorGetMoviesByActor(mockMovieRepo, uint64(1))
 
-			actors, err := u.GetActors(tt.inputPageNum)
+			actors, err := u.GetActors(context.Background(), tt.inputPageNum)
 			assert.Equal(t, tt.expectedActorResponse, actors)
 			assert.Equal(t, tt.expectedError, err)
 		})
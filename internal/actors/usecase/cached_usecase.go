@@ -0,0 +1,112 @@
+package actorsUsecase
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/cache"
+)
+
+// CachedActorsUsecase serves actor reads from the cache and drops the
+// entries a write makes stale. Movies show their cast and are found by actor
+// names, so changes of an actor drop the movie lists and the movies tagged
+// with the actor too.
+type CachedActorsUsecase struct {
+	actorsUsecase domain.ActorsUsecase
+	cache         domain.Cache
+}
+
+func NewCachedActorsUsecase(u domain.ActorsUsecase, c domain.Cache) CachedActorsUsecase {
+	return CachedActorsUsecase{
+		actorsUsecase: u,
+		cache:         c,
+	}
+}
+
+// cachedActor keeps the version, which the JSON form of an actor leaves out.
+type cachedActor struct {
+	Actor   httpModels.ActorResponse `json:"actor"`
+	Version uint64                   `json:"version"`
+}
+
+func (u CachedActorsUsecase) GetActorByID(
+	ctx context.Context,
+	actorID uint64,
+) (httpModels.ActorResponse, error) {
+	cached, err := cache.Load(
+		ctx,
+		u.cache,
+		"actor:"+strconv.FormatUint(actorID, 10),
+		func(cachedActor) []string { return []string{cache.ActorTag(actorID)} },
+		func() (cachedActor, error) {
+			actor, err := u.actorsUsecase.GetActorByID(ctx, actorID)
+			return cachedActor{Actor: actor, Version: actor.Version}, err
+		},
+	)
+	if err != nil {
+		return httpModels.ActorResponse{}, err
+	}
+	cached.Actor.Version = cached.Version
+	return cached.Actor, nil
+}
+
+func (u CachedActorsUsecase) GetActors(
+	ctx context.Context,
+	pageNum uint64,
+) ([]httpModels.GetActorsResponse, error) {
+	return cache.Load(
+		ctx,
+		u.cache,
+		"actors?page="+strconv.FormatUint(pageNum, 10),
+		func([]httpModels.GetActorsResponse) []string { return []string{cache.ActorsTag} },
+		func() ([]httpModels.GetActorsResponse, error) {
+			return u.actorsUsecase.GetActors(ctx, pageNum)
+		},
+	)
+}
+
+func (u CachedActorsUsecase) CreateActor(ctx context.Context, actor httpModels.Actor) (uint64, error) {
+	actorID, err := u.actorsUsecase.CreateActor(ctx, actor)
+	if err == nil {
+		u.cache.Invalidate(ctx, cache.ActorsTag)
+	}
+	return actorID, err
+}
+
+func (u CachedActorsUsecase) UpdateActor(
+	ctx context.Context,
+	actor httpModels.Actor,
+	actorID, version uint64,
+) (httpModels.ActorResponse, error) {
+	updatedActor, err := u.actorsUsecase.UpdateActor(ctx, actor, actorID, version)
+	if err == nil {
+		u.invalidate(ctx, actorID)
+	}
+	return updatedActor, err
+}
+
+func (u CachedActorsUsecase) PatchActor(
+	ctx context.Context,
+	actorID, version uint64,
+	patch domain.Patch,
+) (httpModels.ActorResponse, error) {
+	updatedActor, err := u.actorsUsecase.PatchActor(ctx, actorID, version, patch)
+	if err == nil {
+		u.invalidate(ctx, actorID)
+	}
+	return updatedActor, err
+}
+
+func (u CachedActorsUsecase) DeleteActorByID(ctx context.Context, actorID, version uint64) error {
+	err := u.actorsUsecase.DeleteActorByID(ctx, actorID, version)
+	if err == nil {
+		u.invalidate(ctx, actorID)
+	}
+	return err
+}
+
+func (u CachedActorsUsecase) invalidate(ctx context.Context, actorID uint64) {
+	u.cache.Invalidate(ctx, cache.ActorTag(actorID), cache.ActorsTag, cache.MoviesTag)
+}
@@ -0,0 +1,75 @@
+package actorsUsecase
+
+import (
+	"context"
+
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/tracing"
+)
+
+// TracedActorsUsecase makes a span of every call, the statements reading
+// the actor and the films it acted in are its children.
+type TracedActorsUsecase struct {
+	actorsUsecase domain.ActorsUsecase
+}
+
+func NewTracedActorsUsecase(u domain.ActorsUsecase) TracedActorsUsecase {
+	return TracedActorsUsecase{actorsUsecase: u}
+}
+
+func (u TracedActorsUsecase) CreateActor(
+	ctx context.Context,
+	actor httpModels.Actor,
+) (_ uint64, err error) {
+	ctx, span := tracing.Start(ctx, "ActorsUsecase.CreateActor")
+	defer func() { tracing.End(span, err) }()
+	return u.actorsUsecase.CreateActor(ctx, actor)
+}
+
+func (u TracedActorsUsecase) GetActorByID(
+	ctx context.Context,
+	actorID uint64,
+) (_ httpModels.ActorResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ActorsUsecase.GetActorByID")
+	defer func() { tracing.End(span, err) }()
+	return u.actorsUsecase.GetActorByID(ctx, actorID)
+}
+
+func (u TracedActorsUsecase) UpdateActor(
+	ctx context.Context,
+	actor httpModels.Actor,
+	actorID, version uint64,
+) (_ httpModels.ActorResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ActorsUsecase.UpdateActor")
+	defer func() { tracing.End(span, err) }()
+	return u.actorsUsecase.UpdateActor(ctx, actor, actorID, version)
+}
+
+func (u TracedActorsUsecase) PatchActor(
+	ctx context.Context,
+	actorID, version uint64,
+	patch domain.Patch,
+) (_ httpModels.ActorResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ActorsUsecase.PatchActor")
+	defer func() { tracing.End(span, err) }()
+	return u.actorsUsecase.PatchActor(ctx, actorID, version, patch)
+}
+
+func (u TracedActorsUsecase) DeleteActorByID(
+	ctx context.Context,
+	actorID, version uint64,
+) (err error) {
+	ctx, span := tracing.Start(ctx, "ActorsUsecase.DeleteActorByID")
+	defer func() { tracing.End(span, err) }()
+	return u.actorsUsecase.DeleteActorByID(ctx, actorID, version)
+}
+
+func (u TracedActorsUsecase) GetActors(
+	ctx context.Context,
+	pageNum uint64,
+) (_ []httpModels.GetActorsResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ActorsUsecase.GetActors")
+	defer func() { tracing.End(span, err) }()
+	return u.actorsUsecase.GetActors(ctx, pageNum)
+}
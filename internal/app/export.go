@@ -0,0 +1,48 @@
+package app
+
+import (
+	"context"
+	"net/url"
+	"os"
+
+	httpCatalog "github.com/themilchenko/vk-tech_internship-problem_2024/internal/catalog/delivery"
+	catalogRepository "github.com/themilchenko/vk-tech_internship-problem_2024/internal/catalog/repository"
+	catalogUsecase "github.com/themilchenko/vk-tech_internship-problem_2024/internal/catalog/usecase"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/config"
+	httpMovies "github.com/themilchenko/vk-tech_internship-problem_2024/internal/movies/delivery"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/dblog"
+)
+
+// Export writes the catalog to the file at path like GET /export does,
+// query holds the filters of GET /movies. The file is left out when the
+// export fails.
+func Export(ctx context.Context, c *config.Config, path, format string, query url.Values) error {
+	filter, err := httpMovies.ParseMoviesFilter(query)
+	if err != nil {
+		return err
+	}
+
+	catalogDB, err := catalogRepository.NewPostgres(c.FormatDbAddr(), dblog.New(c.SlowQueryThreshold))
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoder, err := httpCatalog.NewExportEncoder(format, file)
+	if err == nil {
+		err = catalogUsecase.NewCatalogUsecase(catalogDB).Export(ctx, filter, encoder)
+	}
+	if err == nil {
+		err = file.Close()
+	}
+	if err != nil {
+		os.Remove(path)
+		return err
+	}
+	return nil
+}
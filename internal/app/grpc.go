@@ -0,0 +1,72 @@
+package app
+
+import (
+	"net"
+
+	grpcActors "github.com/themilchenko/vk-tech_internship-problem_2024/internal/actors/delivery/grpc"
+	grpcAuth "github.com/themilchenko/vk-tech_internship-problem_2024/internal/auth/delivery/grpc"
+	authMiddleware "github.com/themilchenko/vk-tech_internship-problem_2024/internal/auth/delivery/middleware"
+	grpcMovies "github.com/themilchenko/vk-tech_internship-problem_2024/internal/movies/delivery/grpc"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/grpcstatus"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/tracing"
+	filmlibraryv1 "github.com/themilchenko/vk-tech_internship-problem_2024/pkg/api/filmlibrary/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+	reflectionv1 "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	reflectionv1alpha "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+)
+
+// grpcAccess is the gRPC side of makeRouter: who may call what. Methods
+// missing here need a session, like routes behind LoginRequired.
+var grpcAccess = map[string]authMiddleware.Access{
+	filmlibraryv1.AuthService_SignUp_FullMethodName:         authMiddleware.AccessPublic,
+	filmlibraryv1.AuthService_Login_FullMethodName:          authMiddleware.AccessPublic,
+	filmlibraryv1.AuthService_LoginTwoFactor_FullMethodName: authMiddleware.AccessPublic,
+
+	filmlibraryv1.MoviesService_CreateMovie_FullMethodName:          authMiddleware.AccessAdmin,
+	filmlibraryv1.MoviesService_UpdateMovie_FullMethodName:          authMiddleware.AccessAdmin,
+	filmlibraryv1.MoviesService_DeleteMovie_FullMethodName:          authMiddleware.AccessAdmin,
+	filmlibraryv1.MoviesService_AddActorToMovie_FullMethodName:      authMiddleware.AccessAdmin,
+	filmlibraryv1.MoviesService_RemoveActorFromMovie_FullMethodName: authMiddleware.AccessAdmin,
+	filmlibraryv1.MoviesService_ReplaceCast_FullMethodName:          authMiddleware.AccessAdmin,
+	filmlibraryv1.MoviesService_BatchCast_FullMethodName:            authMiddleware.AccessAdmin,
+
+	filmlibraryv1.ActorsService_CreateActor_FullMethodName: authMiddleware.AccessAdmin,
+	filmlibraryv1.ActorsService_UpdateActor_FullMethodName: authMiddleware.AccessAdmin,
+	filmlibraryv1.ActorsService_DeleteActor_FullMethodName: authMiddleware.AccessAdmin,
+
+	reflectionv1.ServerReflection_ServerReflectionInfo_FullMethodName:      authMiddleware.AccessPublic,
+	reflectionv1alpha.ServerReflection_ServerReflectionInfo_FullMethodName: authMiddleware.AccessPublic,
+}
+
+// StartGRPC serves gRPC on the address of the grpc settings.
+func (s *Server) StartGRPC() error {
+	if err := s.setUp(); err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", s.Config.GRPC.Address)
+	if err != nil {
+		return err
+	}
+	return s.GRPCServer.Serve(listener)
+}
+
+func (s *Server) makeGRPCServer() {
+	s.GRPCServer = grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			tracing.UnaryServerInterceptor,
+			grpcstatus.UnaryServerInterceptor,
+			s.authMiddleware.UnaryInterceptor(grpcAccess),
+		),
+		grpc.ChainStreamInterceptor(
+			grpcstatus.StreamServerInterceptor,
+			s.authMiddleware.StreamInterceptor(grpcAccess),
+		),
+	)
+
+	filmlibraryv1.RegisterAuthServiceServer(s.GRPCServer, grpcAuth.NewAuthServer(s.authUsecase))
+	filmlibraryv1.RegisterMoviesServiceServer(s.GRPCServer, grpcMovies.NewMoviesServer(s.moviesUsecase))
+	filmlibraryv1.RegisterActorsServiceServer(s.GRPCServer, grpcActors.NewActorsServer(s.actorsUsecase))
+	reflection.Register(s.GRPCServer)
+}
@@ -1,22 +1,53 @@
 package app
 
 import (
+	"context"
+	"log/slog"
 	"net/http"
+	"sync"
 
+	"github.com/themilchenko/vk-tech_internship-problem_2024/docs"
 	httpActors "github.com/themilchenko/vk-tech_internship-problem_2024/internal/actors/delivery"
 	actorsRepository "github.com/themilchenko/vk-tech_internship-problem_2024/internal/actors/repository"
 	actorsUsecase "github.com/themilchenko/vk-tech_internship-problem_2024/internal/actors/usecase"
+	httpAudit "github.com/themilchenko/vk-tech_internship-problem_2024/internal/audit/delivery"
+	auditRepository "github.com/themilchenko/vk-tech_internship-problem_2024/internal/audit/repository"
+	auditUsecase "github.com/themilchenko/vk-tech_internship-problem_2024/internal/audit/usecase"
 	httpAuth "github.com/themilchenko/vk-tech_internship-problem_2024/internal/auth/delivery"
 	authMiddleware "github.com/themilchenko/vk-tech_internship-problem_2024/internal/auth/delivery/middleware"
 	authRepository "github.com/themilchenko/vk-tech_internship-problem_2024/internal/auth/repository"
 	authUsecase "github.com/themilchenko/vk-tech_internship-problem_2024/internal/auth/usecase"
+	httpBatch "github.com/themilchenko/vk-tech_internship-problem_2024/internal/batch/delivery"
+	httpCatalog "github.com/themilchenko/vk-tech_internship-problem_2024/internal/catalog/delivery"
+	catalogRepository "github.com/themilchenko/vk-tech_internship-problem_2024/internal/catalog/repository"
+	catalogUsecase "github.com/themilchenko/vk-tech_internship-problem_2024/internal/catalog/usecase"
 	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/config"
 	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	httpGraphQL "github.com/themilchenko/vk-tech_internship-problem_2024/internal/graphql/delivery"
+	graphqlRepository "github.com/themilchenko/vk-tech_internship-problem_2024/internal/graphql/repository"
+	graphqlUsecase "github.com/themilchenko/vk-tech_internship-problem_2024/internal/graphql/usecase"
 	httpMovies "github.com/themilchenko/vk-tech_internship-problem_2024/internal/movies/delivery"
 	moviesRepository "github.com/themilchenko/vk-tech_internship-problem_2024/internal/movies/repository"
 	moviesUsecase "github.com/themilchenko/vk-tech_internship-problem_2024/internal/movies/usecase"
+	httpRevisions "github.com/themilchenko/vk-tech_internship-problem_2024/internal/revisions/delivery"
+	revisionsRepository "github.com/themilchenko/vk-tech_internship-problem_2024/internal/revisions/repository"
+	revisionsUsecase "github.com/themilchenko/vk-tech_internship-problem_2024/internal/revisions/usecase"
+	httpTrash "github.com/themilchenko/vk-tech_internship-problem_2024/internal/trash/delivery"
+	trashRepository "github.com/themilchenko/vk-tech_internship-problem_2024/internal/trash/repository"
+	trashUsecase "github.com/themilchenko/vk-tech_internship-problem_2024/internal/trash/usecase"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/cache"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/dblog"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/dbtx"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/etag"
 	password "github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/hash"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/metrics"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/notifier"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/openapi"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/requestctx"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/tracing"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/validate"
 	logger "github.com/themilchenko/vk-tech_internship-problem_2024/pkg"
+	"google.golang.org/grpc"
 )
 
 const (
@@ -24,17 +55,40 @@ const (
 )
 
 type Server struct {
-	Server *http.Server
-	Config *config.Config
-	Router *http.ServeMux
+	Server     *http.Server
+	GRPCServer *grpc.Server
+	Config     *config.Config
+	Router     *http.ServeMux
 
-	authUsecase   domain.AuthUsecase
-	actorsUsecase domain.ActorsUsecase
-	moviesUsecase domain.MoviesUsecase
+	// MetricsServer serves /metrics, nil when it is turned off.
+	MetricsServer *http.Server
 
-	authHandler   httpAuth.AuthHandler
-	actorsHandler httpActors.ActorsHandler
-	moviesHandler httpMovies.ActorsHandler
+	routes []string
+
+	initOnce sync.Once
+	initErr  error
+
+	authUsecase      domain.AuthUsecase
+	actorsUsecase    domain.ActorsUsecase
+	moviesUsecase    domain.MoviesUsecase
+	auditUsecase     domain.AuditUsecase
+	revisionsUsecase domain.RevisionsUsecase
+	trashUsecase     domain.TrashUsecase
+	catalogUsecase   domain.CatalogUsecase
+	graphqlUsecase   domain.GraphQLUsecase
+
+	cache      domain.Cache
+	transactor domain.Transactor
+
+	authHandler      httpAuth.AuthHandler
+	actorsHandler    httpActors.ActorsHandler
+	moviesHandler    httpMovies.ActorsHandler
+	auditHandler     httpAudit.AuditHandler
+	revisionsHandler httpRevisions.RevisionsHandler
+	trashHandler     httpTrash.TrashHandler
+	catalogHandler   httpCatalog.CatalogHandler
+	batchHandler     httpBatch.BatchHandler
+	graphqlHandler   httpGraphQL.GraphQLHandler
 
 	authMiddleware *authMiddleware.Middleware
 }
@@ -46,137 +100,453 @@ func NewServer(s *http.Server, c *config.Config) *Server {
 	}
 }
 
+// Start serves HTTP. It may run beside StartGRPC, the server is set up once
+// for both.
 func (s *Server) Start() error {
-	s.init()
+	if err := s.setUp(); err != nil {
+		return err
+	}
+	go s.purgeTrash()
 	return s.Server.ListenAndServe()
 }
 
+func (s *Server) setUp() error {
+	s.initOnce.Do(func() {
+		s.initErr = s.init()
+	})
+	return s.initErr
+}
+
 func (s *Server) init() error {
-	s.makeUsecases()
-	s.makeHandlers()
+	if err := s.makeUsecases(); err != nil {
+		return err
+	}
 	s.makeMiddlewares()
-	s.makeRouter()
+	s.makeHandlers()
+	if err := s.makeRouter(); err != nil {
+		return err
+	}
+	s.makeGRPCServer()
+	s.makeMetricsServer()
 
 	return nil
 }
 
-func (s *Server) makeRouter() {
+func (s *Server) makeRouter() error {
+	spec, err := openapi.Load(docs.Swagger)
+	if err != nil {
+		return err
+	}
+
 	s.Router = http.NewServeMux()
-	http.Handle("/", logger.Middleware(s.Router))
+	s.routes = nil
+
+	// The spec and its UI are beside the API, they aren't in the spec.
+	root := http.NewServeMux()
+	root.Handle(routed("GET "+baseURLPath+"/openapi.yaml", openapi.SpecHandler(docs.Swagger)))
+	root.Handle(routed("GET "+baseURLPath+"/docs/", openapi.UIHandler(baseURLPath+"/docs/", baseURLPath+"/openapi.yaml")))
+	root.Handle("/", validate.LimitBody(s.Config.MaxBodySize, map[string]int64{
+		"POST " + baseURLPath + "/import": s.Config.Catalog.ImportMaxBodySize,
+	})(spec.Middleware(openapi.Mode(s.Config.OpenAPI.Validation))(s.Router)))
+	s.Server.Handler = requestctx.Middleware(
+		tracing.Middleware(metrics.Middleware(logger.Middleware(root))),
+	)
+
+	ifMatch := etag.Required(s.Config.Concurrency.RequireIfMatch)
+	cacheControl := cache.Control(s.Config.Cache.MaxAge)
 
 	// authorization
-	s.Router.HandleFunc("GET "+baseURLPath+"/auth", s.authHandler.Auth)
-	s.Router.HandleFunc("POST "+baseURLPath+"/signup", s.authHandler.Signup)
-	s.Router.HandleFunc("POST "+baseURLPath+"/login", s.authHandler.Login)
-	s.Router.HandleFunc(
+	s.handle("GET "+baseURLPath+"/auth", s.authHandler.Auth)
+	s.handle("POST "+baseURLPath+"/signup", s.authHandler.Signup)
+	s.handle("POST "+baseURLPath+"/login", s.authHandler.Login)
+	s.handle("POST "+baseURLPath+"/login/2fa", s.authHandler.LoginTwoFactor)
+	s.handle(
 		"DELETE "+baseURLPath+"/logout",
 		s.authMiddleware.LoginRequired(s.authHandler.Logout),
 	)
+	s.handle(
+		"PUT "+baseURLPath+"/me/password",
+		s.authMiddleware.LoginRequired(s.authHandler.ChangePassword),
+	)
+	s.handle(
+		"POST "+baseURLPath+"/me/2fa/enroll",
+		s.authMiddleware.LoginRequired(s.authHandler.EnrollTOTP),
+	)
+	s.handle(
+		"POST "+baseURLPath+"/me/2fa/activate",
+		s.authMiddleware.LoginRequired(s.authHandler.ActivateTOTP),
+	)
+	s.handle(
+		"DELETE "+baseURLPath+"/me/2fa",
+		s.authMiddleware.LoginRequired(s.authHandler.DisableTOTP),
+	)
+	s.handle(
+		"POST "+baseURLPath+"/me/2fa/recovery-codes",
+		s.authMiddleware.LoginRequired(s.authHandler.RegenerateRecoveryCodes),
+	)
+	s.handle("GET "+baseURLPath+"/oidc/{provider}/login", s.authHandler.OIDCLogin)
+	s.handle("GET "+baseURLPath+"/oidc/{provider}/callback", s.authHandler.OIDCCallback)
+	s.handle(
+		"POST "+baseURLPath+"/me/oidc/{provider}/link",
+		s.authMiddleware.LoginRequired(s.authHandler.OIDCLink),
+	)
+	s.handle("POST "+baseURLPath+"/password/reset", s.authHandler.RequestPasswordReset)
+	s.handle(
+		"POST "+baseURLPath+"/password/reset/confirm",
+		s.authHandler.ConfirmPasswordReset,
+	)
+	s.handle(
+		"DELETE "+baseURLPath+"/users/{username}/lockout",
+		s.authMiddleware.LoginRequired(
+			s.authMiddleware.AccessRestriction(s.authHandler.UnlockUser),
+		),
+	)
+
+	// audit
+	s.handle(
+		"GET "+baseURLPath+"/audit",
+		s.authMiddleware.LoginRequired(
+			s.authMiddleware.AccessRestriction(s.auditHandler.GetEntries),
+		),
+	)
+
+	// catalog
+	s.handle(
+		"POST "+baseURLPath+"/import",
+		s.authMiddleware.LoginRequired(
+			s.authMiddleware.AccessRestriction(s.catalogHandler.Import),
+		),
+	)
+	s.handle(
+		"GET "+baseURLPath+"/export",
+		s.authMiddleware.LoginRequired(s.catalogHandler.Export),
+	)
+
+	// batch
+	s.handle(
+		"POST "+baseURLPath+"/batch",
+		s.authMiddleware.LoginRequired(s.batchHandler.Batch),
+	)
+
+	// graphql
+	s.handle(
+		"POST "+baseURLPath+"/graphql",
+		s.authMiddleware.LoginRequired(s.graphqlHandler.GraphQL),
+	)
+
+	// cache
+	s.handle(
+		"GET "+baseURLPath+"/cache/stats",
+		s.authMiddleware.LoginRequired(
+			s.authMiddleware.AccessRestriction(cache.StatsHandler(s.cache)),
+		),
+	)
+
+	// revisions
+	s.handle(
+		"GET "+baseURLPath+"/movies/{id}/revisions",
+		s.authMiddleware.LoginRequired(s.revisionsHandler.GetMovieRevisions),
+	)
+	s.handle(
+		"POST "+baseURLPath+"/movies/{id}/revisions/{number}/revert",
+		s.authMiddleware.LoginRequired(
+			s.authMiddleware.AccessRestriction(s.revisionsHandler.RevertMovie),
+		),
+	)
+	s.handle(
+		"GET "+baseURLPath+"/actors/{id}/revisions",
+		s.authMiddleware.LoginRequired(s.revisionsHandler.GetActorRevisions),
+	)
+	s.handle(
+		"POST "+baseURLPath+"/actors/{id}/revisions/{number}/revert",
+		s.authMiddleware.LoginRequired(
+			s.authMiddleware.AccessRestriction(s.revisionsHandler.RevertActor),
+		),
+	)
+	s.handle(
+		"GET "+baseURLPath+"/catalog",
+		s.authMiddleware.LoginRequired(s.revisionsHandler.GetCatalog),
+	)
+
+	// trash
+	s.handle(
+		"GET "+baseURLPath+"/trash",
+		s.authMiddleware.LoginRequired(
+			s.authMiddleware.AccessRestriction(s.trashHandler.GetTrash),
+		),
+	)
+	s.handle(
+		"POST "+baseURLPath+"/trash/{type}/{id}/restore",
+		s.authMiddleware.LoginRequired(
+			s.authMiddleware.AccessRestriction(s.trashHandler.Restore),
+		),
+	)
 
 	// actors
-	s.Router.HandleFunc(
+	s.handle(
 		"POST "+baseURLPath+"/actors",
 		s.authMiddleware.LoginRequired(
 			s.authMiddleware.AccessRestriction(s.actorsHandler.CreateActor),
 		),
 	)
-	s.Router.HandleFunc(
+	s.handle(
 		"GET "+baseURLPath+"/actors",
-		s.authMiddleware.LoginRequired(s.actorsHandler.GetActors),
+		s.authMiddleware.LoginRequired(cacheControl(s.actorsHandler.GetActors)),
 	)
-	s.Router.HandleFunc(
+	s.handle(
 		"PUT "+baseURLPath+"/actors/{id}",
 		s.authMiddleware.LoginRequired(
-			s.authMiddleware.AccessRestriction(s.actorsHandler.UpdateActor),
+			s.authMiddleware.AccessRestriction(ifMatch(s.actorsHandler.UpdateActor)),
+		),
+	)
+	s.handle(
+		"PATCH "+baseURLPath+"/actors/{id}",
+		s.authMiddleware.LoginRequired(
+			s.authMiddleware.AccessRestriction(ifMatch(s.actorsHandler.PatchActor)),
 		),
 	)
-	s.Router.HandleFunc(
+	s.handle(
 		"GET "+baseURLPath+"/actors/{id}",
-		s.authMiddleware.LoginRequired(s.actorsHandler.GetActor),
+		s.authMiddleware.LoginRequired(cacheControl(s.actorsHandler.GetActor)),
 	)
-	s.Router.HandleFunc(
+	s.handle(
 		"DELETE "+baseURLPath+"/actors/{id}",
 		s.authMiddleware.LoginRequired(
-			s.authMiddleware.AccessRestriction(s.actorsHandler.DeleteActor),
+			s.authMiddleware.AccessRestriction(ifMatch(s.actorsHandler.DeleteActor)),
 		),
 	)
 
 	// movies
-	s.Router.HandleFunc(
+	s.handle(
 		"POST "+baseURLPath+"/movies",
 		s.authMiddleware.LoginRequired(
 			s.authMiddleware.AccessRestriction(s.moviesHandler.CreateMovie),
 		),
 	)
-	s.Router.HandleFunc(
+	s.handle(
 		"GET "+baseURLPath+"/movies",
-		s.authMiddleware.LoginRequired(s.moviesHandler.GetMovies),
+		s.authMiddleware.LoginRequired(cacheControl(s.moviesHandler.GetMovies)),
 	)
-	s.Router.HandleFunc(
+	s.handle(
 		"PUT "+baseURLPath+"/movies/{id}",
 		s.authMiddleware.LoginRequired(
-			s.authMiddleware.AccessRestriction(s.moviesHandler.UpdateMovie),
+			s.authMiddleware.AccessRestriction(ifMatch(s.moviesHandler.UpdateMovie)),
+		),
+	)
+	s.handle(
+		"PATCH "+baseURLPath+"/movies/{id}",
+		s.authMiddleware.LoginRequired(
+			s.authMiddleware.AccessRestriction(ifMatch(s.moviesHandler.PatchMovie)),
 		),
 	)
-	s.Router.HandleFunc(
+	s.handle(
 		"DELETE "+baseURLPath+"/movies/{id}",
 		s.authMiddleware.LoginRequired(
-			s.authMiddleware.AccessRestriction(s.moviesHandler.DeleteMovie),
+			s.authMiddleware.AccessRestriction(ifMatch(s.moviesHandler.DeleteMovie)),
 		),
 	)
-	s.Router.HandleFunc(
+	s.handle(
 		"GET "+baseURLPath+"/movies/{id}",
-		s.authMiddleware.LoginRequired(s.moviesHandler.GetMovie),
+		s.authMiddleware.LoginRequired(cacheControl(s.moviesHandler.GetMovie)),
+	)
+	s.handle(
+		"PUT "+baseURLPath+"/movies/{id}/cast",
+		s.authMiddleware.LoginRequired(
+			s.authMiddleware.AccessRestriction(ifMatch(s.moviesHandler.ReplaceCast)),
+		),
 	)
-	s.Router.HandleFunc(
+	s.handle(
+		"POST "+baseURLPath+"/movies/{id}/cast:batch",
+		s.authMiddleware.LoginRequired(
+			s.authMiddleware.AccessRestriction(ifMatch(s.moviesHandler.BatchCast)),
+		),
+	)
+	s.handle(
 		"POST "+baseURLPath+"/movies/{movieID}/actors/{actorID}",
 		s.authMiddleware.LoginRequired(
 			s.authMiddleware.AccessRestriction(s.moviesHandler.AddActorToMovie),
 		),
 	)
-	s.Router.HandleFunc(
+	s.handle(
 		"DELETE "+baseURLPath+"/movies/{movieID}/actors/{actorID}",
 		s.authMiddleware.LoginRequired(
 			s.authMiddleware.AccessRestriction(s.moviesHandler.DeleteActorFromMoive),
 		),
 	)
+
+	return nil
+}
+
+// handle registers a route of the API, routes_test.go checks that the spec
+// has all of them. Logs of the request name the route.
+func (s *Server) handle(pattern string, handler http.HandlerFunc) {
+	s.Router.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		handler(w, r.WithContext(requestctx.WithRoute(r.Context(), pattern)))
+	})
+	s.routes = append(s.routes, pattern)
+}
+
+// routed is handle for the routes beside the API.
+func routed(pattern string, handler http.Handler) (string, http.Handler) {
+	return pattern, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.ServeHTTP(w, r.WithContext(requestctx.WithRoute(r.Context(), pattern)))
+	})
 }
 
 func (s *Server) makeHandlers() {
 	s.authHandler = httpAuth.NewAuthHandler(s.authUsecase, s.Config.CookieSettings)
 	s.actorsHandler = httpActors.NewActorsUsecase(s.actorsUsecase)
 	s.moviesHandler = httpMovies.NewActorsUsecase(s.moviesUsecase)
+	s.auditHandler = httpAudit.NewAuditHandler(s.auditUsecase)
+	s.revisionsHandler = httpRevisions.NewRevisionsHandler(s.revisionsUsecase)
+	s.trashHandler = httpTrash.NewTrashHandler(s.trashUsecase)
+	s.catalogHandler = httpCatalog.NewCatalogHandler(
+		s.catalogUsecase,
+		int(s.Config.Catalog.ImportMaxBodySize),
+	)
+	s.graphqlHandler = httpGraphQL.NewGraphQLHandler(
+		s.graphqlUsecase,
+		s.moviesUsecase,
+		s.actorsUsecase,
+		s.authUsecase,
+		s.authMiddleware.Admin,
+		int(s.Config.PageSize),
+	)
+	s.batchHandler = httpBatch.NewBatchHandler(
+		http.HandlerFunc(s.serveBatchItem),
+		s.transactor,
+		s.Config.Batch.MaxItems,
+	)
+}
+
+// serveBatchItem routes a request of a batch. The router is made after the
+// handlers, so it is looked up on every call.
+func (s *Server) serveBatchItem(w http.ResponseWriter, r *http.Request) {
+	s.Router.ServeHTTP(w, r)
 }
 
 func (s *Server) makeUsecases() error {
 	pgParams := s.Config.FormatDbAddr()
+	dbLogger := dblog.New(s.Config.SlowQueryThreshold)
+
+	authDB, err := authRepository.NewPostgres(pgParams, dbLogger)
+	if err != nil {
+		return err
+	}
+
+	actorsDB, err := actorsRepository.NewPostgres(pgParams, s.Config.PageSize, dbLogger)
+	if err != nil {
+		return err
+	}
+
+	moviesDB, err := moviesRepository.NewPostgres(pgParams, dbLogger)
+	if err != nil {
+		return err
+	}
+
+	auditDB, err := auditRepository.NewPostgres(pgParams, s.Config.PageSize, dbLogger)
+	if err != nil {
+		return err
+	}
+
+	revisionsDB, err := revisionsRepository.NewPostgres(pgParams, dbLogger)
+	if err != nil {
+		return err
+	}
 
-	authDB, err := authRepository.NewPostgres(pgParams)
+	trashDB, err := trashRepository.NewPostgres(pgParams, s.Config.PageSize, dbLogger)
 	if err != nil {
 		return err
 	}
 
-	actorsDB, err := actorsRepository.NewPostgres(pgParams, s.Config.PageSize)
+	catalogDB, err := catalogRepository.NewPostgres(pgParams, dbLogger)
 	if err != nil {
 		return err
 	}
 
-	moviesDB, err := moviesRepository.NewPostgres(pgParams)
+	graphqlDB, err := graphqlRepository.NewPostgres(pgParams, dbLogger)
+	if err != nil {
+		return err
+	}
+
+	metrics.Sessions(authDB.CountActiveSessions)
+
+	transactor, err := dbtx.NewPostgres(pgParams)
+	if err != nil {
+		return err
+	}
+
+	policy, err := password.NewPolicy(s.Config.Credentials)
 	if err != nil {
 		return err
 	}
 
 	s.authUsecase = authUsecase.NewAuthUsecase(
 		authDB,
+		notifier.NewOutbox(s.Config.PasswordReset.OutboxPath),
+		s.makeIdentityProviders(),
 		s.Config.CookieSettings,
-		password.HashPassword,
+		s.Config.PasswordReset,
+		s.Config.LoginGuard,
+		s.Config.TwoFactor,
+		s.Config.OIDC,
+		policy,
+		policy.HashPassword,
 	)
 	s.actorsUsecase = actorsUsecase.NewActorsUsecase(actorsDB, moviesDB)
 	s.moviesUsecase = moviesUsecase.NewMoviesUsecase(moviesDB, actorsDB)
+	s.auditUsecase = auditUsecase.NewAuditUsecase(auditDB)
+	s.revisionsUsecase = revisionsUsecase.NewRevisionsUsecase(revisionsDB, moviesDB, actorsDB)
+	s.trashUsecase = trashUsecase.NewTrashUsecase(trashDB, s.Config.Trash)
+	s.catalogUsecase = catalogUsecase.NewCatalogUsecase(catalogDB)
+	s.graphqlUsecase = graphqlUsecase.NewGraphQLUsecase(graphqlDB)
+	s.transactor = transactor
+
+	s.cache = cache.NewLRU(s.Config.Cache.Size, s.Config.Cache.TTL)
+	if s.Config.Cache.Size > 0 {
+		s.actorsUsecase = actorsUsecase.NewCachedActorsUsecase(s.actorsUsecase, s.cache)
+		s.moviesUsecase = moviesUsecase.NewCachedMoviesUsecase(s.moviesUsecase, s.cache)
+		s.revisionsUsecase = revisionsUsecase.NewCachedRevisionsUsecase(s.revisionsUsecase, s.cache)
+		s.trashUsecase = trashUsecase.NewCachedTrashUsecase(s.trashUsecase, s.cache)
+		s.catalogUsecase = catalogUsecase.NewCachedCatalogUsecase(s.catalogUsecase, s.cache)
+	}
+
+	// Spans wrap the cache, a hit is a span without statements.
+	s.authUsecase = authUsecase.NewTracedAuthUsecase(s.authUsecase)
+	s.actorsUsecase = actorsUsecase.NewTracedActorsUsecase(s.actorsUsecase)
+	s.moviesUsecase = moviesUsecase.NewTracedMoviesUsecase(s.moviesUsecase)
+	s.auditUsecase = auditUsecase.NewTracedAuditUsecase(s.auditUsecase)
+	s.revisionsUsecase = revisionsUsecase.NewTracedRevisionsUsecase(s.revisionsUsecase)
+	s.trashUsecase = trashUsecase.NewTracedTrashUsecase(s.trashUsecase)
+	s.catalogUsecase = catalogUsecase.NewTracedCatalogUsecase(s.catalogUsecase)
+	s.graphqlUsecase = graphqlUsecase.NewTracedGraphQLUsecase(s.graphqlUsecase)
 
 	return nil
 }
 
+// makeIdentityProviders runs discovery for every configured provider. A
+// provider that is down at startup is skipped, so it can't take the whole
+// API with it.
+func (s *Server) makeIdentityProviders() map[string]domain.IdentityProvider {
+	providers := make(map[string]domain.IdentityProvider)
+	for _, p := range s.Config.OIDC.Providers {
+		provider, err := authRepository.NewOIDCProvider(
+			context.Background(),
+			p,
+			&http.Client{Transport: tracing.Transport(nil)},
+		)
+		if err != nil {
+			slog.Warn("oidc provider is disabled", "provider", p.Name, "error", err)
+			continue
+		}
+		providers[p.Name] = provider
+	}
+	return providers
+}
+
 func (s *Server) makeMiddlewares() {
-	s.authMiddleware = authMiddleware.NewMiddleware(s.authUsecase)
+	s.authMiddleware = authMiddleware.NewMiddleware(
+		s.authUsecase,
+		s.Config.TwoFactor.RequireForAdmins,
+	)
 }
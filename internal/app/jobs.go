@@ -0,0 +1,37 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// purgeTrash empties the trash of everything older than the retention
+// period until the server shuts down.
+func (s *Server) purgeTrash() {
+	settings := s.Config.Trash
+	if settings.PurgeAfter <= 0 || settings.PurgeInterval <= 0 {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.Server.RegisterOnShutdown(cancel)
+
+	ticker := time.NewTicker(settings.PurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := s.trashUsecase.Purge(ctx)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to purge trash", "error", err)
+		} else if purged > 0 {
+			slog.InfoContext(ctx, "purged trash", "items", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
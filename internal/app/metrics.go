@@ -0,0 +1,39 @@
+package app
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/metrics"
+)
+
+// StartMetrics serves /metrics on the address of the metrics settings,
+// apart from the API. It returns right away when the address is empty.
+func (s *Server) StartMetrics() error {
+	if err := s.setUp(); err != nil {
+		return err
+	}
+	if s.MetricsServer == nil {
+		return nil
+	}
+
+	err := s.MetricsServer.ListenAndServe()
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+func (s *Server) makeMetricsServer() {
+	if s.Config.Metrics.Address == "" {
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", metrics.Handler())
+	s.MetricsServer = &http.Server{
+		Addr:        s.Config.Metrics.Address,
+		Handler:     mux,
+		ReadTimeout: s.Config.Server.Timeout,
+	}
+}
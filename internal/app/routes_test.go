@@ -0,0 +1,30 @@
+package app
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/docs"
+	authMiddleware "github.com/themilchenko/vk-tech_internship-problem_2024/internal/auth/delivery/middleware"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/config"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/openapi"
+)
+
+func TestApp_routesInSpec(t *testing.T) {
+	s := &Server{
+		Server:         &http.Server{},
+		Config:         config.NewConfig(),
+		authMiddleware: authMiddleware.NewMiddleware(nil, false),
+	}
+	require.NoError(t, s.makeRouter())
+
+	spec, err := openapi.Load(docs.Swagger)
+	require.NoError(t, err)
+
+	require.NotEmpty(t, s.routes)
+	for _, route := range s.routes {
+		assert.True(t, spec.HasOperation(route), "%s is missing in docs/swagger.yml", route)
+	}
+}
@@ -0,0 +1,85 @@
+package httpAudit
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/problem"
+)
+
+type AuditHandler struct {
+	auditUsecase domain.AuditUsecase
+}
+
+func NewAuditHandler(a domain.AuditUsecase) AuditHandler {
+	return AuditHandler{
+		auditUsecase: a,
+	}
+}
+
+func (h AuditHandler) GetEntries(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseFilter(r)
+	if err != nil {
+		problem.Write(w, r, domain.ErrBadRequest.Wrap(err))
+		return
+	}
+
+	entries, err := h.auditUsecase.GetEntries(r.Context(), filter)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	responseData, err := json.Marshal(entries)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(responseData)
+}
+
+func parseFilter(r *http.Request) (httpModels.AuditFilter, error) {
+	query := r.URL.Query()
+	filter := httpModels.AuditFilter{Entity: query.Get("entity")}
+
+	var err error
+	ids := []struct {
+		name  string
+		value *uint64
+	}{
+		{"entityId", &filter.EntityID},
+		{"userId", &filter.UserID},
+		{"page", &filter.Page},
+	}
+	for _, id := range ids {
+		if v := query.Get(id.name); v != "" {
+			if *id.value, err = strconv.ParseUint(v, 10, 64); err != nil {
+				return httpModels.AuditFilter{}, err
+			}
+		}
+	}
+
+	times := []struct {
+		name  string
+		value *time.Time
+	}{
+		{"from", &filter.From},
+		{"to", &filter.To},
+	}
+	for _, t := range times {
+		if v := query.Get(t.name); v != "" {
+			if *t.value, err = time.Parse(time.RFC3339, v); err != nil {
+				return httpModels.AuditFilter{}, err
+			}
+		}
+	}
+
+	return filter, nil
+}
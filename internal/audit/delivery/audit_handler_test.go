@@ -0,0 +1,136 @@
+package httpAudit
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/docs"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	mockDomain "github.com/themilchenko/vk-tech_internship-problem_2024/internal/mocks/domain"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/openapi"
+	"go.uber.org/mock/gomock"
+)
+
+var loadSpec = sync.OnceValues(func() (*openapi.Spec, error) {
+	return openapi.Load(docs.Swagger)
+})
+
+// strict checks the handlers behind mux against the spec, as the API is
+// checked in tests.
+func strict(t *testing.T, mux http.Handler) http.Handler {
+	spec, err := loadSpec()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return spec.Strict(mux)
+}
+
+func TestHandler_GetEntries(t *testing.T) {
+	type mockBehavior func(r *mockDomain.MockAuditUsecase, filter httpModels.AuditFilter)
+
+	tests := []struct {
+		name                 string
+		query                string
+		filter               httpModels.AuditFilter
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:  "All filters",
+			query: "?entity=movie&entityId=3&userId=1&from=2024-03-01T00:00:00Z&to=2024-03-02T00:00:00Z&page=2",
+			filter: httpModels.AuditFilter{
+				Entity:   "movie",
+				EntityID: 3,
+				UserID:   1,
+				From:     time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+				To:       time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC),
+				Page:     2,
+			},
+			mockBehavior: func(m *mockDomain.MockAuditUsecase, filter httpModels.AuditFilter) {
+				m.EXPECT().
+					GetEntries(gomock.Any(), filter).
+					Return([]httpModels.AuditEntry{{
+						ID:        7,
+						CreatedAt: "2024-03-01T10:00:00Z",
+						UserID:    1,
+						RequestID: "req-1",
+						Entity:    "movie",
+						EntityID:  3,
+						Action:    "update",
+						Diff:      []byte(`{"rating":{"after":9,"before":8}}`),
+					}}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `[{"id":7,"createdAt":"2024-03-01T10:00:00Z","userId":1,"requestId":"req-1","entity":"movie","entityId":3,"action":"update","diff":{"rating":{"after":9,"before":8}}}]`,
+		},
+		{
+			name:   "No filters",
+			filter: httpModels.AuditFilter{},
+			mockBehavior: func(m *mockDomain.MockAuditUsecase, filter httpModels.AuditFilter) {
+				m.EXPECT().GetEntries(gomock.Any(), filter).Return([]httpModels.AuditEntry{}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `[]`,
+		},
+		{
+			name:                 "Bad time",
+			query:                "?from=yesterday",
+			mockBehavior:         func(m *mockDomain.MockAuditUsecase, filter httpModels.AuditFilter) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"bad request: parsing time \"yesterday\" as \"2006-01-02T15:04:05Z07:00\": cannot parse \"yesterday\" as \"2006\"","code":"bad_request"}`,
+		},
+		{
+			name:  "Reversed range",
+			query: "?from=2024-03-02T00:00:00Z&to=2024-03-01T00:00:00Z",
+			filter: httpModels.AuditFilter{
+				From: time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC),
+				To:   time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			},
+			mockBehavior: func(m *mockDomain.MockAuditUsecase, filter httpModels.AuditFilter) {
+				m.EXPECT().GetEntries(gomock.Any(), filter).Return(nil, domain.ErrValidation)
+			},
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"` + domain.ErrValidation.Error() + `","code":"validation_failed"}`,
+		},
+		{
+			name: "Internal server error",
+			mockBehavior: func(m *mockDomain.MockAuditUsecase, filter httpModels.AuditFilter) {
+				m.EXPECT().GetEntries(gomock.Any(), filter).Return(nil, errors.New("db is down"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"server error","code":"internal_error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cntx := gomock.NewController(t)
+			defer cntx.Finish()
+
+			mockAuditUsecase := mockDomain.NewMockAuditUsecase(cntx)
+
+			tt.mockBehavior(mockAuditUsecase, tt.filter)
+
+			handler := NewAuditHandler(mockAuditUsecase)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /audit", handler.GetEntries)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/audit"+tt.query, nil)
+
+			strict(t, mux).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, strings.Trim(w.Body.String(), "\n"))
+		})
+	}
+}
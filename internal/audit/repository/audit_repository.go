@@ -0,0 +1,87 @@
+package auditRepository
+
+import (
+	"context"
+
+	gormModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/gorm"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/dbtx"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/metrics"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/pgerrors"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/sqlcomment"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/tracing"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+)
+
+type Postgres struct {
+	DB *gorm.DB
+
+	pageSize uint64
+}
+
+func NewPostgres(url string, ps uint64, logger gormLogger.Interface) (*Postgres, error) {
+	db, err := gorm.Open(postgres.Open(url), &gorm.Config{Logger: logger})
+	if err != nil {
+		return nil, err
+	}
+	if err = db.Use(pgerrors.Plugin{}); err != nil {
+		return nil, err
+	}
+	if err = db.Use(dbtx.Plugin{}); err != nil {
+		return nil, err
+	}
+	if err = db.Use(sqlcomment.Plugin{}); err != nil {
+		return nil, err
+	}
+	if err = db.Use(metrics.Plugin{DB: "audit"}); err != nil {
+		return nil, err
+	}
+	if err = db.Use(tracing.Plugin{DB: "audit"}); err != nil {
+		return nil, err
+	}
+
+	db.AutoMigrate(
+		gormModels.AuditEntry{},
+	)
+
+	return &Postgres{
+		DB:       db,
+		pageSize: ps,
+	}, nil
+}
+
+func (db Postgres) GetEntries(
+	ctx context.Context,
+	filter httpModels.AuditFilter,
+) ([]gormModels.AuditEntry, error) {
+	query := db.DB.WithContext(ctx).Model(&gormModels.AuditEntry{})
+
+	if filter.Entity != "" {
+		query = query.Where("entity = ?", filter.Entity)
+	}
+	if filter.EntityID != 0 {
+		query = query.Where("entity_id = ?", filter.EntityID)
+	}
+	if filter.UserID != 0 {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_at < ?", filter.To)
+	}
+
+	offset := db.pageSize * (filter.Page - 1)
+	var recievedEntries []gormModels.AuditEntry
+	if err := query.Order("id DESC").
+		Offset(int(offset)).
+		Limit(int(db.pageSize)).
+		Find(&recievedEntries).
+		Error; err != nil {
+		return nil, err
+	}
+	return recievedEntries, nil
+}
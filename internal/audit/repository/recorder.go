@@ -0,0 +1,186 @@
+package auditRepository
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sync"
+	"time"
+
+	gormModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/gorm"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/requestctx"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+const (
+	ActionCreate  = "create"
+	ActionUpdate  = "update"
+	ActionDelete  = "delete"
+	ActionRestore = "restore"
+
+	redacted = "[redacted]"
+)
+
+// Bookkeeping columns change on every write and would only add noise.
+var ignoredColumns = map[string]struct{}{
+	"id":             {},
+	"created_at":     {},
+	"updated_at":     {},
+	"deleted_at":     {},
+	"totp_last_step": {},
+	"version":        {},
+}
+
+// Secrets are never written to the log, only the fact that they changed.
+var redactedColumns = map[string]struct{}{
+	"password":    {},
+	"totp_secret": {},
+}
+
+var schemaCache = &sync.Map{}
+
+type change map[string]interface{}
+
+// Record writes an audit entry for a change of one entity. It has to be
+// called with the transaction of the change, so both are committed or rolled
+// back together. A nil before means the entity was created, a nil after that
+// it was deleted. Updates that don't change anything are not recorded.
+func Record(
+	ctx context.Context,
+	tx *gorm.DB,
+	entity string,
+	entityID uint64,
+	before, after interface{},
+) error {
+	action := ActionUpdate
+	switch {
+	case before == nil:
+		action = ActionCreate
+	case after == nil:
+		action = ActionDelete
+	}
+	return record(ctx, tx, action, entity, entityID, before, after)
+}
+
+// RecordRestore writes an audit entry for an entity that was brought back
+// from the trash.
+func RecordRestore(
+	ctx context.Context,
+	tx *gorm.DB,
+	entity string,
+	entityID uint64,
+	after interface{},
+) error {
+	return record(ctx, tx, ActionRestore, entity, entityID, nil, after)
+}
+
+func record(
+	ctx context.Context,
+	tx *gorm.DB,
+	action, entity string,
+	entityID uint64,
+	before, after interface{},
+) error {
+	diff, err := makeDiff(ctx, tx.NamingStrategy, before, after)
+	if err != nil {
+		return err
+	}
+	if len(diff) == 0 && action == ActionUpdate {
+		return nil
+	}
+
+	encodedDiff, err := json.Marshal(diff)
+	if err != nil {
+		return err
+	}
+
+	return tx.Session(&gorm.Session{NewDB: true}).Create(&gormModels.AuditEntry{
+		UserID:    requestctx.UserID(ctx),
+		RequestID: requestctx.RequestID(ctx),
+		Entity:    entity,
+		EntityID:  entityID,
+		Action:    action,
+		Diff:      string(encodedDiff),
+	}).Error
+}
+
+func makeDiff(
+	ctx context.Context,
+	namer schema.Namer,
+	before, after interface{},
+) (map[string]change, error) {
+	beforeColumns, err := columns(ctx, namer, before)
+	if err != nil {
+		return nil, err
+	}
+	afterColumns, err := columns(ctx, namer, after)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := make(map[string]change)
+	for name, value := range beforeColumns {
+		newValue, ok := afterColumns[name]
+		if ok && equal(value, newValue) {
+			continue
+		}
+
+		c := change{"before": value}
+		if ok {
+			c["after"] = newValue
+		}
+		diff[name] = c
+	}
+	for name, value := range afterColumns {
+		if _, ok := beforeColumns[name]; !ok {
+			diff[name] = change{"after": value}
+		}
+	}
+
+	for name, c := range diff {
+		if _, ok := redactedColumns[name]; !ok {
+			continue
+		}
+		for k := range c {
+			c[k] = redacted
+		}
+	}
+
+	return diff, nil
+}
+
+func columns(
+	ctx context.Context,
+	namer schema.Namer,
+	model interface{},
+) (map[string]interface{}, error) {
+	if model == nil {
+		return nil, nil
+	}
+
+	s, err := schema.Parse(model, schemaCache, namer)
+	if err != nil {
+		return nil, err
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(model))
+	values := make(map[string]interface{}, len(s.DBNames))
+	for _, name := range s.DBNames {
+		if _, ok := ignoredColumns[name]; ok {
+			continue
+		}
+		v, _ := s.FieldsByDBName[name].ValueOf(ctx, value)
+		values[name] = v
+	}
+	return values, nil
+}
+
+func equal(a, b interface{}) bool {
+	if t, ok := a.(time.Time); ok {
+		if u, ok := b.(time.Time); ok {
+			return t.Equal(u)
+		}
+	}
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,73 @@
+package auditRepository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	gormModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/gorm"
+	"gorm.io/gorm/schema"
+)
+
+func TestRecorder_MakeDiff(t *testing.T) {
+	releaseDate := time.Date(2006, 1, 2, 0, 0, 0, 0, time.UTC)
+	movie := gormModels.Movie{
+		ID:          1,
+		Title:       "Title",
+		Description: "Description",
+		ReleaseDate: releaseDate,
+		Rating:      8,
+	}
+	updatedMovie := movie
+	updatedMovie.Rating = 0
+	updatedMovie.ReleaseDate = releaseDate.In(time.FixedZone("MSK", 3*60*60))
+	updatedMovie.UpdatedAt = time.Now()
+
+	tests := []struct {
+		name         string
+		before       interface{}
+		after        interface{}
+		expectedDiff map[string]change
+	}{
+		{
+			name:   "Create",
+			before: nil,
+			after:  gormModels.ActorMovieRelation{MovieID: 1, ActorID: 2},
+			expectedDiff: map[string]change{
+				"movie_id": {"after": uint64(1)},
+				"actor_id": {"after": uint64(2)},
+			},
+		},
+		{
+			name:   "Update keeps only changed columns",
+			before: movie,
+			after:  updatedMovie,
+			expectedDiff: map[string]change{
+				"rating": {"before": float32(8), "after": float32(0)},
+			},
+		},
+		{
+			name:         "Nothing changed",
+			before:       movie,
+			after:        movie,
+			expectedDiff: map[string]change{},
+		},
+		{
+			name:   "Secrets are redacted",
+			before: gormModels.User{ID: 1, Username: "Jane", Password: "old-hash"},
+			after:  gormModels.User{ID: 1, Username: "Jane", Password: "new-hash", TOTPLastStep: 5},
+			expectedDiff: map[string]change{
+				"password": {"before": redacted, "after": redacted},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff, err := makeDiff(context.Background(), schema.NamingStrategy{}, tt.before, tt.after)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedDiff, diff)
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package auditUsecase
+
+import (
+	"context"
+
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+)
+
+const defaultPage = 1
+
+type AuditUsecase struct {
+	auditRepository domain.AuditRepository
+}
+
+func NewAuditUsecase(a domain.AuditRepository) AuditUsecase {
+	return AuditUsecase{
+		auditRepository: a,
+	}
+}
+
+func (u AuditUsecase) GetEntries(
+	ctx context.Context,
+	filter httpModels.AuditFilter,
+) ([]httpModels.AuditEntry, error) {
+	if filter.Page == 0 {
+		filter.Page = defaultPage
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && filter.To.Before(filter.From) {
+		return nil, domain.ErrValidation
+	}
+
+	entries, err := u.auditRepository.GetEntries(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	httpEntries := make([]httpModels.AuditEntry, len(entries))
+	for i, e := range entries {
+		httpEntries[i] = e.ToHTTPModel()
+	}
+	return httpEntries, nil
+}
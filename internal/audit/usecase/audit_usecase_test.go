@@ -0,0 +1,83 @@
+package auditUsecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	mockDomain "github.com/themilchenko/vk-tech_internship-problem_2024/internal/mocks/domain"
+	gormModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/gorm"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUsecase_GetEntries(t *testing.T) {
+	type mockBehavior func(r *mockDomain.MockAuditRepository, filter httpModels.AuditFilter)
+
+	createdAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		inputFilter     httpModels.AuditFilter
+		mockBehavior    mockBehavior
+		expectedEntries []httpModels.AuditEntry
+		expectedError   error
+	}{
+		{
+			name:        "First page by default",
+			inputFilter: httpModels.AuditFilter{Entity: "actor"},
+			mockBehavior: func(m *mockDomain.MockAuditRepository, filter httpModels.AuditFilter) {
+				filter.Page = 1
+				m.EXPECT().
+					GetEntries(gomock.Any(), filter).
+					Return([]gormModels.AuditEntry{{
+						ID:        1,
+						CreatedAt: createdAt,
+						UserID:    2,
+						Entity:    "actor",
+						EntityID:  3,
+						Action:    "delete",
+						Diff:      `{"name":{"before":"John"}}`,
+					}}, nil)
+			},
+			expectedEntries: []httpModels.AuditEntry{{
+				ID:        1,
+				CreatedAt: "2024-03-01T10:00:00Z",
+				UserID:    2,
+				Entity:    "actor",
+				EntityID:  3,
+				Action:    "delete",
+				Diff:      []byte(`{"name":{"before":"John"}}`),
+			}},
+			expectedError: nil,
+		},
+		{
+			name: "Reversed time range",
+			inputFilter: httpModels.AuditFilter{
+				From: createdAt,
+				To:   createdAt.Add(-time.Hour),
+			},
+			mockBehavior:    func(m *mockDomain.MockAuditRepository, filter httpModels.AuditFilter) {},
+			expectedEntries: nil,
+			expectedError:   domain.ErrValidation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mockDomain.NewMockAuditRepository(ctrl)
+			tt.mockBehavior(mockRepo, tt.inputFilter)
+
+			u := NewAuditUsecase(mockRepo)
+
+			entries, err := u.GetEntries(context.Background(), tt.inputFilter)
+			assert.Equal(t, tt.expectedEntries, entries)
+			assert.ErrorIs(t, err, tt.expectedError)
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package auditUsecase
+
+import (
+	"context"
+
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/tracing"
+)
+
+// TracedAuditUsecase makes a span of reads of the audit log.
+type TracedAuditUsecase struct {
+	auditUsecase domain.AuditUsecase
+}
+
+func NewTracedAuditUsecase(u domain.AuditUsecase) TracedAuditUsecase {
+	return TracedAuditUsecase{auditUsecase: u}
+}
+
+func (u TracedAuditUsecase) GetEntries(
+	ctx context.Context,
+	filter httpModels.AuditFilter,
+) (_ []httpModels.AuditEntry, err error) {
+	ctx, span := tracing.Start(ctx, "AuditUsecase.GetEntries")
+	defer func() { tracing.End(span, err) }()
+	return u.auditUsecase.GetEntries(ctx, filter)
+}
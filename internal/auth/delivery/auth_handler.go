@@ -9,7 +9,8 @@ import (
 	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/config"
 	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
 	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
-	"github.com/themilchenko/vk-tech_internship-problem_2024/pkg"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/problem"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/validate"
 )
 
 type AuthHandler struct {
@@ -28,23 +29,19 @@ func NewAuthHandler(a domain.AuthUsecase, c config.CookieSettings) AuthHandler {
 func (h AuthHandler) Auth(w http.ResponseWriter, r *http.Request) {
 	cookie, err := r.Cookie(CookieName)
 	if err != nil {
-		pkg.HandleError(w, err.Error(), http.StatusUnauthorized)
+		problem.Write(w, r, domain.ErrNoSession)
 		return
 	}
 
-	userID, err := h.authUsecase.Auth(cookie.Value)
+	userID, err := h.authUsecase.Auth(r.Context(), cookie.Value)
 	if err != nil {
-		if errors.Is(err, domain.ErrNotFound) {
-			pkg.HandleError(w, err.Error(), http.StatusNotFound)
-		} else {
-			pkg.HandleError(w, err.Error(), http.StatusUnauthorized)
-		}
+		problem.Write(w, r, err)
 		return
 	}
 
 	responseData, err := json.Marshal(httpModels.ID{ID: userID})
 	if err != nil {
-		pkg.HandleError(w, err.Error(), http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -55,18 +52,19 @@ func (h AuthHandler) Auth(w http.ResponseWriter, r *http.Request) {
 
 func (h AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	var authUser httpModels.AuthUser
-	err := json.NewDecoder(r.Body).Decode(&authUser)
+	err := validate.DecodeJSON(r, &authUser)
 	if err != nil {
-		pkg.HandleError(w, err.Error(), http.StatusBadRequest)
+		problem.Write(w, r, err)
 		return
 	}
 
-	session, userID, err := h.authUsecase.Login(authUser)
+	session, userID, err := h.authUsecase.Login(r.Context(), authUser, clientIP(r))
 	if err != nil {
-		if errors.Is(err, domain.ErrNotFound) {
-			pkg.HandleError(w, err.Error(), http.StatusNotFound)
+		var twoFactorErr domain.TwoFactorRequiredError
+		if errors.As(err, &twoFactorErr) {
+			h.writeTwoFactorChallenge(w, r, twoFactorErr.Challenge)
 		} else {
-			pkg.HandleError(w, err.Error(), http.StatusInternalServerError)
+			problem.Write(w, r, err)
 		}
 		return
 	}
@@ -76,7 +74,7 @@ func (h AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 
 	responseData, err := json.Marshal(httpModels.ID{ID: userID})
 	if err != nil {
-		pkg.HandleError(w, err.Error(), http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -88,12 +86,12 @@ func (h AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 func (h AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 	cookie, err := r.Cookie(CookieName)
 	if err != nil {
-		pkg.HandleError(w, err.Error(), http.StatusUnauthorized)
+		problem.Write(w, r, domain.ErrNoSession)
 		return
 	}
 
-	if err = h.authUsecase.Logout(cookie.Value); err != nil {
-		pkg.HandleError(w, err.Error(), http.StatusUnauthorized)
+	if err = h.authUsecase.Logout(r.Context(), cookie.Value); err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -102,6 +100,9 @@ func (h AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 		httpModels.DeleteExpire["month"],
 		httpModels.DeleteExpire["day"],
 	)
+	// The path of the cookie as it was set, a request cookie has none and
+	// clients would keep the session otherwise.
+	cookie.Path = "/"
 	http.SetCookie(w, cookie)
 
 	w.Header().Set("Content-Type", "application/json")
@@ -111,18 +112,14 @@ func (h AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 
 func (h AuthHandler) Signup(w http.ResponseWriter, r *http.Request) {
 	var receivedUser httpModels.AuthUser
-	if err := json.NewDecoder(r.Body).Decode(&receivedUser); err != nil {
-		pkg.HandleError(w, err.Error(), http.StatusBadRequest)
+	if err := validate.DecodeJSON(r, &receivedUser); err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
-	sessionID, userID, err := h.authUsecase.SignUp(receivedUser)
+	sessionID, userID, err := h.authUsecase.SignUp(r.Context(), receivedUser)
 	if err != nil {
-		if errors.Is(err, domain.ErrUserAlreadyExist) {
-			pkg.HandleError(w, err.Error(), http.StatusConflict)
-		} else {
-			pkg.HandleError(w, err.Error(), http.StatusInternalServerError)
-		}
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -131,7 +128,232 @@ func (h AuthHandler) Signup(w http.ResponseWriter, r *http.Request) {
 
 	responseData, err := json.Marshal(httpModels.ID{ID: userID})
 	if err != nil {
-		pkg.HandleError(w, err.Error(), http.StatusInternalServerError)
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(responseData)
+}
+
+func (h AuthHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	cookie, err := r.Cookie(CookieName)
+	if err != nil {
+		problem.Write(w, r, domain.ErrNoSession)
+		return
+	}
+
+	var passwords httpModels.ChangePassword
+	if err = validate.DecodeJSON(r, &passwords); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	if err = h.authUsecase.ChangePassword(r.Context(), cookie.Value, passwords); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(httpModels.EmptyModel)
+}
+
+func (h AuthHandler) RequestPasswordReset(w http.ResponseWriter, r *http.Request) {
+	var resetRequest httpModels.PasswordResetRequest
+	if err := validate.DecodeJSON(r, &resetRequest); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	if err := h.authUsecase.RequestPasswordReset(r.Context(), resetRequest.Username); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(httpModels.EmptyModel)
+}
+
+func (h AuthHandler) ConfirmPasswordReset(w http.ResponseWriter, r *http.Request) {
+	var confirm httpModels.PasswordResetConfirm
+	if err := validate.DecodeJSON(r, &confirm); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	if err := h.authUsecase.ConfirmPasswordReset(r.Context(), confirm); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(httpModels.EmptyModel)
+}
+
+func (h AuthHandler) UnlockUser(w http.ResponseWriter, r *http.Request) {
+	if err := h.authUsecase.UnlockUser(r.Context(), r.PathValue("username")); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(httpModels.EmptyModel)
+}
+
+func (h AuthHandler) writeTwoFactorChallenge(
+	w http.ResponseWriter,
+	r *http.Request,
+	challenge string,
+) {
+	responseData, err := json.Marshal(httpModels.TwoFactorChallenge{
+		TwoFactorRequired: true,
+		Challenge:         challenge,
+	})
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	w.Write(responseData)
+}
+
+func (h AuthHandler) LoginTwoFactor(w http.ResponseWriter, r *http.Request) {
+	var login httpModels.TwoFactorLogin
+	if err := validate.DecodeJSON(r, &login); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	session, userID, err := h.authUsecase.VerifyTwoFactor(r.Context(), login)
+	if err != nil {
+		if errors.Is(err, domain.ErrTwoFactorCode) {
+			// The client is not logged in until the second step is done.
+			err = domain.ErrAuth.Wrap(err)
+		}
+		problem.Write(w, r, err)
+		return
+	}
+
+	cookie := h.makeHTTPCookie(session)
+	http.SetCookie(w, cookie)
+
+	responseData, err := json.Marshal(httpModels.ID{ID: userID})
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(responseData)
+}
+
+func (h AuthHandler) EnrollTOTP(w http.ResponseWriter, r *http.Request) {
+	cookie, err := r.Cookie(CookieName)
+	if err != nil {
+		problem.Write(w, r, domain.ErrNoSession)
+		return
+	}
+
+	enrollment, err := h.authUsecase.EnrollTOTP(r.Context(), cookie.Value)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	responseData, err := json.Marshal(enrollment)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(responseData)
+}
+
+func (h AuthHandler) ActivateTOTP(w http.ResponseWriter, r *http.Request) {
+	cookie, err := r.Cookie(CookieName)
+	if err != nil {
+		problem.Write(w, r, domain.ErrNoSession)
+		return
+	}
+
+	var code httpModels.TOTPCode
+	if err = validate.DecodeJSON(r, &code); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	recoveryCodes, err := h.authUsecase.ActivateTOTP(r.Context(), cookie.Value, code.Code)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	responseData, err := json.Marshal(recoveryCodes)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(responseData)
+}
+
+func (h AuthHandler) DisableTOTP(w http.ResponseWriter, r *http.Request) {
+	cookie, err := r.Cookie(CookieName)
+	if err != nil {
+		problem.Write(w, r, domain.ErrNoSession)
+		return
+	}
+
+	var code httpModels.TOTPCode
+	if err = validate.DecodeJSON(r, &code); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	if err = h.authUsecase.DisableTOTP(r.Context(), cookie.Value, code.Code); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(httpModels.EmptyModel)
+}
+
+func (h AuthHandler) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	cookie, err := r.Cookie(CookieName)
+	if err != nil {
+		problem.Write(w, r, domain.ErrNoSession)
+		return
+	}
+
+	var code httpModels.TOTPCode
+	if err = validate.DecodeJSON(r, &code); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	recoveryCodes, err := h.authUsecase.RegenerateRecoveryCodes(r.Context(), cookie.Value, code.Code)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	responseData, err := json.Marshal(recoveryCodes)
+	if err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
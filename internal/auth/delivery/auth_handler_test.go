@@ -6,15 +6,33 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/docs"
 	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/config"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
 	mockDomain "github.com/themilchenko/vk-tech_internship-problem_2024/internal/mocks/domain"
 	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/openapi"
 	"go.uber.org/mock/gomock"
 )
 
+var loadSpec = sync.OnceValues(func() (*openapi.Spec, error) {
+	return openapi.Load(docs.Swagger)
+})
+
+// strict checks the handlers behind mux against the spec, as the API is
+// checked in tests.
+func strict(t *testing.T, mux http.Handler) http.Handler {
+	spec, err := loadSpec()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return spec.Strict(mux)
+}
+
 func TestHandler_SignUp(t *testing.T) {
 	type mockBehavior func(r *mockDomain.MockAuthUsecase, user httpModels.AuthUser)
 
@@ -39,7 +57,7 @@ func TestHandler_SignUp(t *testing.T) {
 			sessionID: "cc982d23-e3e0-439e-a8b7-7fff4eeeb2f2",
 			mockBehavior: func(m *mockDomain.MockAuthUsecase, user httpModels.AuthUser) {
 				m.EXPECT().
-					SignUp(user).
+					SignUp(gomock.Any(), user).
 					Return("cc982d23-e3e0-439e-a8b7-7fff4eeeb2f2", uint64(1), nil)
 			},
 			cookieSettings:       config.CookieSettings{},
@@ -52,12 +70,31 @@ func TestHandler_SignUp(t *testing.T) {
 			inputUser: httpModels.AuthUser{},
 			mockBehavior: func(m *mockDomain.MockAuthUsecase, user httpModels.AuthUser) {
 				m.EXPECT().
-					SignUp(user).
+					SignUp(gomock.Any(), user).
 					Return("", uint64(0), errors.New("empty password"))
 			},
 			cookieSettings:       config.CookieSettings{},
 			expectedStatusCode:   http.StatusInternalServerError,
-			expectedResponseBody: `{"error":"empty password"}`,
+			expectedResponseBody: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"server error","code":"internal_error"}`,
+		},
+		{
+			name:      "Policy violation",
+			inputBody: `{"username":"Jane","password":"123","role":"user"}`,
+			inputUser: httpModels.AuthUser{
+				Username: "Jane",
+				Password: "123",
+				Role:     "user",
+			},
+			mockBehavior: func(m *mockDomain.MockAuthUsecase, user httpModels.AuthUser) {
+				m.EXPECT().
+					SignUp(gomock.Any(), user).
+					Return("", uint64(0), domain.ValidationError{Violations: []domain.FieldError{
+						{Field: "password", Rule: "min_length", Message: "must be at least 8 characters long"},
+					}})
+			},
+			cookieSettings:       config.CookieSettings{},
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"validation failed","code":"validation_failed","violations":[{"field":"password","rule":"min_length","message":"must be at least 8 characters long"}]}`,
 		},
 		{
 			name:                 "Bad request",
@@ -66,7 +103,7 @@ func TestHandler_SignUp(t *testing.T) {
 			mockBehavior:         func(m *mockDomain.MockAuthUsecase, user httpModels.AuthUser) {},
 			cookieSettings:       config.CookieSettings{},
 			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: `{"error":"invalid character 's' looking for beginning of value"}`,
+			expectedResponseBody: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"failed to unmarshal json: invalid character 's' looking for beginning of value","code":"invalid_json"}`,
 		},
 	}
 
@@ -91,7 +128,7 @@ func TestHandler_SignUp(t *testing.T) {
 				bytes.NewBufferString(tc.inputBody),
 			)
 
-			mux.ServeHTTP(w, req)
+			strict(t, mux).ServeHTTP(w, req)
 
 			assert.Equal(t, tc.expectedStatusCode, w.Code)
 			assert.Equal(t, tc.expectedResponseBody, strings.Trim(w.Body.String(), "\n"))
@@ -122,7 +159,7 @@ func TestHandler_Login(t *testing.T) {
 			sessionID: "cc982d23-e3e0-439e-a8b7-7fff4eeeb2f2",
 			mockBehavior: func(m *mockDomain.MockAuthUsecase, user httpModels.AuthUser) {
 				m.EXPECT().
-					Login(user).
+					Login(gomock.Any(), user, "192.0.2.1").
 					Return("cc982d23-e3e0-439e-a8b7-7fff4eeeb2f2", uint64(1), nil)
 			},
 			cookieSettings:       config.CookieSettings{},
@@ -138,12 +175,60 @@ func TestHandler_Login(t *testing.T) {
 			},
 			mockBehavior: func(m *mockDomain.MockAuthUsecase, user httpModels.AuthUser) {
 				m.EXPECT().
-					Login(user).
+					Login(gomock.Any(), user, "192.0.2.1").
 					Return("", uint64(0), errors.New("empty password"))
 			},
 			cookieSettings:       config.CookieSettings{},
 			expectedStatusCode:   http.StatusInternalServerError,
-			expectedResponseBody: `{"error":"empty password"}`,
+			expectedResponseBody: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"server error","code":"internal_error"}`,
+		},
+		{
+			name:      "Invalid credentials",
+			inputBody: `{"username":"Jane","password":"321"}`,
+			inputUser: httpModels.AuthUser{
+				Username: "Jane",
+				Password: "321",
+			},
+			mockBehavior: func(m *mockDomain.MockAuthUsecase, user httpModels.AuthUser) {
+				m.EXPECT().
+					Login(gomock.Any(), user, "192.0.2.1").
+					Return("", uint64(0), domain.ErrInvalidLoginOrPassword)
+			},
+			cookieSettings:       config.CookieSettings{},
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"type":"about:blank","title":"Unauthorized","status":401,"detail":"invalid login or password","code":"invalid_credentials"}`,
+		},
+		{
+			name:      "Two-factor required",
+			inputBody: `{"username":"Jane","password":"123"}`,
+			inputUser: httpModels.AuthUser{
+				Username: "Jane",
+				Password: "123",
+			},
+			mockBehavior: func(m *mockDomain.MockAuthUsecase, user httpModels.AuthUser) {
+				m.EXPECT().
+					Login(gomock.Any(), user, "192.0.2.1").
+					Return("", uint64(0), domain.TwoFactorRequiredError{Challenge: "abc"})
+			},
+			cookieSettings:       config.CookieSettings{},
+			expectedStatusCode:   http.StatusAccepted,
+			expectedResponseBody: `{"twoFactorRequired":true,"challenge":"abc"}`,
+		},
+		{
+			name:      "Too many attempts",
+			inputBody: `{"username":"Jane","password":"123"}`,
+			inputUser: httpModels.AuthUser{
+				Username: "Jane",
+				Password: "123",
+			},
+			mockBehavior: func(m *mockDomain.MockAuthUsecase, user httpModels.AuthUser) {
+				m.EXPECT().
+					Login(gomock.Any(), user, "192.0.2.1").
+					Return("", uint64(0), domain.ErrTooManyAttempts)
+			},
+			cookieSettings:       config.CookieSettings{},
+			expectedStatusCode:   http.StatusTooManyRequests,
+			expectedResponseBody: `{"type":"about:blank","title":"Too Many Requests","status":429,"detail":"too many failed login attempts, try again later","code":"too_many_attempts"}`,
 		},
 		{
 			name:                 "Bad request",
@@ -152,7 +237,7 @@ func TestHandler_Login(t *testing.T) {
 			mockBehavior:         func(m *mockDomain.MockAuthUsecase, user httpModels.AuthUser) {},
 			cookieSettings:       config.CookieSettings{},
 			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: `{"error":"invalid character 's' looking for beginning of value"}`,
+			expectedResponseBody: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"failed to unmarshal json: invalid character 's' looking for beginning of value","code":"invalid_json"}`,
 		},
 	}
 
@@ -177,7 +262,7 @@ func TestHandler_Login(t *testing.T) {
 				bytes.NewBufferString(tc.inputBody),
 			)
 
-			mux.ServeHTTP(w, req)
+			strict(t, mux).ServeHTTP(w, req)
 
 			assert.Equal(t, tc.expectedStatusCode, w.Code)
 			assert.Equal(t, tc.expectedResponseBody, strings.Trim(w.Body.String(), "\n"))
@@ -201,7 +286,7 @@ func TestHandler_Logout(t *testing.T) {
 			sessionID: "cc982d23-e3e0-439e-a8b7-7fff4eeeb2f2",
 			mockBehavior: func(m *mockDomain.MockAuthUsecase, sessionID string) {
 				m.EXPECT().
-					Logout(sessionID).
+					Logout(gomock.Any(), sessionID).
 					Return(nil)
 			},
 			cookieSettings:       config.CookieSettings{},
@@ -213,12 +298,12 @@ func TestHandler_Logout(t *testing.T) {
 			sessionID: "cc982d23-e3e0-439e-a8b7-7fff4eeeb2f2",
 			mockBehavior: func(m *mockDomain.MockAuthUsecase, sessionID string) {
 				m.EXPECT().
-					Logout(sessionID).
-					Return(errors.New("session not found"))
+					Logout(gomock.Any(), sessionID).
+					Return(domain.ErrNoSession)
 			},
 			cookieSettings:       config.CookieSettings{},
 			expectedStatusCode:   http.StatusUnauthorized,
-			expectedResponseBody: `{"error":"session not found"}`,
+			expectedResponseBody: `{"type":"about:blank","title":"Unauthorized","status":401,"detail":"no existing session","code":"no_session"}`,
 		},
 	}
 
@@ -246,7 +331,7 @@ func TestHandler_Logout(t *testing.T) {
 				req.Header.Add("Cookie", "session_id="+tc.sessionID)
 			}
 
-			mux.ServeHTTP(w, req)
+			strict(t, mux).ServeHTTP(w, req)
 
 			assert.Equal(t, tc.expectedStatusCode, w.Code)
 			assert.Equal(t, tc.expectedResponseBody, strings.Trim(w.Body.String(), "\n"))
@@ -270,7 +355,7 @@ func TestHandler_Auth(t *testing.T) {
 			sessionID: "cc982d23-e3e0-439e-a8b7-7fff4eeeb2f2",
 			mockBehavior: func(m *mockDomain.MockAuthUsecase, sessionID string) {
 				m.EXPECT().
-					Auth(sessionID).
+					Auth(gomock.Any(), sessionID).
 					Return(uint64(1), nil)
 			},
 			cookieSettings:       config.CookieSettings{},
@@ -282,12 +367,12 @@ func TestHandler_Auth(t *testing.T) {
 			sessionID: "cc982d23-e3e0-439e-a8b7-7fff4eeeb2f2",
 			mockBehavior: func(m *mockDomain.MockAuthUsecase, sessionID string) {
 				m.EXPECT().
-					Auth(sessionID).
-					Return(uint64(0), errors.New("session not found"))
+					Auth(gomock.Any(), sessionID).
+					Return(uint64(0), domain.ErrNoSession)
 			},
 			cookieSettings:       config.CookieSettings{},
 			expectedStatusCode:   http.StatusUnauthorized,
-			expectedResponseBody: `{"error":"session not found"}`,
+			expectedResponseBody: `{"type":"about:blank","title":"Unauthorized","status":401,"detail":"no existing session","code":"no_session"}`,
 		},
 	}
 
@@ -315,7 +400,92 @@ func TestHandler_Auth(t *testing.T) {
 				req.Header.Add("Cookie", "session_id="+tc.sessionID)
 			}
 
-			mux.ServeHTTP(w, req)
+			strict(t, mux).ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Equal(t, tc.expectedResponseBody, strings.Trim(w.Body.String(), "\n"))
+		})
+	}
+}
+
+func TestHandler_ChangePassword(t *testing.T) {
+	type mockBehavior func(r *mockDomain.MockAuthUsecase, sessionID string, passwords httpModels.ChangePassword)
+
+	tests := []struct {
+		name                 string
+		sessionID            string
+		inputBody            string
+		inputPasswords       httpModels.ChangePassword
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Successful change",
+			sessionID: "cc982d23-e3e0-439e-a8b7-7fff4eeeb2f2",
+			inputBody: `{"oldPassword":"old","newPassword":"new"}`,
+			inputPasswords: httpModels.ChangePassword{
+				OldPassword: "old",
+				NewPassword: "new",
+			},
+			mockBehavior: func(m *mockDomain.MockAuthUsecase, sessionID string, passwords httpModels.ChangePassword) {
+				m.EXPECT().
+					ChangePassword(gomock.Any(), sessionID, passwords).
+					Return(nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{}`,
+		},
+		{
+			name:      "Wrong old password",
+			sessionID: "cc982d23-e3e0-439e-a8b7-7fff4eeeb2f2",
+			inputBody: `{"oldPassword":"wrong","newPassword":"new"}`,
+			inputPasswords: httpModels.ChangePassword{
+				OldPassword: "wrong",
+				NewPassword: "new",
+			},
+			mockBehavior: func(m *mockDomain.MockAuthUsecase, sessionID string, passwords httpModels.ChangePassword) {
+				m.EXPECT().
+					ChangePassword(gomock.Any(), sessionID, passwords).
+					Return(domain.ErrPasswordsNotEqual)
+			},
+			expectedStatusCode:   http.StatusForbidden,
+			expectedResponseBody: `{"type":"about:blank","title":"Forbidden","status":403,"detail":"passwords not the same","code":"wrong_password"}`,
+		},
+		{
+			name:                 "No session",
+			inputBody:            `{"oldPassword":"old","newPassword":"new"}`,
+			mockBehavior:         func(m *mockDomain.MockAuthUsecase, sessionID string, passwords httpModels.ChangePassword) {},
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedResponseBody: `{"type":"about:blank","title":"Unauthorized","status":401,"detail":"no existing session","code":"no_session"}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cntx := gomock.NewController(t)
+			defer cntx.Finish()
+
+			mockAuthUsecase := mockDomain.NewMockAuthUsecase(cntx)
+
+			tc.mockBehavior(mockAuthUsecase, tc.sessionID, tc.inputPasswords)
+
+			handler := NewAuthHandler(mockAuthUsecase, config.CookieSettings{})
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("PUT /me/password", handler.ChangePassword)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(
+				http.MethodPut,
+				"/me/password",
+				bytes.NewBufferString(tc.inputBody),
+			)
+			if len(tc.sessionID) > 0 {
+				req.Header.Add("Cookie", "session_id="+tc.sessionID)
+			}
+
+			strict(t, mux).ServeHTTP(w, req)
 
 			assert.Equal(t, tc.expectedStatusCode, w.Code)
 			assert.Equal(t, tc.expectedResponseBody, strings.Trim(w.Body.String(), "\n"))
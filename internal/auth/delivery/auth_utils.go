@@ -1,12 +1,18 @@
 package httpAuth
 
 import (
+	"net"
 	"net/http"
 	"time"
 )
 
 const (
-	CookieName = "session_id"
+	CookieName          = "session_id"
+	OIDCStateCookieName = "oidc_state"
+
+	// oidcStateMaxAge matches the default state TTL, the stored state
+	// expires on its own anyway.
+	oidcStateMaxAge = 10 * time.Minute
 )
 
 func (h AuthHandler) makeHTTPCookie(sessionID string) *http.Cookie {
@@ -24,6 +30,21 @@ func (h AuthHandler) makeHTTPCookie(sessionID string) *http.Cookie {
 	}
 }
 
+// makeOIDCStateCookie ties the login state to the browser that started the
+// flow. Lax mode still sends it on the top level redirect back from the
+// identity provider.
+func (h AuthHandler) makeOIDCStateCookie(state string) *http.Cookie {
+	return &http.Cookie{
+		Name:     OIDCStateCookieName,
+		Value:    state,
+		MaxAge:   int(oidcStateMaxAge.Seconds()),
+		Secure:   h.cookieSettings.Secure,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+		Path:     "/",
+	}
+}
+
 func GetCookie(w http.ResponseWriter, r *http.Request) (*http.Cookie, error) {
 	cookie, err := r.Cookie(CookieName)
 	if err != nil {
@@ -32,3 +53,13 @@ func GetCookie(w http.ResponseWriter, r *http.Request) (*http.Cookie, error) {
 
 	return cookie, nil
 }
+
+// clientIP takes the address of the direct peer. Forwarding headers are not
+// trusted, otherwise anyone could pick their own IP to dodge login throttling.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
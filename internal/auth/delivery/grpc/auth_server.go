@@ -0,0 +1,161 @@
+package grpcAuth
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/requestctx"
+	filmlibraryv1 "github.com/themilchenko/vk-tech_internship-problem_2024/pkg/api/filmlibrary/v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// SessionMetadataKey is the metadata with the session of a call, the gRPC
+// twin of the session_id cookie.
+const SessionMetadataKey = "session-id"
+
+type AuthServer struct {
+	filmlibraryv1.UnimplementedAuthServiceServer
+
+	authUsecase domain.AuthUsecase
+}
+
+func NewAuthServer(a domain.AuthUsecase) *AuthServer {
+	return &AuthServer{authUsecase: a}
+}
+
+func (s *AuthServer) SignUp(
+	ctx context.Context,
+	req *filmlibraryv1.SignUpRequest,
+) (*filmlibraryv1.SignUpResponse, error) {
+	sessionID, userID, err := s.authUsecase.SignUp(ctx, httpModels.AuthUser{
+		Username: req.GetUsername(),
+		Password: req.GetPassword(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &filmlibraryv1.SignUpResponse{
+		Session: &filmlibraryv1.Session{SessionId: sessionID, UserId: userID},
+	}, nil
+}
+
+func (s *AuthServer) Login(
+	ctx context.Context,
+	req *filmlibraryv1.LoginRequest,
+) (*filmlibraryv1.LoginResponse, error) {
+	sessionID, userID, err := s.authUsecase.Login(ctx, httpModels.AuthUser{
+		Username: req.GetUsername(),
+		Password: req.GetPassword(),
+	}, clientIP(ctx))
+	if err != nil {
+		var twoFactorErr domain.TwoFactorRequiredError
+		if errors.As(err, &twoFactorErr) {
+			return &filmlibraryv1.LoginResponse{
+				Result: &filmlibraryv1.LoginResponse_TwoFactorChallenge{
+					TwoFactorChallenge: twoFactorErr.Challenge,
+				},
+			}, nil
+		}
+		return nil, err
+	}
+	return &filmlibraryv1.LoginResponse{
+		Result: &filmlibraryv1.LoginResponse_Session{
+			Session: &filmlibraryv1.Session{SessionId: sessionID, UserId: userID},
+		},
+	}, nil
+}
+
+func (s *AuthServer) LoginTwoFactor(
+	ctx context.Context,
+	req *filmlibraryv1.LoginTwoFactorRequest,
+) (*filmlibraryv1.LoginTwoFactorResponse, error) {
+	sessionID, userID, err := s.authUsecase.VerifyTwoFactor(ctx, httpModels.TwoFactorLogin{
+		Challenge: req.GetChallenge(),
+		Code:      req.GetCode(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &filmlibraryv1.LoginTwoFactorResponse{
+		Session: &filmlibraryv1.Session{SessionId: sessionID, UserId: userID},
+	}, nil
+}
+
+func (s *AuthServer) Logout(
+	ctx context.Context,
+	req *filmlibraryv1.LogoutRequest,
+) (*filmlibraryv1.LogoutResponse, error) {
+	sessionID, err := SessionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.authUsecase.Logout(ctx, sessionID); err != nil {
+		return nil, err
+	}
+	return &filmlibraryv1.LogoutResponse{}, nil
+}
+
+func (s *AuthServer) GetCurrentUser(
+	ctx context.Context,
+	req *filmlibraryv1.GetCurrentUserRequest,
+) (*filmlibraryv1.GetCurrentUserResponse, error) {
+	sessionID, err := SessionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.authUsecase.GetUserBySessionID(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return &filmlibraryv1.GetCurrentUserResponse{
+		User: &filmlibraryv1.User{
+			Id:               requestctx.UserID(ctx),
+			Username:         user.Username,
+			Role:             user.Role,
+			TwoFactorEnabled: user.TwoFactorEnabled,
+		},
+	}, nil
+}
+
+func (s *AuthServer) ChangePassword(
+	ctx context.Context,
+	req *filmlibraryv1.ChangePasswordRequest,
+) (*filmlibraryv1.ChangePasswordResponse, error) {
+	sessionID, err := SessionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.authUsecase.ChangePassword(ctx, sessionID, httpModels.ChangePassword{
+		OldPassword: req.GetOldPassword(),
+		NewPassword: req.GetNewPassword(),
+	}); err != nil {
+		return nil, err
+	}
+	return &filmlibraryv1.ChangePasswordResponse{}, nil
+}
+
+// SessionID reads the session of a call from its metadata.
+func SessionID(ctx context.Context) (string, error) {
+	values := metadata.ValueFromIncomingContext(ctx, SessionMetadataKey)
+	if len(values) == 0 || values[0] == "" {
+		return "", domain.ErrNoSession
+	}
+	return values[0], nil
+}
+
+// clientIP takes the address of the direct peer, like for HTTP logins.
+func clientIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
@@ -1,20 +1,25 @@
 package authMiddleware
 
 import (
+	"context"
 	"net/http"
 
 	httpAuth "github.com/themilchenko/vk-tech_internship-problem_2024/internal/auth/delivery"
 	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
-	"github.com/themilchenko/vk-tech_internship-problem_2024/pkg"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/problem"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/requestctx"
 )
 
 type Middleware struct {
 	authUsecase domain.AuthUsecase
+
+	requireAdminTwoFactor bool
 }
 
-func NewMiddleware(a domain.AuthUsecase) *Middleware {
+func NewMiddleware(a domain.AuthUsecase, requireAdminTwoFactor bool) *Middleware {
 	return &Middleware{
-		authUsecase: a,
+		authUsecase:           a,
+		requireAdminTwoFactor: requireAdminTwoFactor,
 	}
 }
 
@@ -22,37 +27,52 @@ func (m Middleware) LoginRequired(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		cookie, err := r.Cookie(httpAuth.CookieName)
 		if err != nil {
-			pkg.HandleError(w, err.Error(), http.StatusUnauthorized)
+			problem.Write(w, r, domain.ErrNoSession)
 			return
 		}
 
-		if _, err = m.authUsecase.Auth(cookie.Value); err != nil {
-			pkg.HandleError(w, err.Error(), http.StatusUnauthorized)
+		userID, err := m.authUsecase.Auth(r.Context(), cookie.Value)
+		if err != nil {
+			problem.Write(w, r, err)
 			return
 		}
 
-		next(w, r)
+		next(w, r.WithContext(requestctx.WithUserID(r.Context(), userID)))
 	}
 }
 
 func (m Middleware) AccessRestriction(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		cookie, err := r.Cookie(httpAuth.CookieName)
-		if err != nil {
-			pkg.HandleError(w, err.Error(), http.StatusUnauthorized)
-			return
-		}
-
-		user, err := m.authUsecase.GetUserBySessionID(cookie.Value)
-		if err != nil {
-			pkg.HandleError(w, err.Error(), http.StatusNotFound)
-			return
-		}
-		if user.Role != "admin" {
-			pkg.HandleError(w, domain.ErrForbidden.Error(), http.StatusForbidden)
+		if err := m.Admin(r); err != nil {
+			problem.Write(w, r, err)
 			return
 		}
 
 		next(w, r)
 	}
 }
+
+// Admin is the check of AccessRestriction for handlers that restrict only
+// some of what they do, like GraphQL mutations.
+func (m Middleware) Admin(r *http.Request) error {
+	cookie, err := r.Cookie(httpAuth.CookieName)
+	if err != nil {
+		return domain.ErrNoSession
+	}
+
+	return m.admin(r.Context(), cookie.Value)
+}
+
+func (m Middleware) admin(ctx context.Context, sessionID string) error {
+	user, err := m.authUsecase.GetUserBySessionID(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if user.Role != "admin" {
+		return domain.ErrForbidden
+	}
+	if m.requireAdminTwoFactor && !user.TwoFactorEnabled {
+		return domain.ErrTwoFactorEnrollment
+	}
+	return nil
+}
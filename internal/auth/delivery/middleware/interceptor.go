@@ -0,0 +1,87 @@
+package authMiddleware
+
+import (
+	"context"
+
+	grpcAuth "github.com/themilchenko/vk-tech_internship-problem_2024/internal/auth/delivery/grpc"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/requestctx"
+	"google.golang.org/grpc"
+)
+
+// Access is who may call a gRPC method.
+type Access int
+
+const (
+	// AccessLogin is LoginRequired, methods missing in the access map get
+	// it.
+	AccessLogin Access = iota
+	AccessPublic
+	// AccessAdmin is LoginRequired with AccessRestriction.
+	AccessAdmin
+)
+
+// UnaryInterceptor checks the session of unary calls, access holds the
+// access of methods by full method name.
+func (m Middleware) UnaryInterceptor(access map[string]Access) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		ctx, err := m.authorize(ctx, access[info.FullMethod])
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor is UnaryInterceptor for streams, e.g. server reflection.
+func (m Middleware) StreamInterceptor(access map[string]Access) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		ctx, err := m.authorize(ss.Context(), access[info.FullMethod])
+		if err != nil {
+			return err
+		}
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func (m Middleware) authorize(ctx context.Context, access Access) (context.Context, error) {
+	if access == AccessPublic {
+		return ctx, nil
+	}
+
+	sessionID, err := grpcAuth.SessionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := m.authUsecase.Auth(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	ctx = requestctx.WithUserID(ctx, userID)
+
+	if access == AccessAdmin {
+		if err = m.admin(ctx, sessionID); err != nil {
+			return nil, err
+		}
+	}
+	return ctx, nil
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
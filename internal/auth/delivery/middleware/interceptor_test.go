@@ -0,0 +1,124 @@
+package authMiddleware
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	grpcAuth "github.com/themilchenko/vk-tech_internship-problem_2024/internal/auth/delivery/grpc"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	mockDomain "github.com/themilchenko/vk-tech_internship-problem_2024/internal/mocks/domain"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/requestctx"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestMiddleware_UnaryInterceptor(t *testing.T) {
+	type mockBehavior func(a *mockDomain.MockAuthUsecase)
+
+	access := map[string]Access{
+		"/public": AccessPublic,
+		"/admin":  AccessAdmin,
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		sessionID      string
+		requireTwoFA   bool
+		mockBehavior   mockBehavior
+		expectedUserID uint64
+		expectedErr    error
+	}{
+		{
+			name:         "Public",
+			method:       "/public",
+			mockBehavior: func(a *mockDomain.MockAuthUsecase) {},
+		},
+		{
+			name:         "No session",
+			method:       "/login",
+			mockBehavior: func(a *mockDomain.MockAuthUsecase) {},
+			expectedErr:  domain.ErrNoSession,
+		},
+		{
+			name:      "Login",
+			method:    "/login",
+			sessionID: "session",
+			mockBehavior: func(a *mockDomain.MockAuthUsecase) {
+				a.EXPECT().Auth(gomock.Any(), "session").Return(uint64(1), nil)
+			},
+			expectedUserID: 1,
+		},
+		{
+			name:      "Admin",
+			method:    "/admin",
+			sessionID: "session",
+			mockBehavior: func(a *mockDomain.MockAuthUsecase) {
+				a.EXPECT().Auth(gomock.Any(), "session").Return(uint64(1), nil)
+				a.EXPECT().
+					GetUserBySessionID(gomock.Any(), "session").
+					Return(httpModels.AuthUser{Username: "admin", Role: "admin"}, nil)
+			},
+			expectedUserID: 1,
+		},
+		{
+			name:      "Not an admin",
+			method:    "/admin",
+			sessionID: "session",
+			mockBehavior: func(a *mockDomain.MockAuthUsecase) {
+				a.EXPECT().Auth(gomock.Any(), "session").Return(uint64(2), nil)
+				a.EXPECT().
+					GetUserBySessionID(gomock.Any(), "session").
+					Return(httpModels.AuthUser{Username: "user", Role: "user"}, nil)
+			},
+			expectedErr: domain.ErrForbidden,
+		},
+		{
+			name:         "Admin without two-factor authentication",
+			method:       "/admin",
+			sessionID:    "session",
+			requireTwoFA: true,
+			mockBehavior: func(a *mockDomain.MockAuthUsecase) {
+				a.EXPECT().Auth(gomock.Any(), "session").Return(uint64(1), nil)
+				a.EXPECT().
+					GetUserBySessionID(gomock.Any(), "session").
+					Return(httpModels.AuthUser{Username: "admin", Role: "admin"}, nil)
+			},
+			expectedErr: domain.ErrTwoFactorEnrollment,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cntx := gomock.NewController(t)
+			defer cntx.Finish()
+
+			auth := mockDomain.NewMockAuthUsecase(cntx)
+			tt.mockBehavior(auth)
+
+			interceptor := NewMiddleware(auth, tt.requireTwoFA).UnaryInterceptor(access)
+
+			ctx := context.Background()
+			if tt.sessionID != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(grpcAuth.SessionMetadataKey, tt.sessionID))
+			}
+
+			var userID uint64
+			_, err := interceptor(
+				ctx,
+				nil,
+				&grpc.UnaryServerInfo{FullMethod: tt.method},
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					userID = requestctx.UserID(ctx)
+					return nil, nil
+				},
+			)
+
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expectedUserID, userID)
+		})
+	}
+}
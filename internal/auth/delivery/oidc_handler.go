@@ -0,0 +1,94 @@
+package httpAuth
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/problem"
+)
+
+func (h AuthHandler) OIDCLogin(w http.ResponseWriter, r *http.Request) {
+	h.redirectToProvider(w, r, "", http.StatusFound)
+}
+
+// OIDCLink starts the same flow as OIDCLogin, but the identity is attached to
+// the logged in user. It is a POST, so that a link or an image elsewhere
+// can't start it on behalf of the user.
+func (h AuthHandler) OIDCLink(w http.ResponseWriter, r *http.Request) {
+	cookie, err := r.Cookie(CookieName)
+	if err != nil {
+		problem.Write(w, r, domain.ErrNoSession)
+		return
+	}
+
+	h.redirectToProvider(w, r, cookie.Value, http.StatusSeeOther)
+}
+
+func (h AuthHandler) redirectToProvider(
+	w http.ResponseWriter,
+	r *http.Request,
+	sessionID string,
+	code int,
+) {
+	url, state, err := h.authUsecase.OIDCLoginURL(r.Context(), r.PathValue("provider"), sessionID)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	http.SetCookie(w, h.makeOIDCStateCookie(state))
+	http.Redirect(w, r, url, code)
+}
+
+func (h AuthHandler) OIDCCallback(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	// The state is good for one callback only, whatever its outcome.
+	stateCookie, err := r.Cookie(OIDCStateCookieName)
+	expired := h.makeOIDCStateCookie("")
+	expired.MaxAge = -1
+	http.SetCookie(w, expired)
+	if err != nil ||
+		subtle.ConstantTimeCompare([]byte(stateCookie.Value), []byte(query.Get("state"))) != 1 {
+		problem.Write(w, r, domain.ErrOIDCState)
+		return
+	}
+
+	if idpErr := query.Get("error"); idpErr != "" {
+		problem.Write(w, r, domain.ErrOIDCExchange.Wrap(errors.New(idpErr)))
+		return
+	}
+
+	session, userID, err := h.authUsecase.OIDCCallback(
+		r.Context(),
+		r.PathValue("provider"),
+		query.Get("code"),
+		query.Get("state"),
+	)
+	if err != nil {
+		var twoFactorErr domain.TwoFactorRequiredError
+		if errors.As(err, &twoFactorErr) {
+			h.writeTwoFactorChallenge(w, r, twoFactorErr.Challenge)
+		} else {
+			problem.Write(w, r, err)
+		}
+		return
+	}
+
+	cookie := h.makeHTTPCookie(session)
+	http.SetCookie(w, cookie)
+
+	responseData, err := json.Marshal(httpModels.ID{ID: userID})
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(responseData)
+}
@@ -0,0 +1,155 @@
+package httpAuth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/config"
+	mockDomain "github.com/themilchenko/vk-tech_internship-problem_2024/internal/mocks/domain"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandler_OIDCRedirect(t *testing.T) {
+	type mockBehavior func(r *mockDomain.MockAuthUsecase)
+
+	tests := []struct {
+		name               string
+		method             string
+		target             string
+		sessionID          string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+	}{
+		{
+			name:   "Login",
+			method: http.MethodGet,
+			target: "/oidc/corp/login",
+			mockBehavior: func(m *mockDomain.MockAuthUsecase) {
+				m.EXPECT().
+					OIDCLoginURL(gomock.Any(), "corp", "").
+					Return("https://idp.example.com/auth?state=xyz", "xyz", nil)
+			},
+			expectedStatusCode: http.StatusFound,
+		},
+		{
+			name:      "Link",
+			method:    http.MethodPost,
+			target:    "/me/oidc/corp/link",
+			sessionID: "cc982d23-e3e0-439e-a8b7-7fff4eeeb2f2",
+			mockBehavior: func(m *mockDomain.MockAuthUsecase) {
+				m.EXPECT().
+					OIDCLoginURL(gomock.Any(), "corp", "cc982d23-e3e0-439e-a8b7-7fff4eeeb2f2").
+					Return("https://idp.example.com/auth?state=xyz", "xyz", nil)
+			},
+			expectedStatusCode: http.StatusSeeOther,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cntx := gomock.NewController(t)
+			defer cntx.Finish()
+
+			mockAuthUsecase := mockDomain.NewMockAuthUsecase(cntx)
+
+			tc.mockBehavior(mockAuthUsecase)
+
+			handler := NewAuthHandler(mockAuthUsecase, config.CookieSettings{})
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /oidc/{provider}/login", handler.OIDCLogin)
+			mux.HandleFunc("POST /me/oidc/{provider}/link", handler.OIDCLink)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(tc.method, tc.target, nil)
+			if tc.sessionID != "" {
+				req.AddCookie(&http.Cookie{Name: CookieName, Value: tc.sessionID})
+			}
+
+			strict(t, mux).ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Equal(t, "https://idp.example.com/auth?state=xyz", w.Header().Get("Location"))
+
+			cookies := w.Result().Cookies()
+			require.Len(t, cookies, 1)
+			assert.Equal(t, OIDCStateCookieName, cookies[0].Name)
+			assert.Equal(t, "xyz", cookies[0].Value)
+			assert.True(t, cookies[0].HttpOnly)
+			assert.Equal(t, http.SameSiteLaxMode, cookies[0].SameSite)
+			assert.Equal(t, 600, cookies[0].MaxAge)
+		})
+	}
+}
+
+func TestHandler_OIDCCallback(t *testing.T) {
+	type mockBehavior func(r *mockDomain.MockAuthUsecase)
+
+	tests := []struct {
+		name                 string
+		stateCookie          string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "Successful login",
+			stateCookie: "xyz",
+			mockBehavior: func(m *mockDomain.MockAuthUsecase) {
+				m.EXPECT().
+					OIDCCallback(gomock.Any(), "corp", "code", "xyz").
+					Return("cc982d23-e3e0-439e-a8b7-7fff4eeeb2f2", uint64(1), nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"id":1}`,
+		},
+		{
+			name:                 "No state cookie",
+			mockBehavior:         func(m *mockDomain.MockAuthUsecase) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"login state is invalid or expired","code":"invalid_oidc_state"}`,
+		},
+		{
+			name:                 "State from another browser",
+			stateCookie:          "abc",
+			mockBehavior:         func(m *mockDomain.MockAuthUsecase) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"login state is invalid or expired","code":"invalid_oidc_state"}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cntx := gomock.NewController(t)
+			defer cntx.Finish()
+
+			mockAuthUsecase := mockDomain.NewMockAuthUsecase(cntx)
+
+			tc.mockBehavior(mockAuthUsecase)
+
+			handler := NewAuthHandler(mockAuthUsecase, config.CookieSettings{})
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /oidc/{provider}/callback", handler.OIDCCallback)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/oidc/corp/callback?code=code&state=xyz", nil)
+			if tc.stateCookie != "" {
+				req.AddCookie(&http.Cookie{Name: OIDCStateCookieName, Value: tc.stateCookie})
+			}
+
+			strict(t, mux).ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			assert.Equal(t, tc.expectedResponseBody, strings.Trim(w.Body.String(), "\n"))
+
+			cleared := w.Result().Cookies()[0]
+			assert.Equal(t, OIDCStateCookieName, cleared.Name)
+			assert.Equal(t, -1, cleared.MaxAge)
+		})
+	}
+}
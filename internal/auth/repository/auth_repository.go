@@ -1,24 +1,60 @@
 package authRepository
 
 import (
+	"context"
+	"time"
+
+	auditRepository "github.com/themilchenko/vk-tech_internship-problem_2024/internal/audit/repository"
+
 	gormModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/gorm"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/dbtx"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/metrics"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/pgerrors"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/sqlcomment"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/tracing"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	gormLogger "gorm.io/gorm/logger"
 )
 
+const userEntity = "user"
+
 type Postgres struct {
 	DB *gorm.DB
 }
 
-func NewPostgres(url string) (*Postgres, error) {
-	db, err := gorm.Open(postgres.Open(url), &gorm.Config{})
+func NewPostgres(url string, logger gormLogger.Interface) (*Postgres, error) {
+	db, err := gorm.Open(postgres.Open(url), &gorm.Config{Logger: logger})
 	if err != nil {
 		return nil, err
 	}
+	if err = db.Use(pgerrors.Plugin{}); err != nil {
+		return nil, err
+	}
+	if err = db.Use(dbtx.Plugin{}); err != nil {
+		return nil, err
+	}
+	if err = db.Use(sqlcomment.Plugin{}); err != nil {
+		return nil, err
+	}
+	if err = db.Use(metrics.Plugin{DB: "auth"}); err != nil {
+		return nil, err
+	}
+	if err = db.Use(tracing.Plugin{DB: "auth"}); err != nil {
+		return nil, err
+	}
 
 	db.AutoMigrate(
 		gormModels.User{},
 		gormModels.Session{},
+		gormModels.PasswordResetToken{},
+		gormModels.LoginAttempt{},
+		gormModels.RecoveryCode{},
+		gormModels.TwoFactorChallenge{},
+		gormModels.ExternalIdentity{},
+		gormModels.OIDCState{},
+		gormModels.AuditEntry{},
 	)
 
 	return &Postgres{
@@ -26,47 +62,408 @@ func NewPostgres(url string) (*Postgres, error) {
 	}, nil
 }
 
-func (db Postgres) CreateUser(user gormModels.User) (uint64, error) {
-	var userID gormModels.User
-	if err := db.DB.Create(&user).Scan(&userID).Error; err != nil {
+func (db Postgres) CreateUser(ctx context.Context, user gormModels.User) (uint64, error) {
+	if err := db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+		return auditRepository.Record(ctx, tx, userEntity, user.ID, nil, user)
+	}); err != nil {
 		return 0, err
 	}
-	return userID.ID, nil
+	return user.ID, nil
 }
 
-func (db Postgres) CreateSession(session gormModels.Session) (string, error) {
-	if err := db.DB.Create(&session).Error; err != nil {
+func (db Postgres) CreateSession(ctx context.Context, session gormModels.Session) (string, error) {
+	if err := db.DB.WithContext(ctx).Create(&session).Error; err != nil {
 		return "", err
 	}
 	return session.SessionID, nil
 }
 
-func (db Postgres) DeleteBySessionID(sessionID string) error {
-	if err := db.DB.Unscoped().Delete(&gormModels.Session{}, "session_id = ?", sessionID).
+func (db Postgres) DeleteBySessionID(ctx context.Context, sessionID string) error {
+	if err := db.DB.WithContext(ctx).Unscoped().Delete(&gormModels.Session{}, "session_id = ?", sessionID).
 		Error; err != nil {
 		return err
 	}
 	return nil
 }
 
-func (db Postgres) GetUserBySessionID(sessionID string) (gormModels.User, error) {
+func (db Postgres) GetUserBySessionID(
+	ctx context.Context,
+	sessionID string,
+) (gormModels.User, error) {
 	var recievedUser gormModels.User
-	if err := db.DB.
+	if err := db.DB.WithContext(ctx).
 		Joins("JOIN sessions ON users.id = sessions.user_id").
 		Where("sessions.session_id = ?", sessionID).
-		Select("users.id, users.username, users.password, users.role").
-		First(&recievedUser).Scan(&recievedUser).Error; err != nil {
+		Select("users.id, users.username, users.password, users.role, users.totp_enabled").
+		First(&recievedUser).Error; err != nil {
+		return gormModels.User{}, err
+	}
+	return recievedUser, nil
+}
+
+func (db Postgres) CountActiveSessions(ctx context.Context) (int64, error) {
+	var count int64
+	if err := db.DB.WithContext(ctx).Model(&gormModels.Session{}).
+		Where("expire_date > ?", time.Now()).
+		Count(&count).
+		Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (db Postgres) GetUserByUsername(
+	ctx context.Context,
+	username string,
+) (gormModels.User, error) {
+	var recievedUser gormModels.User
+	if err := db.DB.WithContext(ctx).Where("username = ?", username).
+		First(&recievedUser).
+		Error; err != nil {
 		return gormModels.User{}, err
 	}
 	return recievedUser, nil
 }
 
-func (db Postgres) GetUserByUsername(username string) (gormModels.User, error) {
+func (db Postgres) UpdatePassword(ctx context.Context, userID uint64, hash string) error {
+	return db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return updateUser(ctx, tx, userID, map[string]interface{}{"password": hash})
+	})
+}
+
+func (db Postgres) DeleteUserSessions(
+	ctx context.Context,
+	userID uint64,
+	exceptSessionID string,
+) error {
+	if err := db.DB.WithContext(ctx).Unscoped().
+		Where("user_id = ? AND session_id <> ?", userID, exceptSessionID).
+		Delete(&gormModels.Session{}).
+		Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (db Postgres) CreateResetToken(
+	ctx context.Context,
+	token gormModels.PasswordResetToken,
+) error {
+	if err := db.DB.WithContext(ctx).Create(&token).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (db Postgres) GetResetToken(
+	ctx context.Context,
+	tokenHash string,
+) (gormModels.PasswordResetToken, error) {
+	var recievedToken gormModels.PasswordResetToken
+	if err := db.DB.WithContext(ctx).Where("token_hash = ?", tokenHash).
+		First(&recievedToken).
+		Error; err != nil {
+		return gormModels.PasswordResetToken{}, err
+	}
+	return recievedToken, nil
+}
+
+func (db Postgres) ResetPassword(ctx context.Context, tokenID, userID uint64, hash string) error {
+	return db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// The token is marked used only if nobody else has consumed it in the
+		// meantime, so two concurrent confirmations can't both succeed.
+		res := tx.Model(&gormModels.PasswordResetToken{}).
+			Where("id = ? AND used = ?", tokenID, false).
+			Update("used", true)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if err := updateUser(ctx, tx, userID, map[string]interface{}{"password": hash}); err != nil {
+			return err
+		}
+
+		return tx.Unscoped().
+			Where("user_id = ?", userID).
+			Delete(&gormModels.Session{}).
+			Error
+	})
+}
+
+func (db Postgres) GetLoginAttempts(
+	ctx context.Context,
+	keys []string,
+) ([]gormModels.LoginAttempt, error) {
+	var recievedAttempts []gormModels.LoginAttempt
+	if err := db.DB.WithContext(ctx).Where("key IN ?", keys).
+		Find(&recievedAttempts).
+		Error; err != nil {
+		return nil, err
+	}
+	return recievedAttempts, nil
+}
+
+// RegisterLoginFailure counts a failure for the key. A count last touched
+// before staleBefore starts over from this failure.
+func (db Postgres) RegisterLoginFailure(
+	ctx context.Context,
+	key string,
+	staleBefore time.Time,
+) (gormModels.LoginAttempt, error) {
+	attempt := gormModels.LoginAttempt{
+		Key:      key,
+		Failures: 1,
+	}
+	// Counting happens in a single upsert so concurrent failures for the same
+	// key are never lost.
+	if err := db.DB.WithContext(ctx).Clauses(
+		clause.OnConflict{
+			Columns: []clause.Column{{Name: "key"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"failures": gorm.Expr(
+					"CASE WHEN login_attempts.updated_at < ? THEN 1 ELSE login_attempts.failures + 1 END",
+					staleBefore,
+				),
+				"updated_at": time.Now(),
+			}),
+		},
+		clause.Returning{},
+	).Create(&attempt).Error; err != nil {
+		return gormModels.LoginAttempt{}, err
+	}
+	return attempt, nil
+}
+
+func (db Postgres) BlockLogin(ctx context.Context, key string, until time.Time) error {
+	if err := db.DB.WithContext(ctx).Model(&gormModels.LoginAttempt{}).
+		Where("key = ?", key).
+		Update("blocked_until", until).
+		Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (db Postgres) ResetLoginFailures(ctx context.Context, key string) error {
+	if err := db.DB.WithContext(ctx).Unscoped().
+		Where("key = ?", key).
+		Delete(&gormModels.LoginAttempt{}).
+		Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (db Postgres) GetUserByID(ctx context.Context, userID uint64) (gormModels.User, error) {
 	var recievedUser gormModels.User
-	if err := db.DB.Model(&gormModels.User{
-		Username: username,
-	}).Scan(&recievedUser).Error; err != nil {
+	if err := db.DB.WithContext(ctx).Where("id = ?", userID).
+		First(&recievedUser).
+		Error; err != nil {
 		return gormModels.User{}, err
 	}
 	return recievedUser, nil
 }
+
+func (db Postgres) UpdateTOTP(
+	ctx context.Context,
+	userID uint64,
+	secret string,
+	enabled bool,
+) error {
+	return db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return updateUser(ctx, tx, userID, map[string]interface{}{
+			"totp_secret":  secret,
+			"totp_enabled": enabled,
+		})
+	})
+}
+
+// UseTOTPStep remembers the last accepted step. A code from the same or an
+// earlier step is rejected, so an intercepted code can't be replayed.
+func (db Postgres) UseTOTPStep(ctx context.Context, userID uint64, step int64) error {
+	res := db.DB.WithContext(ctx).Model(&gormModels.User{}).
+		Where("id = ? AND totp_last_step < ?", userID, step).
+		Update("totp_last_step", step)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (db Postgres) ReplaceRecoveryCodes(
+	ctx context.Context,
+	userID uint64,
+	codes []gormModels.RecoveryCode,
+) error {
+	return db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().
+			Where("user_id = ?", userID).
+			Delete(&gormModels.RecoveryCode{}).
+			Error; err != nil {
+			return err
+		}
+		if len(codes) == 0 {
+			return nil
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+func (db Postgres) UseRecoveryCode(ctx context.Context, userID uint64, codeHash string) error {
+	res := db.DB.WithContext(ctx).Model(&gormModels.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used = ?", userID, codeHash, false).
+		Update("used", true)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (db Postgres) CreateTwoFactorChallenge(
+	ctx context.Context,
+	challenge gormModels.TwoFactorChallenge,
+) error {
+	if err := db.DB.WithContext(ctx).Create(&challenge).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (db Postgres) GetTwoFactorChallenge(
+	ctx context.Context,
+	tokenHash string,
+) (gormModels.TwoFactorChallenge, error) {
+	var recievedChallenge gormModels.TwoFactorChallenge
+	if err := db.DB.WithContext(ctx).Where("token_hash = ?", tokenHash).
+		First(&recievedChallenge).
+		Error; err != nil {
+		return gormModels.TwoFactorChallenge{}, err
+	}
+	return recievedChallenge, nil
+}
+
+func (db Postgres) UseChallengeAttempt(ctx context.Context, challengeID, maxAttempts uint64) error {
+	res := db.DB.WithContext(ctx).Model(&gormModels.TwoFactorChallenge{}).
+		Where("id = ? AND attempts < ?", challengeID, maxAttempts).
+		Update("attempts", gorm.Expr("attempts + 1"))
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (db Postgres) DeleteTwoFactorChallenge(ctx context.Context, challengeID uint64) error {
+	if err := db.DB.WithContext(ctx).Unscoped().
+		Delete(&gormModels.TwoFactorChallenge{}, "id = ?", challengeID).
+		Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (db Postgres) CreateOIDCState(ctx context.Context, state gormModels.OIDCState) error {
+	if err := db.DB.WithContext(ctx).Create(&state).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// ConsumeOIDCState returns the state and deletes it in one go, so every
+// state can finish exactly one login.
+func (db Postgres) ConsumeOIDCState(
+	ctx context.Context,
+	stateHash string,
+) (gormModels.OIDCState, error) {
+	var recievedState gormModels.OIDCState
+	err := db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("state_hash = ?", stateHash).
+			First(&recievedState).
+			Error; err != nil {
+			return err
+		}
+
+		res := tx.Unscoped().Delete(&gormModels.OIDCState{}, "id = ?", recievedState.ID)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return gormModels.OIDCState{}, err
+	}
+	return recievedState, nil
+}
+
+func (db Postgres) GetExternalIdentity(
+	ctx context.Context,
+	provider, subject string,
+) (gormModels.ExternalIdentity, error) {
+	var recievedIdentity gormModels.ExternalIdentity
+	if err := db.DB.WithContext(ctx).Where("provider = ? AND subject = ?", provider, subject).
+		First(&recievedIdentity).
+		Error; err != nil {
+		return gormModels.ExternalIdentity{}, err
+	}
+	return recievedIdentity, nil
+}
+
+func (db Postgres) CreateExternalIdentity(
+	ctx context.Context,
+	identity gormModels.ExternalIdentity,
+) error {
+	if err := db.DB.WithContext(ctx).Create(&identity).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+func (db Postgres) UpdateRole(ctx context.Context, userID uint64, role string) error {
+	return db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return updateUser(ctx, tx, userID, map[string]interface{}{"role": role})
+	})
+}
+
+// updateUser changes the given columns and records the change in the audit
+// log of the same transaction.
+func updateUser(
+	ctx context.Context,
+	tx *gorm.DB,
+	userID uint64,
+	columns map[string]interface{},
+) error {
+	var before gormModels.User
+	if err := tx.First(&before, "id = ?", userID).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Model(&gormModels.User{}).
+		Where("id = ?", userID).
+		Updates(columns).
+		Error; err != nil {
+		return err
+	}
+
+	var after gormModels.User
+	if err := tx.First(&after, "id = ?", userID).Error; err != nil {
+		return err
+	}
+	return auditRepository.Record(ctx, tx, userEntity, userID, before, after)
+}
@@ -0,0 +1,143 @@
+package authRepository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/config"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	"golang.org/x/oauth2"
+)
+
+const (
+	defaultUsernameClaim = "preferred_username"
+	defaultGroupsClaim   = "groups"
+	defaultRole          = "user"
+	adminRole            = "admin"
+)
+
+type OIDCProvider struct {
+	settings config.OIDCProviderSettings
+	client   *http.Client
+
+	oauth2   oauth2.Config
+	verifier *oidc.IDTokenVerifier
+}
+
+// NewOIDCProvider reads the discovery document of the issuer. The client is
+// used for every request to the provider, tests pass the one of a local stub.
+func NewOIDCProvider(
+	ctx context.Context,
+	s config.OIDCProviderSettings,
+	client *http.Client,
+) (*OIDCProvider, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	ctx = oidc.ClientContext(ctx, client)
+
+	provider, err := oidc.NewProvider(ctx, s.IssuerURL)
+	if err != nil {
+		return nil, err
+	}
+	slog.InfoContext(ctx, "oidc provider discovered", "provider", s.Name, "issuer", s.IssuerURL)
+
+	if s.UsernameClaim == "" {
+		s.UsernameClaim = defaultUsernameClaim
+	}
+	if s.GroupsClaim == "" {
+		s.GroupsClaim = defaultGroupsClaim
+	}
+	if s.DefaultRole == "" {
+		s.DefaultRole = defaultRole
+	}
+
+	return &OIDCProvider{
+		settings: s,
+		client:   client,
+		oauth2: oauth2.Config{
+			ClientID:     s.ClientID,
+			ClientSecret: s.ClientSecret,
+			RedirectURL:  s.RedirectURL,
+			Endpoint:     provider.Endpoint(),
+			Scopes:       append([]string{oidc.ScopeOpenID}, s.Scopes...),
+		},
+		verifier: provider.Verifier(&oidc.Config{ClientID: s.ClientID}),
+	}, nil
+}
+
+func (p *OIDCProvider) AuthCodeURL(state, nonce, verifier string) string {
+	return p.oauth2.AuthCodeURL(
+		state,
+		oidc.Nonce(nonce),
+		oauth2.S256ChallengeOption(verifier),
+	)
+}
+
+func (p *OIDCProvider) Exchange(
+	ctx context.Context,
+	code, nonce, verifier string,
+) (domain.ExternalIdentity, error) {
+	ctx = oidc.ClientContext(ctx, p.client)
+
+	token, err := p.oauth2.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return domain.ExternalIdentity{}, err
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return domain.ExternalIdentity{}, errors.New("no id_token in token response")
+	}
+
+	idToken, err := p.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return domain.ExternalIdentity{}, err
+	}
+	if idToken.Nonce != nonce {
+		return domain.ExternalIdentity{}, errors.New("id_token nonce mismatch")
+	}
+
+	var claims map[string]interface{}
+	if err = idToken.Claims(&claims); err != nil {
+		return domain.ExternalIdentity{}, err
+	}
+
+	identity := domain.ExternalIdentity{Subject: idToken.Subject}
+	if username, ok := claims[p.settings.UsernameClaim].(string); ok {
+		identity.Username = username
+	}
+	if groups, ok := claims[p.settings.GroupsClaim].([]interface{}); ok {
+		for _, g := range groups {
+			identity.Groups = append(identity.Groups, fmt.Sprint(g))
+		}
+	}
+
+	return identity, nil
+}
+
+// RoleFor maps IdP groups to a local role, admin wins over everything else.
+// Without a configured mapping it returns an empty role, which leaves the
+// role of an existing user untouched.
+func (p *OIDCProvider) RoleFor(groups []string) string {
+	if len(p.settings.RoleMapping) == 0 {
+		return ""
+	}
+
+	role := p.settings.DefaultRole
+	for _, g := range groups {
+		mapped, ok := p.settings.RoleMapping[g]
+		if !ok {
+			continue
+		}
+		if mapped == adminRole {
+			return adminRole
+		}
+		role = mapped
+	}
+	return role
+}
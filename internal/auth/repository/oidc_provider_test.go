@@ -0,0 +1,217 @@
+package authRepository
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/config"
+)
+
+// stubIdP is a minimal OIDC provider: discovery, keys and a token endpoint
+// that checks the PKCE verifier against the challenge of the auth request.
+type stubIdP struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	challenge string
+	nonce     string
+	claims    map[string]interface{}
+}
+
+func newStubIdP(t *testing.T) *stubIdP {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	idp := &stubIdP{key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", idp.discovery)
+	mux.HandleFunc("GET /keys", idp.keys)
+	mux.HandleFunc("POST /token", idp.token)
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+
+	return idp
+}
+
+func (idp *stubIdP) discovery(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"issuer":                                idp.server.URL,
+		"authorization_endpoint":                idp.server.URL + "/auth",
+		"token_endpoint":                        idp.server.URL + "/token",
+		"jwks_uri":                              idp.server.URL + "/keys",
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+	})
+}
+
+func (idp *stubIdP) keys(w http.ResponseWriter, r *http.Request) {
+	encode := base64.RawURLEncoding.EncodeToString
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"alg": "RS256",
+			"use": "sig",
+			"kid": "test",
+			"n":   encode(idp.key.N.Bytes()),
+			"e":   encode(big.NewInt(int64(idp.key.E)).Bytes()),
+		}},
+	})
+}
+
+func (idp *stubIdP) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	sum := sha256.Sum256([]byte(r.Form.Get("code_verifier")))
+	if r.Form.Get("code") != "good-code" ||
+		base64.RawURLEncoding.EncodeToString(sum[:]) != idp.challenge {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"invalid_grant"}`))
+		return
+	}
+
+	claims := map[string]interface{}{
+		"iss":   idp.server.URL,
+		"aud":   "film-library",
+		"sub":   "42",
+		"nonce": idp.nonce,
+		"iat":   time.Now().Unix(),
+		"exp":   time.Now().Add(time.Minute).Unix(),
+	}
+	for k, v := range idp.claims {
+		claims[k] = v
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"access_token": "access",
+		"token_type":   "Bearer",
+		"expires_in":   60,
+		"id_token":     idp.sign(claims),
+	})
+}
+
+func (idp *stubIdP) sign(claims map[string]interface{}) string {
+	encode := base64.RawURLEncoding.EncodeToString
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": "test"})
+	payload, _ := json.Marshal(claims)
+
+	signed := encode(header) + "." + encode(payload)
+	sum := sha256.Sum256([]byte(signed))
+	signature, _ := rsa.SignPKCS1v15(rand.Reader, idp.key, crypto.SHA256, sum[:])
+
+	return signed + "." + encode(signature)
+}
+
+// authorize plays the browser part: it reads the PKCE challenge and nonce
+// from the URL the user would be redirected to.
+func (idp *stubIdP) authorize(t *testing.T, authURL string) {
+	u, err := url.Parse(authURL)
+	require.NoError(t, err)
+
+	query := u.Query()
+	assert.Equal(t, idp.server.URL+"/auth", u.Scheme+"://"+u.Host+u.Path)
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+	assert.Equal(t, "openid profile", query.Get("scope"))
+
+	idp.challenge = query.Get("code_challenge")
+	idp.nonce = query.Get("nonce")
+}
+
+func TestOIDCProvider_Exchange(t *testing.T) {
+	idp := newStubIdP(t)
+	idp.claims = map[string]interface{}{
+		"preferred_username": "jane",
+		"groups":             []string{"staff", "film-admins"},
+	}
+
+	settings := config.OIDCProviderSettings{
+		Name:        "corp",
+		IssuerURL:   idp.server.URL,
+		ClientID:    "film-library",
+		RedirectURL: "http://localhost:8080/api/v1/oidc/corp/callback",
+		Scopes:      []string{"profile"},
+	}
+
+	ctx := context.Background()
+	provider, err := NewOIDCProvider(ctx, settings, idp.server.Client())
+	require.NoError(t, err)
+
+	idp.authorize(t, provider.AuthCodeURL("state", "nonce-1", "verifier-0123456789-0123456789-0123456789"))
+	assert.Equal(t, "nonce-1", idp.nonce)
+
+	identity, err := provider.Exchange(ctx, "good-code", "nonce-1", "verifier-0123456789-0123456789-0123456789")
+	require.NoError(t, err)
+	assert.Equal(t, "42", identity.Subject)
+	assert.Equal(t, "jane", identity.Username)
+	assert.Equal(t, []string{"staff", "film-admins"}, identity.Groups)
+
+	// PKCE verifier of another flow
+	_, err = provider.Exchange(ctx, "good-code", "nonce-1", "verifier-9876543210-9876543210-9876543210")
+	assert.Error(t, err)
+
+	// replayed id_token of another login
+	_, err = provider.Exchange(ctx, "good-code", "nonce-2", "verifier-0123456789-0123456789-0123456789")
+	assert.Error(t, err)
+}
+
+func TestOIDCProvider_RoleFor(t *testing.T) {
+	idp := newStubIdP(t)
+
+	tests := []struct {
+		name     string
+		mapping  map[string]string
+		groups   []string
+		expected string
+	}{
+		{
+			name:     "No mapping keeps the role",
+			groups:   []string{"film-admins"},
+			expected: "",
+		},
+		{
+			name:     "Admin wins",
+			mapping:  map[string]string{"film-admins": "admin", "staff": "user"},
+			groups:   []string{"film-admins", "staff"},
+			expected: "admin",
+		},
+		{
+			name:     "Unmapped groups get the default role",
+			mapping:  map[string]string{"film-admins": "admin"},
+			groups:   []string{"staff"},
+			expected: "user",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, err := NewOIDCProvider(
+				context.Background(),
+				config.OIDCProviderSettings{
+					IssuerURL:   idp.server.URL,
+					ClientID:    "film-library",
+					RoleMapping: tt.mapping,
+				},
+				idp.server.Client(),
+			)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected, provider.RoleFor(tt.groups))
+		})
+	}
+}
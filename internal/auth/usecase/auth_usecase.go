@@ -1,7 +1,12 @@
 package authUsecase
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"log/slog"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,6 +15,7 @@ import (
 	gormModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/gorm"
 	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
 	password "github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/hash"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/metrics"
 	"gorm.io/gorm"
 )
 
@@ -18,27 +24,73 @@ type (
 	cookieCreator func(userID uint64, c config.CookieSettings) gormModels.Session
 )
 
+const tokenSize = 32
+
 type AuthUsecase struct {
 	authRepository domain.AuthRepository
+	notifier       domain.Notifier
+	providers      map[string]domain.IdentityProvider
 
 	cookieSettings config.CookieSettings
+	resetSettings  config.PasswordResetSettings
+	loginGuard     config.LoginGuardSettings
+	twoFactor      config.TwoFactorSettings
+	oidcSettings   config.OIDCSettings
+	policy         password.Policy
 	cookieCreator  cookieCreator
 	hashCreator    hashCreator
 }
 
-func NewAuthUsecase(a domain.AuthRepository, c config.CookieSettings, h hashCreator) AuthUsecase {
+func NewAuthUsecase(
+	a domain.AuthRepository,
+	n domain.Notifier,
+	ip map[string]domain.IdentityProvider,
+	c config.CookieSettings,
+	r config.PasswordResetSettings,
+	l config.LoginGuardSettings,
+	tf config.TwoFactorSettings,
+	o config.OIDCSettings,
+	p password.Policy,
+	h hashCreator,
+) AuthUsecase {
 	return AuthUsecase{
 		authRepository: a,
+		notifier:       n,
+		providers:      ip,
 		cookieSettings: c,
+		resetSettings:  r,
+		loginGuard:     l,
+		twoFactor:      tf,
+		oidcSettings:   o,
+		policy:         p,
 		cookieCreator:  generateCookie,
 		hashCreator:    h,
 	}
 }
 
-func NewCustomAuthUsecase(a domain.AuthRepository, c config.CookieSettings, h hashCreator, cc cookieCreator) AuthUsecase {
+func NewCustomAuthUsecase(
+	a domain.AuthRepository,
+	n domain.Notifier,
+	ip map[string]domain.IdentityProvider,
+	c config.CookieSettings,
+	r config.PasswordResetSettings,
+	l config.LoginGuardSettings,
+	tf config.TwoFactorSettings,
+	o config.OIDCSettings,
+	p password.Policy,
+	h hashCreator,
+	cc cookieCreator,
+) AuthUsecase {
 	return AuthUsecase{
 		authRepository: a,
+		notifier:       n,
+		providers:      ip,
 		cookieSettings: c,
+		resetSettings:  r,
+		loginGuard:     l,
+		twoFactor:      tf,
+		oidcSettings:   o,
+		policy:         p,
 		cookieCreator:  cc,
 		hashCreator:    h,
 	}
@@ -56,76 +108,263 @@ func generateCookie(userID uint64, c config.CookieSettings) gormModels.Session {
 	}
 }
 
-func (u AuthUsecase) SignUp(user httpModels.AuthUser) (string, uint64, error) {
+func generateToken() (string, error) {
+	b := make([]byte, tokenSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// hashToken is what gets stored in the database, so a leaked table
+// doesn't hand out working reset links or login challenges.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (u AuthUsecase) SignUp(ctx context.Context, user httpModels.AuthUser) (string, uint64, error) {
+	violations := append(
+		u.policy.ValidateUsername("username", user.Username),
+		u.policy.ValidatePassword("password", user.Password, user.Username)...,
+	)
+	if len(violations) != 0 {
+		return "", 0, domain.ValidationError{Violations: violations}
+	}
+
 	hash, err := u.hashCreator(user.Password)
 	if err != nil {
 		return "", 0, err
 	}
 
-	userID, err := u.authRepository.CreateUser(gormModels.User{
+	userID, err := u.authRepository.CreateUser(ctx, gormModels.User{
 		Username: user.Username,
 		Password: hash,
 		Role:     user.Role,
 	})
 	if err != nil {
-		if errors.Is(err, gorm.ErrDuplicatedKey) {
+		// pgerrors makes unique violations ErrAlreadyExists.
+		if errors.Is(err, domain.ErrAlreadyExists) {
 			return "", 0, domain.ErrUserAlreadyExist
 		} else {
 			return "", 0, err
 		}
 	}
 
-	sessionID, err := u.authRepository.CreateSession(u.cookieCreator(userID, u.cookieSettings))
+	sessionID, err := u.authRepository.CreateSession(ctx, u.cookieCreator(userID, u.cookieSettings))
 	if err != nil {
 		return "", 0, err
 	}
 
+	slog.InfoContext(ctx, "user signed up", "user_id", userID, "username", user.Username)
 	return sessionID, userID, nil
 }
 
-func (u AuthUsecase) Login(user httpModels.AuthUser) (string, uint64, error) {
-	recUser, err := u.authRepository.GetUserByUsername(user.Username)
+func (u AuthUsecase) Login(
+	ctx context.Context,
+	user httpModels.AuthUser,
+	ip string,
+) (string, uint64, error) {
+	sessionID, userID, err := u.login(ctx, user, ip)
+	metrics.Login("password", err)
+	return sessionID, userID, err
+}
+
+func (u AuthUsecase) login(
+	ctx context.Context,
+	user httpModels.AuthUser,
+	ip string,
+) (string, uint64, error) {
+	keys := loginAttemptKeys(user.Username, ip)
+	if err := u.checkLoginBlocked(ctx, keys); err != nil {
+		return "", 0, err
+	}
+
+	recUser, err := u.authRepository.GetUserByUsername(ctx, user.Username)
 	if err != nil {
-		switch err.Error() {
-		case gorm.ErrRecordNotFound.Error():
-			return "", 0, domain.ErrNotFound
-		default:
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
 			return "", 0, domain.ErrInternal
 		}
+		password.CheckHashPassword(user.Password, dummyHash())
+		return "", 0, u.loginFailed(ctx, keys)
+	}
+
+	if !password.CheckHashPassword(user.Password, recUser.Password) {
+		return "", 0, u.loginFailed(ctx, keys)
 	}
 
-	matchPassword := password.CheckHashPassword(user.Password, recUser.Password)
+	if u.policy.NeedsRehash(recUser.Password) {
+		u.rehashPassword(ctx, recUser.ID, user.Password)
+	}
 
-	if !matchPassword {
-		return "", 0, domain.ErrPasswordsNotEqual
+	// The failures are only forgotten once the second factor is right too,
+	// otherwise the password would buy unlimited challenges to guess codes.
+	if recUser.TOTPEnabled {
+		return "", 0, u.startTwoFactor(ctx, recUser.ID, ip)
 	}
 
-	sessionID, err := u.authRepository.CreateSession(u.cookieCreator(recUser.ID, u.cookieSettings))
+	if err = u.loginSucceeded(ctx, keys); err != nil {
+		return "", 0, err
+	}
+
+	sessionID, err := u.authRepository.CreateSession(ctx, u.cookieCreator(recUser.ID, u.cookieSettings))
 	if err != nil {
 		return "", 0, domain.ErrInternal
 	}
+	slog.InfoContext(ctx, "user logged in", "user_id", recUser.ID)
 	return sessionID, recUser.ID, nil
 }
 
-func (u AuthUsecase) Logout(sessionID string) error {
-	return u.authRepository.DeleteBySessionID(sessionID)
+// rehashPassword upgrades the stored hash to the configured bcrypt cost. It
+// is best effort: the user is already authenticated, so a failure here must
+// not break the login.
+func (u AuthUsecase) rehashPassword(ctx context.Context, userID uint64, plain string) {
+	hash, err := u.hashCreator(plain)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to rehash password", "user_id", userID, "error", err)
+		return
+	}
+	if err = u.authRepository.UpdatePassword(ctx, userID, hash); err != nil {
+		slog.ErrorContext(ctx, "failed to rehash password", "user_id", userID, "error", err)
+	}
+}
+
+func (u AuthUsecase) Logout(ctx context.Context, sessionID string) error {
+	if err := u.authRepository.DeleteBySessionID(ctx, sessionID); err != nil {
+		return err
+	}
+	slog.InfoContext(ctx, "user logged out")
+	return nil
 }
 
-func (u AuthUsecase) Auth(sessionID string) (uint64, error) {
-	user, err := u.authRepository.GetUserBySessionID(sessionID)
+func (u AuthUsecase) Auth(ctx context.Context, sessionID string) (uint64, error) {
+	user, err := u.authRepository.GetUserBySessionID(ctx, sessionID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return 0, domain.ErrNotFound
+			return 0, domain.ErrNoSession
 		}
 		return 0, err
 	}
 	return user.ID, nil
 }
 
-func (u AuthUsecase) GetUserBySessionID(sessionID string) (httpModels.AuthUser, error) {
-	recievedUser, err := u.authRepository.GetUserBySessionID(sessionID)
+func (u AuthUsecase) GetUserBySessionID(
+	ctx context.Context,
+	sessionID string,
+) (httpModels.AuthUser, error) {
+	recievedUser, err := u.authRepository.GetUserBySessionID(ctx, sessionID)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return httpModels.AuthUser{}, domain.ErrNoSession
+		}
 		return httpModels.AuthUser{}, err
 	}
 	return recievedUser.ToHTTPModel(), nil
 }
+
+func (u AuthUsecase) ChangePassword(
+	ctx context.Context,
+	sessionID string,
+	passwords httpModels.ChangePassword,
+) error {
+	user, err := u.authRepository.GetUserBySessionID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.ErrNoSession
+		}
+		return err
+	}
+
+	if !password.CheckHashPassword(passwords.OldPassword, user.Password) {
+		return domain.ErrPasswordsNotEqual
+	}
+
+	violations := u.policy.ValidatePassword("newPassword", passwords.NewPassword, user.Username)
+	if len(violations) != 0 {
+		return domain.ValidationError{Violations: violations}
+	}
+
+	hash, err := u.hashCreator(passwords.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	if err = u.authRepository.UpdatePassword(ctx, user.ID, hash); err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "password changed", "user_id", user.ID)
+	return u.authRepository.DeleteUserSessions(ctx, user.ID, sessionID)
+}
+
+func (u AuthUsecase) RequestPasswordReset(ctx context.Context, username string) error {
+	user, err := u.authRepository.GetUserByUsername(ctx, username)
+	if err != nil {
+		// Unknown usernames are not reported to the caller, otherwise the
+		// endpoint could be used to enumerate accounts.
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	token, err := generateToken()
+	if err != nil {
+		return err
+	}
+
+	if err = u.authRepository.CreateResetToken(ctx, gormModels.PasswordResetToken{
+		UserID:     user.ID,
+		TokenHash:  hashToken(token),
+		ExpireDate: time.Now().Add(u.resetSettings.TokenTTL),
+	}); err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "password reset requested", "user_id", user.ID)
+	return u.notifier.SendPasswordReset(user.Username, token)
+}
+
+func (u AuthUsecase) ConfirmPasswordReset(
+	ctx context.Context,
+	confirm httpModels.PasswordResetConfirm,
+) error {
+	token, err := u.authRepository.GetResetToken(ctx, hashToken(confirm.Token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.ErrResetToken
+		}
+		return err
+	}
+
+	if token.Used || time.Now().After(token.ExpireDate) {
+		return domain.ErrResetToken
+	}
+
+	user, err := u.authRepository.GetUserByID(ctx, token.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.ErrResetToken
+		}
+		return err
+	}
+
+	violations := u.policy.ValidatePassword("newPassword", confirm.NewPassword, user.Username)
+	if len(violations) != 0 {
+		return domain.ValidationError{Violations: violations}
+	}
+
+	hash, err := u.hashCreator(confirm.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	if err = u.authRepository.ResetPassword(ctx, token.ID, token.UserID, hash); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.ErrResetToken
+		}
+		return err
+	}
+	slog.InfoContext(ctx, "password reset", "user_id", token.UserID)
+	return nil
+}
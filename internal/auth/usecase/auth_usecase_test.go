@@ -1,8 +1,11 @@
 package authUsecase
 
 import (
+	"context"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/config"
@@ -11,7 +14,9 @@ import (
 	gormModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/gorm"
 	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
 	password "github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/hash"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/totp"
 	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
 )
 
 func TestUsecase_SignUp(t *testing.T) {
@@ -36,18 +41,33 @@ func TestUsecase_SignUp(t *testing.T) {
 			},
 			mockBehavior: func(m *mockRepository.MockAuthRepository, user gormModels.User) {
 				m.EXPECT().
-					CreateUser(user).
+					CreateUser(gomock.Any(), user).
 					Return(uint64(1), nil)
 			},
 			mockBehaviorSession: func(m *mockRepository.MockAuthRepository, session gormModels.Session) {
 				m.EXPECT().
-					CreateSession(session).
+					CreateSession(gomock.Any(), session).
 					Return("cc982d23-e3e0-439e-a8b7-7fff4eeeb2f2", nil)
 			},
 			expectedSessionID: "cc982d23-e3e0-439e-a8b7-7fff4eeeb2f2",
 			expectedUserID:    uint64(1),
 			expectedError:     nil,
 		},
+		{
+			name: "Username taken",
+			inputUser: httpModels.AuthUser{
+				Username: "Jane",
+				Password: "123",
+				Role:     "admin",
+			},
+			mockBehavior: func(m *mockRepository.MockAuthRepository, user gormModels.User) {
+				m.EXPECT().
+					CreateUser(gomock.Any(), user).
+					Return(uint64(0), domain.ErrAlreadyExists.Wrap(errors.New("idx_users_username")))
+			},
+			mockBehaviorSession: func(m *mockRepository.MockAuthRepository, session gormModels.Session) {},
+			expectedError:       domain.ErrUserAlreadyExist,
+		},
 	}
 
 	for _, tt := range tests {
@@ -57,7 +77,16 @@ func TestUsecase_SignUp(t *testing.T) {
 
 			mockRepo := mockRepository.NewMockAuthRepository(ctrl)
 
-			u := NewCustomAuthUsecase(mockRepo, config.CookieSettings{},
+			u := NewCustomAuthUsecase(
+				mockRepo,
+				nil,
+				nil,
+				config.CookieSettings{},
+				config.PasswordResetSettings{},
+				config.LoginGuardSettings{},
+				config.TwoFactorSettings{},
+				config.OIDCSettings{},
+				password.Policy{},
 				func(password string) (string, error) {
 					if len(password) == 0 {
 						return "", domain.ErrInternal
@@ -81,7 +110,7 @@ func TestUsecase_SignUp(t *testing.T) {
 			tt.mockBehavior(mockRepo, user)
 			tt.mockBehaviorSession(mockRepo, u.cookieCreator(1, config.CookieSettings{}))
 
-			sessionID, userID, err := u.SignUp(tt.inputUser)
+			sessionID, userID, err := u.SignUp(context.Background(), tt.inputUser)
 			assert.Equal(t, tt.expectedSessionID, sessionID)
 			assert.Equal(t, tt.expectedUserID, userID)
 			assert.Equal(t, tt.expectedError, err)
@@ -111,7 +140,16 @@ func TestUsecase_Login(t *testing.T) {
 			mockBehaviorGetUser: func(m *mockRepository.MockAuthRepository, username string) {
 				hashedPassword, _ := password.HashPassword("123") // Хешируем пароль
 				m.EXPECT().
-					GetUserByUsername(username).
+					GetLoginAttempts(gomock.Any(), []string{"user:" + username, "ip:10.0.0.1"}).
+					Return(nil, nil)
+				m.EXPECT().
+					ResetLoginFailures(gomock.Any(), "user:"+username).
+					Return(nil)
+				m.EXPECT().
+					ResetLoginFailures(gomock.Any(), "ip:10.0.0.1").
+					Return(nil)
+				m.EXPECT().
+					GetUserByUsername(gomock.Any(), username).
 					Return(gormModels.User{
 						ID:       1,
 						Username: "Jane",
@@ -121,7 +159,7 @@ func TestUsecase_Login(t *testing.T) {
 			},
 			mockBehaviorSession: func(m *mockRepository.MockAuthRepository, session gormModels.Session) {
 				m.EXPECT().
-					CreateSession(session).
+					CreateSession(gomock.Any(), session).
 					Return("cc982d23-e3e0-439e-a8b7-7fff4eeeb2f2", nil)
 			},
 			expectedSessionID: "cc982d23-e3e0-439e-a8b7-7fff4eeeb2f2",
@@ -136,13 +174,87 @@ func TestUsecase_Login(t *testing.T) {
 			},
 			mockBehaviorGetUser: func(m *mockRepository.MockAuthRepository, username string) {
 				m.EXPECT().
-					GetUserByUsername(username).
+					GetLoginAttempts(gomock.Any(), []string{"user:" + username, "ip:10.0.0.1"}).
+					Return(nil, nil)
+				m.EXPECT().
+					GetUserByUsername(gomock.Any(), username).
 					Return(gormModels.User{}, errors.New("empty password"))
 			},
 			mockBehaviorSession: func(m *mockRepository.MockAuthRepository, session gormModels.Session) {},
 			expectedSessionID:   "",
 			expectedUserID:      uint64(0),
-			expectedError:       errors.New("server error"),
+			expectedError:       domain.ErrInternal,
+		},
+		{
+			name: "Unknown user",
+			inputUser: httpModels.AuthUser{
+				Username: "Jane",
+				Password: "123",
+			},
+			mockBehaviorGetUser: func(m *mockRepository.MockAuthRepository, username string) {
+				m.EXPECT().
+					GetLoginAttempts(gomock.Any(), []string{"user:" + username, "ip:10.0.0.1"}).
+					Return(nil, nil)
+				m.EXPECT().
+					GetUserByUsername(gomock.Any(), username).
+					Return(gormModels.User{}, gorm.ErrRecordNotFound)
+				m.EXPECT().
+					RegisterLoginFailure(gomock.Any(), "user:"+username, gomock.Any()).
+					Return(gormModels.LoginAttempt{Failures: 1}, nil)
+				m.EXPECT().
+					RegisterLoginFailure(gomock.Any(), "ip:10.0.0.1", gomock.Any()).
+					Return(gormModels.LoginAttempt{Failures: 1}, nil)
+			},
+			mockBehaviorSession: func(m *mockRepository.MockAuthRepository, session gormModels.Session) {},
+			expectedSessionID:   "",
+			expectedUserID:      uint64(0),
+			expectedError:       domain.ErrInvalidLoginOrPassword,
+		},
+		{
+			name: "Wrong password",
+			inputUser: httpModels.AuthUser{
+				Username: "Jane",
+				Password: "321",
+			},
+			mockBehaviorGetUser: func(m *mockRepository.MockAuthRepository, username string) {
+				hashedPassword, _ := password.HashPassword("123")
+				m.EXPECT().
+					GetLoginAttempts(gomock.Any(), []string{"user:" + username, "ip:10.0.0.1"}).
+					Return(nil, nil)
+				m.EXPECT().
+					GetUserByUsername(gomock.Any(), username).
+					Return(gormModels.User{ID: 1, Username: "Jane", Password: hashedPassword}, nil)
+				m.EXPECT().
+					RegisterLoginFailure(gomock.Any(), "user:"+username, gomock.Any()).
+					Return(gormModels.LoginAttempt{Failures: 1}, nil)
+				m.EXPECT().
+					RegisterLoginFailure(gomock.Any(), "ip:10.0.0.1", gomock.Any()).
+					Return(gormModels.LoginAttempt{Failures: 1}, nil)
+			},
+			mockBehaviorSession: func(m *mockRepository.MockAuthRepository, session gormModels.Session) {},
+			expectedSessionID:   "",
+			expectedUserID:      uint64(0),
+			expectedError:       domain.ErrInvalidLoginOrPassword,
+		},
+		{
+			name: "Blocked",
+			inputUser: httpModels.AuthUser{
+				Username: "Jane",
+				Password: "123",
+			},
+			mockBehaviorGetUser: func(m *mockRepository.MockAuthRepository, username string) {
+				m.EXPECT().
+					GetLoginAttempts(gomock.Any(), []string{"user:" + username, "ip:10.0.0.1"}).
+					Return([]gormModels.LoginAttempt{{
+						Key:          "ip:10.0.0.1",
+						Failures:     10,
+						BlockedUntil: time.Now().Add(time.Minute),
+					}}, nil)
+			},
+			mockBehaviorSession: func(m *mockRepository.MockAuthRepository, session gormModels.Session) {},
+			expectedSessionID:   "",
+			expectedUserID:      uint64(0),
+			expectedError:       domain.ErrTooManyAttempts,
 		},
 	}
 
@@ -153,7 +265,16 @@ func TestUsecase_Login(t *testing.T) {
 
 			mockRepo := mockRepository.NewMockAuthRepository(ctrl)
 
-			u := NewCustomAuthUsecase(mockRepo, config.CookieSettings{},
+			u := NewCustomAuthUsecase(
+				mockRepo,
+				nil,
+				nil,
+				config.CookieSettings{},
+				config.PasswordResetSettings{},
+				config.LoginGuardSettings{},
+				config.TwoFactorSettings{},
+				config.OIDCSettings{},
+				password.Policy{},
 				func(password string) (string, error) {
 					if len(password) == 0 {
 						return "", domain.ErrInternal
@@ -171,7 +292,7 @@ func TestUsecase_Login(t *testing.T) {
 			tt.mockBehaviorGetUser(mockRepo, tt.inputUser.Username)
 			tt.mockBehaviorSession(mockRepo, u.cookieCreator(1, config.CookieSettings{}))
 
-			sessionID, userID, err := u.Login(tt.inputUser)
+			sessionID, userID, err := u.Login(context.Background(), tt.inputUser, "10.0.0.1")
 			assert.Equal(t, tt.expectedSessionID, sessionID)
 			assert.Equal(t, tt.expectedUserID, userID)
 			assert.Equal(t, tt.expectedError, err)
@@ -194,7 +315,7 @@ func TestUsecase_Auth(t *testing.T) {
 			sessionID: "cc982d23-e3e0-439e-a8b7-7fff4eeeb2f2",
 			mockBehaviorSession: func(m *mockRepository.MockAuthRepository, session gormModels.Session) {
 				m.EXPECT().
-					GetUserBySessionID(session.SessionID).
+					GetUserBySessionID(gomock.Any(), session.SessionID).
 					Return(gormModels.User{
 						ID:       1,
 						Username: "Jane",
@@ -210,7 +331,7 @@ func TestUsecase_Auth(t *testing.T) {
 			sessionID:           "cc982d23-e3e0-439e-a8b7-7fff4eeeb2f2",
 			mockBehaviorSession: func(m *mockRepository.MockAuthRepository, session gormModels.Session) {
 				m.EXPECT().
-					GetUserBySessionID(session.SessionID).
+					GetUserBySessionID(gomock.Any(), session.SessionID).
 					Return(gormModels.User{}, errors.New("session not found"))
 			},
 			expectedUserID:      uint64(0),
@@ -225,7 +346,16 @@ func TestUsecase_Auth(t *testing.T) {
 
 			mockRepo := mockRepository.NewMockAuthRepository(ctrl)
 
-			u := NewCustomAuthUsecase(mockRepo, config.CookieSettings{},
+			u := NewCustomAuthUsecase(
+				mockRepo,
+				nil,
+				nil,
+				config.CookieSettings{},
+				config.PasswordResetSettings{},
+				config.LoginGuardSettings{},
+				config.TwoFactorSettings{},
+				config.OIDCSettings{},
+				password.Policy{},
 				func(password string) (string, error) {
 					if len(password) == 0 {
 						return "", domain.ErrInternal
@@ -245,9 +375,836 @@ func TestUsecase_Auth(t *testing.T) {
 				SessionID: "cc982d23-e3e0-439e-a8b7-7fff4eeeb2f2",
 			})
 
-			userID, err := u.Auth(tt.sessionID)
+			userID, err := u.Auth(context.Background(), tt.sessionID)
 			assert.Equal(t, tt.expectedUserID, userID)
 			assert.Equal(t, tt.expectedError, err)
 		})
 	}
 }
+
+func TestUsecase_ChangePassword(t *testing.T) {
+	type mockBehavior func(r *mockRepository.MockAuthRepository, sessionID string)
+
+	oldHash, _ := password.HashPassword("old")
+
+	tests := []struct {
+		name          string
+		sessionID     string
+		passwords     httpModels.ChangePassword
+		mockBehavior  mockBehavior
+		expectedError error
+	}{
+		{
+			name:      "Successful change",
+			sessionID: "cc982d23-e3e0-439e-a8b7-7fff4eeeb2f2",
+			passwords: httpModels.ChangePassword{
+				OldPassword: "old",
+				NewPassword: "new",
+			},
+			mockBehavior: func(m *mockRepository.MockAuthRepository, sessionID string) {
+				m.EXPECT().
+					GetUserBySessionID(gomock.Any(), sessionID).
+					Return(gormModels.User{ID: 1, Username: "Jane", Password: oldHash}, nil)
+				m.EXPECT().
+					UpdatePassword(gomock.Any(), uint64(1), "new").
+					Return(nil)
+				m.EXPECT().
+					DeleteUserSessions(gomock.Any(), uint64(1), sessionID).
+					Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:      "Wrong old password",
+			sessionID: "cc982d23-e3e0-439e-a8b7-7fff4eeeb2f2",
+			passwords: httpModels.ChangePassword{
+				OldPassword: "wrong",
+				NewPassword: "new",
+			},
+			mockBehavior: func(m *mockRepository.MockAuthRepository, sessionID string) {
+				m.EXPECT().
+					GetUserBySessionID(gomock.Any(), sessionID).
+					Return(gormModels.User{ID: 1, Username: "Jane", Password: oldHash}, nil)
+			},
+			expectedError: domain.ErrPasswordsNotEqual,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mockRepository.NewMockAuthRepository(ctrl)
+
+			u := NewCustomAuthUsecase(
+				mockRepo,
+				nil,
+				nil,
+				config.CookieSettings{},
+				config.PasswordResetSettings{},
+				config.LoginGuardSettings{},
+				config.TwoFactorSettings{},
+				config.OIDCSettings{},
+				password.Policy{},
+				func(password string) (string, error) {
+					return password, nil
+				},
+				generateCookie,
+			)
+
+			tt.mockBehavior(mockRepo, tt.sessionID)
+
+			err := u.ChangePassword(context.Background(), tt.sessionID, tt.passwords)
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestUsecase_PasswordReset(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mockRepository.NewMockAuthRepository(ctrl)
+	mockNotifier := mockRepository.NewMockNotifier(ctrl)
+
+	u := NewCustomAuthUsecase(mockRepo, mockNotifier, nil, config.CookieSettings{},
+		config.PasswordResetSettings{TokenTTL: time.Hour},
+		config.LoginGuardSettings{},
+		config.TwoFactorSettings{},
+		config.OIDCSettings{},
+		password.Policy{},
+		func(password string) (string, error) {
+			return password, nil
+		},
+		generateCookie,
+	)
+
+	var storedToken gormModels.PasswordResetToken
+	var sentToken string
+
+	mockRepo.EXPECT().
+		GetUserByUsername(gomock.Any(), "Jane").
+		Return(gormModels.User{ID: 1, Username: "Jane"}, nil)
+	mockRepo.EXPECT().
+		CreateResetToken(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, token gormModels.PasswordResetToken) error {
+			storedToken = token
+			return nil
+		})
+	mockNotifier.EXPECT().
+		SendPasswordReset("Jane", gomock.Any()).
+		DoAndReturn(func(username, token string) error {
+			sentToken = token
+			return nil
+		})
+
+	assert.NoError(t, u.RequestPasswordReset(context.Background(), "Jane"))
+	assert.Equal(t, uint64(1), storedToken.UserID)
+	assert.NotEqual(t, sentToken, storedToken.TokenHash)
+	assert.Equal(t, hashToken(sentToken), storedToken.TokenHash)
+
+	storedToken.ID = 7
+	mockRepo.EXPECT().
+		GetResetToken(gomock.Any(), storedToken.TokenHash).
+		Return(storedToken, nil)
+	mockRepo.EXPECT().
+		GetUserByID(gomock.Any(), uint64(1)).
+		Return(gormModels.User{ID: 1, Username: "Jane"}, nil)
+	mockRepo.EXPECT().
+		ResetPassword(gomock.Any(), uint64(7), uint64(1), "new").
+		Return(nil)
+
+	assert.NoError(t, u.ConfirmPasswordReset(context.Background(), httpModels.PasswordResetConfirm{
+		Token:       sentToken,
+		NewPassword: "new",
+	}))
+
+	storedToken.Used = true
+	mockRepo.EXPECT().
+		GetResetToken(gomock.Any(), storedToken.TokenHash).
+		Return(storedToken, nil)
+
+	assert.Equal(t, domain.ErrResetToken, u.ConfirmPasswordReset(context.Background(), httpModels.PasswordResetConfirm{
+		Token:       sentToken,
+		NewPassword: "other",
+	}))
+}
+
+func TestUsecase_PasswordResetRejections(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mockRepository.NewMockAuthRepository(ctrl)
+	mockNotifier := mockRepository.NewMockNotifier(ctrl)
+
+	u := NewCustomAuthUsecase(mockRepo, mockNotifier, nil, config.CookieSettings{},
+		config.PasswordResetSettings{TokenTTL: time.Hour},
+		config.LoginGuardSettings{},
+		config.TwoFactorSettings{},
+		config.OIDCSettings{},
+		password.Policy{},
+		func(password string) (string, error) {
+			return password, nil
+		},
+		generateCookie,
+	)
+
+	mockRepo.EXPECT().
+		GetUserByUsername(gomock.Any(), "Ghost").
+		Return(gormModels.User{}, gorm.ErrRecordNotFound)
+
+	assert.NoError(t, u.RequestPasswordReset(context.Background(), "Ghost"))
+
+	mockRepo.EXPECT().
+		GetResetToken(gomock.Any(), gomock.Any()).
+		Return(gormModels.PasswordResetToken{
+			ID:         1,
+			UserID:     1,
+			ExpireDate: time.Now().Add(-time.Minute),
+		}, nil)
+
+	assert.Equal(t, domain.ErrResetToken, u.ConfirmPasswordReset(context.Background(), httpModels.PasswordResetConfirm{
+		Token:       "expired",
+		NewPassword: "new",
+	}))
+
+	mockRepo.EXPECT().
+		GetResetToken(gomock.Any(), gomock.Any()).
+		Return(gormModels.PasswordResetToken{
+			ID:         2,
+			UserID:     1,
+			ExpireDate: time.Now().Add(time.Minute),
+		}, nil)
+	mockRepo.EXPECT().
+		GetUserByID(gomock.Any(), uint64(1)).
+		Return(gormModels.User{ID: 1, Username: "Jane"}, nil)
+
+	assert.Equal(t, domain.ValidationError{Violations: []domain.FieldError{
+		{Field: "newPassword", Rule: "not_username", Message: "must differ from the username"},
+	}}, u.ConfirmPasswordReset(context.Background(), httpModels.PasswordResetConfirm{
+		Token:       "username",
+		NewPassword: "jane",
+	}))
+}
+
+func TestUsecase_LoginDelay(t *testing.T) {
+	u := AuthUsecase{
+		loginGuard: config.LoginGuardSettings{
+			MaxFailures:     5,
+			BaseDelay:       time.Second,
+			MaxDelay:        5 * time.Second,
+			LockoutDuration: time.Hour,
+		},
+	}
+
+	tests := []struct {
+		failures      uint64
+		expectedDelay time.Duration
+	}{
+		{failures: 0, expectedDelay: 0},
+		{failures: 1, expectedDelay: time.Second},
+		{failures: 2, expectedDelay: 2 * time.Second},
+		{failures: 3, expectedDelay: 4 * time.Second},
+		{failures: 4, expectedDelay: 5 * time.Second},
+		{failures: 5, expectedDelay: time.Hour},
+		{failures: 100, expectedDelay: time.Hour},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expectedDelay, u.loginDelay(tt.failures))
+	}
+}
+
+func TestUsecase_SignUpPolicy(t *testing.T) {
+	policy, err := password.NewPolicy(config.CredentialsSettings{
+		PasswordMinLength:     8,
+		PasswordMaxLength:     72,
+		RequireLower:          true,
+		RequireUpper:          true,
+		RequireDigit:          true,
+		RejectCommonPasswords: true,
+		UsernameMinLength:     3,
+		UsernameMaxLength:     32,
+		UsernamePattern:       `^[A-Za-z0-9_.-]+$`,
+	})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		inputUser     httpModels.AuthUser
+		expectedRules []string
+	}{
+		{
+			name:          "Bad username and short password",
+			inputUser:     httpModels.AuthUser{Username: "J!", Password: "abc"},
+			expectedRules: []string{"min_length", "pattern", "min_length", "uppercase", "digit"},
+		},
+		{
+			name:          "Common password",
+			inputUser:     httpModels.AuthUser{Username: "Jane", Password: "password"},
+			expectedRules: []string{"uppercase", "digit", "common"},
+		},
+		{
+			name:          "Password equal to username",
+			inputUser:     httpModels.AuthUser{Username: "JaneDoe123", Password: "janedoe123"},
+			expectedRules: []string{"uppercase", "not_username"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mockRepository.NewMockAuthRepository(ctrl)
+
+			u := NewCustomAuthUsecase(
+				mockRepo,
+				nil,
+				nil,
+				config.CookieSettings{},
+				config.PasswordResetSettings{},
+				config.LoginGuardSettings{},
+				config.TwoFactorSettings{},
+				config.OIDCSettings{},
+				policy,
+				policy.HashPassword,
+				generateCookie,
+			)
+
+			_, _, err := u.SignUp(context.Background(), tt.inputUser)
+
+			var validationErr domain.ValidationError
+			assert.True(t, errors.As(err, &validationErr))
+
+			rules := make([]string, len(validationErr.Violations))
+			for i, v := range validationErr.Violations {
+				rules[i] = v.Rule
+			}
+			assert.Equal(t, tt.expectedRules, rules)
+		})
+	}
+}
+
+func TestUsecase_LoginRehash(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mockRepository.NewMockAuthRepository(ctrl)
+
+	policy, err := password.NewPolicy(config.CredentialsSettings{BcryptCost: 5})
+	assert.NoError(t, err)
+
+	u := NewCustomAuthUsecase(
+		mockRepo,
+		nil,
+		nil,
+		config.CookieSettings{},
+		config.PasswordResetSettings{},
+		config.LoginGuardSettings{},
+		config.TwoFactorSettings{},
+		config.OIDCSettings{},
+		policy,
+		policy.HashPassword,
+		func(userID uint64, c config.CookieSettings) gormModels.Session {
+			return gormModels.Session{
+				UserID:    userID,
+				SessionID: "cc982d23-e3e0-439e-a8b7-7fff4eeeb2f2",
+			}
+		},
+	)
+
+	// The stored hash uses the default cost, so it has to be upgraded.
+	oldHash, _ := password.HashPassword("Secret123")
+
+	mockRepo.EXPECT().
+		GetLoginAttempts(gomock.Any(), gomock.Any()).
+		Return(nil, nil)
+	mockRepo.EXPECT().
+		GetUserByUsername(gomock.Any(), "Jane").
+		Return(gormModels.User{ID: 1, Username: "Jane", Password: oldHash}, nil)
+	mockRepo.EXPECT().
+		UpdatePassword(gomock.Any(), uint64(1), gomock.Any()).
+		DoAndReturn(func(_ context.Context, userID uint64, hash string) error {
+			assert.False(t, policy.NeedsRehash(hash))
+			assert.True(t, password.CheckHashPassword("Secret123", hash))
+			return nil
+		})
+	mockRepo.EXPECT().
+		ResetLoginFailures(gomock.Any(), "user:Jane").
+		Return(nil)
+	mockRepo.EXPECT().
+		CreateSession(gomock.Any(), gomock.Any()).
+		Return("cc982d23-e3e0-439e-a8b7-7fff4eeeb2f2", nil)
+
+	_, userID, err := u.Login(context.Background(), httpModels.AuthUser{Username: "Jane", Password: "Secret123"}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), userID)
+}
+
+func TestUsecase_TwoFactor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mockRepository.NewMockAuthRepository(ctrl)
+
+	u := NewCustomAuthUsecase(
+		mockRepo,
+		nil,
+		nil,
+		config.CookieSettings{},
+		config.PasswordResetSettings{},
+		config.LoginGuardSettings{},
+		config.TwoFactorSettings{
+			Issuer:             "Film Library",
+			ChallengeTTL:       time.Minute,
+			MaxCodeAttempts:    3,
+			RecoveryCodesCount: 2,
+		},
+		config.OIDCSettings{},
+		password.Policy{},
+		func(password string) (string, error) {
+			return password, nil
+		},
+		func(userID uint64, c config.CookieSettings) gormModels.Session {
+			return gormModels.Session{
+				UserID:    userID,
+				SessionID: "cc982d23-e3e0-439e-a8b7-7fff4eeeb2f2",
+			}
+		},
+	)
+
+	const sessionID = "cc982d23-e3e0-439e-a8b7-7fff4eeeb2f2"
+	user := gormModels.User{ID: 1, Username: "Jane"}
+
+	// enrollment
+	mockRepo.EXPECT().GetUserBySessionID(gomock.Any(), sessionID).Return(user, nil)
+	mockRepo.EXPECT().GetUserByID(gomock.Any(), uint64(1)).Return(user, nil)
+	mockRepo.EXPECT().
+		UpdateTOTP(gomock.Any(), uint64(1), gomock.Any(), false).
+		DoAndReturn(func(_ context.Context, userID uint64, secret string, enabled bool) error {
+			user.TOTPSecret = secret
+			return nil
+		})
+
+	enrollment, err := u.EnrollTOTP(context.Background(), sessionID)
+	assert.NoError(t, err)
+	assert.Equal(t, user.TOTPSecret, enrollment.Secret)
+	assert.Contains(t, enrollment.URI, "otpauth://totp/Film%20Library:Jane?")
+
+	// activation with a code from the app
+	code, err := totp.Code(user.TOTPSecret, totp.Step(time.Now()))
+	assert.NoError(t, err)
+
+	var storedCodes []gormModels.RecoveryCode
+	mockRepo.EXPECT().GetUserBySessionID(gomock.Any(), sessionID).Return(user, nil)
+	mockRepo.EXPECT().GetUserByID(gomock.Any(), uint64(1)).Return(user, nil)
+	mockRepo.EXPECT().UseTOTPStep(gomock.Any(), uint64(1), gomock.Any()).Return(nil)
+	mockRepo.EXPECT().UpdateTOTP(gomock.Any(), uint64(1), user.TOTPSecret, true).Return(nil)
+	mockRepo.EXPECT().
+		ReplaceRecoveryCodes(gomock.Any(), uint64(1), gomock.Any()).
+		DoAndReturn(func(_ context.Context, userID uint64, codes []gormModels.RecoveryCode) error {
+			storedCodes = codes
+			return nil
+		})
+
+	recoveryCodes, err := u.ActivateTOTP(context.Background(), sessionID, code)
+	assert.NoError(t, err)
+	assert.Len(t, recoveryCodes.Codes, 2)
+	assert.Len(t, storedCodes, 2)
+	assert.Equal(t, hashToken(normalizeRecoveryCode(recoveryCodes.Codes[0])), storedCodes[0].CodeHash)
+
+	// login stops at the challenge
+	user.TOTPEnabled = true
+	hashedPassword, _ := password.HashPassword("123")
+	user.Password = hashedPassword
+
+	var storedChallenge gormModels.TwoFactorChallenge
+	mockRepo.EXPECT().GetLoginAttempts(gomock.Any(), gomock.Any()).Return(nil, nil)
+	mockRepo.EXPECT().GetUserByUsername(gomock.Any(), "Jane").Return(user, nil)
+	mockRepo.EXPECT().
+		CreateTwoFactorChallenge(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, challenge gormModels.TwoFactorChallenge) error {
+			storedChallenge = challenge
+			return nil
+		})
+
+	sessionIDAfterLogin, _, err := u.Login(context.Background(), httpModels.AuthUser{Username: "Jane", Password: "123"}, "")
+	assert.Empty(t, sessionIDAfterLogin)
+
+	var twoFactorErr domain.TwoFactorRequiredError
+	assert.True(t, errors.As(err, &twoFactorErr))
+	assert.Equal(t, hashToken(twoFactorErr.Challenge), storedChallenge.TokenHash)
+
+	// second step with a recovery code
+	storedChallenge.ID = 5
+	mockRepo.EXPECT().GetTwoFactorChallenge(gomock.Any(), storedChallenge.TokenHash).Return(storedChallenge, nil)
+	mockRepo.EXPECT().UseChallengeAttempt(gomock.Any(), uint64(5), uint64(3)).Return(nil)
+	mockRepo.EXPECT().GetUserByID(gomock.Any(), uint64(1)).Return(user, nil)
+	mockRepo.EXPECT().GetLoginAttempts(gomock.Any(), []string{"user:Jane"}).Return(nil, nil)
+	mockRepo.EXPECT().
+		UseRecoveryCode(gomock.Any(), uint64(1), storedCodes[1].CodeHash).
+		Return(nil)
+	mockRepo.EXPECT().ResetLoginFailures(gomock.Any(), "user:Jane").Return(nil)
+	mockRepo.EXPECT().DeleteTwoFactorChallenge(gomock.Any(), uint64(5)).Return(nil)
+	mockRepo.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Return(sessionID, nil)
+
+	newSessionID, userID, err := u.VerifyTwoFactor(context.Background(), httpModels.TwoFactorLogin{
+		Challenge: twoFactorErr.Challenge,
+		Code:      strings.ToUpper(recoveryCodes.Codes[1]),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, sessionID, newSessionID)
+	assert.Equal(t, uint64(1), userID)
+
+	// exhausted challenge
+	mockRepo.EXPECT().GetTwoFactorChallenge(gomock.Any(), storedChallenge.TokenHash).Return(storedChallenge, nil)
+	mockRepo.EXPECT().UseChallengeAttempt(gomock.Any(), uint64(5), uint64(3)).Return(gorm.ErrRecordNotFound)
+
+	_, _, err = u.VerifyTwoFactor(context.Background(), httpModels.TwoFactorLogin{
+		Challenge: twoFactorErr.Challenge,
+		Code:      "000000",
+	})
+	assert.Equal(t, domain.ErrTwoFactorChallenge, err)
+}
+
+// loginAttempts keeps the login guard counters of a mocked repository.
+type loginAttempts map[string]*gormModels.LoginAttempt
+
+func (a loginAttempts) expect(m *mockRepository.MockAuthRepository) {
+	m.EXPECT().
+		GetLoginAttempts(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, keys []string) ([]gormModels.LoginAttempt, error) {
+			var attempts []gormModels.LoginAttempt
+			for _, key := range keys {
+				if attempt, ok := a[key]; ok {
+					attempts = append(attempts, *attempt)
+				}
+			}
+			return attempts, nil
+		}).
+		AnyTimes()
+	m.EXPECT().
+		RegisterLoginFailure(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(
+			_ context.Context,
+			key string,
+			staleBefore time.Time,
+		) (gormModels.LoginAttempt, error) {
+			if _, ok := a[key]; !ok {
+				a[key] = &gormModels.LoginAttempt{Key: key}
+			}
+			if a[key].UpdatedAt.Before(staleBefore) {
+				a[key].Failures = 0
+			}
+			a[key].Failures++
+			a[key].UpdatedAt = time.Now()
+			return *a[key], nil
+		}).
+		AnyTimes()
+	m.EXPECT().
+		BlockLogin(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, key string, until time.Time) error {
+			a[key].BlockedUntil = until
+			a[key].UpdatedAt = time.Now()
+			return nil
+		}).
+		AnyTimes()
+	m.EXPECT().
+		ResetLoginFailures(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, key string) error {
+			delete(a, key)
+			return nil
+		}).
+		AnyTimes()
+}
+
+// passTime moves every attempt d into the past.
+func (a loginAttempts) passTime(d time.Duration) {
+	for _, attempt := range a {
+		attempt.UpdatedAt = attempt.UpdatedAt.Add(-d)
+		attempt.BlockedUntil = attempt.BlockedUntil.Add(-d)
+	}
+}
+
+func TestUsecase_LockoutExpires(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mockRepository.NewMockAuthRepository(ctrl)
+
+	u := NewCustomAuthUsecase(
+		mockRepo,
+		nil,
+		nil,
+		config.CookieSettings{},
+		config.PasswordResetSettings{},
+		config.LoginGuardSettings{
+			MaxFailures:     3,
+			LockoutDuration: time.Hour,
+			FailureWindow:   24 * time.Hour,
+		},
+		config.TwoFactorSettings{},
+		config.OIDCSettings{},
+		password.Policy{},
+		func(password string) (string, error) {
+			return password, nil
+		},
+		generateCookie,
+	)
+
+	hashedPassword, _ := password.HashPassword("123")
+	user := gormModels.User{ID: 1, Username: "Jane", Password: hashedPassword}
+
+	attempts := loginAttempts{}
+	attempts.expect(mockRepo)
+	mockRepo.EXPECT().GetUserByUsername(gomock.Any(), "Jane").Return(user, nil).AnyTimes()
+
+	typo := httpModels.AuthUser{Username: "Jane", Password: "321"}
+	for i := 0; i < 3; i++ {
+		_, _, err := u.Login(context.Background(), typo, "10.0.0.1")
+		assert.Equal(t, domain.ErrInvalidLoginOrPassword, err)
+	}
+	_, _, err := u.Login(context.Background(), typo, "10.0.0.1")
+	assert.Equal(t, domain.ErrTooManyAttempts, err)
+
+	// The lockout is over, the window is capped by it.
+	attempts.passTime(time.Hour + time.Minute)
+
+	_, _, err = u.Login(context.Background(), typo, "10.0.0.1")
+	assert.Equal(t, domain.ErrInvalidLoginOrPassword, err)
+	assert.Equal(t, uint64(1), attempts["user:Jane"].Failures)
+	assert.Equal(t, uint64(1), attempts["ip:10.0.0.1"].Failures)
+
+	_, _, err = u.Login(context.Background(), typo, "10.0.0.1")
+	assert.Equal(t, domain.ErrInvalidLoginOrPassword, err)
+}
+
+func TestUsecase_TwoFactorLockout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mockRepository.NewMockAuthRepository(ctrl)
+
+	u := NewCustomAuthUsecase(
+		mockRepo,
+		nil,
+		nil,
+		config.CookieSettings{},
+		config.PasswordResetSettings{},
+		config.LoginGuardSettings{MaxFailures: 3, LockoutDuration: time.Hour},
+		config.TwoFactorSettings{ChallengeTTL: time.Minute, MaxCodeAttempts: 3},
+		config.OIDCSettings{},
+		password.Policy{},
+		func(password string) (string, error) {
+			return password, nil
+		},
+		generateCookie,
+	)
+
+	hashedPassword, _ := password.HashPassword("123")
+	secret, err := totp.GenerateSecret()
+	assert.NoError(t, err)
+	user := gormModels.User{ID: 1, Username: "Jane", Password: hashedPassword, TOTPEnabled: true, TOTPSecret: secret}
+
+	attempts := loginAttempts{}
+	attempts.expect(mockRepo)
+
+	var storedChallenge gormModels.TwoFactorChallenge
+	mockRepo.EXPECT().GetUserByUsername(gomock.Any(), "Jane").Return(user, nil).AnyTimes()
+	mockRepo.EXPECT().GetUserByID(gomock.Any(), uint64(1)).Return(user, nil).AnyTimes()
+	mockRepo.EXPECT().
+		CreateTwoFactorChallenge(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, challenge gormModels.TwoFactorChallenge) error {
+			storedChallenge = challenge
+			return nil
+		}).
+		AnyTimes()
+	mockRepo.EXPECT().
+		GetTwoFactorChallenge(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, tokenHash string) (gormModels.TwoFactorChallenge, error) {
+			return storedChallenge, nil
+		}).
+		AnyTimes()
+	mockRepo.EXPECT().UseChallengeAttempt(gomock.Any(), gomock.Any(), uint64(3)).Return(nil).AnyTimes()
+	mockRepo.EXPECT().UseRecoveryCode(gomock.Any(), uint64(1), gomock.Any()).Return(gorm.ErrRecordNotFound).AnyTimes()
+
+	// A fresh challenge for every wrong code must not start the count over.
+	for i := 0; i < 3; i++ {
+		_, _, err = u.Login(context.Background(), httpModels.AuthUser{Username: "Jane", Password: "123"}, "10.0.0.1")
+		var twoFactorErr domain.TwoFactorRequiredError
+		assert.True(t, errors.As(err, &twoFactorErr))
+		assert.Equal(t, "10.0.0.1", storedChallenge.IP)
+
+		_, _, err = u.VerifyTwoFactor(context.Background(), httpModels.TwoFactorLogin{
+			Challenge: twoFactorErr.Challenge,
+			Code:      "not-a-code",
+		})
+		assert.Equal(t, domain.ErrTwoFactorCode, err)
+	}
+
+	assert.Equal(t, uint64(3), attempts["user:Jane"].Failures)
+	assert.Equal(t, uint64(3), attempts["ip:10.0.0.1"].Failures)
+
+	_, _, err = u.Login(context.Background(), httpModels.AuthUser{Username: "Jane", Password: "123"}, "10.0.0.1")
+	assert.Equal(t, domain.ErrTooManyAttempts, err)
+}
+
+func TestUsecase_OIDC(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mockRepository.NewMockAuthRepository(ctrl)
+	mockProvider := mockRepository.NewMockIdentityProvider(ctrl)
+
+	u := NewCustomAuthUsecase(
+		mockRepo,
+		nil,
+		map[string]domain.IdentityProvider{"corp": mockProvider},
+		config.CookieSettings{},
+		config.PasswordResetSettings{},
+		config.LoginGuardSettings{},
+		config.TwoFactorSettings{},
+		config.OIDCSettings{StateTTL: time.Minute},
+		password.Policy{},
+		func(password string) (string, error) {
+			return password, nil
+		},
+		func(userID uint64, c config.CookieSettings) gormModels.Session {
+			return gormModels.Session{
+				UserID:    userID,
+				SessionID: "cc982d23-e3e0-439e-a8b7-7fff4eeeb2f2",
+			}
+		},
+	)
+
+	const sessionID = "cc982d23-e3e0-439e-a8b7-7fff4eeeb2f2"
+	ctx := context.Background()
+
+	_, _, err := u.OIDCLoginURL(context.Background(), "unknown", "")
+	assert.ErrorIs(t, err, domain.ErrUnknownProvider)
+
+	// login url
+	var storedState gormModels.OIDCState
+	var state string
+	mockRepo.EXPECT().
+		CreateOIDCState(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, s gormModels.OIDCState) error {
+			storedState = s
+			return nil
+		})
+	mockProvider.EXPECT().
+		AuthCodeURL(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(s, nonce, verifier string) string {
+			state = s
+			return "https://idp.example.com/auth?state=" + s
+		})
+
+	url, returnedState, err := u.OIDCLoginURL(context.Background(), "corp", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://idp.example.com/auth?state="+state, url)
+	assert.Equal(t, state, returnedState)
+	assert.Equal(t, hashToken(state), storedState.StateHash)
+	assert.Equal(t, "corp", storedState.Provider)
+	assert.Zero(t, storedState.LinkUserID)
+
+	// first login provisions a user, the username is taken locally
+	identity := domain.ExternalIdentity{Subject: "42", Username: "Jane", Groups: []string{"staff"}}
+	mockRepo.EXPECT().ConsumeOIDCState(gomock.Any(), storedState.StateHash).Return(storedState, nil)
+	mockProvider.EXPECT().
+		Exchange(ctx, "code", storedState.Nonce, storedState.Verifier).
+		Return(identity, nil)
+	mockProvider.EXPECT().RoleFor([]string{"staff"}).Return("")
+	mockRepo.EXPECT().
+		GetExternalIdentity(gomock.Any(), "corp", "42").
+		Return(gormModels.ExternalIdentity{}, gorm.ErrRecordNotFound)
+	mockRepo.EXPECT().GetUserByUsername(gomock.Any(), "Jane").Return(gormModels.User{ID: 1}, nil)
+	mockRepo.EXPECT().
+		CreateUser(gomock.Any(), gormModels.User{Username: "corp-42", Role: "user"}).
+		Return(uint64(7), nil)
+	mockRepo.EXPECT().
+		CreateExternalIdentity(gomock.Any(), gormModels.ExternalIdentity{Provider: "corp", Subject: "42", UserID: 7}).
+		Return(nil)
+	mockRepo.EXPECT().GetUserByID(gomock.Any(), uint64(7)).Return(gormModels.User{ID: 7}, nil)
+	mockRepo.EXPECT().
+		CreateSession(gomock.Any(), gormModels.Session{UserID: 7, SessionID: sessionID}).
+		Return(sessionID, nil)
+
+	newSessionID, userID, err := u.OIDCCallback(ctx, "corp", "code", state)
+	assert.NoError(t, err)
+	assert.Equal(t, sessionID, newSessionID)
+	assert.Equal(t, uint64(7), userID)
+
+	// next login finds the link and applies the mapped role
+	mockRepo.EXPECT().ConsumeOIDCState(gomock.Any(), storedState.StateHash).Return(storedState, nil)
+	mockProvider.EXPECT().Exchange(ctx, "code", gomock.Any(), gomock.Any()).Return(identity, nil)
+	mockProvider.EXPECT().RoleFor([]string{"staff"}).Return("admin")
+	mockRepo.EXPECT().
+		GetExternalIdentity(gomock.Any(), "corp", "42").
+		Return(gormModels.ExternalIdentity{Provider: "corp", Subject: "42", UserID: 7}, nil)
+	mockRepo.EXPECT().UpdateRole(gomock.Any(), uint64(7), "admin").Return(nil)
+	mockRepo.EXPECT().GetUserByID(gomock.Any(), uint64(7)).Return(gormModels.User{ID: 7}, nil)
+	mockRepo.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Return(sessionID, nil)
+
+	_, userID, err = u.OIDCCallback(ctx, "corp", "code", state)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(7), userID)
+
+	// the identity provider doesn't stand in for the second factor
+	mockRepo.EXPECT().ConsumeOIDCState(gomock.Any(), storedState.StateHash).Return(storedState, nil)
+	mockProvider.EXPECT().Exchange(ctx, "code", gomock.Any(), gomock.Any()).Return(identity, nil)
+	mockProvider.EXPECT().RoleFor([]string{"staff"}).Return("")
+	mockRepo.EXPECT().
+		GetExternalIdentity(gomock.Any(), "corp", "42").
+		Return(gormModels.ExternalIdentity{Provider: "corp", Subject: "42", UserID: 7}, nil)
+	mockRepo.EXPECT().
+		GetUserByID(gomock.Any(), uint64(7)).
+		Return(gormModels.User{ID: 7, TOTPEnabled: true}, nil)
+	mockRepo.EXPECT().
+		CreateTwoFactorChallenge(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, c gormModels.TwoFactorChallenge) error {
+			assert.Equal(t, uint64(7), c.UserID)
+			return nil
+		})
+
+	newSessionID, _, err = u.OIDCCallback(ctx, "corp", "code", state)
+	var twoFactorErr domain.TwoFactorRequiredError
+	assert.ErrorAs(t, err, &twoFactorErr)
+	assert.NotEmpty(t, twoFactorErr.Challenge)
+	assert.Empty(t, newSessionID)
+
+	// linking an identity that belongs to someone else
+	linkState := storedState
+	linkState.LinkUserID = 1
+	mockRepo.EXPECT().ConsumeOIDCState(gomock.Any(), gomock.Any()).Return(linkState, nil)
+	mockProvider.EXPECT().Exchange(ctx, "code", gomock.Any(), gomock.Any()).Return(identity, nil)
+	mockProvider.EXPECT().RoleFor(gomock.Any()).Return("")
+	mockRepo.EXPECT().
+		GetExternalIdentity(gomock.Any(), "corp", "42").
+		Return(gormModels.ExternalIdentity{UserID: 7}, nil)
+
+	_, _, err = u.OIDCCallback(ctx, "corp", "code", state)
+	assert.ErrorIs(t, err, domain.ErrConflict)
+
+	// state rejections
+	mockRepo.EXPECT().
+		ConsumeOIDCState(gomock.Any(), gomock.Any()).
+		Return(gormModels.OIDCState{}, gorm.ErrRecordNotFound)
+	_, _, err = u.OIDCCallback(ctx, "corp", "code", "forged")
+	assert.ErrorIs(t, err, domain.ErrOIDCState)
+
+	expired := storedState
+	expired.ExpireDate = time.Now().Add(-time.Second)
+	mockRepo.EXPECT().ConsumeOIDCState(gomock.Any(), gomock.Any()).Return(expired, nil)
+	_, _, err = u.OIDCCallback(ctx, "corp", "code", state)
+	assert.ErrorIs(t, err, domain.ErrOIDCState)
+
+	// failed exchange
+	mockRepo.EXPECT().ConsumeOIDCState(gomock.Any(), gomock.Any()).Return(storedState, nil)
+	mockProvider.EXPECT().
+		Exchange(ctx, "code", gomock.Any(), gomock.Any()).
+		Return(domain.ExternalIdentity{}, errors.New("invalid_grant"))
+	_, _, err = u.OIDCCallback(ctx, "corp", "code", state)
+	assert.ErrorIs(t, err, domain.ErrOIDCExchange)
+}
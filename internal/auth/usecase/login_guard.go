@@ -0,0 +1,128 @@
+package authUsecase
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	password "github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/hash"
+)
+
+const (
+	userAttemptPrefix = "user:"
+	ipAttemptPrefix   = "ip:"
+)
+
+// dummyHash is checked against when the username doesn't exist, so an
+// unknown user takes as long to reject as a wrong password.
+var dummyHash = sync.OnceValue(func() string {
+	hash, _ := password.HashPassword("dummy-password")
+	return hash
+})
+
+func loginAttemptKeys(username, ip string) []string {
+	keys := []string{userAttemptPrefix + username}
+	if ip != "" {
+		keys = append(keys, ipAttemptPrefix+ip)
+	}
+	return keys
+}
+
+func (u AuthUsecase) checkLoginBlocked(ctx context.Context, keys []string) error {
+	attempts, err := u.authRepository.GetLoginAttempts(ctx, keys)
+	if err != nil {
+		return domain.ErrInternal
+	}
+
+	now := time.Now()
+	for _, a := range attempts {
+		if now.Before(a.BlockedUntil) {
+			slog.WarnContext(ctx, "login blocked", "key", a.Key, "blocked_until", a.BlockedUntil)
+			return domain.ErrTooManyAttempts
+		}
+	}
+	return nil
+}
+
+// loginFailed counts the failure against every key and always reports the
+// same error, whatever the reason of the failure was.
+func (u AuthUsecase) loginFailed(ctx context.Context, keys []string) error {
+	now := time.Now()
+	var staleBefore time.Time
+	if window := u.failureWindow(); window > 0 {
+		staleBefore = now.Add(-window)
+	}
+
+	for _, key := range keys {
+		attempt, err := u.authRepository.RegisterLoginFailure(ctx, key, staleBefore)
+		if err != nil {
+			return domain.ErrInternal
+		}
+
+		slog.WarnContext(ctx, "login failed", "key", key, "failures", attempt.Failures)
+		if delay := u.loginDelay(attempt.Failures); delay > 0 {
+			if err = u.authRepository.BlockLogin(ctx, key, now.Add(delay)); err != nil {
+				return domain.ErrInternal
+			}
+		}
+	}
+	return domain.ErrInvalidLoginOrPassword
+}
+
+// loginSucceeded forgets the failures of every key, the ones of the address
+// too, so that a user who mistyped the password isn't slowed down on the
+// next attempts from it.
+func (u AuthUsecase) loginSucceeded(ctx context.Context, keys []string) error {
+	for _, key := range keys {
+		if err := u.authRepository.ResetLoginFailures(ctx, key); err != nil {
+			return domain.ErrInternal
+		}
+	}
+	return nil
+}
+
+// failureWindow is how long a failure is remembered after the last one. It is
+// never longer than the lockout: once a lockout is over the count starts
+// again, a single typo must not lock the user out for another round.
+func (u AuthUsecase) failureWindow() time.Duration {
+	g := u.loginGuard
+	window := g.FailureWindow
+	if g.LockoutDuration > 0 && (window <= 0 || g.LockoutDuration < window) {
+		window = g.LockoutDuration
+	}
+	return window
+}
+
+// loginDelay doubles the wait after every failure up to MaxDelay and switches
+// to a full lockout once MaxFailures is reached.
+func (u AuthUsecase) loginDelay(failures uint64) time.Duration {
+	g := u.loginGuard
+	if g.MaxFailures > 0 && failures >= g.MaxFailures {
+		return g.LockoutDuration
+	}
+	if g.BaseDelay <= 0 || failures == 0 {
+		return 0
+	}
+
+	delay := g.BaseDelay
+	for i := uint64(1); i < failures; i++ {
+		delay *= 2
+		if g.MaxDelay > 0 && delay >= g.MaxDelay {
+			return g.MaxDelay
+		}
+	}
+	if g.MaxDelay > 0 && delay > g.MaxDelay {
+		return g.MaxDelay
+	}
+	return delay
+}
+
+func (u AuthUsecase) UnlockUser(ctx context.Context, username string) error {
+	if err := u.authRepository.ResetLoginFailures(ctx, userAttemptPrefix+username); err != nil {
+		return err
+	}
+	slog.InfoContext(ctx, "user unlocked", "username", username)
+	return nil
+}
@@ -0,0 +1,217 @@
+package authUsecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	gormModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/gorm"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/metrics"
+	"gorm.io/gorm"
+)
+
+const defaultExternalRole = "user"
+
+// OIDCLoginURL starts the authorization code flow and returns the redirect
+// URL with its state. The caller has to tie the state to the browser, the
+// stored hash alone would accept a callback started by anyone. With a session
+// the resulting identity is linked to the logged in user instead of logging in.
+func (u AuthUsecase) OIDCLoginURL(
+	ctx context.Context,
+	provider, linkSessionID string,
+) (string, string, error) {
+	p, ok := u.providers[provider]
+	if !ok {
+		return "", "", domain.ErrUnknownProvider
+	}
+
+	var linkUserID uint64
+	if linkSessionID != "" {
+		user, err := u.authRepository.GetUserBySessionID(ctx, linkSessionID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return "", "", domain.ErrNoSession
+			}
+			return "", "", err
+		}
+		linkUserID = user.ID
+	}
+
+	state, err := generateToken()
+	if err != nil {
+		return "", "", err
+	}
+	nonce, err := generateToken()
+	if err != nil {
+		return "", "", err
+	}
+	verifier, err := generateToken()
+	if err != nil {
+		return "", "", err
+	}
+
+	if err = u.authRepository.CreateOIDCState(ctx, gormModels.OIDCState{
+		StateHash:  hashToken(state),
+		Provider:   provider,
+		Nonce:      nonce,
+		Verifier:   verifier,
+		LinkUserID: linkUserID,
+		ExpireDate: time.Now().Add(u.oidcSettings.StateTTL),
+	}); err != nil {
+		return "", "", err
+	}
+
+	return p.AuthCodeURL(state, nonce, verifier), state, nil
+}
+
+func (u AuthUsecase) OIDCCallback(
+	ctx context.Context,
+	provider, code, state string,
+) (string, uint64, error) {
+	sessionID, userID, err := u.oidcCallback(ctx, provider, code, state)
+	metrics.Login("oidc", err)
+	return sessionID, userID, err
+}
+
+func (u AuthUsecase) oidcCallback(
+	ctx context.Context,
+	provider, code, state string,
+) (string, uint64, error) {
+	p, ok := u.providers[provider]
+	if !ok {
+		return "", 0, domain.ErrUnknownProvider
+	}
+
+	loginState, err := u.authRepository.ConsumeOIDCState(ctx, hashToken(state))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", 0, domain.ErrOIDCState
+		}
+		return "", 0, err
+	}
+	if loginState.Provider != provider || time.Now().After(loginState.ExpireDate) {
+		return "", 0, domain.ErrOIDCState
+	}
+
+	identity, err := p.Exchange(ctx, code, loginState.Nonce, loginState.Verifier)
+	if err != nil {
+		slog.WarnContext(ctx, "oidc exchange failed", "provider", provider, "error", err)
+		return "", 0, domain.ErrOIDCExchange
+	}
+	if identity.Subject == "" {
+		return "", 0, domain.ErrOIDCExchange
+	}
+
+	userID, err := u.resolveExternalUser(
+		ctx,
+		provider,
+		identity,
+		loginState.LinkUserID,
+		p.RoleFor(identity.Groups),
+	)
+	if err != nil {
+		return "", 0, err
+	}
+
+	// The identity provider stands in for the password only, the second
+	// factor is still ours to check.
+	user, err := u.authRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		return "", 0, err
+	}
+	if user.TOTPEnabled {
+		return "", 0, u.startTwoFactor(ctx, userID, "")
+	}
+
+	sessionID, err := u.authRepository.CreateSession(ctx, u.cookieCreator(userID, u.cookieSettings))
+	if err != nil {
+		return "", 0, domain.ErrInternal
+	}
+	slog.InfoContext(ctx, "user logged in", "user_id", userID, "provider", provider)
+	return sessionID, userID, nil
+}
+
+// resolveExternalUser finds the local user behind the identity. Unknown
+// identities are linked to linkUserID if set, otherwise a new user without a
+// local password is created. Users are never matched by username, that would
+// let anyone with an IdP account take over a local one.
+func (u AuthUsecase) resolveExternalUser(
+	ctx context.Context,
+	provider string,
+	identity domain.ExternalIdentity,
+	linkUserID uint64,
+	role string,
+) (uint64, error) {
+	link, err := u.authRepository.GetExternalIdentity(ctx, provider, identity.Subject)
+	switch {
+	case err == nil:
+		if linkUserID != 0 && linkUserID != link.UserID {
+			return 0, domain.ErrConflict
+		}
+		return link.UserID, u.syncRole(ctx, link.UserID, role)
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return 0, err
+	}
+
+	userID := linkUserID
+	if userID != 0 {
+		if err = u.syncRole(ctx, userID, role); err != nil {
+			return 0, err
+		}
+	} else {
+		username, err := u.externalUsername(ctx, provider, identity)
+		if err != nil {
+			return 0, err
+		}
+		if role == "" {
+			role = defaultExternalRole
+		}
+
+		userID, err = u.authRepository.CreateUser(ctx, gormModels.User{
+			Username: username,
+			Role:     role,
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if err = u.authRepository.CreateExternalIdentity(ctx, gormModels.ExternalIdentity{
+		Provider: provider,
+		Subject:  identity.Subject,
+		UserID:   userID,
+	}); err != nil {
+		return 0, err
+	}
+	return userID, nil
+}
+
+func (u AuthUsecase) syncRole(ctx context.Context, userID uint64, role string) error {
+	if role == "" {
+		return nil
+	}
+	return u.authRepository.UpdateRole(ctx, userID, role)
+}
+
+func (u AuthUsecase) externalUsername(
+	ctx context.Context,
+	provider string,
+	identity domain.ExternalIdentity,
+) (string, error) {
+	fallback := provider + "-" + identity.Subject
+	if identity.Username == "" {
+		return fallback, nil
+	}
+
+	_, err := u.authRepository.GetUserByUsername(ctx, identity.Username)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return identity.Username, nil
+	case err != nil:
+		return "", err
+	default:
+		return fallback, nil
+	}
+}
@@ -0,0 +1,150 @@
+package authUsecase
+
+import (
+	"context"
+
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/tracing"
+)
+
+// TracedAuthUsecase makes a span of every call. Auth runs on every request
+// with a session, so its span is the cost of the session lookup.
+type TracedAuthUsecase struct {
+	authUsecase domain.AuthUsecase
+}
+
+func NewTracedAuthUsecase(u domain.AuthUsecase) TracedAuthUsecase {
+	return TracedAuthUsecase{authUsecase: u}
+}
+
+func (u TracedAuthUsecase) SignUp(
+	ctx context.Context,
+	user httpModels.AuthUser,
+) (_ string, _ uint64, err error) {
+	ctx, span := tracing.Start(ctx, "AuthUsecase.SignUp")
+	defer func() { tracing.End(span, err) }()
+	return u.authUsecase.SignUp(ctx, user)
+}
+
+func (u TracedAuthUsecase) Login(
+	ctx context.Context,
+	user httpModels.AuthUser,
+	ip string,
+) (_ string, _ uint64, err error) {
+	ctx, span := tracing.Start(ctx, "AuthUsecase.Login")
+	defer func() { tracing.End(span, err) }()
+	return u.authUsecase.Login(ctx, user, ip)
+}
+
+func (u TracedAuthUsecase) Logout(ctx context.Context, sessionID string) (err error) {
+	ctx, span := tracing.Start(ctx, "AuthUsecase.Logout")
+	defer func() { tracing.End(span, err) }()
+	return u.authUsecase.Logout(ctx, sessionID)
+}
+
+func (u TracedAuthUsecase) Auth(ctx context.Context, sessionID string) (_ uint64, err error) {
+	ctx, span := tracing.Start(ctx, "AuthUsecase.Auth")
+	defer func() { tracing.End(span, err) }()
+	return u.authUsecase.Auth(ctx, sessionID)
+}
+
+func (u TracedAuthUsecase) GetUserBySessionID(
+	ctx context.Context,
+	sessionID string,
+) (_ httpModels.AuthUser, err error) {
+	ctx, span := tracing.Start(ctx, "AuthUsecase.GetUserBySessionID")
+	defer func() { tracing.End(span, err) }()
+	return u.authUsecase.GetUserBySessionID(ctx, sessionID)
+}
+
+func (u TracedAuthUsecase) ChangePassword(
+	ctx context.Context,
+	sessionID string,
+	passwords httpModels.ChangePassword,
+) (err error) {
+	ctx, span := tracing.Start(ctx, "AuthUsecase.ChangePassword")
+	defer func() { tracing.End(span, err) }()
+	return u.authUsecase.ChangePassword(ctx, sessionID, passwords)
+}
+
+func (u TracedAuthUsecase) RequestPasswordReset(ctx context.Context, username string) (err error) {
+	ctx, span := tracing.Start(ctx, "AuthUsecase.RequestPasswordReset")
+	defer func() { tracing.End(span, err) }()
+	return u.authUsecase.RequestPasswordReset(ctx, username)
+}
+
+func (u TracedAuthUsecase) ConfirmPasswordReset(
+	ctx context.Context,
+	confirm httpModels.PasswordResetConfirm,
+) (err error) {
+	ctx, span := tracing.Start(ctx, "AuthUsecase.ConfirmPasswordReset")
+	defer func() { tracing.End(span, err) }()
+	return u.authUsecase.ConfirmPasswordReset(ctx, confirm)
+}
+
+func (u TracedAuthUsecase) UnlockUser(ctx context.Context, username string) (err error) {
+	ctx, span := tracing.Start(ctx, "AuthUsecase.UnlockUser")
+	defer func() { tracing.End(span, err) }()
+	return u.authUsecase.UnlockUser(ctx, username)
+}
+
+func (u TracedAuthUsecase) EnrollTOTP(
+	ctx context.Context,
+	sessionID string,
+) (_ httpModels.TOTPEnrollment, err error) {
+	ctx, span := tracing.Start(ctx, "AuthUsecase.EnrollTOTP")
+	defer func() { tracing.End(span, err) }()
+	return u.authUsecase.EnrollTOTP(ctx, sessionID)
+}
+
+func (u TracedAuthUsecase) ActivateTOTP(
+	ctx context.Context,
+	sessionID, code string,
+) (_ httpModels.RecoveryCodes, err error) {
+	ctx, span := tracing.Start(ctx, "AuthUsecase.ActivateTOTP")
+	defer func() { tracing.End(span, err) }()
+	return u.authUsecase.ActivateTOTP(ctx, sessionID, code)
+}
+
+func (u TracedAuthUsecase) DisableTOTP(ctx context.Context, sessionID, code string) (err error) {
+	ctx, span := tracing.Start(ctx, "AuthUsecase.DisableTOTP")
+	defer func() { tracing.End(span, err) }()
+	return u.authUsecase.DisableTOTP(ctx, sessionID, code)
+}
+
+func (u TracedAuthUsecase) RegenerateRecoveryCodes(
+	ctx context.Context,
+	sessionID, code string,
+) (_ httpModels.RecoveryCodes, err error) {
+	ctx, span := tracing.Start(ctx, "AuthUsecase.RegenerateRecoveryCodes")
+	defer func() { tracing.End(span, err) }()
+	return u.authUsecase.RegenerateRecoveryCodes(ctx, sessionID, code)
+}
+
+func (u TracedAuthUsecase) VerifyTwoFactor(
+	ctx context.Context,
+	login httpModels.TwoFactorLogin,
+) (_ string, _ uint64, err error) {
+	ctx, span := tracing.Start(ctx, "AuthUsecase.VerifyTwoFactor")
+	defer func() { tracing.End(span, err) }()
+	return u.authUsecase.VerifyTwoFactor(ctx, login)
+}
+
+func (u TracedAuthUsecase) OIDCLoginURL(
+	ctx context.Context,
+	provider, linkSessionID string,
+) (_, _ string, err error) {
+	ctx, span := tracing.Start(ctx, "AuthUsecase.OIDCLoginURL")
+	defer func() { tracing.End(span, err) }()
+	return u.authUsecase.OIDCLoginURL(ctx, provider, linkSessionID)
+}
+
+func (u TracedAuthUsecase) OIDCCallback(
+	ctx context.Context,
+	provider, code, state string,
+) (_ string, _ uint64, err error) {
+	ctx, span := tracing.Start(ctx, "AuthUsecase.OIDCCallback")
+	defer func() { tracing.End(span, err) }()
+	return u.authUsecase.OIDCCallback(ctx, provider, code, state)
+}
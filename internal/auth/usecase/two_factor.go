@@ -0,0 +1,299 @@
+package authUsecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	gormModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/gorm"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/metrics"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/totp"
+	"gorm.io/gorm"
+)
+
+const recoveryCodeSize = 10
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+func generateRecoveryCode() (string, error) {
+	b := make([]byte, recoveryCodeSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	code := strings.ToLower(recoveryCodeEncoding.EncodeToString(b))
+	return code[:8] + "-" + code[8:], nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.ReplaceAll(code, "-", "")
+	return strings.ReplaceAll(code, " ", "")
+}
+
+// sessionUser loads the full user record, the session lookup only selects
+// the columns needed for authorization.
+func (u AuthUsecase) sessionUser(ctx context.Context, sessionID string) (gormModels.User, error) {
+	sessionUser, err := u.authRepository.GetUserBySessionID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return gormModels.User{}, domain.ErrNoSession
+		}
+		return gormModels.User{}, err
+	}
+	return u.authRepository.GetUserByID(ctx, sessionUser.ID)
+}
+
+func (u AuthUsecase) EnrollTOTP(
+	ctx context.Context,
+	sessionID string,
+) (httpModels.TOTPEnrollment, error) {
+	user, err := u.sessionUser(ctx, sessionID)
+	if err != nil {
+		return httpModels.TOTPEnrollment{}, err
+	}
+	if user.TOTPEnabled {
+		return httpModels.TOTPEnrollment{}, domain.ErrTwoFactorEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return httpModels.TOTPEnrollment{}, err
+	}
+
+	// The secret stays inactive until the user proves the app is set up
+	// by sending the first code.
+	if err = u.authRepository.UpdateTOTP(ctx, user.ID, secret, false); err != nil {
+		return httpModels.TOTPEnrollment{}, err
+	}
+
+	return httpModels.TOTPEnrollment{
+		Secret: secret,
+		URI:    totp.URI(u.twoFactor.Issuer, user.Username, secret),
+	}, nil
+}
+
+func (u AuthUsecase) ActivateTOTP(
+	ctx context.Context,
+	sessionID, code string,
+) (httpModels.RecoveryCodes, error) {
+	user, err := u.sessionUser(ctx, sessionID)
+	if err != nil {
+		return httpModels.RecoveryCodes{}, err
+	}
+	if user.TOTPEnabled {
+		return httpModels.RecoveryCodes{}, domain.ErrTwoFactorEnabled
+	}
+	if user.TOTPSecret == "" {
+		return httpModels.RecoveryCodes{}, domain.ErrTwoFactorDisabled
+	}
+
+	if err = u.checkTOTP(ctx, user, code); err != nil {
+		return httpModels.RecoveryCodes{}, err
+	}
+
+	if err = u.authRepository.UpdateTOTP(ctx, user.ID, user.TOTPSecret, true); err != nil {
+		return httpModels.RecoveryCodes{}, err
+	}
+	slog.InfoContext(ctx, "two-factor authentication enabled", "user_id", user.ID)
+
+	return u.issueRecoveryCodes(ctx, user.ID)
+}
+
+func (u AuthUsecase) DisableTOTP(ctx context.Context, sessionID, code string) error {
+	user, err := u.sessionUser(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if !user.TOTPEnabled {
+		return domain.ErrTwoFactorDisabled
+	}
+
+	if err = u.checkSecondFactor(ctx, user, code); err != nil {
+		return err
+	}
+
+	if err = u.authRepository.UpdateTOTP(ctx, user.ID, "", false); err != nil {
+		return err
+	}
+	slog.InfoContext(ctx, "two-factor authentication disabled", "user_id", user.ID)
+	return u.authRepository.ReplaceRecoveryCodes(ctx, user.ID, nil)
+}
+
+func (u AuthUsecase) RegenerateRecoveryCodes(
+	ctx context.Context,
+	sessionID, code string,
+) (httpModels.RecoveryCodes, error) {
+	user, err := u.sessionUser(ctx, sessionID)
+	if err != nil {
+		return httpModels.RecoveryCodes{}, err
+	}
+	if !user.TOTPEnabled {
+		return httpModels.RecoveryCodes{}, domain.ErrTwoFactorDisabled
+	}
+
+	if err = u.checkTOTP(ctx, user, code); err != nil {
+		return httpModels.RecoveryCodes{}, err
+	}
+
+	return u.issueRecoveryCodes(ctx, user.ID)
+}
+
+func (u AuthUsecase) VerifyTwoFactor(
+	ctx context.Context,
+	login httpModels.TwoFactorLogin,
+) (string, uint64, error) {
+	sessionID, userID, err := u.verifyTwoFactor(ctx, login)
+	metrics.Login("two_factor", err)
+	return sessionID, userID, err
+}
+
+func (u AuthUsecase) verifyTwoFactor(
+	ctx context.Context,
+	login httpModels.TwoFactorLogin,
+) (string, uint64, error) {
+	challenge, err := u.authRepository.GetTwoFactorChallenge(ctx, hashToken(login.Challenge))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", 0, domain.ErrTwoFactorChallenge
+		}
+		return "", 0, err
+	}
+	if time.Now().After(challenge.ExpireDate) {
+		return "", 0, domain.ErrTwoFactorChallenge
+	}
+
+	if err = u.authRepository.UseChallengeAttempt(ctx,
+		challenge.ID,
+		u.twoFactor.MaxCodeAttempts,
+	); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", 0, domain.ErrTwoFactorChallenge
+		}
+		return "", 0, err
+	}
+
+	user, err := u.authRepository.GetUserByID(ctx, challenge.UserID)
+	if err != nil {
+		return "", 0, err
+	}
+
+	keys := loginAttemptKeys(user.Username, challenge.IP)
+	if err = u.checkLoginBlocked(ctx, keys); err != nil {
+		return "", 0, err
+	}
+
+	if err = u.checkSecondFactor(ctx, user, login.Code); err != nil {
+		if errors.Is(err, domain.ErrTwoFactorCode) {
+			if failedErr := u.loginFailed(ctx, keys); errors.Is(failedErr, domain.ErrInternal) {
+				return "", 0, failedErr
+			}
+		}
+		return "", 0, err
+	}
+
+	if err = u.loginSucceeded(ctx, keys); err != nil {
+		return "", 0, err
+	}
+
+	if err = u.authRepository.DeleteTwoFactorChallenge(ctx, challenge.ID); err != nil {
+		return "", 0, err
+	}
+
+	sessionID, err := u.authRepository.CreateSession(ctx, u.cookieCreator(user.ID, u.cookieSettings))
+	if err != nil {
+		return "", 0, domain.ErrInternal
+	}
+	slog.InfoContext(ctx, "user logged in", "user_id", user.ID, "two_factor", true)
+	return sessionID, user.ID, nil
+}
+
+// startTwoFactor is called by Login after the password was accepted. Instead
+// of a session the user gets a short-lived challenge to send with the code.
+// ip is the address of the login, empty for OIDC logins.
+func (u AuthUsecase) startTwoFactor(ctx context.Context, userID uint64, ip string) error {
+	token, err := generateToken()
+	if err != nil {
+		return err
+	}
+
+	if err = u.authRepository.CreateTwoFactorChallenge(ctx, gormModels.TwoFactorChallenge{
+		UserID:     userID,
+		TokenHash:  hashToken(token),
+		ExpireDate: time.Now().Add(u.twoFactor.ChallengeTTL),
+		IP:         ip,
+	}); err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "second factor required", "user_id", userID)
+	return domain.TwoFactorRequiredError{Challenge: token}
+}
+
+func (u AuthUsecase) checkTOTP(ctx context.Context, user gormModels.User, code string) error {
+	step, ok := totp.Validate(user.TOTPSecret, strings.TrimSpace(code), time.Now())
+	if !ok {
+		return domain.ErrTwoFactorCode
+	}
+
+	if err := u.authRepository.UseTOTPStep(ctx, user.ID, step); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.ErrTwoFactorCode
+		}
+		return err
+	}
+	return nil
+}
+
+// checkSecondFactor accepts either a code from the app or one of the
+// recovery codes, which are burnt on use.
+func (u AuthUsecase) checkSecondFactor(
+	ctx context.Context,
+	user gormModels.User,
+	code string,
+) error {
+	err := u.checkTOTP(ctx, user, code)
+	if !errors.Is(err, domain.ErrTwoFactorCode) {
+		return err
+	}
+
+	if err = u.authRepository.UseRecoveryCode(ctx,
+		user.ID,
+		hashToken(normalizeRecoveryCode(code)),
+	); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.ErrTwoFactorCode
+		}
+		return err
+	}
+	return nil
+}
+
+func (u AuthUsecase) issueRecoveryCodes(
+	ctx context.Context,
+	userID uint64,
+) (httpModels.RecoveryCodes, error) {
+	codes := make([]string, u.twoFactor.RecoveryCodesCount)
+	stored := make([]gormModels.RecoveryCode, u.twoFactor.RecoveryCodesCount)
+	for i := range codes {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return httpModels.RecoveryCodes{}, err
+		}
+		codes[i] = code
+		stored[i] = gormModels.RecoveryCode{
+			UserID:   userID,
+			CodeHash: hashToken(normalizeRecoveryCode(code)),
+		}
+	}
+
+	if err := u.authRepository.ReplaceRecoveryCodes(ctx, userID, stored); err != nil {
+		return httpModels.RecoveryCodes{}, err
+	}
+	return httpModels.RecoveryCodes{Codes: codes}, nil
+}
@@ -0,0 +1,270 @@
+package httpBatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/problem"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/validate"
+)
+
+// methods are the methods a batch may use.
+var methods = []string{
+	http.MethodGet,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+}
+
+// sharedHeaders are copied from the batch request to every request in it,
+// so they all run as the caller.
+var sharedHeaders = []string{"Cookie", "Authorization"}
+
+type BatchHandler struct {
+	router     http.Handler
+	transactor domain.Transactor
+	maxItems   int
+}
+
+// NewBatchHandler makes the handler that runs the requests of a batch with
+// router, t begins the transactions of atomic batches.
+func NewBatchHandler(router http.Handler, t domain.Transactor, maxItems int) BatchHandler {
+	return BatchHandler{
+		router:     router,
+		transactor: t,
+		maxItems:   maxItems,
+	}
+}
+
+// Batch runs the requests one by one, each through the middlewares of its
+// route. It answers 200 with every response, and 422 when an atomic batch
+// was rolled back; the requests after the failed one are answered with 424.
+func (h BatchHandler) Batch(w http.ResponseWriter, r *http.Request) {
+	var batch httpModels.BatchRequest
+	if err := validate.DecodeJSON(r, &batch); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	if violations := h.check(r, batch.Requests); len(violations) > 0 {
+		problem.Write(w, r, domain.ValidationError{Violations: violations})
+		return
+	}
+
+	ctx := r.Context()
+	var tx domain.Transaction
+	if batch.Atomic {
+		var err error
+		if ctx, tx, err = h.transactor.Begin(ctx); err != nil {
+			problem.Write(w, r, err)
+			return
+		}
+	}
+
+	response := httpModels.BatchResponse{
+		Responses: make([]httpModels.BatchItemResponse, 0, len(batch.Requests)),
+	}
+	for _, item := range batch.Requests {
+		if response.RolledBack {
+			response.Responses = append(response.Responses, failedDependency(item))
+			continue
+		}
+
+		itemResponse := h.serve(r.WithContext(ctx), item)
+		response.Responses = append(response.Responses, itemResponse)
+		response.RolledBack = batch.Atomic && itemResponse.Status >= http.StatusBadRequest
+	}
+
+	if tx != nil {
+		var err error
+		if response.RolledBack {
+			err = tx.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+		if err != nil {
+			problem.Write(w, r, err)
+			return
+		}
+	}
+
+	responseData, err := json.Marshal(response)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	status := http.StatusOK
+	if response.RolledBack {
+		status = http.StatusUnprocessableEntity
+	}
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(status)
+	w.Write(responseData)
+}
+
+// check returns what is wrong with the requests before any of them runs. A
+// batch can't contain batches.
+func (h BatchHandler) check(r *http.Request, items []httpModels.BatchItem) []domain.FieldError {
+	var violations []domain.FieldError
+	if h.maxItems > 0 && len(items) > h.maxItems {
+		violations = append(violations, domain.FieldError{
+			Field:   "requests",
+			Rule:    "max",
+			Message: fmt.Sprintf("must have at most %d items", h.maxItems),
+		})
+	}
+
+	for i, item := range items {
+		field := fmt.Sprintf("requests[%d]", i)
+		if item.Method != "" && !isMethod(item.Method) {
+			violations = append(violations, domain.FieldError{
+				Field:   field + ".method",
+				Rule:    "oneof",
+				Message: "must be one of " + strings.Join(methods, ", "),
+			})
+		}
+
+		path, _, _ := strings.Cut(item.Path, "?")
+		switch {
+		case item.Path == "":
+		case !strings.HasPrefix(path, "/"):
+			violations = append(violations, domain.FieldError{
+				Field:   field + ".path",
+				Rule:    "path",
+				Message: "must start with /",
+			})
+		case strings.TrimSuffix(path, "/") == r.URL.Path:
+			violations = append(violations, domain.FieldError{
+				Field:   field + ".path",
+				Rule:    "path",
+				Message: "must not be a batch",
+			})
+		}
+	}
+	return violations
+}
+
+func isMethod(method string) bool {
+	for _, m := range methods {
+		if method == m {
+			return true
+		}
+	}
+	return false
+}
+
+// serve runs one request of the batch made by r.
+func (h BatchHandler) serve(r *http.Request, item httpModels.BatchItem) httpModels.BatchItemResponse {
+	var body io.Reader = http.NoBody
+	hasBody := len(item.Body) > 0 && string(item.Body) != "null"
+	if hasBody {
+		body = bytes.NewReader(item.Body)
+	}
+
+	req, err := http.NewRequestWithContext(r.Context(), item.Method, item.Path, body)
+	if err != nil {
+		return httpModels.BatchItemResponse{
+			ID:     item.ID,
+			Status: http.StatusBadRequest,
+			Body:   problemBody(domain.ErrBadRequest.Wrap(err)),
+		}
+	}
+	req.Host = r.Host
+	req.RemoteAddr = r.RemoteAddr
+	req.RequestURI = item.Path
+	for _, name := range sharedHeaders {
+		if values := r.Header.Values(name); len(values) > 0 {
+			req.Header[name] = values
+		}
+	}
+	if hasBody {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for name, value := range item.Headers {
+		req.Header.Set(name, value)
+	}
+
+	recorder := newRecorder()
+	h.router.ServeHTTP(recorder, req)
+
+	headers := make(map[string]string, len(recorder.header))
+	for name, values := range recorder.header {
+		headers[name] = strings.Join(values, ", ")
+	}
+	return httpModels.BatchItemResponse{
+		ID:      item.ID,
+		Status:  recorder.status,
+		Headers: headers,
+		Body:    jsonBody(recorder.header.Get("Content-Type"), recorder.body.Bytes()),
+	}
+}
+
+func failedDependency(item httpModels.BatchItem) httpModels.BatchItemResponse {
+	return httpModels.BatchItemResponse{
+		ID:      item.ID,
+		Status:  http.StatusFailedDependency,
+		Headers: map[string]string{"Content-Type": problem.ContentType},
+		Body:    problemBody(domain.ErrFailedDependency),
+	}
+}
+
+func problemBody(err error) json.RawMessage {
+	data, _ := json.Marshal(problem.New(err))
+	return data
+}
+
+// jsonBody keeps a JSON body as is and turns any other into a JSON string.
+func jsonBody(contentType string, body []byte) json.RawMessage {
+	if len(body) == 0 {
+		return nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")) &&
+		json.Valid(body) {
+		return bytes.TrimSpace(body)
+	}
+
+	data, _ := json.Marshal(string(body))
+	return data
+}
+
+// recorder keeps the response to one request of a batch.
+type recorder struct {
+	header      http.Header
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func newRecorder() *recorder {
+	return &recorder{
+		header: http.Header{},
+		status: http.StatusOK,
+	}
+}
+
+func (r *recorder) Header() http.Header {
+	return r.header
+}
+
+func (r *recorder) WriteHeader(status int) {
+	if r.wroteHeader {
+		return
+	}
+	r.status = status
+	r.wroteHeader = true
+}
+
+func (r *recorder) Write(data []byte) (int, error) {
+	r.WriteHeader(http.StatusOK)
+	return r.body.Write(data)
+}
@@ -0,0 +1,178 @@
+package httpBatch
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/docs"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/openapi"
+)
+
+var loadSpec = sync.OnceValues(func() (*openapi.Spec, error) {
+	return openapi.Load(docs.Swagger)
+})
+
+// strict checks the handlers behind mux against the spec, as the API is
+// checked in tests.
+func strict(t *testing.T, mux http.Handler) http.Handler {
+	spec, err := loadSpec()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return spec.Strict(mux)
+}
+
+type fakeTransaction struct {
+	ended string
+}
+
+func (t *fakeTransaction) Commit() error {
+	t.ended = "commit"
+	return nil
+}
+
+func (t *fakeTransaction) Rollback() error {
+	t.ended = "rollback"
+	return nil
+}
+
+type fakeTransactor struct {
+	tx *fakeTransaction
+}
+
+func (t *fakeTransactor) Begin(ctx context.Context) (context.Context, domain.Transaction, error) {
+	t.tx = &fakeTransaction{}
+	return ctx, t.tx, nil
+}
+
+func newRouter() *http.ServeMux {
+	router := http.NewServeMux()
+	router.HandleFunc("GET /api/v1/movies/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") != "1" {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		w.Write([]byte(`{"id":1,"cookie":"` + r.Header.Get("Cookie") + `"}` + "\n"))
+	})
+	router.HandleFunc("POST /api/v1/movies", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", r.Header.Get("Content-Type"))
+		w.WriteHeader(http.StatusCreated)
+		w.Write(body)
+	})
+	return router
+}
+
+func TestHandler_Batch(t *testing.T) {
+	tests := []struct {
+		name                 string
+		inputBody            string
+		maxItems             int
+		expectedStatusCode   int
+		expectedResponseBody string
+		expectedTransaction  string
+	}{
+		{
+			name: "OK",
+			inputBody: `{"requests":[` +
+				`{"id":"a","method":"GET","path":"/api/v1/movies/1"},` +
+				`{"id":"b","method":"POST","path":"/api/v1/movies","body":{"title":"Title"}},` +
+				`{"method":"GET","path":"/api/v1/movies/2"}]}`,
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"responses":[` +
+				`{"id":"a","status":200,"headers":{"Content-Type":"application/json; charset=UTF-8"},"body":{"id":1,"cookie":"session_id=token"}},` +
+				`{"id":"b","status":201,"headers":{"Content-Type":"application/json"},"body":{"title":"Title"}},` +
+				`{"status":404,"headers":{"Content-Type":"text/plain; charset=utf-8","X-Content-Type-Options":"nosniff"},"body":"not found\n"}]}`,
+		},
+		{
+			name: "Atomic",
+			inputBody: `{"atomic":true,"requests":[` +
+				`{"method":"POST","path":"/api/v1/movies","headers":{"Content-Type":"application/json; charset=UTF-8"},"body":{}}]}`,
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"responses":[` +
+				`{"status":201,"headers":{"Content-Type":"application/json; charset=UTF-8"},"body":{}}]}`,
+			expectedTransaction: "commit",
+		},
+		{
+			name: "Atomic with a failed request",
+			inputBody: `{"atomic":true,"requests":[` +
+				`{"method":"GET","path":"/api/v1/movies/2"},` +
+				`{"id":"b","method":"GET","path":"/api/v1/movies/1"}]}`,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"rolledBack":true,"responses":[` +
+				`{"status":404,"headers":{"Content-Type":"text/plain; charset=utf-8","X-Content-Type-Options":"nosniff"},"body":"not found\n"},` +
+				`{"id":"b","status":424,"headers":{"Content-Type":"application/problem+json"},"body":{"type":"about:blank","title":"Failed Dependency","status":424,"detail":"an earlier request of the batch failed","code":"failed_dependency"}}]}`,
+			expectedTransaction: "rollback",
+		},
+		{
+			name: "Invalid requests",
+			inputBody: `{"requests":[` +
+				`{"method":"HEAD","path":"/api/v1/movies/1"},` +
+				`{"method":"POST","path":"/api/v1/batch"},` +
+				`{"method":"GET","path":"movies"},` +
+				`{"path":"/api/v1/movies/1"}]}`,
+			maxItems:           3,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"validation failed","code":"validation_failed","violations":[` +
+				`{"field":"requests[3].method","rule":"required","message":"is required"}]}`,
+		},
+		{
+			name: "Broken rules of the batch",
+			inputBody: `{"requests":[` +
+				`{"method":"HEAD","path":"/api/v1/movies/1"},` +
+				`{"method":"POST","path":"/api/v1/batch"},` +
+				`{"method":"GET","path":"movies"}]}`,
+			maxItems:           2,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"validation failed","code":"validation_failed","violations":[` +
+				`{"field":"requests","rule":"max","message":"must have at most 2 items"},` +
+				`{"field":"requests[0].method","rule":"oneof","message":"must be one of GET, POST, PUT, PATCH, DELETE"},` +
+				`{"field":"requests[1].path","rule":"path","message":"must not be a batch"},` +
+				`{"field":"requests[2].path","rule":"path","message":"must start with /"}]}`,
+		},
+		{
+			name:               "No requests",
+			inputBody:          `{"requests":[]}`,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"validation failed","code":"validation_failed","violations":[` +
+				`{"field":"requests","rule":"min","message":"must have at least 1 items"}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transactor := &fakeTransactor{}
+			handler := NewBatchHandler(newRouter(), transactor, tt.maxItems)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("POST /api/v1/batch", handler.Batch)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(
+				http.MethodPost,
+				"/api/v1/batch",
+				bytes.NewBufferString(tt.inputBody),
+			)
+			req.Header.Set("Cookie", "session_id=token")
+
+			strict(t, mux).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, strings.Trim(w.Body.String(), "\n"))
+			if tt.expectedTransaction != "" {
+				assert.Equal(t, tt.expectedTransaction, transactor.tx.ended)
+			} else {
+				assert.Nil(t, transactor.tx)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package httpCatalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	httpMovies "github.com/themilchenko/vk-tech_internship-problem_2024/internal/movies/delivery"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/problem"
+)
+
+type CatalogHandler struct {
+	catalogUsecase domain.CatalogUsecase
+	maxLine        int
+}
+
+// NewCatalogHandler makes the handler, maxLine bounds one NDJSON line.
+func NewCatalogHandler(c domain.CatalogUsecase, maxLine int) CatalogHandler {
+	return CatalogHandler{
+		catalogUsecase: c,
+		maxLine:        maxLine,
+	}
+}
+
+// Import answers 422 with the report when an atomic import was rolled back
+// because of failed rows, and 200 with the report otherwise.
+func (h CatalogHandler) Import(w http.ResponseWriter, r *http.Request) {
+	options := domain.ImportOptions{Mode: r.URL.Query().Get("mode")}
+	if dryRun := r.URL.Query().Get("dryRun"); dryRun != "" {
+		var err error
+		if options.DryRun, err = strconv.ParseBool(dryRun); err != nil {
+			problem.Write(w, r, domain.ErrBadRequest.Wrap(err))
+			return
+		}
+	}
+
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		problem.Write(w, r, domain.ErrMediaType.Wrap(err))
+		return
+	}
+
+	var rows []httpModels.ImportRow
+	switch mediaType {
+	case CSVType:
+		rows, err = parseCSV(r.Body)
+	case NDJSONType:
+		rows, err = parseNDJSON(r.Body, h.maxLine)
+	default:
+		err = domain.ErrMediaType.Wrap(fmt.Errorf("use %s or %s", CSVType, NDJSONType))
+	}
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	report, err := h.catalogUsecase.Import(r.Context(), rows, options)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	responseData, err := json.Marshal(report)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	status := http.StatusOK
+	if !report.Applied && !report.DryRun {
+		status = http.StatusUnprocessableEntity
+	}
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(status)
+	w.Write(responseData)
+}
+
+// Export streams the catalog in the format of the format parameter, json by
+// default. It takes the filters of GET /movies. An error after the first
+// bytes are sent can't be told to the client anymore, so the connection is
+// aborted to keep the client from taking a cut export for a whole one.
+func (h CatalogHandler) Export(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = domain.ExportJSON
+	}
+
+	filter, err := httpMovies.ParseMoviesFilter(r.URL.Query())
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	body := &sentWriter{w: w}
+	encoder, err := NewExportEncoder(format, body)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	// An export can take longer than the write timeout of the server.
+	http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", exportTypes[format])
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="catalog.%s"`, format))
+
+	if err = h.catalogUsecase.Export(r.Context(), filter, encoder); err != nil {
+		if !body.sent {
+			w.Header().Del("Content-Disposition")
+			problem.Write(w, r, err)
+			return
+		}
+		slog.ErrorContext(r.Context(), "export is cut", "error", err)
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// sentWriter tells whether anything was written.
+type sentWriter struct {
+	w    io.Writer
+	sent bool
+}
+
+func (s *sentWriter) Write(p []byte) (int, error) {
+	s.sent = true
+	return s.w.Write(p)
+}
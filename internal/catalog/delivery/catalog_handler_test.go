@@ -0,0 +1,316 @@
+package httpCatalog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/docs"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	mockDomain "github.com/themilchenko/vk-tech_internship-problem_2024/internal/mocks/domain"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/metrics"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/openapi"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/requestctx"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/tracing"
+	logger "github.com/themilchenko/vk-tech_internship-problem_2024/pkg"
+	"go.uber.org/mock/gomock"
+)
+
+var loadSpec = sync.OnceValues(func() (*openapi.Spec, error) {
+	return openapi.Load(docs.Swagger)
+})
+
+// strict checks the handlers behind mux against the spec, as the API is
+// checked in tests.
+func strict(t *testing.T, mux http.Handler) http.Handler {
+	spec, err := loadSpec()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return spec.Strict(mux)
+}
+
+func TestHandler_Import(t *testing.T) {
+	type mockBehavior func(r *mockDomain.MockCatalogUsecase)
+
+	tests := []struct {
+		name                 string
+		query                string
+		contentType          string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "CSV",
+			query:       "?mode=best-effort",
+			contentType: "text/csv; charset=utf-8",
+			inputBody: "type,name,birthDate,title,releaseDate,rating,cast\n" +
+				"actor,John,1980-01-02,,,,\n" +
+				"movie,,,Title,2006-01-02,5.5,John|Jane\n" +
+				"movie,,,Other,2006-01-02,high,\n",
+			mockBehavior: func(m *mockDomain.MockCatalogUsecase) {
+				m.EXPECT().
+					Import(gomock.Any(), []httpModels.ImportRow{
+						{Line: 2, Type: "actor", Name: "John", BirthDate: "1980-01-02"},
+						{
+							Line:        3,
+							Type:        "movie",
+							Title:       "Title",
+							ReleaseDate: "2006-01-02",
+							Rating:      5.5,
+							Cast:        []string{"John", "Jane"},
+						},
+						{
+							Line:        4,
+							Type:        "movie",
+							Title:       "Other",
+							ReleaseDate: "2006-01-02",
+							Err: domain.ValidationError{Violations: []domain.FieldError{
+								{Field: "rating", Rule: "type", Message: `can't parse "high"`},
+							}},
+						},
+					}, domain.ImportOptions{Mode: "best-effort"}).
+					Return(domain.ImportReport{Mode: "best-effort", Applied: true, Rows: []domain.ImportRowReport{}}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"mode":"best-effort","dryRun":false,"applied":true,"created":0,"updated":0,"failed":0,"rows":[]}`,
+		},
+		{
+			name:        "NDJSON",
+			query:       "?dryRun=true",
+			contentType: "application/x-ndjson",
+			inputBody: `{"type":"actor","name":"John","gender":true}` + "\n\n" +
+				`{"type":"movie","title":"Title","cast":["John"]}` + "\n",
+			mockBehavior: func(m *mockDomain.MockCatalogUsecase) {
+				m.EXPECT().
+					Import(gomock.Any(), []httpModels.ImportRow{
+						{Line: 1, Type: "actor", Name: "John", Gender: true},
+						{Line: 3, Type: "movie", Title: "Title", Cast: []string{"John"}},
+					}, domain.ImportOptions{DryRun: true}).
+					Return(domain.ImportReport{Mode: "atomic", DryRun: true, Rows: []domain.ImportRowReport{}}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"mode":"atomic","dryRun":true,"applied":false,"created":0,"updated":0,"failed":0,"rows":[]}`,
+		},
+		{
+			name:        "Rolled back",
+			contentType: "application/x-ndjson",
+			inputBody:   `{"type":"movie","title":"Title","rating":11}`,
+			mockBehavior: func(m *mockDomain.MockCatalogUsecase) {
+				m.EXPECT().
+					Import(gomock.Any(), gomock.Any(), domain.ImportOptions{}).
+					Return(domain.ImportReport{Mode: "atomic", Failed: 1, Rows: []domain.ImportRowReport{}}, nil)
+			},
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"mode":"atomic","dryRun":false,"applied":false,"created":0,"updated":0,"failed":1,"rows":[]}`,
+		},
+		{
+			name:                 "Unknown column",
+			contentType:          "text/csv",
+			inputBody:            "type,year\nmovie,2006\n",
+			mockBehavior:         func(m *mockDomain.MockCatalogUsecase) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"bad request: unknown column \"year\"","code":"bad_request"}`,
+		},
+		{
+			name:                 "Bad dryRun",
+			query:                "?dryRun=maybe",
+			contentType:          "text/csv",
+			mockBehavior:         func(m *mockDomain.MockCatalogUsecase) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"bad request: strconv.ParseBool: parsing \"maybe\": invalid syntax","code":"bad_request"}`,
+		},
+		{
+			name:                 "Unsupported media type",
+			contentType:          "application/json",
+			inputBody:            `[]`,
+			mockBehavior:         func(m *mockDomain.MockCatalogUsecase) {},
+			expectedStatusCode:   http.StatusUnsupportedMediaType,
+			expectedResponseBody: `{"type":"about:blank","title":"Unsupported Media Type","status":415,"detail":"unsupported media type: use text/csv or application/x-ndjson","code":"unsupported_media_type"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cntx := gomock.NewController(t)
+			defer cntx.Finish()
+
+			mockCatalogUsecase := mockDomain.NewMockCatalogUsecase(cntx)
+
+			tt.mockBehavior(mockCatalogUsecase)
+
+			handler := NewCatalogHandler(mockCatalogUsecase, 1024)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("POST /import", handler.Import)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(
+				http.MethodPost,
+				"/import"+tt.query,
+				bytes.NewBufferString(tt.inputBody),
+			)
+			req.Header.Set("Content-Type", tt.contentType)
+
+			strict(t, mux).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, strings.Trim(w.Body.String(), "\n"))
+		})
+	}
+}
+
+func TestHandler_Export(t *testing.T) {
+	type mockBehavior func(r *mockDomain.MockCatalogUsecase)
+
+	tests := []struct {
+		name                 string
+		query                string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedContentType  string
+		expectedResponseBody string
+	}{
+		{
+			name:  "CSV",
+			query: "?format=csv&title=god&filter=title&order=false",
+			mockBehavior: func(m *mockDomain.MockCatalogUsecase) {
+				m.EXPECT().
+					Export(gomock.Any(), httpModels.MoviesFilter{
+						Title:  "god",
+						SortBy: "title",
+					}, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ httpModels.MoviesFilter, e domain.ExportEncoder) error {
+						e.Movie(httpModels.MovieResponse{ID: 1, Title: "The Godfather", ReleaseDate: "1972-03-24"})
+						return e.Close()
+					})
+			},
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "text/csv; charset=UTF-8",
+			expectedResponseBody: "type,id,title,description,releaseDate,rating,cast,name,gender,birthDate,movies\n" +
+				"movie,1,The Godfather,,1972-03-24,0,,,,,",
+		},
+		{
+			name: "JSON by default",
+			mockBehavior: func(m *mockDomain.MockCatalogUsecase) {
+				m.EXPECT().
+					Export(gomock.Any(), httpModels.MoviesFilter{SortBy: "rating", Order: true}, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ httpModels.MoviesFilter, e domain.ExportEncoder) error {
+						return e.Close()
+					})
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedContentType:  "application/json; charset=UTF-8",
+			expectedResponseBody: `{"movies":[],"actors":[]}`,
+		},
+		{
+			name: "Error before the first bytes",
+			mockBehavior: func(m *mockDomain.MockCatalogUsecase) {
+				m.EXPECT().
+					Export(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(errors.New("db is down"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedContentType:  "application/problem+json",
+			expectedResponseBody: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"server error","code":"internal_error"}`,
+		},
+		{
+			name:                 "Unknown format",
+			query:                "?format=xml",
+			mockBehavior:         func(m *mockDomain.MockCatalogUsecase) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedContentType:  "application/problem+json",
+			expectedResponseBody: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"bad request: format must be json, ndjson or csv","code":"bad_request"}`,
+		},
+		{
+			name:                 "Bad filter",
+			query:                "?filter=year",
+			mockBehavior:         func(m *mockDomain.MockCatalogUsecase) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedContentType:  "application/problem+json",
+			expectedResponseBody: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"bad request","code":"bad_request"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cntx := gomock.NewController(t)
+			defer cntx.Finish()
+
+			mockCatalogUsecase := mockDomain.NewMockCatalogUsecase(cntx)
+
+			tt.mockBehavior(mockCatalogUsecase)
+
+			handler := NewCatalogHandler(mockCatalogUsecase, 1024)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /export", handler.Export)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/export"+tt.query, nil)
+
+			strict(t, mux).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedContentType, w.Header().Get("Content-Type"))
+			assert.Equal(t, tt.expectedResponseBody, strings.Trim(w.Body.String(), "\n"))
+		})
+	}
+}
+
+// An export cut after the first bytes must cut the connection through all
+// the middlewares of the server, not end in a 500 glued to the body.
+func TestHandler_ExportAborted(t *testing.T) {
+	for _, format := range []string{"json", "csv"} {
+		t.Run(format, func(t *testing.T) {
+			cntx := gomock.NewController(t)
+			defer cntx.Finish()
+
+			mockCatalogUsecase := mockDomain.NewMockCatalogUsecase(cntx)
+			mockCatalogUsecase.EXPECT().
+				Export(gomock.Any(), gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ httpModels.MoviesFilter, e domain.ExportEncoder) error {
+					// More than the buffer of the encoder, so that bytes are sent.
+					for i := uint64(1); i <= 200; i++ {
+						e.Movie(httpModels.MovieResponse{ID: i, Title: "The Godfather", ReleaseDate: "1972-03-24"})
+					}
+					return errors.New("db is down")
+				})
+
+			handler := NewCatalogHandler(mockCatalogUsecase, 1024)
+
+			spec, err := openapi.Load(docs.Swagger)
+			assert.NoError(t, err)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /api/v1/export", handler.Export)
+
+			server := httptest.NewServer(requestctx.Middleware(
+				tracing.Middleware(metrics.Middleware(logger.Middleware(
+					spec.Middleware(openapi.ModeLog)(mux),
+				))),
+			))
+			defer server.Close()
+
+			resp, err := http.Get(server.URL + "/api/v1/export?format=" + format)
+			if err != nil {
+				return
+			}
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+			assert.NotContains(t, string(body), "Internal Server Error")
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package httpCatalog
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+)
+
+// exportTypes are the content types of the export formats.
+var exportTypes = map[string]string{
+	domain.ExportJSON:   "application/json; charset=UTF-8",
+	domain.ExportNDJSON: NDJSONType,
+	domain.ExportCSV:    CSVType + "; charset=UTF-8",
+}
+
+// exportColumns are the import columns with the ids and the filmography of
+// actors added.
+var exportColumns = []string{
+	"type",
+	"id",
+	"title",
+	"description",
+	"releaseDate",
+	"rating",
+	"cast",
+	"name",
+	"gender",
+	"birthDate",
+	"movies",
+}
+
+// NewExportEncoder returns the encoder of the format that writes to w.
+func NewExportEncoder(format string, w io.Writer) (domain.ExportEncoder, error) {
+	switch format {
+	case domain.ExportJSON:
+		return &jsonEncoder{w: bufio.NewWriter(w)}, nil
+	case domain.ExportNDJSON:
+		buf := bufio.NewWriter(w)
+		return ndjsonEncoder{w: buf, encoder: json.NewEncoder(buf)}, nil
+	case domain.ExportCSV:
+		encoder := csvEncoder{w: csv.NewWriter(w)}
+		return encoder, encoder.w.Write(exportColumns)
+	}
+	return nil, domain.ErrBadRequest.Wrap(fmt.Errorf(
+		"format must be %s, %s or %s",
+		domain.ExportJSON,
+		domain.ExportNDJSON,
+		domain.ExportCSV,
+	))
+}
+
+// jsonEncoder writes one object with the movies and actors arrays, the way
+// GET /movies and GET /actors return them.
+type jsonEncoder struct {
+	w       *bufio.Writer
+	section string
+}
+
+func (e *jsonEncoder) Movie(movie httpModels.MovieResponse) error {
+	return e.item("movies", movie)
+}
+
+func (e *jsonEncoder) Actor(actor httpModels.GetActorsResponse) error {
+	return e.item("actors", actor)
+}
+
+func (e *jsonEncoder) Close() error {
+	e.open("actors")
+	e.w.WriteString("]}\n")
+	return e.w.Flush()
+}
+
+func (e *jsonEncoder) item(section string, v any) error {
+	if e.section == section {
+		e.w.WriteByte(',')
+	} else {
+		e.open(section)
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = e.w.Write(data)
+	return err
+}
+
+// open closes the arrays before the section and opens it.
+func (e *jsonEncoder) open(section string) {
+	for e.section != section {
+		switch e.section {
+		case "":
+			e.w.WriteString(`{"movies":[`)
+			e.section = "movies"
+		case "movies":
+			e.w.WriteString(`],"actors":[`)
+			e.section = "actors"
+		}
+	}
+}
+
+// ndjsonEncoder writes a line per movie or actor, told apart by type.
+type ndjsonEncoder struct {
+	w       *bufio.Writer
+	encoder *json.Encoder
+}
+
+func (e ndjsonEncoder) Movie(movie httpModels.MovieResponse) error {
+	return e.encoder.Encode(struct {
+		Type string `json:"type"`
+		httpModels.MovieResponse
+	}{domain.MovieEntity, movie})
+}
+
+func (e ndjsonEncoder) Actor(actor httpModels.GetActorsResponse) error {
+	return e.encoder.Encode(struct {
+		Type string `json:"type"`
+		httpModels.GetActorsResponse
+	}{domain.ActorEntity, actor})
+}
+
+func (e ndjsonEncoder) Close() error {
+	return e.w.Flush()
+}
+
+// csvEncoder writes a record per movie or actor in exportColumns. The cast
+// and the movies are names and titles split by castSeparator, like in an
+// import.
+type csvEncoder struct {
+	w *csv.Writer
+}
+
+func (e csvEncoder) Movie(movie httpModels.MovieResponse) error {
+	cast := make([]string, len(movie.CastList))
+	for i, actor := range movie.CastList {
+		cast[i] = actor.Name
+	}
+
+	return e.w.Write([]string{
+		domain.MovieEntity,
+		strconv.FormatUint(movie.ID, 10),
+		movie.Title,
+		movie.Description,
+		movie.ReleaseDate,
+		strconv.FormatFloat(float64(movie.Rating), 'f', -1, 32),
+		strings.Join(cast, castSeparator),
+		"",
+		"",
+		"",
+		"",
+	})
+}
+
+func (e csvEncoder) Actor(actor httpModels.GetActorsResponse) error {
+	movies := make([]string, len(actor.ActedInFilms))
+	for i, movie := range actor.ActedInFilms {
+		movies[i] = movie.Title
+	}
+
+	return e.w.Write([]string{
+		domain.ActorEntity,
+		strconv.FormatUint(actor.Actor.ID, 10),
+		"",
+		"",
+		"",
+		"",
+		"",
+		actor.Actor.Name,
+		strconv.FormatBool(actor.Actor.Gender),
+		actor.Actor.BirthDate,
+		strings.Join(movies, castSeparator),
+	})
+}
+
+func (e csvEncoder) Close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
@@ -0,0 +1,106 @@
+package httpCatalog
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+)
+
+func TestExportEncoder(t *testing.T) {
+	movies := []httpModels.MovieResponse{
+		{
+			ID:          1,
+			Title:       "The Godfather",
+			Description: "Family, \"business\"",
+			ReleaseDate: "1972-03-24",
+			Rating:      9.2,
+			CastList: []httpModels.ActorResponse{
+				{ID: 2, Name: "Marlon Brando", Gender: true, BirthDate: "1924-04-03"},
+				{ID: 3, Name: "Al Pacino", Gender: true, BirthDate: "1940-04-25"},
+			},
+		},
+		{ID: 4, Title: "Unknown", ReleaseDate: "2000-01-01"},
+	}
+	actors := []httpModels.GetActorsResponse{
+		{
+			Actor: httpModels.ActorResponse{ID: 2, Name: "Marlon Brando", Gender: true, BirthDate: "1924-04-03"},
+			ActedInFilms: []httpModels.MovieWithoutCastList{
+				{ID: 1, Title: "The Godfather", ReleaseDate: "1972-03-24", Rating: 9.2},
+			},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		format         string
+		movies         []httpModels.MovieResponse
+		actors         []httpModels.GetActorsResponse
+		expectedOutput string
+	}{
+		{
+			name:   "JSON",
+			format: domain.ExportJSON,
+			movies: movies,
+			actors: actors,
+			expectedOutput: `{"movies":[` +
+				`{"id":1,"title":"The Godfather","description":"Family, \"business\"","releaseDate":"1972-03-24","rating":9.2,"castList":[{"id":2,"name":"Marlon Brando","gender":true,"birthDate":"1924-04-03"},{"id":3,"name":"Al Pacino","gender":true,"birthDate":"1940-04-25"}]},` +
+				`{"id":4,"title":"Unknown","releaseDate":"2000-01-01"}],` +
+				`"actors":[{"actor":{"id":2,"name":"Marlon Brando","gender":true,"birthDate":"1924-04-03"},"actedInFilms":[{"id":1,"title":"The Godfather","description":"","releaseDate":"1972-03-24","rating":9.2}]}]}` + "\n",
+		},
+		{
+			name:           "Empty JSON",
+			format:         domain.ExportJSON,
+			expectedOutput: `{"movies":[],"actors":[]}` + "\n",
+		},
+		{
+			name:   "Only actors in JSON",
+			format: domain.ExportJSON,
+			actors: []httpModels.GetActorsResponse{
+				{Actor: httpModels.ActorResponse{ID: 5, Name: "John"}},
+			},
+			expectedOutput: `{"movies":[],"actors":[{"actor":{"id":5,"name":"John","gender":false}}]}` + "\n",
+		},
+		{
+			name:   "NDJSON",
+			format: domain.ExportNDJSON,
+			movies: movies[1:],
+			actors: actors,
+			expectedOutput: `{"type":"movie","id":4,"title":"Unknown","releaseDate":"2000-01-01"}` + "\n" +
+				`{"type":"actor","actor":{"id":2,"name":"Marlon Brando","gender":true,"birthDate":"1924-04-03"},"actedInFilms":[{"id":1,"title":"The Godfather","description":"","releaseDate":"1972-03-24","rating":9.2}]}` + "\n",
+		},
+		{
+			name:   "CSV",
+			format: domain.ExportCSV,
+			movies: movies,
+			actors: actors,
+			expectedOutput: "type,id,title,description,releaseDate,rating,cast,name,gender,birthDate,movies\n" +
+				`movie,1,The Godfather,"Family, ""business""",1972-03-24,9.2,Marlon Brando|Al Pacino,,,,` + "\n" +
+				"movie,4,Unknown,,2000-01-01,0,,,,,\n" +
+				"actor,2,,,,,,Marlon Brando,true,1924-04-03,The Godfather\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			encoder, err := NewExportEncoder(tt.format, &output)
+			assert.NoError(t, err)
+
+			for _, movie := range tt.movies {
+				assert.NoError(t, encoder.Movie(movie))
+			}
+			for _, actor := range tt.actors {
+				assert.NoError(t, encoder.Actor(actor))
+			}
+			assert.NoError(t, encoder.Close())
+
+			assert.Equal(t, tt.expectedOutput, output.String())
+		})
+	}
+
+	_, err := NewExportEncoder("xml", &bytes.Buffer{})
+	assert.ErrorIs(t, err, domain.ErrBadRequest)
+}
@@ -0,0 +1,161 @@
+package httpCatalog
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/validate"
+)
+
+const (
+	CSVType    = "text/csv"
+	NDJSONType = "application/x-ndjson"
+
+	// castSeparator splits the names in the cast column of a CSV file.
+	castSeparator = "|"
+)
+
+var csvColumns = map[string]bool{
+	"type":        true,
+	"title":       true,
+	"description": true,
+	"releaseDate": true,
+	"rating":      true,
+	"cast":        true,
+	"name":        true,
+	"gender":      true,
+	"birthDate":   true,
+}
+
+// parseCSV reads rows of a CSV file whose header names the columns, any
+// subset of csvColumns in any order. Broken values fail only their row.
+func parseCSV(body io.Reader) ([]httpModels.ImportRow, error) {
+	reader := csv.NewReader(body)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, readError(err)
+	}
+	for _, column := range header {
+		if !csvColumns[column] {
+			return nil, domain.ErrBadRequest.Wrap(fmt.Errorf("unknown column %q", column))
+		}
+	}
+
+	var rows []httpModels.ImportRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) && errors.Is(err, csv.ErrFieldCount) {
+			rows = append(rows, httpModels.ImportRow{
+				Line: parseErr.StartLine,
+				Err:  domain.ErrBadRequest.Wrap(parseErr.Err),
+			})
+			continue
+		}
+		if err != nil {
+			return nil, readError(err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		rows = append(rows, csvRow(line, header, record))
+	}
+}
+
+func csvRow(line int, header, record []string) httpModels.ImportRow {
+	row := httpModels.ImportRow{Line: line}
+
+	var violations []domain.FieldError
+	for i, value := range record {
+		var err error
+		switch header[i] {
+		case "type":
+			row.Type = value
+		case "title":
+			row.Title = value
+		case "description":
+			row.Description = value
+		case "releaseDate":
+			row.ReleaseDate = value
+		case "rating":
+			if value != "" {
+				var rating float64
+				rating, err = strconv.ParseFloat(value, 32)
+				row.Rating = float32(rating)
+			}
+		case "cast":
+			if value != "" {
+				row.Cast = strings.Split(value, castSeparator)
+			}
+		case "name":
+			row.Name = value
+		case "gender":
+			if value != "" {
+				row.Gender, err = strconv.ParseBool(value)
+			}
+		case "birthDate":
+			row.BirthDate = value
+		}
+
+		if err != nil {
+			violations = append(violations, domain.FieldError{
+				Field:   header[i],
+				Rule:    "type",
+				Message: fmt.Sprintf("can't parse %q", value),
+			})
+		}
+	}
+
+	if len(violations) > 0 {
+		row.Err = domain.ValidationError{Violations: violations}
+	}
+	return row
+}
+
+// parseNDJSON reads one JSON row per line, blank lines are skipped.
+func parseNDJSON(body io.Reader, maxLine int) ([]httpModels.ImportRow, error) {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLine)
+
+	var rows []httpModels.ImportRow
+	for line := 1; scanner.Scan(); line++ {
+		data := scanner.Bytes()
+		if len(strings.TrimSpace(string(data))) == 0 {
+			continue
+		}
+
+		row := httpModels.ImportRow{Line: line}
+		if err := validate.Unmarshal(data, &row); err != nil {
+			row.Err = err
+		}
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, readError(err)
+	}
+	return rows, nil
+}
+
+func readError(err error) error {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return domain.ErrBodyTooLarge.Wrap(err)
+	}
+	return domain.ErrBadRequest.Wrap(err)
+}
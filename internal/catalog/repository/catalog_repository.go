@@ -0,0 +1,239 @@
+package catalogRepository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	actorsRepository "github.com/themilchenko/vk-tech_internship-problem_2024/internal/actors/repository"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	gormModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/gorm"
+	moviesRepository "github.com/themilchenko/vk-tech_internship-problem_2024/internal/movies/repository"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/dbtx"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/metrics"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/pgerrors"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/sqlcomment"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/tracing"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+)
+
+// errRollback makes Transaction roll back an import that must not be saved.
+var errRollback = errors.New("import is rolled back")
+
+type Postgres struct {
+	DB *gorm.DB
+}
+
+func NewPostgres(url string, logger gormLogger.Interface) (*Postgres, error) {
+	db, err := gorm.Open(postgres.Open(url), &gorm.Config{Logger: logger})
+	if err != nil {
+		return nil, err
+	}
+	if err = db.Use(pgerrors.Plugin{}); err != nil {
+		return nil, err
+	}
+	if err = db.Use(dbtx.Plugin{}); err != nil {
+		return nil, err
+	}
+	if err = db.Use(sqlcomment.Plugin{}); err != nil {
+		return nil, err
+	}
+	if err = db.Use(metrics.Plugin{DB: "catalog"}); err != nil {
+		return nil, err
+	}
+	if err = db.Use(tracing.Plugin{DB: "catalog"}); err != nil {
+		return nil, err
+	}
+
+	return &Postgres{
+		DB: db,
+	}, nil
+}
+
+// Import saves every row in a savepoint of one transaction, so a failed row
+// doesn't take the others with it. Rows go through the movie and actor
+// repositories, so they are audited and versioned like any other change.
+func (db Postgres) Import(
+	ctx context.Context,
+	rows []gormModels.ImportRow,
+	atomic, dryRun bool,
+) ([]gormModels.ImportResult, bool, error) {
+	results := make([]gormModels.ImportResult, len(rows))
+
+	err := db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		actors := actorsRepository.Postgres{DB: tx}
+		movies := moviesRepository.Postgres{DB: tx}
+
+		failed := false
+		for _, pass := range []bool{true, false} {
+			for i, row := range rows {
+				if (row.Actor != nil) != pass {
+					continue
+				}
+
+				err := tx.Transaction(func(tx *gorm.DB) error {
+					var err error
+					if row.Actor != nil {
+						results[i], err = importActor(ctx, tx, actors, *row.Actor)
+					} else {
+						results[i], err = importMovie(ctx, tx, movies, *row.Movie, row.Cast)
+					}
+					return err
+				})
+				if err != nil {
+					results[i] = gormModels.ImportResult{Err: err}
+					failed = true
+				}
+			}
+		}
+
+		if dryRun || (atomic && failed) {
+			return errRollback
+		}
+		return nil
+	})
+	if errors.Is(err, errRollback) {
+		return results, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return results, true, nil
+}
+
+func importActor(
+	ctx context.Context,
+	tx *gorm.DB,
+	actors actorsRepository.Postgres,
+	actor gormModels.Actor,
+) (gormModels.ImportResult, error) {
+	var existing gormModels.Actor
+	err := tx.Unscoped().Where("name = ?", actor.Name).First(&existing).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		actorID, err := actors.CreateActor(ctx, actor)
+		return gormModels.ImportResult{ID: actorID, Created: true}, err
+	}
+	if err != nil {
+		return gormModels.ImportResult{}, err
+	}
+	if existing.DeletedAt.Valid {
+		return gormModels.ImportResult{}, domain.ErrConflict.Wrap(
+			fmt.Errorf("actor %q is in the trash", actor.Name),
+		)
+	}
+
+	_, err = actors.PatchActor(ctx, existing.ID, 0, func(a *gormModels.Actor) error {
+		a.Gender = actor.Gender
+		a.BirthDate = actor.BirthDate
+		return nil
+	})
+	return gormModels.ImportResult{ID: existing.ID}, err
+}
+
+// importMovie creates or updates the movie and makes its cast the named
+// actors.
+func importMovie(
+	ctx context.Context,
+	tx *gorm.DB,
+	movies moviesRepository.Postgres,
+	movie gormModels.Movie,
+	cast []string,
+) (gormModels.ImportResult, error) {
+	castIDs, err := findActors(tx, cast)
+	if err != nil {
+		return gormModels.ImportResult{}, err
+	}
+
+	var existing gormModels.Movie
+	err = tx.Unscoped().Where("title = ?", movie.Title).First(&existing).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		movieID, err := movies.CreateMovieWithCastList(ctx, movie, castIDs)
+		return gormModels.ImportResult{ID: movieID, Created: true}, err
+	}
+	if err != nil {
+		return gormModels.ImportResult{}, err
+	}
+	if existing.DeletedAt.Valid {
+		return gormModels.ImportResult{}, domain.ErrConflict.Wrap(
+			fmt.Errorf("movie %q is in the trash", movie.Title),
+		)
+	}
+
+	if _, err = movies.PatchMovie(ctx, existing.ID, 0, func(m *gormModels.Movie) error {
+		m.Description = movie.Description
+		m.ReleaseDate = movie.ReleaseDate
+		m.Rating = movie.Rating
+		return nil
+	}); err != nil {
+		return gormModels.ImportResult{}, err
+	}
+
+	var currentIDs []uint64
+	if err = tx.Model(&gormModels.ActorMovieRelation{}).
+		Where("movie_id = ?", existing.ID).
+		Pluck("actor_id", &currentIDs).
+		Error; err != nil {
+		return gormModels.ImportResult{}, err
+	}
+
+	wanted := make(map[uint64]bool, len(castIDs))
+	for _, actorID := range castIDs {
+		wanted[actorID] = true
+	}
+	for _, actorID := range currentIDs {
+		if wanted[actorID] {
+			delete(wanted, actorID)
+			continue
+		}
+		if err = movies.DeleteActorFromMovie(ctx, existing.ID, actorID); err != nil {
+			return gormModels.ImportResult{}, err
+		}
+	}
+	for _, actorID := range castIDs {
+		if !wanted[actorID] {
+			continue
+		}
+		if err = movies.AddActorToMovie(ctx, existing.ID, actorID); err != nil {
+			return gormModels.ImportResult{}, err
+		}
+	}
+	return gormModels.ImportResult{ID: existing.ID}, nil
+}
+
+// findActors returns the ids of the named actors in the same order. A name
+// that isn't found is a violation of the cast item.
+func findActors(tx *gorm.DB, names []string) ([]uint64, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	var actors []gormModels.Actor
+	if err := tx.Where("name IN ?", names).Find(&actors).Error; err != nil {
+		return nil, err
+	}
+	idsByName := make(map[string]uint64, len(actors))
+	for _, actor := range actors {
+		idsByName[actor.Name] = actor.ID
+	}
+
+	ids := make([]uint64, 0, len(names))
+	var violations []domain.FieldError
+	for i, name := range names {
+		actorID, ok := idsByName[name]
+		if !ok {
+			violations = append(violations, domain.FieldError{
+				Field:   fmt.Sprintf("cast[%d]", i),
+				Rule:    "exists",
+				Message: fmt.Sprintf("actor %q not found", name),
+			})
+			continue
+		}
+		ids = append(ids, actorID)
+	}
+	if len(violations) > 0 {
+		return nil, domain.ValidationError{Violations: violations}
+	}
+	return ids, nil
+}
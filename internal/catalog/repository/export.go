@@ -0,0 +1,171 @@
+package catalogRepository
+
+import (
+	"context"
+
+	gormModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/gorm"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	moviesRepository "github.com/themilchenko/vk-tech_internship-problem_2024/internal/movies/repository"
+	"gorm.io/gorm"
+)
+
+// exportBatchSize is how many rows of a cursor are joined with their
+// relations at once. It bounds the memory an export takes.
+const exportBatchSize = 500
+
+func (db Postgres) ExportMovies(
+	ctx context.Context,
+	filter httpModels.MoviesFilter,
+	fn func(movie gormModels.Movie, cast []gormModels.Actor) error,
+) error {
+	query := moviesRepository.FilterMovies(
+		db.DB.WithContext(ctx).Model(&gormModels.Movie{}).Select("movies.*"),
+		filter.Title,
+		filter.Actor,
+		filter.SortBy,
+		filter.Order,
+	)
+
+	return scanBatches(db.DB, query, func(movies []gormModels.Movie) error {
+		ids := make([]uint64, len(movies))
+		for i, movie := range movies {
+			ids[i] = movie.ID
+		}
+
+		var relations []gormModels.ActorMovieRelation
+		if err := db.DB.WithContext(ctx).
+			Where("movie_id IN ?", ids).
+			Order("id").
+			Find(&relations).
+			Error; err != nil {
+			return err
+		}
+		actorIDs := make([]uint64, len(relations))
+		for i, relation := range relations {
+			actorIDs[i] = relation.ActorID
+		}
+
+		var actors []gormModels.Actor
+		if err := db.DB.WithContext(ctx).Where("id IN ?", actorIDs).Find(&actors).Error; err != nil {
+			return err
+		}
+		actorsByID := make(map[uint64]gormModels.Actor, len(actors))
+		for _, actor := range actors {
+			actorsByID[actor.ID] = actor
+		}
+
+		cast := make(map[uint64][]gormModels.Actor, len(movies))
+		for _, relation := range relations {
+			if actor, ok := actorsByID[relation.ActorID]; ok {
+				cast[relation.MovieID] = append(cast[relation.MovieID], actor)
+			}
+		}
+
+		for _, movie := range movies {
+			if err := fn(movie, cast[movie.ID]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (db Postgres) ExportActors(
+	ctx context.Context,
+	filter httpModels.MoviesFilter,
+	fn func(actor gormModels.Actor, movies []gormModels.Movie) error,
+) error {
+	query := db.DB.WithContext(ctx).Model(&gormModels.Actor{}).Order("id")
+	if filter.Title != "" || filter.Actor != "" {
+		movieIDs := moviesRepository.FilterMovies(
+			db.DB.Model(&gormModels.Movie{}).Select("movies.id"),
+			filter.Title,
+			filter.Actor,
+			"",
+			true,
+		)
+		query = query.Where(
+			"id IN (?)",
+			db.DB.Model(&gormModels.ActorMovieRelation{}).
+				Select("actor_id").
+				Where("movie_id IN (?)", movieIDs),
+		)
+	}
+
+	return scanBatches(db.DB, query, func(actors []gormModels.Actor) error {
+		ids := make([]uint64, len(actors))
+		for i, actor := range actors {
+			ids[i] = actor.ID
+		}
+
+		var relations []gormModels.ActorMovieRelation
+		if err := db.DB.WithContext(ctx).
+			Where("actor_id IN ?", ids).
+			Order("id").
+			Find(&relations).
+			Error; err != nil {
+			return err
+		}
+		movieIDs := make([]uint64, len(relations))
+		for i, relation := range relations {
+			movieIDs[i] = relation.MovieID
+		}
+
+		var movies []gormModels.Movie
+		if err := db.DB.WithContext(ctx).Where("id IN ?", movieIDs).Find(&movies).Error; err != nil {
+			return err
+		}
+		moviesByID := make(map[uint64]gormModels.Movie, len(movies))
+		for _, movie := range movies {
+			moviesByID[movie.ID] = movie
+		}
+
+		filmography := make(map[uint64][]gormModels.Movie, len(actors))
+		for _, relation := range relations {
+			if movie, ok := moviesByID[relation.MovieID]; ok {
+				filmography[relation.ActorID] = append(filmography[relation.ActorID], movie)
+			}
+		}
+
+		for _, actor := range actors {
+			if err := fn(actor, filmography[actor.ID]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// scanBatches reads the query with a cursor and hands the rows to fn
+// exportBatchSize at a time, so the whole result is never in memory.
+func scanBatches[T any](db *gorm.DB, query *gorm.DB, fn func(batch []T) error) error {
+	rows, err := query.Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	batch := make([]T, 0, exportBatchSize)
+	for rows.Next() {
+		var item T
+		if err = db.ScanRows(rows, &item); err != nil {
+			return err
+		}
+		batch = append(batch, item)
+
+		if len(batch) == exportBatchSize {
+			if err = fn(batch); err != nil {
+				return err
+			}
+			batch = batch[:0]
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	if len(batch) == 0 {
+		return nil
+	}
+	return fn(batch)
+}
@@ -0,0 +1,57 @@
+package catalogUsecase
+
+import (
+	"context"
+
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/cache"
+)
+
+// CachedCatalogUsecase drops the cached reads of the items an import has
+// changed.
+type CachedCatalogUsecase struct {
+	catalogUsecase domain.CatalogUsecase
+	cache          domain.Cache
+}
+
+func NewCachedCatalogUsecase(u domain.CatalogUsecase, c domain.Cache) CachedCatalogUsecase {
+	return CachedCatalogUsecase{
+		catalogUsecase: u,
+		cache:          c,
+	}
+}
+
+func (u CachedCatalogUsecase) Import(
+	ctx context.Context,
+	rows []httpModels.ImportRow,
+	options domain.ImportOptions,
+) (domain.ImportReport, error) {
+	report, err := u.catalogUsecase.Import(ctx, rows, options)
+	if err != nil || !report.Applied {
+		return report, err
+	}
+
+	tags := []string{cache.MoviesTag, cache.ActorsTag}
+	for _, row := range report.Rows {
+		if row.ID == 0 {
+			continue
+		}
+		if row.Type == domain.ActorEntity {
+			tags = append(tags, cache.ActorTag(row.ID))
+		} else {
+			tags = append(tags, cache.MovieTag(row.ID))
+		}
+	}
+	u.cache.Invalidate(ctx, tags...)
+	return report, nil
+}
+
+// Export streams the database as it is, so it skips the cache.
+func (u CachedCatalogUsecase) Export(
+	ctx context.Context,
+	filter httpModels.MoviesFilter,
+	encoder domain.ExportEncoder,
+) error {
+	return u.catalogUsecase.Export(ctx, filter, encoder)
+}
@@ -0,0 +1,246 @@
+package catalogUsecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	actorsUsecase "github.com/themilchenko/vk-tech_internship-problem_2024/internal/actors/usecase"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	gormModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/gorm"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	moviesUsecase "github.com/themilchenko/vk-tech_internship-problem_2024/internal/movies/usecase"
+)
+
+type CatalogUsecase struct {
+	catalogRepository domain.CatalogRepository
+}
+
+func NewCatalogUsecase(c domain.CatalogRepository) CatalogUsecase {
+	return CatalogUsecase{
+		catalogRepository: c,
+	}
+}
+
+// Import checks every row like the create endpoints do and upserts the
+// valid ones. An atomic import with invalid rows still goes to the database
+// as a dry run, so the report covers every row at once.
+func (u CatalogUsecase) Import(
+	ctx context.Context,
+	rows []httpModels.ImportRow,
+	options domain.ImportOptions,
+) (domain.ImportReport, error) {
+	switch options.Mode {
+	case "":
+		options.Mode = domain.ImportAtomic
+	case domain.ImportAtomic, domain.ImportBestEffort:
+	default:
+		return domain.ImportReport{}, domain.ErrBadRequest.Wrap(
+			fmt.Errorf("mode must be %s or %s", domain.ImportAtomic, domain.ImportBestEffort),
+		)
+	}
+	if len(rows) == 0 {
+		return domain.ImportReport{}, domain.ErrBadRequest.Wrap(errors.New("nothing to import"))
+	}
+
+	report := domain.ImportReport{
+		Mode:   options.Mode,
+		DryRun: options.DryRun,
+		Rows:   make([]domain.ImportRowReport, len(rows)),
+	}
+
+	var valid []gormModels.ImportRow
+	var validIndexes []int
+	for i, row := range rows {
+		report.Rows[i] = domain.ImportRowReport{
+			Line: row.Line,
+			Type: row.Type,
+			Key:  row.Title,
+		}
+		if row.Type == domain.ActorEntity {
+			report.Rows[i].Key = row.Name
+		}
+
+		gormRow, err := checkRow(row)
+		if err != nil {
+			fail(ctx, &report, i, err)
+			continue
+		}
+		valid = append(valid, gormRow)
+		validIndexes = append(validIndexes, i)
+	}
+
+	atomic := options.Mode == domain.ImportAtomic
+	dryRun := options.DryRun || (atomic && report.Failed > 0)
+
+	results, applied, err := u.catalogRepository.Import(ctx, valid, atomic, dryRun)
+	if err != nil {
+		return domain.ImportReport{}, err
+	}
+	report.Applied = applied
+
+	for j, result := range results {
+		i := validIndexes[j]
+		if result.Err != nil {
+			fail(ctx, &report, i, result.Err)
+			continue
+		}
+
+		if result.Created {
+			report.Rows[i].Action = domain.ImportCreated
+			report.Created++
+		} else {
+			report.Rows[i].Action = domain.ImportUpdated
+			report.Updated++
+		}
+		if applied {
+			report.Rows[i].ID = result.ID
+		}
+	}
+	slog.InfoContext(ctx, "catalog imported",
+		"created", report.Created,
+		"updated", report.Updated,
+		"failed", report.Failed,
+		"applied", report.Applied,
+	)
+	return report, nil
+}
+
+// Export writes the movies picked by the filter and then the actors,
+// in the shapes of GET /movies and GET /actors.
+func (u CatalogUsecase) Export(
+	ctx context.Context,
+	filter httpModels.MoviesFilter,
+	encoder domain.ExportEncoder,
+) error {
+	if err := u.catalogRepository.ExportMovies(
+		ctx,
+		filter,
+		func(movie gormModels.Movie, cast []gormModels.Actor) error {
+			httpMovie := movie.ToHTTPResponse()
+			httpMovie.CastList = make([]httpModels.ActorResponse, len(cast))
+			for i, actor := range cast {
+				httpMovie.CastList[i] = actor.ToHTTPModel()
+			}
+			return encoder.Movie(httpMovie)
+		},
+	); err != nil {
+		return err
+	}
+
+	if err := u.catalogRepository.ExportActors(
+		ctx,
+		filter,
+		func(actor gormModels.Actor, movies []gormModels.Movie) error {
+			httpActor := httpModels.GetActorsResponse{
+				Actor:        actor.ToHTTPModel(),
+				ActedInFilms: make([]httpModels.MovieWithoutCastList, len(movies)),
+			}
+			for i, movie := range movies {
+				httpActor.ActedInFilms[i] = movie.ToHTTPMovies()
+			}
+			return encoder.Actor(httpActor)
+		},
+	); err != nil {
+		return err
+	}
+
+	return encoder.Close()
+}
+
+func checkRow(row httpModels.ImportRow) (gormModels.ImportRow, error) {
+	if row.Err != nil {
+		return gormModels.ImportRow{}, row.Err
+	}
+
+	switch row.Type {
+	case domain.MovieEntity:
+		movie, err := moviesUsecase.NewMovie(httpModels.MovieWithIDCast{
+			Title:       row.Title,
+			Description: row.Description,
+			ReleaseDate: row.ReleaseDate,
+			Rating:      row.Rating,
+		})
+		if err != nil {
+			return gormModels.ImportRow{}, err
+		}
+		cast, err := checkCast(row.Cast)
+		if err != nil {
+			return gormModels.ImportRow{}, err
+		}
+		return gormModels.ImportRow{Line: row.Line, Movie: &movie, Cast: cast}, nil
+	case domain.ActorEntity:
+		actor, err := actorsUsecase.NewActor(httpModels.Actor{
+			Name:      row.Name,
+			Gender:    row.Gender,
+			BirthDate: row.BirthDate,
+		})
+		if err != nil {
+			return gormModels.ImportRow{}, err
+		}
+		return gormModels.ImportRow{Line: row.Line, Actor: &actor}, nil
+	}
+
+	return gormModels.ImportRow{}, domain.ValidationError{Violations: []domain.FieldError{{
+		Field:   "type",
+		Rule:    "oneof",
+		Message: fmt.Sprintf("must be %s or %s", domain.MovieEntity, domain.ActorEntity),
+	}}}
+}
+
+// checkCast trims the names and rejects empty and repeated ones.
+func checkCast(cast []string) ([]string, error) {
+	names := make([]string, 0, len(cast))
+	seen := make(map[string]bool, len(cast))
+	var violations []domain.FieldError
+	for i, name := range cast {
+		name = strings.TrimSpace(name)
+		field := fmt.Sprintf("cast[%d]", i)
+		switch {
+		case name == "":
+			violations = append(violations, domain.FieldError{
+				Field:   field,
+				Rule:    "required",
+				Message: "is required",
+			})
+		case seen[name]:
+			violations = append(violations, domain.FieldError{
+				Field:   field,
+				Rule:    "unique",
+				Message: "is listed twice",
+			})
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+
+	if len(violations) > 0 {
+		return nil, domain.ValidationError{Violations: violations}
+	}
+	return names, nil
+}
+
+// fail marks the row as failed. Details of internal errors stay in the log,
+// like they do for whole requests.
+func fail(ctx context.Context, report *domain.ImportReport, i int, err error) {
+	domainErr := domain.AsError(err)
+	rowErr := &domain.ImportRowError{
+		Code:   domainErr.Code,
+		Detail: err.Error(),
+	}
+	if domainErr.Kind == domain.KindInternal {
+		slog.ErrorContext(ctx, "import row failed", "line", report.Rows[i].Line, "error", err)
+		rowErr.Detail = domain.ErrInternal.Error()
+	}
+
+	var validationErr domain.ValidationError
+	if errors.As(err, &validationErr) {
+		rowErr.Violations = validationErr.Violations
+	}
+
+	report.Rows[i].Action = domain.ImportFailed
+	report.Rows[i].Error = rowErr
+	report.Failed++
+}
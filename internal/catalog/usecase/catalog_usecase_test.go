@@ -0,0 +1,267 @@
+package catalogUsecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	mockDomain "github.com/themilchenko/vk-tech_internship-problem_2024/internal/mocks/domain"
+	gormModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/gorm"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUsecase_Import(t *testing.T) {
+	type mockBehavior func(r *mockDomain.MockCatalogRepository)
+
+	actorRow := httpModels.ImportRow{
+		Line:      2,
+		Type:      "actor",
+		Name:      "John",
+		BirthDate: "1980-01-02",
+	}
+	movieRow := httpModels.ImportRow{
+		Line:        3,
+		Type:        "movie",
+		Title:       "Title",
+		ReleaseDate: "2006-01-02",
+		Rating:      5,
+		Cast:        []string{" John "},
+	}
+	gormActor := gormModels.Actor{
+		Name:      "John",
+		BirthDate: time.Date(1980, 1, 2, 0, 0, 0, 0, time.UTC),
+	}
+	gormMovie := gormModels.Movie{
+		Title:       "Title",
+		ReleaseDate: time.Date(2006, 1, 2, 0, 0, 0, 0, time.UTC),
+		Rating:      5,
+	}
+	validRows := []gormModels.ImportRow{
+		{Line: 2, Actor: &gormActor},
+		{Line: 3, Movie: &gormMovie, Cast: []string{"John"}},
+	}
+
+	tests := []struct {
+		name           string
+		rows           []httpModels.ImportRow
+		options        domain.ImportOptions
+		mockBehavior   mockBehavior
+		expectedReport domain.ImportReport
+		expectedError  error
+	}{
+		{
+			name:    "Applied",
+			rows:    []httpModels.ImportRow{actorRow, movieRow},
+			options: domain.ImportOptions{},
+			mockBehavior: func(r *mockDomain.MockCatalogRepository) {
+				r.EXPECT().
+					Import(gomock.Any(), validRows, true, false).
+					Return([]gormModels.ImportResult{{ID: 7, Created: true}, {ID: 1}}, true, nil)
+			},
+			expectedReport: domain.ImportReport{
+				Mode:    "atomic",
+				Applied: true,
+				Created: 1,
+				Updated: 1,
+				Rows: []domain.ImportRowReport{
+					{Line: 2, Type: "actor", Key: "John", Action: "created", ID: 7},
+					{Line: 3, Type: "movie", Key: "Title", Action: "updated", ID: 1},
+				},
+			},
+		},
+		{
+			name: "Atomic with an invalid row",
+			rows: []httpModels.ImportRow{
+				actorRow,
+				{Line: 3, Type: "movie", Title: "Title", ReleaseDate: "2006-01-02", Rating: 11},
+			},
+			options: domain.ImportOptions{Mode: "atomic"},
+			mockBehavior: func(r *mockDomain.MockCatalogRepository) {
+				r.EXPECT().
+					Import(gomock.Any(), validRows[:1], true, true).
+					Return([]gormModels.ImportResult{{Created: true}}, false, nil)
+			},
+			expectedReport: domain.ImportReport{
+				Mode:    "atomic",
+				Created: 1,
+				Failed:  1,
+				Rows: []domain.ImportRowReport{
+					{Line: 2, Type: "actor", Key: "John", Action: "created"},
+					{Line: 3, Type: "movie", Key: "Title", Action: "failed", Error: &domain.ImportRowError{
+						Code:   "validation_failed",
+						Detail: "validation failed",
+						Violations: []domain.FieldError{
+							{Field: "rating", Rule: "max", Message: "must be at most 10"},
+						},
+					}},
+				},
+			},
+		},
+		{
+			name:    "Best effort with a failed row",
+			rows:    []httpModels.ImportRow{actorRow, movieRow},
+			options: domain.ImportOptions{Mode: "best-effort"},
+			mockBehavior: func(r *mockDomain.MockCatalogRepository) {
+				r.EXPECT().
+					Import(gomock.Any(), validRows, false, false).
+					Return([]gormModels.ImportResult{
+						{ID: 7, Created: true},
+						{Err: errors.New("connection reset")},
+					}, true, nil)
+			},
+			expectedReport: domain.ImportReport{
+				Mode:    "best-effort",
+				Applied: true,
+				Created: 1,
+				Failed:  1,
+				Rows: []domain.ImportRowReport{
+					{Line: 2, Type: "actor", Key: "John", Action: "created", ID: 7},
+					{Line: 3, Type: "movie", Key: "Title", Action: "failed", Error: &domain.ImportRowError{
+						Code:   "internal_error",
+						Detail: "server error",
+					}},
+				},
+			},
+		},
+		{
+			name: "Repeated cast name",
+			rows: []httpModels.ImportRow{{
+				Line:        1,
+				Type:        "movie",
+				Title:       "Title",
+				ReleaseDate: "2006-01-02",
+				Cast:        []string{"John", "John"},
+			}},
+			options: domain.ImportOptions{DryRun: true},
+			mockBehavior: func(r *mockDomain.MockCatalogRepository) {
+				r.EXPECT().Import(gomock.Any(), nil, true, true).Return(nil, false, nil)
+			},
+			expectedReport: domain.ImportReport{
+				Mode:   "atomic",
+				DryRun: true,
+				Failed: 1,
+				Rows: []domain.ImportRowReport{
+					{Line: 1, Type: "movie", Key: "Title", Action: "failed", Error: &domain.ImportRowError{
+						Code:   "validation_failed",
+						Detail: "validation failed",
+						Violations: []domain.FieldError{
+							{Field: "cast[1]", Rule: "unique", Message: "is listed twice"},
+						},
+					}},
+				},
+			},
+		},
+		{
+			name:          "Unknown mode",
+			rows:          []httpModels.ImportRow{actorRow},
+			options:       domain.ImportOptions{Mode: "all"},
+			mockBehavior:  func(r *mockDomain.MockCatalogRepository) {},
+			expectedError: domain.ErrBadRequest,
+		},
+		{
+			name:          "Nothing to import",
+			mockBehavior:  func(r *mockDomain.MockCatalogRepository) {},
+			expectedError: domain.ErrBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mockDomain.NewMockCatalogRepository(ctrl)
+			tt.mockBehavior(mockRepo)
+
+			u := NewCatalogUsecase(mockRepo)
+
+			report, err := u.Import(context.Background(), tt.rows, tt.options)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedReport, report)
+		})
+	}
+}
+
+func TestUsecase_Export(t *testing.T) {
+	filter := httpModels.MoviesFilter{Title: "god", SortBy: "rating", Order: true}
+	movie := gormModels.Movie{
+		ID:          1,
+		Title:       "The Godfather",
+		ReleaseDate: time.Date(1972, 3, 24, 0, 0, 0, 0, time.UTC),
+		Rating:      9.2,
+	}
+	actor := gormModels.Actor{
+		ID:        2,
+		Name:      "Marlon Brando",
+		Gender:    true,
+		BirthDate: time.Date(1924, 4, 3, 0, 0, 0, 0, time.UTC),
+	}
+
+	t.Run("Movies then actors", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mockDomain.NewMockCatalogRepository(ctrl)
+		mockEncoder := mockDomain.NewMockExportEncoder(ctrl)
+
+		gomock.InOrder(
+			mockRepo.EXPECT().
+				ExportMovies(gomock.Any(), filter, gomock.Any()).
+				DoAndReturn(func(
+					_ context.Context,
+					_ httpModels.MoviesFilter,
+					fn func(gormModels.Movie, []gormModels.Actor) error,
+				) error {
+					return fn(movie, []gormModels.Actor{actor})
+				}),
+			mockEncoder.EXPECT().Movie(httpModels.MovieResponse{
+				ID:          1,
+				Title:       "The Godfather",
+				ReleaseDate: "1972-03-24",
+				Rating:      9.2,
+				CastList: []httpModels.ActorResponse{
+					{ID: 2, Name: "Marlon Brando", Gender: true, BirthDate: "1924-04-03"},
+				},
+			}),
+			mockRepo.EXPECT().
+				ExportActors(gomock.Any(), filter, gomock.Any()).
+				DoAndReturn(func(
+					_ context.Context,
+					_ httpModels.MoviesFilter,
+					fn func(gormModels.Actor, []gormModels.Movie) error,
+				) error {
+					return fn(actor, nil)
+				}),
+			mockEncoder.EXPECT().Actor(httpModels.GetActorsResponse{
+				Actor:        httpModels.ActorResponse{ID: 2, Name: "Marlon Brando", Gender: true, BirthDate: "1924-04-03"},
+				ActedInFilms: []httpModels.MovieWithoutCastList{},
+			}),
+			mockEncoder.EXPECT().Close(),
+		)
+
+		u := NewCatalogUsecase(mockRepo)
+		assert.NoError(t, u.Export(context.Background(), filter, mockEncoder))
+	})
+
+	t.Run("Failed movies", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mockDomain.NewMockCatalogRepository(ctrl)
+		mockRepo.EXPECT().
+			ExportMovies(gomock.Any(), filter, gomock.Any()).
+			Return(domain.ErrInternal)
+
+		u := NewCatalogUsecase(mockRepo)
+		err := u.Export(context.Background(), filter, mockDomain.NewMockExportEncoder(ctrl))
+		assert.ErrorIs(t, err, domain.ErrInternal)
+	})
+}
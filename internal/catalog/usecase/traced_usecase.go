@@ -0,0 +1,39 @@
+package catalogUsecase
+
+import (
+	"context"
+
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/tracing"
+)
+
+// TracedCatalogUsecase makes a span of imports and exports, they run long
+// and their statements are the children of one span.
+type TracedCatalogUsecase struct {
+	catalogUsecase domain.CatalogUsecase
+}
+
+func NewTracedCatalogUsecase(u domain.CatalogUsecase) TracedCatalogUsecase {
+	return TracedCatalogUsecase{catalogUsecase: u}
+}
+
+func (u TracedCatalogUsecase) Import(
+	ctx context.Context,
+	rows []httpModels.ImportRow,
+	options domain.ImportOptions,
+) (_ domain.ImportReport, err error) {
+	ctx, span := tracing.Start(ctx, "CatalogUsecase.Import")
+	defer func() { tracing.End(span, err) }()
+	return u.catalogUsecase.Import(ctx, rows, options)
+}
+
+func (u TracedCatalogUsecase) Export(
+	ctx context.Context,
+	filter httpModels.MoviesFilter,
+	encoder domain.ExportEncoder,
+) (err error) {
+	ctx, span := tracing.Start(ctx, "CatalogUsecase.Export")
+	defer func() { tracing.End(span, err) }()
+	return u.catalogUsecase.Export(ctx, filter, encoder)
+}
@@ -12,12 +12,58 @@ import (
 var pageSize = 10
 
 const (
-	address     = "localhost"
-	timeout     = 4
-	idleTimeout = 30
-	port        = 8080
-	loggerLevel = "debug"
-	envFile     = ".env"
+	address      = "localhost"
+	timeout      = 4
+	idleTimeout  = 30
+	port         = 8080
+	loggerLevel  = "debug"
+	loggerFormat = "json"
+	slowQuery    = 200 * time.Millisecond
+	envFile      = ".env"
+
+	resetTokenTTL = 30 * time.Minute
+	outboxPath    = "outbox.log"
+
+	maxLoginFailures = 10
+	loginBaseDelay   = time.Second
+	loginMaxDelay    = time.Minute
+	lockoutDuration  = 15 * time.Minute
+	failureWindow    = 15 * time.Minute
+
+	passwordMinLength = 8
+	passwordMaxLength = 72
+	usernameMinLength = 3
+	usernameMaxLength = 32
+	usernamePattern   = `^[A-Za-z0-9_.-]+$`
+	bcryptCost        = 10
+
+	totpIssuer         = "Film Library"
+	challengeTTL       = 5 * time.Minute
+	maxCodeAttempts    = 5
+	recoveryCodesCount = 10
+
+	oidcStateTTL = 10 * time.Minute
+
+	trashPurgeAfter    = 30 * 24 * time.Hour
+	trashPurgeInterval = time.Hour
+
+	maxBodySize = 1 << 20
+
+	cacheSize = 1000
+	cacheTTL  = time.Minute
+
+	importMaxBodySize = 32 << 20
+
+	batchMaxItems = 20
+
+	grpcAddress = "localhost:9090"
+
+	openAPIValidation = "off"
+
+	metricsAddress = "localhost:9100"
+
+	tracingExporter    = "none"
+	tracingSampleRatio = 1.0
 )
 
 type Config struct {
@@ -33,10 +79,27 @@ type Config struct {
 		Port    uint64 `yaml:"port"`
 		SslMode string `yaml:"sslmode"`
 	} `yaml:"database"`
-	EnvFile        string `yaml:"env_file"`
-	LoggerLvl      string `yaml:"logger_level"`
-	CookieSettings CookieSettings
-	PageSize       uint64 `yaml:"page_size"`
+	EnvFile            string        `yaml:"env_file"`
+	LoggerLvl          string        `yaml:"logger_level"`
+	LoggerFormat       string        `yaml:"logger_format"`
+	SlowQueryThreshold time.Duration `yaml:"slow_query_threshold"`
+	CookieSettings     CookieSettings
+	PageSize           uint64                `yaml:"page_size"`
+	MaxBodySize        int64                 `yaml:"max_body_size"`
+	PasswordReset      PasswordResetSettings `yaml:"password_reset"`
+	LoginGuard         LoginGuardSettings    `yaml:"login_guard"`
+	Credentials        CredentialsSettings   `yaml:"credentials"`
+	TwoFactor          TwoFactorSettings     `yaml:"two_factor"`
+	OIDC               OIDCSettings          `yaml:"oidc"`
+	Trash              TrashSettings         `yaml:"trash"`
+	Concurrency        ConcurrencySettings   `yaml:"concurrency"`
+	Cache              CacheSettings         `yaml:"cache"`
+	Catalog            CatalogSettings       `yaml:"catalog"`
+	Batch              BatchSettings         `yaml:"batch"`
+	GRPC               GRPCSettings          `yaml:"grpc"`
+	OpenAPI            OpenAPISettings       `yaml:"openapi"`
+	Metrics            MetricsSettings       `yaml:"metrics"`
+	Tracing            TracingSettings       `yaml:"tracing"`
 }
 
 type CookieSettings struct {
@@ -49,6 +112,120 @@ type CookieSettings struct {
 	} `yaml:"expire_date"`
 }
 
+type PasswordResetSettings struct {
+	TokenTTL   time.Duration `yaml:"token_ttl"`
+	OutboxPath string        `yaml:"outbox_path"`
+}
+
+type LoginGuardSettings struct {
+	MaxFailures     uint64        `yaml:"max_failures"`
+	BaseDelay       time.Duration `yaml:"base_delay"`
+	MaxDelay        time.Duration `yaml:"max_delay"`
+	LockoutDuration time.Duration `yaml:"lockout_duration"`
+	FailureWindow   time.Duration `yaml:"failure_window"`
+}
+
+type CredentialsSettings struct {
+	PasswordMinLength     int    `yaml:"password_min_length"`
+	PasswordMaxLength     int    `yaml:"password_max_length"`
+	RequireLower          bool   `yaml:"require_lower"`
+	RequireUpper          bool   `yaml:"require_upper"`
+	RequireDigit          bool   `yaml:"require_digit"`
+	RequireSymbol         bool   `yaml:"require_symbol"`
+	RejectCommonPasswords bool   `yaml:"reject_common_passwords"`
+	UsernameMinLength     int    `yaml:"username_min_length"`
+	UsernameMaxLength     int    `yaml:"username_max_length"`
+	UsernamePattern       string `yaml:"username_pattern"`
+	BcryptCost            int    `yaml:"bcrypt_cost"`
+}
+
+type TwoFactorSettings struct {
+	Issuer             string        `yaml:"issuer"`
+	RequireForAdmins   bool          `yaml:"require_for_admins"`
+	ChallengeTTL       time.Duration `yaml:"challenge_ttl"`
+	MaxCodeAttempts    uint64        `yaml:"max_code_attempts"`
+	RecoveryCodesCount int           `yaml:"recovery_codes_count"`
+}
+
+type OIDCSettings struct {
+	StateTTL  time.Duration          `yaml:"state_ttl"`
+	Providers []OIDCProviderSettings `yaml:"providers"`
+}
+
+type OIDCProviderSettings struct {
+	Name          string            `yaml:"name"`
+	IssuerURL     string            `yaml:"issuer_url"`
+	ClientID      string            `yaml:"client_id"`
+	ClientSecret  string            `yaml:"client_secret"`
+	RedirectURL   string            `yaml:"redirect_url"`
+	Scopes        []string          `yaml:"scopes"`
+	UsernameClaim string            `yaml:"username_claim"`
+	GroupsClaim   string            `yaml:"groups_claim"`
+	RoleMapping   map[string]string `yaml:"role_mapping"`
+	DefaultRole   string            `yaml:"default_role"`
+}
+
+// TrashSettings control how long deleted movies and actors can be restored.
+// A zero PurgeAfter keeps them forever.
+type TrashSettings struct {
+	PurgeAfter    time.Duration `yaml:"purge_after"`
+	PurgeInterval time.Duration `yaml:"purge_interval"`
+}
+
+// ConcurrencySettings control optimistic locking of movies and actors. With
+// RequireIfMatch, changes without an If-Match header are rejected with 428.
+type ConcurrencySettings struct {
+	RequireIfMatch bool `yaml:"require_if_match"`
+}
+
+// CacheSettings control the cache of movie and actor reads. A zero Size
+// turns it off. MaxAge is sent in Cache-Control, zero makes clients
+// revalidate every time.
+type CacheSettings struct {
+	Size   int           `yaml:"size"`
+	TTL    time.Duration `yaml:"ttl"`
+	MaxAge time.Duration `yaml:"max_age"`
+}
+
+// CatalogSettings control bulk imports. Import files are much larger than
+// other request bodies, so they have their own limit.
+type CatalogSettings struct {
+	ImportMaxBodySize int64 `yaml:"import_max_body_size"`
+}
+
+// BatchSettings limit the number of requests in one POST /batch.
+type BatchSettings struct {
+	MaxItems int `yaml:"max_items"`
+}
+
+// GRPCSettings hold where the gRPC server listens, beside the HTTP one.
+type GRPCSettings struct {
+	Address string `yaml:"address"`
+}
+
+// OpenAPISettings control checking HTTP traffic against docs/swagger.yml:
+// "off", "log" to log what doesn't match or "strict" to reject it.
+type OpenAPISettings struct {
+	Validation string `yaml:"validation"`
+}
+
+// MetricsSettings hold where /metrics is served, apart from the API so that
+// it isn't exposed with it. An empty address turns it off.
+type MetricsSettings struct {
+	Address string `yaml:"address"`
+}
+
+// TracingSettings choose where spans go: "none", "stdout" or "otlp" to send
+// them over OTLP/HTTP to Endpoint, e.g. "localhost:4318". Without an
+// endpoint the OTEL_EXPORTER_OTLP_* environment variables apply.
+// SampleRatio is the share of traces started here that are kept.
+type TracingSettings struct {
+	Exporter    string  `yaml:"exporter"`
+	Endpoint    string  `yaml:"endpoint"`
+	Insecure    bool    `yaml:"insecure"`
+	SampleRatio float64 `yaml:"sample_ratio"`
+}
+
 func NewConfig() *Config {
 	return &Config{
 		Server: struct {
@@ -83,8 +260,11 @@ func NewConfig() *Config {
 			Port:    5432,
 			SslMode: "disable",
 		}),
-		EnvFile:   envFile,
-		LoggerLvl: loggerLevel,
+		EnvFile:            envFile,
+		LoggerLvl:          loggerLevel,
+		LoggerFormat:       loggerFormat,
+		SlowQueryThreshold: slowQuery,
+		MaxBodySize:        maxBodySize,
 		CookieSettings: struct {
 			Secure     bool `yaml:"secure"`
 			HttpOnly   bool `yaml:"http_only"`
@@ -114,6 +294,65 @@ func NewConfig() *Config {
 				Days:   7,
 			},
 		}),
+		PasswordReset: PasswordResetSettings{
+			TokenTTL:   resetTokenTTL,
+			OutboxPath: outboxPath,
+		},
+		LoginGuard: LoginGuardSettings{
+			MaxFailures:     maxLoginFailures,
+			BaseDelay:       loginBaseDelay,
+			MaxDelay:        loginMaxDelay,
+			LockoutDuration: lockoutDuration,
+			FailureWindow:   failureWindow,
+		},
+		Credentials: CredentialsSettings{
+			PasswordMinLength:     passwordMinLength,
+			PasswordMaxLength:     passwordMaxLength,
+			RequireLower:          true,
+			RequireUpper:          true,
+			RequireDigit:          true,
+			RejectCommonPasswords: true,
+			UsernameMinLength:     usernameMinLength,
+			UsernameMaxLength:     usernameMaxLength,
+			UsernamePattern:       usernamePattern,
+			BcryptCost:            bcryptCost,
+		},
+		TwoFactor: TwoFactorSettings{
+			Issuer:             totpIssuer,
+			ChallengeTTL:       challengeTTL,
+			MaxCodeAttempts:    maxCodeAttempts,
+			RecoveryCodesCount: recoveryCodesCount,
+		},
+		OIDC: OIDCSettings{
+			StateTTL: oidcStateTTL,
+		},
+		Trash: TrashSettings{
+			PurgeAfter:    trashPurgeAfter,
+			PurgeInterval: trashPurgeInterval,
+		},
+		Cache: CacheSettings{
+			Size: cacheSize,
+			TTL:  cacheTTL,
+		},
+		Catalog: CatalogSettings{
+			ImportMaxBodySize: importMaxBodySize,
+		},
+		Batch: BatchSettings{
+			MaxItems: batchMaxItems,
+		},
+		GRPC: GRPCSettings{
+			Address: grpcAddress,
+		},
+		OpenAPI: OpenAPISettings{
+			Validation: openAPIValidation,
+		},
+		Metrics: MetricsSettings{
+			Address: metricsAddress,
+		},
+		Tracing: TracingSettings{
+			Exporter:    tracingExporter,
+			SampleRatio: tracingSampleRatio,
+		},
 	}
 }
 
@@ -1,23 +1,42 @@
 package domain
 
 import (
+	"context"
+
 	gormModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/gorm"
 	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
 )
 
+// The version arguments of the mutations are the expected version of the
+// item, as sent in If-Match. Zero means any version.
 type ActorsUsecase interface {
-	CreateActor(actor httpModels.Actor) (uint64, error)
-	GetActorByID(actorID uint64) (httpModels.ActorResponse, error)
-	UpdateActor(actor httpModels.Actor, actorID uint64) (httpModels.ActorResponse, error)
-	DeleteActorByID(actorID uint64) error
-	GetActors(pageNum uint64) ([]httpModels.GetActorsResponse, error)
+	CreateActor(ctx context.Context, actor httpModels.Actor) (uint64, error)
+	GetActorByID(ctx context.Context, actorID uint64) (httpModels.ActorResponse, error)
+	UpdateActor(
+		ctx context.Context,
+		actor httpModels.Actor,
+		actorID, version uint64,
+	) (httpModels.ActorResponse, error)
+	PatchActor(
+		ctx context.Context,
+		actorID, version uint64,
+		patch Patch,
+	) (httpModels.ActorResponse, error)
+	DeleteActorByID(ctx context.Context, actorID, version uint64) error
+	GetActors(ctx context.Context, pageNum uint64) ([]httpModels.GetActorsResponse, error)
 }
 
 type ActorsRepository interface {
-	CreateActor(actor gormModels.Actor) (uint64, error)
-	UpdateActor(actor gormModels.Actor) (gormModels.Actor, error)
-	DeleteActorByID(actorID uint64) error
-	GetActorByID(actorID uint64) (gormModels.Actor, error)
-	GetActorsFromMovie(movieID uint64) ([]gormModels.Actor, error)
-	GetActors(pageNum uint64) ([]gormModels.Actor, error)
+	CreateActor(ctx context.Context, actor gormModels.Actor) (uint64, error)
+	UpdateActor(ctx context.Context, actor gormModels.Actor) (gormModels.Actor, error)
+	PatchActor(
+		ctx context.Context,
+		actorID, version uint64,
+		patch func(actor *gormModels.Actor) error,
+	) (gormModels.Actor, error)
+	DeleteActorByID(ctx context.Context, actorID, version uint64) error
+	GetActorByID(ctx context.Context, actorID uint64) (gormModels.Actor, error)
+	GetActorsByIDs(ctx context.Context, actorIDs []uint64) ([]gormModels.Actor, error)
+	GetActorsFromMovie(ctx context.Context, movieID uint64) ([]gormModels.Actor, error)
+	GetActors(ctx context.Context, pageNum uint64) ([]gormModels.Actor, error)
 }
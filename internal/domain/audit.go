@@ -0,0 +1,16 @@
+package domain
+
+import (
+	"context"
+
+	gormModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/gorm"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+)
+
+type AuditUsecase interface {
+	GetEntries(ctx context.Context, filter httpModels.AuditFilter) ([]httpModels.AuditEntry, error)
+}
+
+type AuditRepository interface {
+	GetEntries(ctx context.Context, filter httpModels.AuditFilter) ([]gormModels.AuditEntry, error)
+}
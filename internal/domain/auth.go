@@ -1,22 +1,91 @@
 package domain
 
 import (
+	"context"
+	"time"
+
 	gormModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/gorm"
 	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
 )
 
 type AuthUsecase interface {
-	SignUp(user httpModels.AuthUser) (string, uint64, error)
-	Login(user httpModels.AuthUser) (string, uint64, error)
-	Logout(sessionID string) error
-	Auth(sessionID string) (uint64, error)
-	GetUserBySessionID(sessionID string) (httpModels.AuthUser, error)
+	SignUp(ctx context.Context, user httpModels.AuthUser) (string, uint64, error)
+	Login(ctx context.Context, user httpModels.AuthUser, ip string) (string, uint64, error)
+	Logout(ctx context.Context, sessionID string) error
+	Auth(ctx context.Context, sessionID string) (uint64, error)
+	GetUserBySessionID(ctx context.Context, sessionID string) (httpModels.AuthUser, error)
+	ChangePassword(ctx context.Context, sessionID string, passwords httpModels.ChangePassword) error
+	RequestPasswordReset(ctx context.Context, username string) error
+	ConfirmPasswordReset(ctx context.Context, confirm httpModels.PasswordResetConfirm) error
+	UnlockUser(ctx context.Context, username string) error
+	EnrollTOTP(ctx context.Context, sessionID string) (httpModels.TOTPEnrollment, error)
+	ActivateTOTP(ctx context.Context, sessionID, code string) (httpModels.RecoveryCodes, error)
+	DisableTOTP(ctx context.Context, sessionID, code string) error
+	RegenerateRecoveryCodes(
+		ctx context.Context,
+		sessionID, code string,
+	) (httpModels.RecoveryCodes, error)
+	VerifyTwoFactor(ctx context.Context, login httpModels.TwoFactorLogin) (string, uint64, error)
+	OIDCLoginURL(ctx context.Context, provider, linkSessionID string) (string, string, error)
+	OIDCCallback(ctx context.Context, provider, code, state string) (string, uint64, error)
 }
 
 type AuthRepository interface {
-	CreateUser(user gormModels.User) (uint64, error)
-	CreateSession(session gormModels.Session) (string, error)
-	DeleteBySessionID(sessionID string) error
-	GetUserBySessionID(sessionID string) (gormModels.User, error)
-	GetUserByUsername(username string) (gormModels.User, error)
+	CreateUser(ctx context.Context, user gormModels.User) (uint64, error)
+	CreateSession(ctx context.Context, session gormModels.Session) (string, error)
+	DeleteBySessionID(ctx context.Context, sessionID string) error
+	GetUserBySessionID(ctx context.Context, sessionID string) (gormModels.User, error)
+	GetUserByUsername(ctx context.Context, username string) (gormModels.User, error)
+	UpdatePassword(ctx context.Context, userID uint64, hash string) error
+	DeleteUserSessions(ctx context.Context, userID uint64, exceptSessionID string) error
+	CountActiveSessions(ctx context.Context) (int64, error)
+	CreateResetToken(ctx context.Context, token gormModels.PasswordResetToken) error
+	GetResetToken(ctx context.Context, tokenHash string) (gormModels.PasswordResetToken, error)
+	ResetPassword(ctx context.Context, tokenID, userID uint64, hash string) error
+	GetLoginAttempts(ctx context.Context, keys []string) ([]gormModels.LoginAttempt, error)
+	RegisterLoginFailure(
+		ctx context.Context,
+		key string,
+		staleBefore time.Time,
+	) (gormModels.LoginAttempt, error)
+	BlockLogin(ctx context.Context, key string, until time.Time) error
+	ResetLoginFailures(ctx context.Context, key string) error
+	GetUserByID(ctx context.Context, userID uint64) (gormModels.User, error)
+	UpdateTOTP(ctx context.Context, userID uint64, secret string, enabled bool) error
+	UseTOTPStep(ctx context.Context, userID uint64, step int64) error
+	ReplaceRecoveryCodes(ctx context.Context, userID uint64, codes []gormModels.RecoveryCode) error
+	UseRecoveryCode(ctx context.Context, userID uint64, codeHash string) error
+	CreateTwoFactorChallenge(ctx context.Context, challenge gormModels.TwoFactorChallenge) error
+	GetTwoFactorChallenge(
+		ctx context.Context,
+		tokenHash string,
+	) (gormModels.TwoFactorChallenge, error)
+	UseChallengeAttempt(ctx context.Context, challengeID, maxAttempts uint64) error
+	DeleteTwoFactorChallenge(ctx context.Context, challengeID uint64) error
+	CreateOIDCState(ctx context.Context, state gormModels.OIDCState) error
+	ConsumeOIDCState(ctx context.Context, stateHash string) (gormModels.OIDCState, error)
+	GetExternalIdentity(
+		ctx context.Context,
+		provider, subject string,
+	) (gormModels.ExternalIdentity, error)
+	CreateExternalIdentity(ctx context.Context, identity gormModels.ExternalIdentity) error
+	UpdateRole(ctx context.Context, userID uint64, role string) error
+}
+
+// IdentityProvider is an external OpenID Connect provider. Exchange is the
+// only place that talks to the network, so tests can swap it for a stub.
+type IdentityProvider interface {
+	AuthCodeURL(state, nonce, verifier string) string
+	Exchange(ctx context.Context, code, nonce, verifier string) (ExternalIdentity, error)
+	RoleFor(groups []string) string
+}
+
+type ExternalIdentity struct {
+	Subject  string
+	Username string
+	Groups   []string
+}
+
+type Notifier interface {
+	SendPasswordReset(username, token string) error
 }
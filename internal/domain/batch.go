@@ -0,0 +1,16 @@
+package domain
+
+import "context"
+
+// Transaction is a database transaction shared by the repositories through
+// the context it was begun with.
+type Transaction interface {
+	Commit() error
+	Rollback() error
+}
+
+// Transactor begins transactions for atomic batches. The returned context
+// carries the transaction, every statement made with it joins it.
+type Transactor interface {
+	Begin(ctx context.Context) (context.Context, Transaction, error)
+}
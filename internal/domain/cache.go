@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"context"
+
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+)
+
+// Cache keeps serialized read results. Every entry carries tags naming what
+// it was built from, so a write drops exactly the entries it makes stale. The
+// in-process LRU is the default, a shared store only has to keep the same
+// contract.
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, bool)
+	// Generation is read before a value is loaded and handed back to Set,
+	// which drops the value if one of its tags was invalidated in between.
+	Generation(ctx context.Context) uint64
+	Set(ctx context.Context, key string, value []byte, generation uint64, tags ...string)
+	Invalidate(ctx context.Context, tags ...string)
+	Stats(ctx context.Context) httpModels.CacheStats
+}
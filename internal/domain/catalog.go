@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"context"
+
+	gormModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/gorm"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+)
+
+// Import modes. An atomic import saves nothing if any row fails, a
+// best-effort one saves every row that can be saved.
+const (
+	ImportAtomic     = "atomic"
+	ImportBestEffort = "best-effort"
+)
+
+// What became of an import row.
+const (
+	ImportCreated = "created"
+	ImportUpdated = "updated"
+	ImportFailed  = "failed"
+)
+
+// Export formats.
+const (
+	ExportJSON   = "json"
+	ExportNDJSON = "ndjson"
+	ExportCSV    = "csv"
+)
+
+type ImportOptions struct {
+	Mode   string
+	DryRun bool
+}
+
+// ImportReport tells what an import did, or would have done when it was a
+// dry run or an atomic import with failed rows. IDs are only reported for
+// applied imports.
+type ImportReport struct {
+	Mode    string            `json:"mode"`
+	DryRun  bool              `json:"dryRun"`
+	Applied bool              `json:"applied"`
+	Created int               `json:"created"`
+	Updated int               `json:"updated"`
+	Failed  int               `json:"failed"`
+	Rows    []ImportRowReport `json:"rows"`
+}
+
+type ImportRowReport struct {
+	Line   int             `json:"line"`
+	Type   string          `json:"type"`
+	Key    string          `json:"key"`
+	Action string          `json:"action"`
+	ID     uint64          `json:"id,omitempty"`
+	Error  *ImportRowError `json:"error,omitempty"`
+}
+
+// ImportRowError describes a failed row like a problem response describes
+// a failed request.
+type ImportRowError struct {
+	Code       string       `json:"code"`
+	Detail     string       `json:"detail"`
+	Violations []FieldError `json:"violations,omitempty"`
+}
+
+// ExportEncoder writes an export item by item as it is read from the
+// database. Every movie comes before the first actor.
+type ExportEncoder interface {
+	Movie(movie httpModels.MovieResponse) error
+	Actor(actor httpModels.GetActorsResponse) error
+	Close() error
+}
+
+type CatalogUsecase interface {
+	Import(
+		ctx context.Context,
+		rows []httpModels.ImportRow,
+		options ImportOptions,
+	) (ImportReport, error)
+	Export(
+		ctx context.Context,
+		filter httpModels.MoviesFilter,
+		encoder ExportEncoder,
+	) error
+}
+
+type CatalogRepository interface {
+	// Import upserts the rows by title or name in one transaction and
+	// reports whether it was committed. Actors go first, so movies can name
+	// actors from the same import.
+	Import(
+		ctx context.Context,
+		rows []gormModels.ImportRow,
+		atomic, dryRun bool,
+	) ([]gormModels.ImportResult, bool, error)
+	// ExportMovies calls fn for every movie picked by the filter, in its
+	// order, along with the cast.
+	ExportMovies(
+		ctx context.Context,
+		filter httpModels.MoviesFilter,
+		fn func(movie gormModels.Movie, cast []gormModels.Actor) error,
+	) error
+	// ExportActors calls fn for every actor along with the movies they acted
+	// in. A filter by title or actor keeps only the cast of the picked movies.
+	ExportActors(
+		ctx context.Context,
+		filter httpModels.MoviesFilter,
+		fn func(actor gormModels.Actor, movies []gormModels.Movie) error,
+	) error
+}
@@ -1,33 +1,228 @@
 package domain
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
+
+// Kind says what went wrong from the client's point of view. The HTTP layer
+// picks the status code by it.
+type Kind int
+
+const (
+	KindInternal Kind = iota
+	KindBadRequest
+	KindValidation
+	KindUnauthenticated
+	KindForbidden
+	KindNotFound
+	KindConflict
+	KindTooManyRequests
+	KindPayloadTooLarge
+	KindUnsupportedMediaType
+	KindPreconditionFailed
+	KindPreconditionRequired
+	KindFailedDependency
+)
+
+// Error is a domain error with a stable code clients can match on. The
+// message may change, the code may not.
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+}
+
+func newError(kind Kind, code, message string) *Error {
+	return &Error{
+		Kind:    kind,
+		Code:    code,
+		Message: message,
+	}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Wrap adds the cause to the message, the result keeps the kind and code.
+func (e *Error) Wrap(cause error) error {
+	return fmt.Errorf("%w: %v", e, cause)
+}
+
+// AsError finds the domain error in the chain. Anything else is internal.
+func AsError(err error) *Error {
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		return domainErr
+	}
+	return ErrInternal
+}
 
 var (
-	ErrConflict               = errors.New("conflict")
-	ErrBadRequest             = errors.New("bad request")
-	ErrUserAlreadyExist       = errors.New("username is already exist")
-	ErrInvalidLoginOrPassword = errors.New("invalid login or password")
-	ErrForbidden              = errors.New("you are not supposed to be here")
+	ErrConflict               = newError(KindConflict, "conflict", "conflict")
+	ErrBadRequest             = newError(KindBadRequest, "bad_request", "bad request")
+	ErrValidation             = newError(KindValidation, "validation_failed", "validation failed")
+	ErrUserAlreadyExist       = newError(KindConflict, "user_exists", "username is already exist")
+	ErrInvalidLoginOrPassword = newError(
+		KindUnauthenticated,
+		"invalid_credentials",
+		"invalid login or password",
+	)
+	ErrForbidden       = newError(KindForbidden, "forbidden", "you are not supposed to be here")
+	ErrTooManyAttempts = newError(
+		KindTooManyRequests,
+		"too_many_attempts",
+		"too many failed login attempts, try again later",
+	)
+
+	ErrCreate = newError(KindInternal, "internal_error", "failed to create item")
+	ErrUpdate = newError(KindInternal, "internal_error", "failed to update item")
+	ErrDelete = newError(KindInternal, "internal_error", "failed to delete item")
 
-	ErrCreate = errors.New("failed to create item")
-	ErrUpdate = errors.New("failed to update item")
-	ErrDelete = errors.New("failed to delete item")
+	ErrResponse  = newError(KindInternal, "internal_error", "failed to response")
+	ErrNotFound  = newError(KindNotFound, "not_found", "failed to find item")
+	ErrNoContent = newError(KindNotFound, "not_found", "no content was found")
 
-	ErrResponse  = errors.New("failed to response")
-	ErrNotFound  = errors.New("failed to find item")
-	ErrNoContent = errors.New("no content was found")
+	ErrAuth       = newError(KindUnauthenticated, "unauthenticated", "failed to authenticate")
+	ErrNoSession  = newError(KindUnauthenticated, "no_session", "no existing session")
+	ErrBadSession = newError(KindUnauthenticated, "bad_session", "bad session")
+
+	ErrInternal      = newError(KindInternal, "internal_error", "server error")
+	ErrJSONMarshal   = newError(KindInternal, "internal_error", "failed to marshal json")
+	ErrJSONUnmarshal = newError(KindBadRequest, "invalid_json", "failed to unmarshal json")
+	ErrBodyTooLarge  = newError(KindPayloadTooLarge, "body_too_large", "request body is too large")
+	ErrCopy          = newError(KindInternal, "internal_error", "failed to copy item")
+	ErrMediaType     = newError(
+		KindUnsupportedMediaType,
+		"unsupported_media_type",
+		"unsupported media type",
+	)
+)
 
-	ErrAuth       = errors.New("failed to authenticate")
-	ErrNoSession  = errors.New("no existing session")
-	ErrBadSession = errors.New("bad session")
+// ErrFailedDependency answers the requests of an atomic batch that were not
+// run because an earlier one failed.
+var ErrFailedDependency = newError(
+	KindFailedDependency,
+	"failed_dependency",
+	"an earlier request of the batch failed",
+)
 
-	ErrInternal      = errors.New("server error")
-	ErrJSONMarshal   = errors.New("failed to marshal json")
-	ErrJSONUnmarshal = errors.New("failed to unmarshal json")
-	ErrCopy          = errors.New("failed to copy item")
+// Constraint violations reported by the database.
+var (
+	ErrAlreadyExists      = newError(KindConflict, "already_exists", "item already exists")
+	ErrReferenceViolation = newError(
+		KindConflict,
+		"reference_violation",
+		"item is referenced by or refers to a missing item",
+	)
+	ErrCheckViolation = newError(KindValidation, "check_violation", "item violates a constraint")
 )
 
+// Partial updates and optimistic concurrency.
 var (
-	ErrUsername          = errors.New("username not exists")
-	ErrPasswordsNotEqual = errors.New("passwords not the same")
+	ErrInvalidPatch = newError(KindBadRequest, "invalid_patch", "invalid patch document")
+	ErrPatchFailed  = newError(KindConflict, "patch_failed", "patch can't be applied")
+
+	ErrVersionMismatch = newError(
+		KindPreconditionFailed,
+		"version_mismatch",
+		"item was changed since it was read",
+	)
+	ErrIfMatchRequired = newError(
+		KindPreconditionRequired,
+		"if_match_required",
+		"If-Match header is required",
+	)
 )
+
+var (
+	ErrUsername          = newError(KindNotFound, "user_not_found", "username not exists")
+	ErrPasswordsNotEqual = newError(KindForbidden, "wrong_password", "passwords not the same")
+	ErrResetToken        = newError(
+		KindBadRequest,
+		"invalid_reset_token",
+		"reset token is invalid or expired",
+	)
+)
+
+var (
+	ErrTwoFactorRequired = newError(
+		KindUnauthenticated,
+		"two_factor_required",
+		"two-factor code required",
+	)
+	ErrTwoFactorEnrollment = newError(
+		KindForbidden,
+		"two_factor_enrollment_required",
+		"two-factor authentication must be enabled for admins",
+	)
+	ErrTwoFactorEnabled = newError(
+		KindConflict,
+		"two_factor_enabled",
+		"two-factor authentication is already enabled",
+	)
+	ErrTwoFactorDisabled = newError(
+		KindConflict,
+		"two_factor_disabled",
+		"two-factor authentication is not enabled",
+	)
+	ErrTwoFactorCode = newError(
+		KindForbidden,
+		"invalid_two_factor_code",
+		"invalid two-factor code",
+	)
+	ErrTwoFactorChallenge = newError(
+		KindUnauthenticated,
+		"invalid_two_factor_challenge",
+		"two-factor challenge is invalid or expired",
+	)
+)
+
+var (
+	ErrUnknownProvider = newError(KindNotFound, "unknown_provider", "unknown identity provider")
+	ErrOIDCState       = newError(
+		KindBadRequest,
+		"invalid_oidc_state",
+		"login state is invalid or expired",
+	)
+	ErrOIDCExchange = newError(
+		KindUnauthenticated,
+		"oidc_exchange_failed",
+		"identity provider rejected the login",
+	)
+)
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// ValidationError carries every violated rule at once, so a client can fix
+// all of them in one go.
+type ValidationError struct {
+	Violations []FieldError
+}
+
+func (e ValidationError) Error() string {
+	return ErrValidation.Error()
+}
+
+func (e ValidationError) Unwrap() error {
+	return ErrValidation
+}
+
+// TwoFactorRequiredError is returned by Login when the password was right but
+// the session is only issued after the second step.
+type TwoFactorRequiredError struct {
+	Challenge string
+}
+
+func (e TwoFactorRequiredError) Error() string {
+	return ErrTwoFactorRequired.Error()
+}
+
+func (e TwoFactorRequiredError) Unwrap() error {
+	return ErrTwoFactorRequired
+}
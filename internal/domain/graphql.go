@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"context"
+
+	gormModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/gorm"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+)
+
+// Page is a slice of a list, Limit items after the first Offset.
+type Page struct {
+	Limit  int
+	Offset int
+}
+
+// GraphQLUsecase reads the lists and relations GraphQL queries ask for. The
+// relations of many items are read at once, so nested fields of a list cost
+// one query per level instead of one per item. Single items and mutations
+// go through the movies and actors usecases.
+type GraphQLUsecase interface {
+	GetMovies(
+		ctx context.Context,
+		filter httpModels.MoviesFilter,
+		page Page,
+	) ([]httpModels.MovieResponse, error)
+	GetActors(ctx context.Context, name string, page Page) ([]httpModels.ActorResponse, error)
+	// GetCasts returns the cast of every movie, keyed by the movie id.
+	GetCasts(ctx context.Context, movieIDs []uint64) (map[uint64][]httpModels.ActorResponse, error)
+	// GetFilmographies returns the movies of every actor, keyed by the actor
+	// id.
+	GetFilmographies(
+		ctx context.Context,
+		actorIDs []uint64,
+	) (map[uint64][]httpModels.MovieResponse, error)
+}
+
+type GraphQLRepository interface {
+	GetMovies(
+		ctx context.Context,
+		filter httpModels.MoviesFilter,
+		page Page,
+	) ([]gormModels.Movie, error)
+	GetActors(ctx context.Context, name string, page Page) ([]gormModels.Actor, error)
+	// GetCasts and GetFilmographies return the relations of the items with
+	// the items on the other side, in the order they were added.
+	GetCasts(
+		ctx context.Context,
+		movieIDs []uint64,
+	) ([]gormModels.ActorMovieRelation, []gormModels.Actor, error)
+	GetFilmographies(
+		ctx context.Context,
+		actorIDs []uint64,
+	) ([]gormModels.ActorMovieRelation, []gormModels.Movie, error)
+}
@@ -1,21 +1,42 @@
 package domain
 
 import (
+	"context"
+
 	gormModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/gorm"
 	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
 )
 
+// The version arguments of the mutations are the expected version of the
+// item, as sent in If-Match. Zero means any version.
 type MoviesUsecase interface {
-	CreateMovie(movie httpModels.MovieWithIDCast) (uint64, error)
+	CreateMovie(ctx context.Context, movie httpModels.MovieWithIDCast) (uint64, error)
 	UpdateMovie(
+		ctx context.Context,
 		movie httpModels.MovieWithoutCastList,
-		movieID uint64,
+		movieID, version uint64,
+	) (httpModels.MovieResponse, error)
+	PatchMovie(
+		ctx context.Context,
+		movieID, version uint64,
+		patch Patch,
 	) (httpModels.MovieResponse, error)
-	GetMovieByID(movieID uint64) (httpModels.MovieResponse, error)
-	DeleteMovieByID(movieID uint64) error
-	DeleteActorFromMovie(movieID, actorID uint64) error
-	AddActorFromMovie(movieID, actorID uint64) error
+	GetMovieByID(ctx context.Context, movieID uint64) (httpModels.MovieResponse, error)
+	DeleteMovieByID(ctx context.Context, movieID, version uint64) error
+	DeleteActorFromMovie(ctx context.Context, movieID, actorID uint64) error
+	AddActorFromMovie(ctx context.Context, movieID, actorID uint64) error
+	ReplaceCast(
+		ctx context.Context,
+		movieID, version uint64,
+		cast httpModels.Cast,
+	) (httpModels.CastDiff, error)
+	BatchCast(
+		ctx context.Context,
+		movieID, version uint64,
+		batch httpModels.CastBatch,
+	) (httpModels.CastDiff, error)
 	GetMovies(
+		ctx context.Context,
 		title, actorName string,
 		sortBy httpModels.SortBy,
 		order bool,
@@ -23,15 +44,32 @@ type MoviesUsecase interface {
 }
 
 type MoviesRepository interface {
-	CreateMovieWithoutCastList(movie gormModels.Movie) (uint64, error)
-	CreateMovieWithCastList(movie gormModels.Movie, castList []uint64) (uint64, error)
-	UpdateMovie(movie gormModels.Movie) (gormModels.Movie, error)
-	GetMovieByID(movieID uint64) (gormModels.Movie, error)
-	DeleteMovieByID(movieID uint64) error
-	DeleteActorFromMovie(movieID, actorID uint64) error
-	AddActorToMovie(movieID, actorID uint64) error
-	GetMoviesOfActor(actorID uint64) ([]gormModels.Movie, error)
+	CreateMovieWithoutCastList(ctx context.Context, movie gormModels.Movie) (uint64, error)
+	CreateMovieWithCastList(
+		ctx context.Context,
+		movie gormModels.Movie,
+		castList []uint64,
+	) (uint64, error)
+	UpdateMovie(ctx context.Context, movie gormModels.Movie) (gormModels.Movie, error)
+	PatchMovie(
+		ctx context.Context,
+		movieID, version uint64,
+		patch func(movie *gormModels.Movie) error,
+	) (gormModels.Movie, error)
+	GetMovieByID(ctx context.Context, movieID uint64) (gormModels.Movie, error)
+	DeleteMovieByID(ctx context.Context, movieID, version uint64) error
+	DeleteActorFromMovie(ctx context.Context, movieID, actorID uint64) error
+	AddActorToMovie(ctx context.Context, movieID, actorID uint64) error
+	// ChangeCast gives change the actor ids of the current cast and makes
+	// the cast what change returns, all in one transaction.
+	ChangeCast(
+		ctx context.Context,
+		movieID, version uint64,
+		change func(cast []uint64) []uint64,
+	) (gormModels.CastChange, error)
+	GetMoviesOfActor(ctx context.Context, actorID uint64) ([]gormModels.Movie, error)
 	GetMovies(
+		ctx context.Context,
 		title, actorName string,
 		sortBy httpModels.SortBy,
 		order bool,
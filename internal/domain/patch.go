@@ -0,0 +1,6 @@
+package domain
+
+// Patch is a partial update of the JSON representation of an entity.
+type Patch interface {
+	Apply(doc []byte) ([]byte, error)
+}
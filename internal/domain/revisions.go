@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	gormModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/gorm"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+)
+
+// Entities with a revision history.
+const (
+	MovieEntity = "movie"
+	ActorEntity = "actor"
+)
+
+type RevisionsUsecase interface {
+	GetRevisions(ctx context.Context, entity string, entityID uint64) ([]httpModels.Revision, error)
+	RevertMovie(ctx context.Context, movieID, number uint64) (httpModels.MovieResponse, error)
+	RevertActor(ctx context.Context, actorID, number uint64) (httpModels.ActorResponse, error)
+	GetCatalogAt(ctx context.Context, at time.Time) (httpModels.Catalog, error)
+}
+
+type RevisionsRepository interface {
+	GetRevisions(ctx context.Context, entity string, entityID uint64) ([]gormModels.Revision, error)
+	GetRevision(
+		ctx context.Context,
+		entity string,
+		entityID, number uint64,
+	) (gormModels.Revision, error)
+	GetRevisionsAt(ctx context.Context, entity string, at time.Time) ([]gormModels.Revision, error)
+}
@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	gormModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/gorm"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+)
+
+type TrashUsecase interface {
+	GetTrash(ctx context.Context, entity string, page uint64) ([]httpModels.TrashItem, error)
+	Restore(ctx context.Context, entity string, entityID uint64) error
+	Purge(ctx context.Context) (int64, error)
+}
+
+type TrashRepository interface {
+	GetTrash(ctx context.Context, entity string, page uint64) ([]gormModels.TrashItem, error)
+	RestoreMovie(ctx context.Context, movieID uint64) error
+	RestoreActor(ctx context.Context, actorID uint64) error
+	Purge(ctx context.Context, deletedBefore time.Time) (int64, error)
+}
@@ -0,0 +1,137 @@
+package httpGraphQL
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sync"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/problem"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/validate"
+)
+
+// maxDepth bounds the nesting of a query, movies of the cast of a movie are
+// three levels.
+const maxDepth = 10
+
+//go:embed schema.graphql
+var schema string
+
+type GraphQLHandler struct {
+	schema *graphql.Schema
+	admin  func(r *http.Request) error
+}
+
+// NewGraphQLHandler makes the handler of the schema. admin tells whether the
+// request may run mutations, pageSize is the page of lists that don't ask
+// for one.
+func NewGraphQLHandler(
+	g domain.GraphQLUsecase,
+	m domain.MoviesUsecase,
+	a domain.ActorsUsecase,
+	auth domain.AuthUsecase,
+	admin func(r *http.Request) error,
+	pageSize int,
+) GraphQLHandler {
+	return GraphQLHandler{
+		schema: graphql.MustParseSchema(
+			schema,
+			&resolver{
+				graphqlUsecase: g,
+				moviesUsecase:  m,
+				actorsUsecase:  a,
+				authUsecase:    auth,
+				pageSize:       pageSize,
+			},
+			graphql.MaxDepth(maxDepth),
+		),
+		admin: admin,
+	}
+}
+
+// GraphQL answers 200 with the data and the errors of the fields, problems
+// are only for requests that can't be run at all.
+func (h GraphQLHandler) GraphQL(w http.ResponseWriter, r *http.Request) {
+	var request httpModels.GraphQLRequest
+	if err := validate.DecodeJSON(r, &request); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), sessionKey{}, &session{r: r, admin: h.admin})
+	response := h.schema.Exec(ctx, request.Query, request.OperationName, request.Variables)
+
+	responseData, err := json.Marshal(response)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(responseData)
+}
+
+type sessionKey struct{}
+
+// session is the request a query came with, the admin check runs once per
+// request however many mutations it has.
+type session struct {
+	r     *http.Request
+	admin func(r *http.Request) error
+
+	once     sync.Once
+	adminErr error
+}
+
+func requireAdmin(ctx context.Context) error {
+	s := ctx.Value(sessionKey{}).(*session)
+	s.once.Do(func() {
+		s.adminErr = s.admin(s.r)
+	})
+	return s.adminErr
+}
+
+func request(ctx context.Context) *http.Request {
+	return ctx.Value(sessionKey{}).(*session).r
+}
+
+// fieldError is a domain error as a GraphQL error. The extensions carry what
+// a problem response would.
+type fieldError struct {
+	problem problem.Problem
+}
+
+func newFieldError(ctx context.Context, err error) error {
+	p := problem.New(err)
+	if p.Status == http.StatusInternalServerError {
+		slog.ErrorContext(ctx, "graphql field failed", "error", err)
+	}
+	return fieldError{problem: p}
+}
+
+// newValidationError is newFieldError for broken rules of the arguments,
+// they are the client's to fix and are not logged.
+func newValidationError(violations []domain.FieldError) error {
+	return fieldError{problem: problem.New(domain.ValidationError{Violations: violations})}
+}
+
+func (e fieldError) Error() string {
+	return e.problem.Detail
+}
+
+func (e fieldError) Extensions() map[string]interface{} {
+	extensions := map[string]interface{}{
+		"code":   e.problem.Code,
+		"status": e.problem.Status,
+	}
+	if len(e.problem.Violations) > 0 {
+		extensions["violations"] = e.problem.Violations
+	}
+	return extensions
+}
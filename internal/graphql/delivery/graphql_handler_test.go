@@ -0,0 +1,196 @@
+package httpGraphQL
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/docs"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	mockDomain "github.com/themilchenko/vk-tech_internship-problem_2024/internal/mocks/domain"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/openapi"
+	"go.uber.org/mock/gomock"
+)
+
+var loadSpec = sync.OnceValues(func() (*openapi.Spec, error) {
+	return openapi.Load(docs.Swagger)
+})
+
+// strict checks the handlers behind mux against the spec, as the API is
+// checked in tests.
+func strict(t *testing.T, mux http.Handler) http.Handler {
+	spec, err := loadSpec()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return spec.Strict(mux)
+}
+
+type mocks struct {
+	graphql *mockDomain.MockGraphQLUsecase
+	movies  *mockDomain.MockMoviesUsecase
+	actors  *mockDomain.MockActorsUsecase
+	auth    *mockDomain.MockAuthUsecase
+}
+
+func TestHandler_GraphQL(t *testing.T) {
+	type mockBehavior func(m mocks)
+
+	godfather := httpModels.MovieResponse{ID: 1, Title: "The Godfather", ReleaseDate: "1972-03-24", Rating: 9.2}
+	heat := httpModels.MovieResponse{ID: 2, Title: "Heat", ReleaseDate: "1995-12-15", Rating: 8.3}
+	brando := httpModels.ActorResponse{ID: 3, Name: "Marlon Brando", Gender: true, BirthDate: "1924-04-03"}
+	pacino := httpModels.ActorResponse{ID: 4, Name: "Al Pacino", Gender: true, BirthDate: "1940-04-25"}
+
+	tests := []struct {
+		name                 string
+		inputBody            string
+		adminErr             error
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Nested lists",
+			inputBody: `{"query":"{ movies(title: \"e\", sortBy: TITLE, order: DESC, first: 2) ` +
+				`{ id title cast { name movies { title } } } }"}`,
+			mockBehavior: func(m mocks) {
+				m.graphql.EXPECT().
+					GetMovies(gomock.Any(), httpModels.MoviesFilter{Title: "e", SortBy: "title"}, domain.Page{Limit: 2}).
+					Return([]httpModels.MovieResponse{godfather, heat}, nil)
+				m.graphql.EXPECT().
+					GetCasts(gomock.Any(), []uint64{1, 2}).
+					Return(map[uint64][]httpModels.ActorResponse{
+						1: {brando, pacino},
+						2: {pacino},
+					}, nil)
+				m.graphql.EXPECT().
+					GetFilmographies(gomock.Any(), []uint64{3, 4}).
+					Return(map[uint64][]httpModels.MovieResponse{
+						3: {godfather},
+						4: {godfather, heat},
+					}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"data":{"movies":[` +
+				`{"id":"1","title":"The Godfather","cast":[` +
+				`{"name":"Marlon Brando","movies":[{"title":"The Godfather"}]},` +
+				`{"name":"Al Pacino","movies":[{"title":"The Godfather"},{"title":"Heat"}]}]},` +
+				`{"id":"2","title":"Heat","cast":[` +
+				`{"name":"Al Pacino","movies":[{"title":"The Godfather"},{"title":"Heat"}]}]}]}}`,
+		},
+		{
+			name:      "Movie with its cast",
+			inputBody: `{"query":"query($id: ID!) { movie(id: $id) { title cast { name } } }","variables":{"id":"1"}}`,
+			mockBehavior: func(m mocks) {
+				movie := godfather
+				movie.CastList = []httpModels.ActorResponse{brando}
+				m.movies.EXPECT().GetMovieByID(gomock.Any(), uint64(1)).Return(movie, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"data":{"movie":{"title":"The Godfather","cast":[{"name":"Marlon Brando"}]}}}`,
+		},
+		{
+			name:      "Unknown movie",
+			inputBody: `{"query":"{ movie(id: 7) { title } }"}`,
+			mockBehavior: func(m mocks) {
+				m.movies.EXPECT().GetMovieByID(gomock.Any(), uint64(7)).Return(httpModels.MovieResponse{}, domain.ErrNotFound)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"data":{"movie":null}}`,
+		},
+		{
+			name:               "Too large page",
+			inputBody:          `{"query":"{ actors(first: 101) { name } }"}`,
+			mockBehavior:       func(m mocks) {},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"errors":[{"message":"validation failed","path":["actors"],` +
+				`"extensions":{"code":"validation_failed","status":422,"violations":[{"field":"first","rule":"range","message":"must be between 1 and 100"}]}}],` +
+				`"data":null}`,
+		},
+		{
+			name: "Create actor",
+			inputBody: `{"query":"mutation { createActor(input: {name: \"Marlon Brando\", gender: true, birthDate: \"1924-04-03\"}) ` +
+				`{ id name } }"}`,
+			mockBehavior: func(m mocks) {
+				m.actors.EXPECT().
+					CreateActor(gomock.Any(), httpModels.Actor{Name: "Marlon Brando", Gender: true, BirthDate: "1924-04-03"}).
+					Return(uint64(3), nil)
+				m.actors.EXPECT().GetActorByID(gomock.Any(), uint64(3)).Return(brando, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"data":{"createActor":{"id":"3","name":"Marlon Brando"}}}`,
+		},
+		{
+			name: "Invalid movie",
+			inputBody: `{"query":"mutation { createMovie(input: {title: \"\", releaseDate: \"1972-03-24\", castIDs: [3]}) ` +
+				`{ id } }"}`,
+			mockBehavior:       func(m mocks) {},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"errors":[{"message":"validation failed","path":["createMovie"],` +
+				`"extensions":{"code":"validation_failed","status":422,"violations":[{"field":"input.title","rule":"required","message":"is required"}]}}],` +
+				`"data":null}`,
+		},
+		{
+			name:               "Not an admin",
+			inputBody:          `{"query":"mutation { deleteMovie(id: 1, version: 2) }"}`,
+			adminErr:           domain.ErrForbidden,
+			mockBehavior:       func(m mocks) {},
+			expectedStatusCode: http.StatusOK,
+			expectedResponseBody: `{"errors":[{"message":"you are not supposed to be here","path":["deleteMovie"],` +
+				`"extensions":{"code":"forbidden","status":403}}],` +
+				`"data":null}`,
+		},
+		{
+			name:               "No query",
+			inputBody:          `{"variables":{}}`,
+			mockBehavior:       func(m mocks) {},
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"validation failed","code":"validation_failed",` +
+				`"violations":[{"field":"query","rule":"required","message":"is required"}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cntx := gomock.NewController(t)
+			defer cntx.Finish()
+
+			m := mocks{
+				graphql: mockDomain.NewMockGraphQLUsecase(cntx),
+				movies:  mockDomain.NewMockMoviesUsecase(cntx),
+				actors:  mockDomain.NewMockActorsUsecase(cntx),
+				auth:    mockDomain.NewMockAuthUsecase(cntx),
+			}
+			tt.mockBehavior(m)
+
+			handler := NewGraphQLHandler(
+				m.graphql,
+				m.movies,
+				m.actors,
+				m.auth,
+				func(r *http.Request) error { return tt.adminErr },
+				20,
+			)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("POST /api/v1/graphql", handler.GraphQL)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(
+				http.MethodPost,
+				"/api/v1/graphql",
+				bytes.NewBufferString(tt.inputBody),
+			)
+
+			strict(t, mux).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, strings.Trim(w.Body.String(), "\n"))
+		})
+	}
+}
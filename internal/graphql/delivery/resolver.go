@@ -0,0 +1,404 @@
+package httpGraphQL
+
+import (
+	"context"
+	"errors"
+
+	"github.com/graph-gophers/graphql-go"
+	httpAuth "github.com/themilchenko/vk-tech_internship-problem_2024/internal/auth/delivery"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/requestctx"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/validate"
+)
+
+// maxPageSize bounds the first argument of the lists.
+const maxPageSize = 100
+
+// sortFields are the sortBy values of GET /movies by the MovieSort values.
+var sortFields = map[string]httpModels.SortBy{
+	"TITLE":        "title",
+	"RATING":       "rating",
+	"RELEASE_DATE": "releaseDate",
+}
+
+// resolver is the root of the schema, its methods are the fields of Query
+// and Mutation.
+type resolver struct {
+	graphqlUsecase domain.GraphQLUsecase
+	moviesUsecase  domain.MoviesUsecase
+	actorsUsecase  domain.ActorsUsecase
+	authUsecase    domain.AuthUsecase
+	pageSize       int
+}
+
+// Queries
+
+func (r *resolver) Movie(ctx context.Context, args struct{ ID graphql.ID }) (*movieResolver, error) {
+	movieID, err := fromID("id", args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	movie, err := r.moviesUsecase.GetMovieByID(ctx, movieID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, newFieldError(ctx, err)
+	}
+	return newMovie(r.graphqlUsecase, movie), nil
+}
+
+func (r *resolver) Movies(ctx context.Context, args struct {
+	Title  *string
+	Actor  *string
+	SortBy string
+	Order  string
+	First  *int32
+	Offset int32
+}) ([]*movieResolver, error) {
+	page, err := r.page(args.First, args.Offset)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := httpModels.MoviesFilter{
+		SortBy: sortFields[args.SortBy],
+		Order:  args.Order == "ASC",
+	}
+	if args.Title != nil {
+		filter.Title = *args.Title
+	}
+	if args.Actor != nil {
+		filter.Actor = *args.Actor
+	}
+
+	movies, err := r.graphqlUsecase.GetMovies(ctx, filter, page)
+	if err != nil {
+		return nil, newFieldError(ctx, err)
+	}
+	return newMovies(r.graphqlUsecase, movies), nil
+}
+
+func (r *resolver) Actor(ctx context.Context, args struct{ ID graphql.ID }) (*actorResolver, error) {
+	actorID, err := fromID("id", args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	actor, err := r.actorsUsecase.GetActorByID(ctx, actorID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, newFieldError(ctx, err)
+	}
+	return newActors(r.graphqlUsecase, []httpModels.ActorResponse{actor})[0], nil
+}
+
+func (r *resolver) Actors(ctx context.Context, args struct {
+	Name   *string
+	First  *int32
+	Offset int32
+}) ([]*actorResolver, error) {
+	page, err := r.page(args.First, args.Offset)
+	if err != nil {
+		return nil, err
+	}
+
+	var name string
+	if args.Name != nil {
+		name = *args.Name
+	}
+
+	actors, err := r.graphqlUsecase.GetActors(ctx, name, page)
+	if err != nil {
+		return nil, newFieldError(ctx, err)
+	}
+	return newActors(r.graphqlUsecase, actors), nil
+}
+
+func (r *resolver) Me(ctx context.Context) (*userResolver, error) {
+	cookie, err := request(ctx).Cookie(httpAuth.CookieName)
+	if err != nil {
+		return nil, newFieldError(ctx, domain.ErrNoSession)
+	}
+
+	user, err := r.authUsecase.GetUserBySessionID(ctx, cookie.Value)
+	if err != nil {
+		return nil, newFieldError(ctx, err)
+	}
+	return &userResolver{id: requestctx.UserID(ctx), user: user}, nil
+}
+
+func (r *resolver) page(first *int32, offset int32) (domain.Page, error) {
+	page := domain.Page{Limit: r.pageSize, Offset: int(offset)}
+	if first != nil {
+		page.Limit = int(*first)
+	}
+
+	var violations []domain.FieldError
+	if page.Limit < 1 || page.Limit > maxPageSize {
+		violations = append(violations, domain.FieldError{
+			Field:   "first",
+			Rule:    "range",
+			Message: "must be between 1 and 100",
+		})
+	}
+	if page.Offset < 0 {
+		violations = append(violations, domain.FieldError{
+			Field:   "offset",
+			Rule:    "min",
+			Message: "must be at least 0",
+		})
+	}
+	if len(violations) > 0 {
+		return domain.Page{}, newValidationError(violations)
+	}
+	return page, nil
+}
+
+// Mutations
+
+type movieInput struct {
+	Title       string
+	Description string
+	ReleaseDate string
+	Rating      float64
+	CastIDs     []graphql.ID
+}
+
+type movieUpdate struct {
+	Title       string
+	Description string
+	ReleaseDate string
+	Rating      float64
+}
+
+type actorInput struct {
+	Name      string
+	Gender    bool
+	BirthDate string
+}
+
+func (r *resolver) CreateMovie(ctx context.Context, args struct{ Input movieInput }) (*movieResolver, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, newFieldError(ctx, err)
+	}
+
+	movie := httpModels.MovieWithIDCast{
+		Title:       args.Input.Title,
+		Description: args.Input.Description,
+		ReleaseDate: args.Input.ReleaseDate,
+		Rating:      float32(args.Input.Rating),
+		CastIDList:  make([]uint64, len(args.Input.CastIDs)),
+	}
+	for i, id := range args.Input.CastIDs {
+		var err error
+		if movie.CastIDList[i], err = fromID("input.castIDs", id); err != nil {
+			return nil, err
+		}
+	}
+	if err := check(movie); err != nil {
+		return nil, err
+	}
+
+	movieID, err := r.moviesUsecase.CreateMovie(ctx, movie)
+	if err != nil {
+		return nil, newFieldError(ctx, err)
+	}
+	return r.Movie(ctx, struct{ ID graphql.ID }{toID(movieID)})
+}
+
+func (r *resolver) UpdateMovie(ctx context.Context, args struct {
+	ID      graphql.ID
+	Input   movieUpdate
+	Version *int32
+}) (*movieResolver, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, newFieldError(ctx, err)
+	}
+	movieID, err := fromID("id", args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	movie := httpModels.MovieWithoutCastList{
+		Title:       args.Input.Title,
+		Description: args.Input.Description,
+		ReleaseDate: args.Input.ReleaseDate,
+		Rating:      float32(args.Input.Rating),
+	}
+	if err = check(movie); err != nil {
+		return nil, err
+	}
+
+	updated, err := r.moviesUsecase.UpdateMovie(ctx, movie, movieID, version(args.Version))
+	if err != nil {
+		return nil, newFieldError(ctx, err)
+	}
+	return newMovies(r.graphqlUsecase, []httpModels.MovieResponse{updated})[0], nil
+}
+
+func (r *resolver) DeleteMovie(ctx context.Context, args struct {
+	ID      graphql.ID
+	Version *int32
+}) (bool, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return false, newFieldError(ctx, err)
+	}
+	movieID, err := fromID("id", args.ID)
+	if err != nil {
+		return false, err
+	}
+
+	if err = r.moviesUsecase.DeleteMovieByID(ctx, movieID, version(args.Version)); err != nil {
+		return false, newFieldError(ctx, err)
+	}
+	return true, nil
+}
+
+func (r *resolver) ReplaceCast(ctx context.Context, args struct {
+	MovieID  graphql.ID
+	ActorIDs []graphql.ID
+	Version  *int32
+}) (*castDiffResolver, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, newFieldError(ctx, err)
+	}
+	movieID, err := fromID("movieID", args.MovieID)
+	if err != nil {
+		return nil, err
+	}
+
+	cast := httpModels.Cast{ActorIDs: make([]uint64, len(args.ActorIDs))}
+	for i, id := range args.ActorIDs {
+		if cast.ActorIDs[i], err = fromID("actorIDs", id); err != nil {
+			return nil, err
+		}
+	}
+
+	diff, err := r.moviesUsecase.ReplaceCast(ctx, movieID, version(args.Version), cast)
+	if err != nil {
+		return nil, newFieldError(ctx, err)
+	}
+	return &castDiffResolver{diff: diff}, nil
+}
+
+func (r *resolver) AddActorToMovie(ctx context.Context, args struct {
+	MovieID graphql.ID
+	ActorID graphql.ID
+}) (bool, error) {
+	return r.changeCast(ctx, args.MovieID, args.ActorID, r.moviesUsecase.AddActorFromMovie)
+}
+
+func (r *resolver) RemoveActorFromMovie(ctx context.Context, args struct {
+	MovieID graphql.ID
+	ActorID graphql.ID
+}) (bool, error) {
+	return r.changeCast(ctx, args.MovieID, args.ActorID, r.moviesUsecase.DeleteActorFromMovie)
+}
+
+func (r *resolver) changeCast(
+	ctx context.Context,
+	movieID, actorID graphql.ID,
+	change func(ctx context.Context, movieID, actorID uint64) error,
+) (bool, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return false, newFieldError(ctx, err)
+	}
+	parsedMovieID, err := fromID("movieID", movieID)
+	if err != nil {
+		return false, err
+	}
+	parsedActorID, err := fromID("actorID", actorID)
+	if err != nil {
+		return false, err
+	}
+
+	if err = change(ctx, parsedMovieID, parsedActorID); err != nil {
+		return false, newFieldError(ctx, err)
+	}
+	return true, nil
+}
+
+func (r *resolver) CreateActor(ctx context.Context, args struct{ Input actorInput }) (*actorResolver, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, newFieldError(ctx, err)
+	}
+
+	actor := httpModels.Actor(args.Input)
+	if err := check(actor); err != nil {
+		return nil, err
+	}
+
+	actorID, err := r.actorsUsecase.CreateActor(ctx, actor)
+	if err != nil {
+		return nil, newFieldError(ctx, err)
+	}
+	return r.Actor(ctx, struct{ ID graphql.ID }{toID(actorID)})
+}
+
+func (r *resolver) UpdateActor(ctx context.Context, args struct {
+	ID      graphql.ID
+	Input   actorInput
+	Version *int32
+}) (*actorResolver, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, newFieldError(ctx, err)
+	}
+	actorID, err := fromID("id", args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	actor := httpModels.Actor(args.Input)
+	if err = check(actor); err != nil {
+		return nil, err
+	}
+
+	updated, err := r.actorsUsecase.UpdateActor(ctx, actor, actorID, version(args.Version))
+	if err != nil {
+		return nil, newFieldError(ctx, err)
+	}
+	return newActors(r.graphqlUsecase, []httpModels.ActorResponse{updated})[0], nil
+}
+
+func (r *resolver) DeleteActor(ctx context.Context, args struct {
+	ID      graphql.ID
+	Version *int32
+}) (bool, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return false, newFieldError(ctx, err)
+	}
+	actorID, err := fromID("id", args.ID)
+	if err != nil {
+		return false, err
+	}
+
+	if err = r.actorsUsecase.DeleteActorByID(ctx, actorID, version(args.Version)); err != nil {
+		return false, newFieldError(ctx, err)
+	}
+	return true, nil
+}
+
+// check applies the rules of the REST bodies to an input, the paths of the
+// violations are prefixed with "input.".
+func check(input interface{}) error {
+	violations := validate.Struct(input)
+	if len(violations) == 0 {
+		return nil
+	}
+	for i := range violations {
+		violations[i].Field = "input." + violations[i].Field
+	}
+	return newValidationError(violations)
+}
+
+func version(v *int32) uint64 {
+	if v == nil || *v < 0 {
+		return 0
+	}
+	return uint64(*v)
+}
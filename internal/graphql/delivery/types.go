@@ -0,0 +1,270 @@
+package httpGraphQL
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+)
+
+// movieList holds movies resolved side by side, e.g. one page of movies or
+// the movies of every actor on a page. The cast of all of them is read at
+// once, when the first one is asked for it, and the actors of all the casts
+// make the next list.
+type movieList struct {
+	graphqlUsecase domain.GraphQLUsecase
+	ids            []uint64
+
+	once  sync.Once
+	casts map[uint64][]httpModels.ActorResponse
+	next  *actorList
+	err   error
+}
+
+func newMovies(g domain.GraphQLUsecase, movies []httpModels.MovieResponse) []*movieResolver {
+	list := &movieList{graphqlUsecase: g}
+	resolvers := make([]*movieResolver, len(movies))
+	for i, movie := range movies {
+		list.ids = append(list.ids, movie.ID)
+		resolvers[i] = &movieResolver{movie: movie, list: list}
+	}
+	return resolvers
+}
+
+// newMovie makes the resolver of a movie read with its cast.
+func newMovie(g domain.GraphQLUsecase, movie httpModels.MovieResponse) *movieResolver {
+	list := &movieList{graphqlUsecase: g, ids: []uint64{movie.ID}}
+	list.once.Do(func() {
+		list.setCasts(map[uint64][]httpModels.ActorResponse{movie.ID: movie.CastList})
+	})
+	return &movieResolver{movie: movie, list: list}
+}
+
+func (l *movieList) cast(ctx context.Context, movieID uint64) ([]*actorResolver, error) {
+	l.once.Do(func() {
+		casts, err := l.graphqlUsecase.GetCasts(ctx, l.ids)
+		if err != nil {
+			l.err = newFieldError(ctx, err)
+			return
+		}
+		l.setCasts(casts)
+	})
+	if l.err != nil {
+		return nil, l.err
+	}
+	return l.next.resolvers(l.casts[movieID]), nil
+}
+
+func (l *movieList) setCasts(casts map[uint64][]httpModels.ActorResponse) {
+	l.casts = casts
+	l.next = &actorList{graphqlUsecase: l.graphqlUsecase}
+	for _, movieID := range l.ids {
+		for _, actor := range casts[movieID] {
+			l.next.add(actor.ID)
+		}
+	}
+}
+
+func (l *movieList) resolvers(movies []httpModels.MovieResponse) []*movieResolver {
+	resolvers := make([]*movieResolver, len(movies))
+	for i, movie := range movies {
+		resolvers[i] = &movieResolver{movie: movie, list: l}
+	}
+	return resolvers
+}
+
+func (l *movieList) add(movieID uint64) {
+	for _, id := range l.ids {
+		if id == movieID {
+			return
+		}
+	}
+	l.ids = append(l.ids, movieID)
+}
+
+// actorList is movieList for actors and the movies they acted in.
+type actorList struct {
+	graphqlUsecase domain.GraphQLUsecase
+	ids            []uint64
+
+	once          sync.Once
+	filmographies map[uint64][]httpModels.MovieResponse
+	next          *movieList
+	err           error
+}
+
+func newActors(g domain.GraphQLUsecase, actors []httpModels.ActorResponse) []*actorResolver {
+	list := &actorList{graphqlUsecase: g}
+	for _, actor := range actors {
+		list.add(actor.ID)
+	}
+	return list.resolvers(actors)
+}
+
+func (l *actorList) movies(ctx context.Context, actorID uint64) ([]*movieResolver, error) {
+	l.once.Do(func() {
+		filmographies, err := l.graphqlUsecase.GetFilmographies(ctx, l.ids)
+		if err != nil {
+			l.err = newFieldError(ctx, err)
+			return
+		}
+		l.filmographies = filmographies
+		l.next = &movieList{graphqlUsecase: l.graphqlUsecase}
+		for _, actorID := range l.ids {
+			for _, movie := range filmographies[actorID] {
+				l.next.add(movie.ID)
+			}
+		}
+	})
+	if l.err != nil {
+		return nil, l.err
+	}
+	return l.next.resolvers(l.filmographies[actorID]), nil
+}
+
+func (l *actorList) resolvers(actors []httpModels.ActorResponse) []*actorResolver {
+	resolvers := make([]*actorResolver, len(actors))
+	for i, actor := range actors {
+		resolvers[i] = &actorResolver{actor: actor, list: l}
+	}
+	return resolvers
+}
+
+func (l *actorList) add(actorID uint64) {
+	for _, id := range l.ids {
+		if id == actorID {
+			return
+		}
+	}
+	l.ids = append(l.ids, actorID)
+}
+
+type movieResolver struct {
+	movie httpModels.MovieResponse
+	list  *movieList
+}
+
+func (m *movieResolver) ID() graphql.ID {
+	return toID(m.movie.ID)
+}
+
+func (m *movieResolver) Title() string {
+	return m.movie.Title
+}
+
+func (m *movieResolver) Description() string {
+	return m.movie.Description
+}
+
+func (m *movieResolver) ReleaseDate() string {
+	return m.movie.ReleaseDate
+}
+
+func (m *movieResolver) Rating() float64 {
+	return float64(m.movie.Rating)
+}
+
+func (m *movieResolver) Version() int32 {
+	return int32(m.movie.Version)
+}
+
+func (m *movieResolver) Cast(ctx context.Context) ([]*actorResolver, error) {
+	return m.list.cast(ctx, m.movie.ID)
+}
+
+type actorResolver struct {
+	actor httpModels.ActorResponse
+	list  *actorList
+}
+
+func (a *actorResolver) ID() graphql.ID {
+	return toID(a.actor.ID)
+}
+
+func (a *actorResolver) Name() string {
+	return a.actor.Name
+}
+
+func (a *actorResolver) Gender() bool {
+	return a.actor.Gender
+}
+
+func (a *actorResolver) BirthDate() string {
+	return a.actor.BirthDate
+}
+
+func (a *actorResolver) Version() int32 {
+	return int32(a.actor.Version)
+}
+
+func (a *actorResolver) Movies(ctx context.Context) ([]*movieResolver, error) {
+	return a.list.movies(ctx, a.actor.ID)
+}
+
+type userResolver struct {
+	id   uint64
+	user httpModels.AuthUser
+}
+
+func (u *userResolver) ID() graphql.ID {
+	return toID(u.id)
+}
+
+func (u *userResolver) Username() string {
+	return u.user.Username
+}
+
+func (u *userResolver) Role() string {
+	return u.user.Role
+}
+
+func (u *userResolver) TwoFactorEnabled() bool {
+	return u.user.TwoFactorEnabled
+}
+
+type castDiffResolver struct {
+	diff httpModels.CastDiff
+}
+
+func (c *castDiffResolver) Added() []graphql.ID {
+	return toIDs(c.diff.Added)
+}
+
+func (c *castDiffResolver) Removed() []graphql.ID {
+	return toIDs(c.diff.Removed)
+}
+
+func (c *castDiffResolver) Unchanged() []graphql.ID {
+	return toIDs(c.diff.Unchanged)
+}
+
+func (c *castDiffResolver) Version() int32 {
+	return int32(c.diff.Version)
+}
+
+func toID(id uint64) graphql.ID {
+	return graphql.ID(strconv.FormatUint(id, 10))
+}
+
+func toIDs(ids []uint64) []graphql.ID {
+	graphqlIDs := make([]graphql.ID, len(ids))
+	for i, id := range ids {
+		graphqlIDs[i] = toID(id)
+	}
+	return graphqlIDs
+}
+
+func fromID(field string, id graphql.ID) (uint64, error) {
+	parsed, err := strconv.ParseUint(string(id), 10, 64)
+	if err != nil {
+		return 0, newValidationError([]domain.FieldError{{
+			Field:   field,
+			Rule:    "type",
+			Message: "must be an integer",
+		}})
+	}
+	return parsed, nil
+}
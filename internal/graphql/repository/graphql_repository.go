@@ -0,0 +1,151 @@
+package graphqlRepository
+
+import (
+	"context"
+
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	gormModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/gorm"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	moviesRepository "github.com/themilchenko/vk-tech_internship-problem_2024/internal/movies/repository"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/dbtx"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/metrics"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/pgerrors"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/sqlcomment"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/tracing"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+)
+
+type Postgres struct {
+	DB *gorm.DB
+}
+
+func NewPostgres(url string, logger gormLogger.Interface) (*Postgres, error) {
+	db, err := gorm.Open(postgres.Open(url), &gorm.Config{Logger: logger})
+	if err != nil {
+		return nil, err
+	}
+	if err = db.Use(pgerrors.Plugin{}); err != nil {
+		return nil, err
+	}
+	if err = db.Use(dbtx.Plugin{}); err != nil {
+		return nil, err
+	}
+	if err = db.Use(sqlcomment.Plugin{}); err != nil {
+		return nil, err
+	}
+	if err = db.Use(metrics.Plugin{DB: "graphql"}); err != nil {
+		return nil, err
+	}
+	if err = db.Use(tracing.Plugin{DB: "graphql"}); err != nil {
+		return nil, err
+	}
+
+	return &Postgres{
+		DB: db,
+	}, nil
+}
+
+// GetMovies orders the movies like GET /movies does, the id breaks ties so
+// pages don't overlap.
+func (db Postgres) GetMovies(
+	ctx context.Context,
+	filter httpModels.MoviesFilter,
+	page domain.Page,
+) ([]gormModels.Movie, error) {
+	var movies []gormModels.Movie
+	if err := moviesRepository.FilterMovies(
+		db.DB.WithContext(ctx).Model(&gormModels.Movie{}).Select("movies.*"),
+		filter.Title,
+		filter.Actor,
+		filter.SortBy,
+		filter.Order,
+	).
+		Order("movies.id").
+		Offset(page.Offset).
+		Limit(page.Limit).
+		Find(&movies).
+		Error; err != nil {
+		return nil, err
+	}
+	return movies, nil
+}
+
+func (db Postgres) GetActors(
+	ctx context.Context,
+	name string,
+	page domain.Page,
+) ([]gormModels.Actor, error) {
+	query := db.DB.WithContext(ctx).Model(&gormModels.Actor{})
+	if name != "" {
+		query = query.Where("name LIKE ?", "%"+name+"%")
+	}
+
+	var actors []gormModels.Actor
+	if err := query.Order("name").
+		Order("id").
+		Offset(page.Offset).
+		Limit(page.Limit).
+		Find(&actors).
+		Error; err != nil {
+		return nil, err
+	}
+	return actors, nil
+}
+
+func (db Postgres) GetCasts(
+	ctx context.Context,
+	movieIDs []uint64,
+) ([]gormModels.ActorMovieRelation, []gormModels.Actor, error) {
+	relations, err := db.getRelations(ctx, "movie_id", movieIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	actorIDs := make([]uint64, len(relations))
+	for i, relation := range relations {
+		actorIDs[i] = relation.ActorID
+	}
+
+	var actors []gormModels.Actor
+	if err = db.DB.WithContext(ctx).Where("id IN ?", actorIDs).Find(&actors).Error; err != nil {
+		return nil, nil, err
+	}
+	return relations, actors, nil
+}
+
+func (db Postgres) GetFilmographies(
+	ctx context.Context,
+	actorIDs []uint64,
+) ([]gormModels.ActorMovieRelation, []gormModels.Movie, error) {
+	relations, err := db.getRelations(ctx, "actor_id", actorIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	movieIDs := make([]uint64, len(relations))
+	for i, relation := range relations {
+		movieIDs[i] = relation.MovieID
+	}
+
+	var movies []gormModels.Movie
+	if err = db.DB.WithContext(ctx).Where("id IN ?", movieIDs).Find(&movies).Error; err != nil {
+		return nil, nil, err
+	}
+	return relations, movies, nil
+}
+
+func (db Postgres) getRelations(
+	ctx context.Context,
+	column string,
+	ids []uint64,
+) ([]gormModels.ActorMovieRelation, error) {
+	var relations []gormModels.ActorMovieRelation
+	if err := db.DB.WithContext(ctx).
+		Where(column+" IN ?", ids).
+		Order("id").
+		Find(&relations).
+		Error; err != nil {
+		return nil, err
+	}
+	return relations, nil
+}
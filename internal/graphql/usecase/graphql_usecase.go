@@ -0,0 +1,100 @@
+package graphqlUsecase
+
+import (
+	"context"
+
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+)
+
+type GraphQLUsecase struct {
+	graphqlRepository domain.GraphQLRepository
+}
+
+func NewGraphQLUsecase(g domain.GraphQLRepository) GraphQLUsecase {
+	return GraphQLUsecase{
+		graphqlRepository: g,
+	}
+}
+
+func (u GraphQLUsecase) GetMovies(
+	ctx context.Context,
+	filter httpModels.MoviesFilter,
+	page domain.Page,
+) ([]httpModels.MovieResponse, error) {
+	movies, err := u.graphqlRepository.GetMovies(ctx, filter, page)
+	if err != nil {
+		return nil, err
+	}
+
+	httpMovies := make([]httpModels.MovieResponse, len(movies))
+	for i, movie := range movies {
+		httpMovies[i] = movie.ToHTTPResponse()
+	}
+	return httpMovies, nil
+}
+
+func (u GraphQLUsecase) GetActors(
+	ctx context.Context,
+	name string,
+	page domain.Page,
+) ([]httpModels.ActorResponse, error) {
+	actors, err := u.graphqlRepository.GetActors(ctx, name, page)
+	if err != nil {
+		return nil, err
+	}
+
+	httpActors := make([]httpModels.ActorResponse, len(actors))
+	for i, actor := range actors {
+		httpActors[i] = actor.ToHTTPModel()
+	}
+	return httpActors, nil
+}
+
+// GetCasts leaves out the relations whose actor is deleted.
+func (u GraphQLUsecase) GetCasts(
+	ctx context.Context,
+	movieIDs []uint64,
+) (map[uint64][]httpModels.ActorResponse, error) {
+	relations, actors, err := u.graphqlRepository.GetCasts(ctx, movieIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	actorsByID := make(map[uint64]httpModels.ActorResponse, len(actors))
+	for _, actor := range actors {
+		actorsByID[actor.ID] = actor.ToHTTPModel()
+	}
+
+	casts := make(map[uint64][]httpModels.ActorResponse, len(movieIDs))
+	for _, relation := range relations {
+		if actor, ok := actorsByID[relation.ActorID]; ok {
+			casts[relation.MovieID] = append(casts[relation.MovieID], actor)
+		}
+	}
+	return casts, nil
+}
+
+// GetFilmographies leaves out the relations whose movie is deleted.
+func (u GraphQLUsecase) GetFilmographies(
+	ctx context.Context,
+	actorIDs []uint64,
+) (map[uint64][]httpModels.MovieResponse, error) {
+	relations, movies, err := u.graphqlRepository.GetFilmographies(ctx, actorIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	moviesByID := make(map[uint64]httpModels.MovieResponse, len(movies))
+	for _, movie := range movies {
+		moviesByID[movie.ID] = movie.ToHTTPResponse()
+	}
+
+	filmographies := make(map[uint64][]httpModels.MovieResponse, len(actorIDs))
+	for _, relation := range relations {
+		if movie, ok := moviesByID[relation.MovieID]; ok {
+			filmographies[relation.ActorID] = append(filmographies[relation.ActorID], movie)
+		}
+	}
+	return filmographies, nil
+}
@@ -0,0 +1,106 @@
+package graphqlUsecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	mockDomain "github.com/themilchenko/vk-tech_internship-problem_2024/internal/mocks/domain"
+	gormModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/gorm"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUsecase_GetCasts(t *testing.T) {
+	type mockBehavior func(r *mockDomain.MockGraphQLRepository)
+
+	john := gormModels.Actor{ID: 2, Name: "John", BirthDate: time.Date(1980, 1, 2, 0, 0, 0, 0, time.UTC)}
+	jane := gormModels.Actor{ID: 3, Name: "Jane", BirthDate: time.Date(1990, 1, 2, 0, 0, 0, 0, time.UTC)}
+
+	tests := []struct {
+		name          string
+		movieIDs      []uint64
+		mockBehavior  mockBehavior
+		expectedCasts map[uint64][]httpModels.ActorResponse
+		expectedError error
+	}{
+		{
+			name:     "OK",
+			movieIDs: []uint64{1, 4, 5},
+			mockBehavior: func(r *mockDomain.MockGraphQLRepository) {
+				r.EXPECT().
+					GetCasts(gomock.Any(), []uint64{1, 4, 5}).
+					Return([]gormModels.ActorMovieRelation{
+						{MovieID: 1, ActorID: 3},
+						{MovieID: 4, ActorID: 2},
+						{MovieID: 1, ActorID: 2},
+						{MovieID: 5, ActorID: 6},
+					}, []gormModels.Actor{john, jane}, nil)
+			},
+			expectedCasts: map[uint64][]httpModels.ActorResponse{
+				1: {
+					{ID: 3, Name: "Jane", BirthDate: "1990-01-02"},
+					{ID: 2, Name: "John", BirthDate: "1980-01-02"},
+				},
+				4: {{ID: 2, Name: "John", BirthDate: "1980-01-02"}},
+			},
+		},
+		{
+			name:     "Failed",
+			movieIDs: []uint64{1},
+			mockBehavior: func(r *mockDomain.MockGraphQLRepository) {
+				r.EXPECT().GetCasts(gomock.Any(), []uint64{1}).Return(nil, nil, domain.ErrInternal)
+			},
+			expectedError: domain.ErrInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mockDomain.NewMockGraphQLRepository(ctrl)
+			tt.mockBehavior(mockRepo)
+
+			u := NewGraphQLUsecase(mockRepo)
+
+			casts, err := u.GetCasts(context.Background(), tt.movieIDs)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedCasts, casts)
+		})
+	}
+}
+
+func TestUsecase_GetFilmographies(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mockDomain.NewMockGraphQLRepository(ctrl)
+	mockRepo.EXPECT().
+		GetFilmographies(gomock.Any(), []uint64{2, 3}).
+		Return([]gormModels.ActorMovieRelation{
+			{MovieID: 1, ActorID: 2},
+			{MovieID: 7, ActorID: 3},
+		}, []gormModels.Movie{{
+			ID:          1,
+			Title:       "Title",
+			ReleaseDate: time.Date(2006, 1, 2, 0, 0, 0, 0, time.UTC),
+			Rating:      5,
+			Version:     2,
+		}}, nil)
+
+	u := NewGraphQLUsecase(mockRepo)
+
+	filmographies, err := u.GetFilmographies(context.Background(), []uint64{2, 3})
+	assert.NoError(t, err)
+	assert.Equal(t, map[uint64][]httpModels.MovieResponse{
+		2: {{ID: 1, Title: "Title", ReleaseDate: "2006-01-02", Rating: 5, Version: 2}},
+	}, filmographies)
+}
@@ -0,0 +1,57 @@
+package graphqlUsecase
+
+import (
+	"context"
+
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/tracing"
+)
+
+// TracedGraphQLUsecase makes a span of every call. Each level of a query
+// is one GetCasts or GetFilmographies span, however many movies it has.
+type TracedGraphQLUsecase struct {
+	graphqlUsecase domain.GraphQLUsecase
+}
+
+func NewTracedGraphQLUsecase(u domain.GraphQLUsecase) TracedGraphQLUsecase {
+	return TracedGraphQLUsecase{graphqlUsecase: u}
+}
+
+func (u TracedGraphQLUsecase) GetMovies(
+	ctx context.Context,
+	filter httpModels.MoviesFilter,
+	page domain.Page,
+) (_ []httpModels.MovieResponse, err error) {
+	ctx, span := tracing.Start(ctx, "GraphQLUsecase.GetMovies")
+	defer func() { tracing.End(span, err) }()
+	return u.graphqlUsecase.GetMovies(ctx, filter, page)
+}
+
+func (u TracedGraphQLUsecase) GetActors(
+	ctx context.Context,
+	name string,
+	page domain.Page,
+) (_ []httpModels.ActorResponse, err error) {
+	ctx, span := tracing.Start(ctx, "GraphQLUsecase.GetActors")
+	defer func() { tracing.End(span, err) }()
+	return u.graphqlUsecase.GetActors(ctx, name, page)
+}
+
+func (u TracedGraphQLUsecase) GetCasts(
+	ctx context.Context,
+	movieIDs []uint64,
+) (_ map[uint64][]httpModels.ActorResponse, err error) {
+	ctx, span := tracing.Start(ctx, "GraphQLUsecase.GetCasts")
+	defer func() { tracing.End(span, err) }()
+	return u.graphqlUsecase.GetCasts(ctx, movieIDs)
+}
+
+func (u TracedGraphQLUsecase) GetFilmographies(
+	ctx context.Context,
+	actorIDs []uint64,
+) (_ map[uint64][]httpModels.MovieResponse, err error) {
+	ctx, span := tracing.Start(ctx, "GraphQLUsecase.GetFilmographies")
+	defer func() { tracing.End(span, err) }()
+	return u.graphqlUsecase.GetFilmographies(ctx, actorIDs)
+}
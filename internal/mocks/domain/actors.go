@@ -10,8 +10,10 @@
 package mock_domain
 
 import (
+	context "context"
 	reflect "reflect"
 
+	domain "github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
 	gormModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/gorm"
 	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
 	gomock "go.uber.org/mock/gomock"
@@ -41,77 +43,92 @@ func (m *MockActorsUsecase) EXPECT() *MockActorsUsecaseMockRecorder {
 }
 
 // CreateActor mocks base method.
-func (m *MockActorsUsecase) CreateActor(actor httpModels.Actor) (uint64, error) {
+func (m *MockActorsUsecase) CreateActor(ctx context.Context, actor httpModels.Actor) (uint64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateActor", actor)
+	ret := m.ctrl.Call(m, "CreateActor", ctx, actor)
 	ret0, _ := ret[0].(uint64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateActor indicates an expected call of CreateActor.
-func (mr *MockActorsUsecaseMockRecorder) CreateActor(actor any) *gomock.Call {
+func (mr *MockActorsUsecaseMockRecorder) CreateActor(ctx, actor any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateActor", reflect.TypeOf((*MockActorsUsecase)(nil).CreateActor), actor)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateActor", reflect.TypeOf((*MockActorsUsecase)(nil).CreateActor), ctx, actor)
 }
 
 // DeleteActorByID mocks base method.
-func (m *MockActorsUsecase) DeleteActorByID(actorID uint64) error {
+func (m *MockActorsUsecase) DeleteActorByID(ctx context.Context, actorID, version uint64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteActorByID", actorID)
+	ret := m.ctrl.Call(m, "DeleteActorByID", ctx, actorID, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteActorByID indicates an expected call of DeleteActorByID.
-func (mr *MockActorsUsecaseMockRecorder) DeleteActorByID(actorID any) *gomock.Call {
+func (mr *MockActorsUsecaseMockRecorder) DeleteActorByID(ctx, actorID, version any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteActorByID", reflect.TypeOf((*MockActorsUsecase)(nil).DeleteActorByID), actorID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteActorByID", reflect.TypeOf((*MockActorsUsecase)(nil).DeleteActorByID), ctx, actorID, version)
 }
 
 // GetActorByID mocks base method.
-func (m *MockActorsUsecase) GetActorByID(actorID uint64) (httpModels.ActorResponse, error) {
+func (m *MockActorsUsecase) GetActorByID(ctx context.Context, actorID uint64) (httpModels.ActorResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetActorByID", actorID)
+	ret := m.ctrl.Call(m, "GetActorByID", ctx, actorID)
 	ret0, _ := ret[0].(httpModels.ActorResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetActorByID indicates an expected call of GetActorByID.
-func (mr *MockActorsUsecaseMockRecorder) GetActorByID(actorID any) *gomock.Call {
+func (mr *MockActorsUsecaseMockRecorder) GetActorByID(ctx, actorID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActorByID", reflect.TypeOf((*MockActorsUsecase)(nil).GetActorByID), actorID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActorByID", reflect.TypeOf((*MockActorsUsecase)(nil).GetActorByID), ctx, actorID)
 }
 
 // GetActors mocks base method.
-func (m *MockActorsUsecase) GetActors(pageNum uint64) ([]httpModels.GetActorsResponse, error) {
+func (m *MockActorsUsecase) GetActors(ctx context.Context, pageNum uint64) ([]httpModels.GetActorsResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetActors", pageNum)
+	ret := m.ctrl.Call(m, "GetActors", ctx, pageNum)
 	ret0, _ := ret[0].([]httpModels.GetActorsResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetActors indicates an expected call of GetActors.
-func (mr *MockActorsUsecaseMockRecorder) GetActors(pageNum any) *gomock.Call {
+func (mr *MockActorsUsecaseMockRecorder) GetActors(ctx, pageNum any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActors", reflect.TypeOf((*MockActorsUsecase)(nil).GetActors), pageNum)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActors", reflect.TypeOf((*MockActorsUsecase)(nil).GetActors), ctx, pageNum)
+}
+
+// PatchActor mocks base method.
+func (m *MockActorsUsecase) PatchActor(ctx context.Context, actorID, version uint64, patch domain.Patch) (httpModels.ActorResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchActor", ctx, actorID, version, patch)
+	ret0, _ := ret[0].(httpModels.ActorResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchActor indicates an expected call of PatchActor.
+func (mr *MockActorsUsecaseMockRecorder) PatchActor(ctx, actorID, version, patch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchActor", reflect.TypeOf((*MockActorsUsecase)(nil).PatchActor), ctx, actorID, version, patch)
 }
 
 // UpdateActor mocks base method.
-func (m *MockActorsUsecase) UpdateActor(actor httpModels.Actor, actorID uint64) (httpModels.ActorResponse, error) {
+func (m *MockActorsUsecase) UpdateActor(ctx context.Context, actor httpModels.Actor, actorID, version uint64) (httpModels.ActorResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateActor", actor, actorID)
+	ret := m.ctrl.Call(m, "UpdateActor", ctx, actor, actorID, version)
 	ret0, _ := ret[0].(httpModels.ActorResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateActor indicates an expected call of UpdateActor.
-func (mr *MockActorsUsecaseMockRecorder) UpdateActor(actor, actorID any) *gomock.Call {
+func (mr *MockActorsUsecaseMockRecorder) UpdateActor(ctx, actor, actorID, version any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateActor", reflect.TypeOf((*MockActorsUsecase)(nil).UpdateActor), actor, actorID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateActor", reflect.TypeOf((*MockActorsUsecase)(nil).UpdateActor), ctx, actor, actorID, version)
 }
 
 // MockActorsRepository is a mock of ActorsRepository interface.
@@ -138,90 +155,120 @@ func (m *MockActorsRepository) EXPECT() *MockActorsRepositoryMockRecorder {
 }
 
 // CreateActor mocks base method.
-func (m *MockActorsRepository) CreateActor(actor gormModels.Actor) (uint64, error) {
+func (m *MockActorsRepository) CreateActor(ctx context.Context, actor gormModels.Actor) (uint64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateActor", actor)
+	ret := m.ctrl.Call(m, "CreateActor", ctx, actor)
 	ret0, _ := ret[0].(uint64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateActor indicates an expected call of CreateActor.
-func (mr *MockActorsRepositoryMockRecorder) CreateActor(actor any) *gomock.Call {
+func (mr *MockActorsRepositoryMockRecorder) CreateActor(ctx, actor any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateActor", reflect.TypeOf((*MockActorsRepository)(nil).CreateActor), actor)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateActor", reflect.TypeOf((*MockActorsRepository)(nil).CreateActor), ctx, actor)
 }
 
 // DeleteActorByID mocks base method.
-func (m *MockActorsRepository) DeleteActorByID(actorID uint64) error {
+func (m *MockActorsRepository) DeleteActorByID(ctx context.Context, actorID, version uint64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteActorByID", actorID)
+	ret := m.ctrl.Call(m, "DeleteActorByID", ctx, actorID, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteActorByID indicates an expected call of DeleteActorByID.
-func (mr *MockActorsRepositoryMockRecorder) DeleteActorByID(actorID any) *gomock.Call {
+func (mr *MockActorsRepositoryMockRecorder) DeleteActorByID(ctx, actorID, version any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteActorByID", reflect.TypeOf((*MockActorsRepository)(nil).DeleteActorByID), actorID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteActorByID", reflect.TypeOf((*MockActorsRepository)(nil).DeleteActorByID), ctx, actorID, version)
 }
 
 // GetActorByID mocks base method.
-func (m *MockActorsRepository) GetActorByID(actorID uint64) (gormModels.Actor, error) {
+func (m *MockActorsRepository) GetActorByID(ctx context.Context, actorID uint64) (gormModels.Actor, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetActorByID", actorID)
+	ret := m.ctrl.Call(m, "GetActorByID", ctx, actorID)
 	ret0, _ := ret[0].(gormModels.Actor)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetActorByID indicates an expected call of GetActorByID.
-func (mr *MockActorsRepositoryMockRecorder) GetActorByID(actorID any) *gomock.Call {
+func (mr *MockActorsRepositoryMockRecorder) GetActorByID(ctx, actorID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActorByID", reflect.TypeOf((*MockActorsRepository)(nil).GetActorByID), actorID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActorByID", reflect.TypeOf((*MockActorsRepository)(nil).GetActorByID), ctx, actorID)
 }
 
 // GetActors mocks base method.
-func (m *MockActorsRepository) GetActors(pageNum uint64) ([]gormModels.Actor, error) {
+func (m *MockActorsRepository) GetActors(ctx context.Context, pageNum uint64) ([]gormModels.Actor, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetActors", pageNum)
+	ret := m.ctrl.Call(m, "GetActors", ctx, pageNum)
 	ret0, _ := ret[0].([]gormModels.Actor)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetActors indicates an expected call of GetActors.
-func (mr *MockActorsRepositoryMockRecorder) GetActors(pageNum any) *gomock.Call {
+func (mr *MockActorsRepositoryMockRecorder) GetActors(ctx, pageNum any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActors", reflect.TypeOf((*MockActorsRepository)(nil).GetActors), pageNum)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActors", reflect.TypeOf((*MockActorsRepository)(nil).GetActors), ctx, pageNum)
+}
+
+// GetActorsByIDs mocks base method.
+func (m *MockActorsRepository) GetActorsByIDs(ctx context.Context, actorIDs []uint64) ([]gormModels.Actor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActorsByIDs", ctx, actorIDs)
+	ret0, _ := ret[0].([]gormModels.Actor)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActorsByIDs indicates an expected call of GetActorsByIDs.
+func (mr *MockActorsRepositoryMockRecorder) GetActorsByIDs(ctx, actorIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActorsByIDs", reflect.TypeOf((*MockActorsRepository)(nil).GetActorsByIDs), ctx, actorIDs)
 }
 
 // GetActorsFromMovie mocks base method.
-func (m *MockActorsRepository) GetActorsFromMovie(movieID uint64) ([]gormModels.Actor, error) {
+func (m *MockActorsRepository) GetActorsFromMovie(ctx context.Context, movieID uint64) ([]gormModels.Actor, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetActorsFromMovie", movieID)
+	ret := m.ctrl.Call(m, "GetActorsFromMovie", ctx, movieID)
 	ret0, _ := ret[0].([]gormModels.Actor)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetActorsFromMovie indicates an expected call of GetActorsFromMovie.
-func (mr *MockActorsRepositoryMockRecorder) GetActorsFromMovie(movieID any) *gomock.Call {
+func (mr *MockActorsRepositoryMockRecorder) GetActorsFromMovie(ctx, movieID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActorsFromMovie", reflect.TypeOf((*MockActorsRepository)(nil).GetActorsFromMovie), ctx, movieID)
+}
+
+// PatchActor mocks base method.
+func (m *MockActorsRepository) PatchActor(ctx context.Context, actorID, version uint64, patch func(*gormModels.Actor) error) (gormModels.Actor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchActor", ctx, actorID, version, patch)
+	ret0, _ := ret[0].(gormModels.Actor)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchActor indicates an expected call of PatchActor.
+func (mr *MockActorsRepositoryMockRecorder) PatchActor(ctx, actorID, version, patch any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActorsFromMovie", reflect.TypeOf((*MockActorsRepository)(nil).GetActorsFromMovie), movieID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchActor", reflect.TypeOf((*MockActorsRepository)(nil).PatchActor), ctx, actorID, version, patch)
 }
 
 // UpdateActor mocks base method.
-func (m *MockActorsRepository) UpdateActor(actor gormModels.Actor) (gormModels.Actor, error) {
+func (m *MockActorsRepository) UpdateActor(ctx context.Context, actor gormModels.Actor) (gormModels.Actor, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateActor", actor)
+	ret := m.ctrl.Call(m, "UpdateActor", ctx, actor)
 	ret0, _ := ret[0].(gormModels.Actor)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateActor indicates an expected call of UpdateActor.
-func (mr *MockActorsRepositoryMockRecorder) UpdateActor(actor any) *gomock.Call {
+func (mr *MockActorsRepositoryMockRecorder) UpdateActor(ctx, actor any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateActor", reflect.TypeOf((*MockActorsRepository)(nil).UpdateActor), actor)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateActor", reflect.TypeOf((*MockActorsRepository)(nil).UpdateActor), ctx, actor)
 }
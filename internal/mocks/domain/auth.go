@@ -10,8 +10,11 @@
 package mock_domain
 
 import (
+	context "context"
 	reflect "reflect"
+	time "time"
 
+	domain "github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
 	gormModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/gorm"
 	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
 	gomock "go.uber.org/mock/gomock"
@@ -40,40 +43,112 @@ func (m *MockAuthUsecase) EXPECT() *MockAuthUsecaseMockRecorder {
 	return m.recorder
 }
 
+// ActivateTOTP mocks base method.
+func (m *MockAuthUsecase) ActivateTOTP(ctx context.Context, sessionID, code string) (httpModels.RecoveryCodes, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ActivateTOTP", ctx, sessionID, code)
+	ret0, _ := ret[0].(httpModels.RecoveryCodes)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ActivateTOTP indicates an expected call of ActivateTOTP.
+func (mr *MockAuthUsecaseMockRecorder) ActivateTOTP(ctx, sessionID, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ActivateTOTP", reflect.TypeOf((*MockAuthUsecase)(nil).ActivateTOTP), ctx, sessionID, code)
+}
+
 // Auth mocks base method.
-func (m *MockAuthUsecase) Auth(sessionID string) (uint64, error) {
+func (m *MockAuthUsecase) Auth(ctx context.Context, sessionID string) (uint64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Auth", sessionID)
+	ret := m.ctrl.Call(m, "Auth", ctx, sessionID)
 	ret0, _ := ret[0].(uint64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Auth indicates an expected call of Auth.
-func (mr *MockAuthUsecaseMockRecorder) Auth(sessionID any) *gomock.Call {
+func (mr *MockAuthUsecaseMockRecorder) Auth(ctx, sessionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Auth", reflect.TypeOf((*MockAuthUsecase)(nil).Auth), ctx, sessionID)
+}
+
+// ChangePassword mocks base method.
+func (m *MockAuthUsecase) ChangePassword(ctx context.Context, sessionID string, passwords httpModels.ChangePassword) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePassword", ctx, sessionID, passwords)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangePassword indicates an expected call of ChangePassword.
+func (mr *MockAuthUsecaseMockRecorder) ChangePassword(ctx, sessionID, passwords any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Auth", reflect.TypeOf((*MockAuthUsecase)(nil).Auth), sessionID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockAuthUsecase)(nil).ChangePassword), ctx, sessionID, passwords)
+}
+
+// ConfirmPasswordReset mocks base method.
+func (m *MockAuthUsecase) ConfirmPasswordReset(ctx context.Context, confirm httpModels.PasswordResetConfirm) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmPasswordReset", ctx, confirm)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConfirmPasswordReset indicates an expected call of ConfirmPasswordReset.
+func (mr *MockAuthUsecaseMockRecorder) ConfirmPasswordReset(ctx, confirm any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmPasswordReset", reflect.TypeOf((*MockAuthUsecase)(nil).ConfirmPasswordReset), ctx, confirm)
+}
+
+// DisableTOTP mocks base method.
+func (m *MockAuthUsecase) DisableTOTP(ctx context.Context, sessionID, code string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableTOTP", ctx, sessionID, code)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableTOTP indicates an expected call of DisableTOTP.
+func (mr *MockAuthUsecaseMockRecorder) DisableTOTP(ctx, sessionID, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableTOTP", reflect.TypeOf((*MockAuthUsecase)(nil).DisableTOTP), ctx, sessionID, code)
+}
+
+// EnrollTOTP mocks base method.
+func (m *MockAuthUsecase) EnrollTOTP(ctx context.Context, sessionID string) (httpModels.TOTPEnrollment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnrollTOTP", ctx, sessionID)
+	ret0, _ := ret[0].(httpModels.TOTPEnrollment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnrollTOTP indicates an expected call of EnrollTOTP.
+func (mr *MockAuthUsecaseMockRecorder) EnrollTOTP(ctx, sessionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnrollTOTP", reflect.TypeOf((*MockAuthUsecase)(nil).EnrollTOTP), ctx, sessionID)
 }
 
 // GetUserBySessionID mocks base method.
-func (m *MockAuthUsecase) GetUserBySessionID(sessionID string) (httpModels.AuthUser, error) {
+func (m *MockAuthUsecase) GetUserBySessionID(ctx context.Context, sessionID string) (httpModels.AuthUser, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserBySessionID", sessionID)
+	ret := m.ctrl.Call(m, "GetUserBySessionID", ctx, sessionID)
 	ret0, _ := ret[0].(httpModels.AuthUser)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetUserBySessionID indicates an expected call of GetUserBySessionID.
-func (mr *MockAuthUsecaseMockRecorder) GetUserBySessionID(sessionID any) *gomock.Call {
+func (mr *MockAuthUsecaseMockRecorder) GetUserBySessionID(ctx, sessionID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserBySessionID", reflect.TypeOf((*MockAuthUsecase)(nil).GetUserBySessionID), sessionID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserBySessionID", reflect.TypeOf((*MockAuthUsecase)(nil).GetUserBySessionID), ctx, sessionID)
 }
 
 // Login mocks base method.
-func (m *MockAuthUsecase) Login(user httpModels.AuthUser) (string, uint64, error) {
+func (m *MockAuthUsecase) Login(ctx context.Context, user httpModels.AuthUser, ip string) (string, uint64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Login", user)
+	ret := m.ctrl.Call(m, "Login", ctx, user, ip)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(uint64)
 	ret2, _ := ret[2].(error)
@@ -81,29 +156,90 @@ func (m *MockAuthUsecase) Login(user httpModels.AuthUser) (string, uint64, error
 }
 
 // Login indicates an expected call of Login.
-func (mr *MockAuthUsecaseMockRecorder) Login(user any) *gomock.Call {
+func (mr *MockAuthUsecaseMockRecorder) Login(ctx, user, ip any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockAuthUsecase)(nil).Login), user)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockAuthUsecase)(nil).Login), ctx, user, ip)
 }
 
 // Logout mocks base method.
-func (m *MockAuthUsecase) Logout(sessionID string) error {
+func (m *MockAuthUsecase) Logout(ctx context.Context, sessionID string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Logout", sessionID)
+	ret := m.ctrl.Call(m, "Logout", ctx, sessionID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Logout indicates an expected call of Logout.
-func (mr *MockAuthUsecaseMockRecorder) Logout(sessionID any) *gomock.Call {
+func (mr *MockAuthUsecaseMockRecorder) Logout(ctx, sessionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockAuthUsecase)(nil).Logout), ctx, sessionID)
+}
+
+// OIDCCallback mocks base method.
+func (m *MockAuthUsecase) OIDCCallback(ctx context.Context, provider, code, state string) (string, uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OIDCCallback", ctx, provider, code, state)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(uint64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// OIDCCallback indicates an expected call of OIDCCallback.
+func (mr *MockAuthUsecaseMockRecorder) OIDCCallback(ctx, provider, code, state any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OIDCCallback", reflect.TypeOf((*MockAuthUsecase)(nil).OIDCCallback), ctx, provider, code, state)
+}
+
+// OIDCLoginURL mocks base method.
+func (m *MockAuthUsecase) OIDCLoginURL(ctx context.Context, provider, linkSessionID string) (string, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OIDCLoginURL", ctx, provider, linkSessionID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// OIDCLoginURL indicates an expected call of OIDCLoginURL.
+func (mr *MockAuthUsecaseMockRecorder) OIDCLoginURL(ctx, provider, linkSessionID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockAuthUsecase)(nil).Logout), sessionID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OIDCLoginURL", reflect.TypeOf((*MockAuthUsecase)(nil).OIDCLoginURL), ctx, provider, linkSessionID)
+}
+
+// RegenerateRecoveryCodes mocks base method.
+func (m *MockAuthUsecase) RegenerateRecoveryCodes(ctx context.Context, sessionID, code string) (httpModels.RecoveryCodes, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegenerateRecoveryCodes", ctx, sessionID, code)
+	ret0, _ := ret[0].(httpModels.RecoveryCodes)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegenerateRecoveryCodes indicates an expected call of RegenerateRecoveryCodes.
+func (mr *MockAuthUsecaseMockRecorder) RegenerateRecoveryCodes(ctx, sessionID, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegenerateRecoveryCodes", reflect.TypeOf((*MockAuthUsecase)(nil).RegenerateRecoveryCodes), ctx, sessionID, code)
+}
+
+// RequestPasswordReset mocks base method.
+func (m *MockAuthUsecase) RequestPasswordReset(ctx context.Context, username string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestPasswordReset", ctx, username)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequestPasswordReset indicates an expected call of RequestPasswordReset.
+func (mr *MockAuthUsecaseMockRecorder) RequestPasswordReset(ctx, username any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestPasswordReset", reflect.TypeOf((*MockAuthUsecase)(nil).RequestPasswordReset), ctx, username)
 }
 
 // SignUp mocks base method.
-func (m *MockAuthUsecase) SignUp(user httpModels.AuthUser) (string, uint64, error) {
+func (m *MockAuthUsecase) SignUp(ctx context.Context, user httpModels.AuthUser) (string, uint64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SignUp", user)
+	ret := m.ctrl.Call(m, "SignUp", ctx, user)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(uint64)
 	ret2, _ := ret[2].(error)
@@ -111,9 +247,39 @@ func (m *MockAuthUsecase) SignUp(user httpModels.AuthUser) (string, uint64, erro
 }
 
 // SignUp indicates an expected call of SignUp.
-func (mr *MockAuthUsecaseMockRecorder) SignUp(user any) *gomock.Call {
+func (mr *MockAuthUsecaseMockRecorder) SignUp(ctx, user any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignUp", reflect.TypeOf((*MockAuthUsecase)(nil).SignUp), user)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignUp", reflect.TypeOf((*MockAuthUsecase)(nil).SignUp), ctx, user)
+}
+
+// UnlockUser mocks base method.
+func (m *MockAuthUsecase) UnlockUser(ctx context.Context, username string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnlockUser", ctx, username)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnlockUser indicates an expected call of UnlockUser.
+func (mr *MockAuthUsecaseMockRecorder) UnlockUser(ctx, username any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlockUser", reflect.TypeOf((*MockAuthUsecase)(nil).UnlockUser), ctx, username)
+}
+
+// VerifyTwoFactor mocks base method.
+func (m *MockAuthUsecase) VerifyTwoFactor(ctx context.Context, login httpModels.TwoFactorLogin) (string, uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyTwoFactor", ctx, login)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(uint64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// VerifyTwoFactor indicates an expected call of VerifyTwoFactor.
+func (mr *MockAuthUsecaseMockRecorder) VerifyTwoFactor(ctx, login any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyTwoFactor", reflect.TypeOf((*MockAuthUsecase)(nil).VerifyTwoFactor), ctx, login)
 }
 
 // MockAuthRepository is a mock of AuthRepository interface.
@@ -139,76 +305,523 @@ func (m *MockAuthRepository) EXPECT() *MockAuthRepositoryMockRecorder {
 	return m.recorder
 }
 
+// BlockLogin mocks base method.
+func (m *MockAuthRepository) BlockLogin(ctx context.Context, key string, until time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockLogin", ctx, key, until)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BlockLogin indicates an expected call of BlockLogin.
+func (mr *MockAuthRepositoryMockRecorder) BlockLogin(ctx, key, until any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockLogin", reflect.TypeOf((*MockAuthRepository)(nil).BlockLogin), ctx, key, until)
+}
+
+// ConsumeOIDCState mocks base method.
+func (m *MockAuthRepository) ConsumeOIDCState(ctx context.Context, stateHash string) (gormModels.OIDCState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeOIDCState", ctx, stateHash)
+	ret0, _ := ret[0].(gormModels.OIDCState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeOIDCState indicates an expected call of ConsumeOIDCState.
+func (mr *MockAuthRepositoryMockRecorder) ConsumeOIDCState(ctx, stateHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeOIDCState", reflect.TypeOf((*MockAuthRepository)(nil).ConsumeOIDCState), ctx, stateHash)
+}
+
+// CountActiveSessions mocks base method.
+func (m *MockAuthRepository) CountActiveSessions(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountActiveSessions", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountActiveSessions indicates an expected call of CountActiveSessions.
+func (mr *MockAuthRepositoryMockRecorder) CountActiveSessions(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountActiveSessions", reflect.TypeOf((*MockAuthRepository)(nil).CountActiveSessions), ctx)
+}
+
+// CreateExternalIdentity mocks base method.
+func (m *MockAuthRepository) CreateExternalIdentity(ctx context.Context, identity gormModels.ExternalIdentity) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateExternalIdentity", ctx, identity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateExternalIdentity indicates an expected call of CreateExternalIdentity.
+func (mr *MockAuthRepositoryMockRecorder) CreateExternalIdentity(ctx, identity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateExternalIdentity", reflect.TypeOf((*MockAuthRepository)(nil).CreateExternalIdentity), ctx, identity)
+}
+
+// CreateOIDCState mocks base method.
+func (m *MockAuthRepository) CreateOIDCState(ctx context.Context, state gormModels.OIDCState) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOIDCState", ctx, state)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateOIDCState indicates an expected call of CreateOIDCState.
+func (mr *MockAuthRepositoryMockRecorder) CreateOIDCState(ctx, state any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOIDCState", reflect.TypeOf((*MockAuthRepository)(nil).CreateOIDCState), ctx, state)
+}
+
+// CreateResetToken mocks base method.
+func (m *MockAuthRepository) CreateResetToken(ctx context.Context, token gormModels.PasswordResetToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateResetToken", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateResetToken indicates an expected call of CreateResetToken.
+func (mr *MockAuthRepositoryMockRecorder) CreateResetToken(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateResetToken", reflect.TypeOf((*MockAuthRepository)(nil).CreateResetToken), ctx, token)
+}
+
 // CreateSession mocks base method.
-func (m *MockAuthRepository) CreateSession(session gormModels.Session) (string, error) {
+func (m *MockAuthRepository) CreateSession(ctx context.Context, session gormModels.Session) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateSession", session)
+	ret := m.ctrl.Call(m, "CreateSession", ctx, session)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateSession indicates an expected call of CreateSession.
-func (mr *MockAuthRepositoryMockRecorder) CreateSession(session any) *gomock.Call {
+func (mr *MockAuthRepositoryMockRecorder) CreateSession(ctx, session any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockAuthRepository)(nil).CreateSession), ctx, session)
+}
+
+// CreateTwoFactorChallenge mocks base method.
+func (m *MockAuthRepository) CreateTwoFactorChallenge(ctx context.Context, challenge gormModels.TwoFactorChallenge) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTwoFactorChallenge", ctx, challenge)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateTwoFactorChallenge indicates an expected call of CreateTwoFactorChallenge.
+func (mr *MockAuthRepositoryMockRecorder) CreateTwoFactorChallenge(ctx, challenge any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockAuthRepository)(nil).CreateSession), session)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTwoFactorChallenge", reflect.TypeOf((*MockAuthRepository)(nil).CreateTwoFactorChallenge), ctx, challenge)
 }
 
 // CreateUser mocks base method.
-func (m *MockAuthRepository) CreateUser(user gormModels.User) (uint64, error) {
+func (m *MockAuthRepository) CreateUser(ctx context.Context, user gormModels.User) (uint64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateUser", user)
+	ret := m.ctrl.Call(m, "CreateUser", ctx, user)
 	ret0, _ := ret[0].(uint64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateUser indicates an expected call of CreateUser.
-func (mr *MockAuthRepositoryMockRecorder) CreateUser(user any) *gomock.Call {
+func (mr *MockAuthRepositoryMockRecorder) CreateUser(ctx, user any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockAuthRepository)(nil).CreateUser), user)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockAuthRepository)(nil).CreateUser), ctx, user)
 }
 
 // DeleteBySessionID mocks base method.
-func (m *MockAuthRepository) DeleteBySessionID(sessionID string) error {
+func (m *MockAuthRepository) DeleteBySessionID(ctx context.Context, sessionID string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteBySessionID", sessionID)
+	ret := m.ctrl.Call(m, "DeleteBySessionID", ctx, sessionID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteBySessionID indicates an expected call of DeleteBySessionID.
-func (mr *MockAuthRepositoryMockRecorder) DeleteBySessionID(sessionID any) *gomock.Call {
+func (mr *MockAuthRepositoryMockRecorder) DeleteBySessionID(ctx, sessionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBySessionID", reflect.TypeOf((*MockAuthRepository)(nil).DeleteBySessionID), ctx, sessionID)
+}
+
+// DeleteTwoFactorChallenge mocks base method.
+func (m *MockAuthRepository) DeleteTwoFactorChallenge(ctx context.Context, challengeID uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTwoFactorChallenge", ctx, challengeID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTwoFactorChallenge indicates an expected call of DeleteTwoFactorChallenge.
+func (mr *MockAuthRepositoryMockRecorder) DeleteTwoFactorChallenge(ctx, challengeID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTwoFactorChallenge", reflect.TypeOf((*MockAuthRepository)(nil).DeleteTwoFactorChallenge), ctx, challengeID)
+}
+
+// DeleteUserSessions mocks base method.
+func (m *MockAuthRepository) DeleteUserSessions(ctx context.Context, userID uint64, exceptSessionID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserSessions", ctx, userID, exceptSessionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserSessions indicates an expected call of DeleteUserSessions.
+func (mr *MockAuthRepositoryMockRecorder) DeleteUserSessions(ctx, userID, exceptSessionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserSessions", reflect.TypeOf((*MockAuthRepository)(nil).DeleteUserSessions), ctx, userID, exceptSessionID)
+}
+
+// GetExternalIdentity mocks base method.
+func (m *MockAuthRepository) GetExternalIdentity(ctx context.Context, provider, subject string) (gormModels.ExternalIdentity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExternalIdentity", ctx, provider, subject)
+	ret0, _ := ret[0].(gormModels.ExternalIdentity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExternalIdentity indicates an expected call of GetExternalIdentity.
+func (mr *MockAuthRepositoryMockRecorder) GetExternalIdentity(ctx, provider, subject any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExternalIdentity", reflect.TypeOf((*MockAuthRepository)(nil).GetExternalIdentity), ctx, provider, subject)
+}
+
+// GetLoginAttempts mocks base method.
+func (m *MockAuthRepository) GetLoginAttempts(ctx context.Context, keys []string) ([]gormModels.LoginAttempt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLoginAttempts", ctx, keys)
+	ret0, _ := ret[0].([]gormModels.LoginAttempt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLoginAttempts indicates an expected call of GetLoginAttempts.
+func (mr *MockAuthRepositoryMockRecorder) GetLoginAttempts(ctx, keys any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoginAttempts", reflect.TypeOf((*MockAuthRepository)(nil).GetLoginAttempts), ctx, keys)
+}
+
+// GetResetToken mocks base method.
+func (m *MockAuthRepository) GetResetToken(ctx context.Context, tokenHash string) (gormModels.PasswordResetToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetResetToken", ctx, tokenHash)
+	ret0, _ := ret[0].(gormModels.PasswordResetToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetResetToken indicates an expected call of GetResetToken.
+func (mr *MockAuthRepositoryMockRecorder) GetResetToken(ctx, tokenHash any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBySessionID", reflect.TypeOf((*MockAuthRepository)(nil).DeleteBySessionID), sessionID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetResetToken", reflect.TypeOf((*MockAuthRepository)(nil).GetResetToken), ctx, tokenHash)
+}
+
+// GetTwoFactorChallenge mocks base method.
+func (m *MockAuthRepository) GetTwoFactorChallenge(ctx context.Context, tokenHash string) (gormModels.TwoFactorChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTwoFactorChallenge", ctx, tokenHash)
+	ret0, _ := ret[0].(gormModels.TwoFactorChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTwoFactorChallenge indicates an expected call of GetTwoFactorChallenge.
+func (mr *MockAuthRepositoryMockRecorder) GetTwoFactorChallenge(ctx, tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTwoFactorChallenge", reflect.TypeOf((*MockAuthRepository)(nil).GetTwoFactorChallenge), ctx, tokenHash)
+}
+
+// GetUserByID mocks base method.
+func (m *MockAuthRepository) GetUserByID(ctx context.Context, userID uint64) (gormModels.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByID", ctx, userID)
+	ret0, _ := ret[0].(gormModels.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByID indicates an expected call of GetUserByID.
+func (mr *MockAuthRepositoryMockRecorder) GetUserByID(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockAuthRepository)(nil).GetUserByID), ctx, userID)
 }
 
 // GetUserBySessionID mocks base method.
-func (m *MockAuthRepository) GetUserBySessionID(sessionID string) (gormModels.User, error) {
+func (m *MockAuthRepository) GetUserBySessionID(ctx context.Context, sessionID string) (gormModels.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserBySessionID", sessionID)
+	ret := m.ctrl.Call(m, "GetUserBySessionID", ctx, sessionID)
 	ret0, _ := ret[0].(gormModels.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetUserBySessionID indicates an expected call of GetUserBySessionID.
-func (mr *MockAuthRepositoryMockRecorder) GetUserBySessionID(sessionID any) *gomock.Call {
+func (mr *MockAuthRepositoryMockRecorder) GetUserBySessionID(ctx, sessionID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserBySessionID", reflect.TypeOf((*MockAuthRepository)(nil).GetUserBySessionID), sessionID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserBySessionID", reflect.TypeOf((*MockAuthRepository)(nil).GetUserBySessionID), ctx, sessionID)
 }
 
 // GetUserByUsername mocks base method.
-func (m *MockAuthRepository) GetUserByUsername(username string) (gormModels.User, error) {
+func (m *MockAuthRepository) GetUserByUsername(ctx context.Context, username string) (gormModels.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserByUsername", username)
+	ret := m.ctrl.Call(m, "GetUserByUsername", ctx, username)
 	ret0, _ := ret[0].(gormModels.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetUserByUsername indicates an expected call of GetUserByUsername.
-func (mr *MockAuthRepositoryMockRecorder) GetUserByUsername(username any) *gomock.Call {
+func (mr *MockAuthRepositoryMockRecorder) GetUserByUsername(ctx, username any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByUsername", reflect.TypeOf((*MockAuthRepository)(nil).GetUserByUsername), ctx, username)
+}
+
+// RegisterLoginFailure mocks base method.
+func (m *MockAuthRepository) RegisterLoginFailure(ctx context.Context, key string, staleBefore time.Time) (gormModels.LoginAttempt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterLoginFailure", ctx, key, staleBefore)
+	ret0, _ := ret[0].(gormModels.LoginAttempt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegisterLoginFailure indicates an expected call of RegisterLoginFailure.
+func (mr *MockAuthRepositoryMockRecorder) RegisterLoginFailure(ctx, key, staleBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterLoginFailure", reflect.TypeOf((*MockAuthRepository)(nil).RegisterLoginFailure), ctx, key, staleBefore)
+}
+
+// ReplaceRecoveryCodes mocks base method.
+func (m *MockAuthRepository) ReplaceRecoveryCodes(ctx context.Context, userID uint64, codes []gormModels.RecoveryCode) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceRecoveryCodes", ctx, userID, codes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceRecoveryCodes indicates an expected call of ReplaceRecoveryCodes.
+func (mr *MockAuthRepositoryMockRecorder) ReplaceRecoveryCodes(ctx, userID, codes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceRecoveryCodes", reflect.TypeOf((*MockAuthRepository)(nil).ReplaceRecoveryCodes), ctx, userID, codes)
+}
+
+// ResetLoginFailures mocks base method.
+func (m *MockAuthRepository) ResetLoginFailures(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetLoginFailures", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetLoginFailures indicates an expected call of ResetLoginFailures.
+func (mr *MockAuthRepositoryMockRecorder) ResetLoginFailures(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetLoginFailures", reflect.TypeOf((*MockAuthRepository)(nil).ResetLoginFailures), ctx, key)
+}
+
+// ResetPassword mocks base method.
+func (m *MockAuthRepository) ResetPassword(ctx context.Context, tokenID, userID uint64, hash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPassword", ctx, tokenID, userID, hash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *MockAuthRepositoryMockRecorder) ResetPassword(ctx, tokenID, userID, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockAuthRepository)(nil).ResetPassword), ctx, tokenID, userID, hash)
+}
+
+// UpdatePassword mocks base method.
+func (m *MockAuthRepository) UpdatePassword(ctx context.Context, userID uint64, hash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePassword", ctx, userID, hash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePassword indicates an expected call of UpdatePassword.
+func (mr *MockAuthRepositoryMockRecorder) UpdatePassword(ctx, userID, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePassword", reflect.TypeOf((*MockAuthRepository)(nil).UpdatePassword), ctx, userID, hash)
+}
+
+// UpdateRole mocks base method.
+func (m *MockAuthRepository) UpdateRole(ctx context.Context, userID uint64, role string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRole", ctx, userID, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRole indicates an expected call of UpdateRole.
+func (mr *MockAuthRepositoryMockRecorder) UpdateRole(ctx, userID, role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRole", reflect.TypeOf((*MockAuthRepository)(nil).UpdateRole), ctx, userID, role)
+}
+
+// UpdateTOTP mocks base method.
+func (m *MockAuthRepository) UpdateTOTP(ctx context.Context, userID uint64, secret string, enabled bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTOTP", ctx, userID, secret, enabled)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTOTP indicates an expected call of UpdateTOTP.
+func (mr *MockAuthRepositoryMockRecorder) UpdateTOTP(ctx, userID, secret, enabled any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTOTP", reflect.TypeOf((*MockAuthRepository)(nil).UpdateTOTP), ctx, userID, secret, enabled)
+}
+
+// UseChallengeAttempt mocks base method.
+func (m *MockAuthRepository) UseChallengeAttempt(ctx context.Context, challengeID, maxAttempts uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseChallengeAttempt", ctx, challengeID, maxAttempts)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UseChallengeAttempt indicates an expected call of UseChallengeAttempt.
+func (mr *MockAuthRepositoryMockRecorder) UseChallengeAttempt(ctx, challengeID, maxAttempts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseChallengeAttempt", reflect.TypeOf((*MockAuthRepository)(nil).UseChallengeAttempt), ctx, challengeID, maxAttempts)
+}
+
+// UseRecoveryCode mocks base method.
+func (m *MockAuthRepository) UseRecoveryCode(ctx context.Context, userID uint64, codeHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseRecoveryCode", ctx, userID, codeHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UseRecoveryCode indicates an expected call of UseRecoveryCode.
+func (mr *MockAuthRepositoryMockRecorder) UseRecoveryCode(ctx, userID, codeHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseRecoveryCode", reflect.TypeOf((*MockAuthRepository)(nil).UseRecoveryCode), ctx, userID, codeHash)
+}
+
+// UseTOTPStep mocks base method.
+func (m *MockAuthRepository) UseTOTPStep(ctx context.Context, userID uint64, step int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseTOTPStep", ctx, userID, step)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UseTOTPStep indicates an expected call of UseTOTPStep.
+func (mr *MockAuthRepositoryMockRecorder) UseTOTPStep(ctx, userID, step any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseTOTPStep", reflect.TypeOf((*MockAuthRepository)(nil).UseTOTPStep), ctx, userID, step)
+}
+
+// MockIdentityProvider is a mock of IdentityProvider interface.
+type MockIdentityProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdentityProviderMockRecorder
+}
+
+// MockIdentityProviderMockRecorder is the mock recorder for MockIdentityProvider.
+type MockIdentityProviderMockRecorder struct {
+	mock *MockIdentityProvider
+}
+
+// NewMockIdentityProvider creates a new mock instance.
+func NewMockIdentityProvider(ctrl *gomock.Controller) *MockIdentityProvider {
+	mock := &MockIdentityProvider{ctrl: ctrl}
+	mock.recorder = &MockIdentityProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdentityProvider) EXPECT() *MockIdentityProviderMockRecorder {
+	return m.recorder
+}
+
+// AuthCodeURL mocks base method.
+func (m *MockIdentityProvider) AuthCodeURL(state, nonce, verifier string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthCodeURL", state, nonce, verifier)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// AuthCodeURL indicates an expected call of AuthCodeURL.
+func (mr *MockIdentityProviderMockRecorder) AuthCodeURL(state, nonce, verifier any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthCodeURL", reflect.TypeOf((*MockIdentityProvider)(nil).AuthCodeURL), state, nonce, verifier)
+}
+
+// Exchange mocks base method.
+func (m *MockIdentityProvider) Exchange(ctx context.Context, code, nonce, verifier string) (domain.ExternalIdentity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Exchange", ctx, code, nonce, verifier)
+	ret0, _ := ret[0].(domain.ExternalIdentity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exchange indicates an expected call of Exchange.
+func (mr *MockIdentityProviderMockRecorder) Exchange(ctx, code, nonce, verifier any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exchange", reflect.TypeOf((*MockIdentityProvider)(nil).Exchange), ctx, code, nonce, verifier)
+}
+
+// RoleFor mocks base method.
+func (m *MockIdentityProvider) RoleFor(groups []string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RoleFor", groups)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// RoleFor indicates an expected call of RoleFor.
+func (mr *MockIdentityProviderMockRecorder) RoleFor(groups any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RoleFor", reflect.TypeOf((*MockIdentityProvider)(nil).RoleFor), groups)
+}
+
+// MockNotifier is a mock of Notifier interface.
+type MockNotifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotifierMockRecorder
+}
+
+// MockNotifierMockRecorder is the mock recorder for MockNotifier.
+type MockNotifierMockRecorder struct {
+	mock *MockNotifier
+}
+
+// NewMockNotifier creates a new mock instance.
+func NewMockNotifier(ctrl *gomock.Controller) *MockNotifier {
+	mock := &MockNotifier{ctrl: ctrl}
+	mock.recorder = &MockNotifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotifier) EXPECT() *MockNotifierMockRecorder {
+	return m.recorder
+}
+
+// SendPasswordReset mocks base method.
+func (m *MockNotifier) SendPasswordReset(username, token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendPasswordReset", username, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendPasswordReset indicates an expected call of SendPasswordReset.
+func (mr *MockNotifierMockRecorder) SendPasswordReset(username, token any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByUsername", reflect.TypeOf((*MockAuthRepository)(nil).GetUserByUsername), username)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendPasswordReset", reflect.TypeOf((*MockNotifier)(nil).SendPasswordReset), username, token)
 }
@@ -0,0 +1,204 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/domain/catalog.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/domain/catalog.go -destination=internal/mocks/domain/catalog.go
+//
+
+// Package mock_domain is a generated GoMock package.
+package mock_domain
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	gormModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/gorm"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockExportEncoder is a mock of ExportEncoder interface.
+type MockExportEncoder struct {
+	ctrl     *gomock.Controller
+	recorder *MockExportEncoderMockRecorder
+}
+
+// MockExportEncoderMockRecorder is the mock recorder for MockExportEncoder.
+type MockExportEncoderMockRecorder struct {
+	mock *MockExportEncoder
+}
+
+// NewMockExportEncoder creates a new mock instance.
+func NewMockExportEncoder(ctrl *gomock.Controller) *MockExportEncoder {
+	mock := &MockExportEncoder{ctrl: ctrl}
+	mock.recorder = &MockExportEncoderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExportEncoder) EXPECT() *MockExportEncoderMockRecorder {
+	return m.recorder
+}
+
+// Actor mocks base method.
+func (m *MockExportEncoder) Actor(actor httpModels.GetActorsResponse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Actor", actor)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Actor indicates an expected call of Actor.
+func (mr *MockExportEncoderMockRecorder) Actor(actor any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Actor", reflect.TypeOf((*MockExportEncoder)(nil).Actor), actor)
+}
+
+// Close mocks base method.
+func (m *MockExportEncoder) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockExportEncoderMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockExportEncoder)(nil).Close))
+}
+
+// Movie mocks base method.
+func (m *MockExportEncoder) Movie(movie httpModels.MovieResponse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Movie", movie)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Movie indicates an expected call of Movie.
+func (mr *MockExportEncoderMockRecorder) Movie(movie any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Movie", reflect.TypeOf((*MockExportEncoder)(nil).Movie), movie)
+}
+
+// MockCatalogUsecase is a mock of CatalogUsecase interface.
+type MockCatalogUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockCatalogUsecaseMockRecorder
+}
+
+// MockCatalogUsecaseMockRecorder is the mock recorder for MockCatalogUsecase.
+type MockCatalogUsecaseMockRecorder struct {
+	mock *MockCatalogUsecase
+}
+
+// NewMockCatalogUsecase creates a new mock instance.
+func NewMockCatalogUsecase(ctrl *gomock.Controller) *MockCatalogUsecase {
+	mock := &MockCatalogUsecase{ctrl: ctrl}
+	mock.recorder = &MockCatalogUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCatalogUsecase) EXPECT() *MockCatalogUsecaseMockRecorder {
+	return m.recorder
+}
+
+// Export mocks base method.
+func (m *MockCatalogUsecase) Export(ctx context.Context, filter httpModels.MoviesFilter, encoder domain.ExportEncoder) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, filter, encoder)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockCatalogUsecaseMockRecorder) Export(ctx, filter, encoder any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockCatalogUsecase)(nil).Export), ctx, filter, encoder)
+}
+
+// Import mocks base method.
+func (m *MockCatalogUsecase) Import(ctx context.Context, rows []httpModels.ImportRow, options domain.ImportOptions) (domain.ImportReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", ctx, rows, options)
+	ret0, _ := ret[0].(domain.ImportReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockCatalogUsecaseMockRecorder) Import(ctx, rows, options any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockCatalogUsecase)(nil).Import), ctx, rows, options)
+}
+
+// MockCatalogRepository is a mock of CatalogRepository interface.
+type MockCatalogRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCatalogRepositoryMockRecorder
+}
+
+// MockCatalogRepositoryMockRecorder is the mock recorder for MockCatalogRepository.
+type MockCatalogRepositoryMockRecorder struct {
+	mock *MockCatalogRepository
+}
+
+// NewMockCatalogRepository creates a new mock instance.
+func NewMockCatalogRepository(ctrl *gomock.Controller) *MockCatalogRepository {
+	mock := &MockCatalogRepository{ctrl: ctrl}
+	mock.recorder = &MockCatalogRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCatalogRepository) EXPECT() *MockCatalogRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ExportActors mocks base method.
+func (m *MockCatalogRepository) ExportActors(ctx context.Context, filter httpModels.MoviesFilter, fn func(gormModels.Actor, []gormModels.Movie) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportActors", ctx, filter, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportActors indicates an expected call of ExportActors.
+func (mr *MockCatalogRepositoryMockRecorder) ExportActors(ctx, filter, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportActors", reflect.TypeOf((*MockCatalogRepository)(nil).ExportActors), ctx, filter, fn)
+}
+
+// ExportMovies mocks base method.
+func (m *MockCatalogRepository) ExportMovies(ctx context.Context, filter httpModels.MoviesFilter, fn func(gormModels.Movie, []gormModels.Actor) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportMovies", ctx, filter, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportMovies indicates an expected call of ExportMovies.
+func (mr *MockCatalogRepositoryMockRecorder) ExportMovies(ctx, filter, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportMovies", reflect.TypeOf((*MockCatalogRepository)(nil).ExportMovies), ctx, filter, fn)
+}
+
+// Import mocks base method.
+func (m *MockCatalogRepository) Import(ctx context.Context, rows []gormModels.ImportRow, atomic, dryRun bool) ([]gormModels.ImportResult, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", ctx, rows, atomic, dryRun)
+	ret0, _ := ret[0].([]gormModels.ImportResult)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockCatalogRepositoryMockRecorder) Import(ctx, rows, atomic, dryRun any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockCatalogRepository)(nil).Import), ctx, rows, atomic, dryRun)
+}
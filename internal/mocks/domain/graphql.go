@@ -0,0 +1,188 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/domain/graphql.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/domain/graphql.go -destination=internal/mocks/domain/graphql.go
+//
+
+// Package mock_domain is a generated GoMock package.
+package mock_domain
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	gormModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/gorm"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockGraphQLUsecase is a mock of GraphQLUsecase interface.
+type MockGraphQLUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockGraphQLUsecaseMockRecorder
+}
+
+// MockGraphQLUsecaseMockRecorder is the mock recorder for MockGraphQLUsecase.
+type MockGraphQLUsecaseMockRecorder struct {
+	mock *MockGraphQLUsecase
+}
+
+// NewMockGraphQLUsecase creates a new mock instance.
+func NewMockGraphQLUsecase(ctrl *gomock.Controller) *MockGraphQLUsecase {
+	mock := &MockGraphQLUsecase{ctrl: ctrl}
+	mock.recorder = &MockGraphQLUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGraphQLUsecase) EXPECT() *MockGraphQLUsecaseMockRecorder {
+	return m.recorder
+}
+
+// GetActors mocks base method.
+func (m *MockGraphQLUsecase) GetActors(ctx context.Context, name string, page domain.Page) ([]httpModels.ActorResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActors", ctx, name, page)
+	ret0, _ := ret[0].([]httpModels.ActorResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActors indicates an expected call of GetActors.
+func (mr *MockGraphQLUsecaseMockRecorder) GetActors(ctx, name, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActors", reflect.TypeOf((*MockGraphQLUsecase)(nil).GetActors), ctx, name, page)
+}
+
+// GetCasts mocks base method.
+func (m *MockGraphQLUsecase) GetCasts(ctx context.Context, movieIDs []uint64) (map[uint64][]httpModels.ActorResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCasts", ctx, movieIDs)
+	ret0, _ := ret[0].(map[uint64][]httpModels.ActorResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCasts indicates an expected call of GetCasts.
+func (mr *MockGraphQLUsecaseMockRecorder) GetCasts(ctx, movieIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCasts", reflect.TypeOf((*MockGraphQLUsecase)(nil).GetCasts), ctx, movieIDs)
+}
+
+// GetFilmographies mocks base method.
+func (m *MockGraphQLUsecase) GetFilmographies(ctx context.Context, actorIDs []uint64) (map[uint64][]httpModels.MovieResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFilmographies", ctx, actorIDs)
+	ret0, _ := ret[0].(map[uint64][]httpModels.MovieResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFilmographies indicates an expected call of GetFilmographies.
+func (mr *MockGraphQLUsecaseMockRecorder) GetFilmographies(ctx, actorIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilmographies", reflect.TypeOf((*MockGraphQLUsecase)(nil).GetFilmographies), ctx, actorIDs)
+}
+
+// GetMovies mocks base method.
+func (m *MockGraphQLUsecase) GetMovies(ctx context.Context, filter httpModels.MoviesFilter, page domain.Page) ([]httpModels.MovieResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMovies", ctx, filter, page)
+	ret0, _ := ret[0].([]httpModels.MovieResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMovies indicates an expected call of GetMovies.
+func (mr *MockGraphQLUsecaseMockRecorder) GetMovies(ctx, filter, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMovies", reflect.TypeOf((*MockGraphQLUsecase)(nil).GetMovies), ctx, filter, page)
+}
+
+// MockGraphQLRepository is a mock of GraphQLRepository interface.
+type MockGraphQLRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockGraphQLRepositoryMockRecorder
+}
+
+// MockGraphQLRepositoryMockRecorder is the mock recorder for MockGraphQLRepository.
+type MockGraphQLRepositoryMockRecorder struct {
+	mock *MockGraphQLRepository
+}
+
+// NewMockGraphQLRepository creates a new mock instance.
+func NewMockGraphQLRepository(ctrl *gomock.Controller) *MockGraphQLRepository {
+	mock := &MockGraphQLRepository{ctrl: ctrl}
+	mock.recorder = &MockGraphQLRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGraphQLRepository) EXPECT() *MockGraphQLRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetActors mocks base method.
+func (m *MockGraphQLRepository) GetActors(ctx context.Context, name string, page domain.Page) ([]gormModels.Actor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActors", ctx, name, page)
+	ret0, _ := ret[0].([]gormModels.Actor)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActors indicates an expected call of GetActors.
+func (mr *MockGraphQLRepositoryMockRecorder) GetActors(ctx, name, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActors", reflect.TypeOf((*MockGraphQLRepository)(nil).GetActors), ctx, name, page)
+}
+
+// GetCasts mocks base method.
+func (m *MockGraphQLRepository) GetCasts(ctx context.Context, movieIDs []uint64) ([]gormModels.ActorMovieRelation, []gormModels.Actor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCasts", ctx, movieIDs)
+	ret0, _ := ret[0].([]gormModels.ActorMovieRelation)
+	ret1, _ := ret[1].([]gormModels.Actor)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetCasts indicates an expected call of GetCasts.
+func (mr *MockGraphQLRepositoryMockRecorder) GetCasts(ctx, movieIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCasts", reflect.TypeOf((*MockGraphQLRepository)(nil).GetCasts), ctx, movieIDs)
+}
+
+// GetFilmographies mocks base method.
+func (m *MockGraphQLRepository) GetFilmographies(ctx context.Context, actorIDs []uint64) ([]gormModels.ActorMovieRelation, []gormModels.Movie, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFilmographies", ctx, actorIDs)
+	ret0, _ := ret[0].([]gormModels.ActorMovieRelation)
+	ret1, _ := ret[1].([]gormModels.Movie)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetFilmographies indicates an expected call of GetFilmographies.
+func (mr *MockGraphQLRepositoryMockRecorder) GetFilmographies(ctx, actorIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilmographies", reflect.TypeOf((*MockGraphQLRepository)(nil).GetFilmographies), ctx, actorIDs)
+}
+
+// GetMovies mocks base method.
+func (m *MockGraphQLRepository) GetMovies(ctx context.Context, filter httpModels.MoviesFilter, page domain.Page) ([]gormModels.Movie, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMovies", ctx, filter, page)
+	ret0, _ := ret[0].([]gormModels.Movie)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMovies indicates an expected call of GetMovies.
+func (mr *MockGraphQLRepositoryMockRecorder) GetMovies(ctx, filter, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMovies", reflect.TypeOf((*MockGraphQLRepository)(nil).GetMovies), ctx, filter, page)
+}
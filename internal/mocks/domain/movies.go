@@ -10,8 +10,10 @@
 package mock_domain
 
 import (
+	context "context"
 	reflect "reflect"
 
+	domain "github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
 	gormModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/gorm"
 	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
 	gomock "go.uber.org/mock/gomock"
@@ -41,105 +43,150 @@ func (m *MockMoviesUsecase) EXPECT() *MockMoviesUsecaseMockRecorder {
 }
 
 // AddActorFromMovie mocks base method.
-func (m *MockMoviesUsecase) AddActorFromMovie(movieID, actorID uint64) error {
+func (m *MockMoviesUsecase) AddActorFromMovie(ctx context.Context, movieID, actorID uint64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddActorFromMovie", movieID, actorID)
+	ret := m.ctrl.Call(m, "AddActorFromMovie", ctx, movieID, actorID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AddActorFromMovie indicates an expected call of AddActorFromMovie.
-func (mr *MockMoviesUsecaseMockRecorder) AddActorFromMovie(movieID, actorID any) *gomock.Call {
+func (mr *MockMoviesUsecaseMockRecorder) AddActorFromMovie(ctx, movieID, actorID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddActorFromMovie", reflect.TypeOf((*MockMoviesUsecase)(nil).AddActorFromMovie), movieID, actorID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddActorFromMovie", reflect.TypeOf((*MockMoviesUsecase)(nil).AddActorFromMovie), ctx, movieID, actorID)
+}
+
+// BatchCast mocks base method.
+func (m *MockMoviesUsecase) BatchCast(ctx context.Context, movieID, version uint64, batch httpModels.CastBatch) (httpModels.CastDiff, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchCast", ctx, movieID, version, batch)
+	ret0, _ := ret[0].(httpModels.CastDiff)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchCast indicates an expected call of BatchCast.
+func (mr *MockMoviesUsecaseMockRecorder) BatchCast(ctx, movieID, version, batch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchCast", reflect.TypeOf((*MockMoviesUsecase)(nil).BatchCast), ctx, movieID, version, batch)
 }
 
 // CreateMovie mocks base method.
-func (m *MockMoviesUsecase) CreateMovie(movie httpModels.MovieWithIDCast) (uint64, error) {
+func (m *MockMoviesUsecase) CreateMovie(ctx context.Context, movie httpModels.MovieWithIDCast) (uint64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateMovie", movie)
+	ret := m.ctrl.Call(m, "CreateMovie", ctx, movie)
 	ret0, _ := ret[0].(uint64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateMovie indicates an expected call of CreateMovie.
-func (mr *MockMoviesUsecaseMockRecorder) CreateMovie(movie any) *gomock.Call {
+func (mr *MockMoviesUsecaseMockRecorder) CreateMovie(ctx, movie any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMovie", reflect.TypeOf((*MockMoviesUsecase)(nil).CreateMovie), movie)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMovie", reflect.TypeOf((*MockMoviesUsecase)(nil).CreateMovie), ctx, movie)
 }
 
 // DeleteActorFromMovie mocks base method.
-func (m *MockMoviesUsecase) DeleteActorFromMovie(movieID, actorID uint64) error {
+func (m *MockMoviesUsecase) DeleteActorFromMovie(ctx context.Context, movieID, actorID uint64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteActorFromMovie", movieID, actorID)
+	ret := m.ctrl.Call(m, "DeleteActorFromMovie", ctx, movieID, actorID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteActorFromMovie indicates an expected call of DeleteActorFromMovie.
-func (mr *MockMoviesUsecaseMockRecorder) DeleteActorFromMovie(movieID, actorID any) *gomock.Call {
+func (mr *MockMoviesUsecaseMockRecorder) DeleteActorFromMovie(ctx, movieID, actorID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteActorFromMovie", reflect.TypeOf((*MockMoviesUsecase)(nil).DeleteActorFromMovie), movieID, actorID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteActorFromMovie", reflect.TypeOf((*MockMoviesUsecase)(nil).DeleteActorFromMovie), ctx, movieID, actorID)
 }
 
 // DeleteMovieByID mocks base method.
-func (m *MockMoviesUsecase) DeleteMovieByID(movieID uint64) error {
+func (m *MockMoviesUsecase) DeleteMovieByID(ctx context.Context, movieID, version uint64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteMovieByID", movieID)
+	ret := m.ctrl.Call(m, "DeleteMovieByID", ctx, movieID, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteMovieByID indicates an expected call of DeleteMovieByID.
-func (mr *MockMoviesUsecaseMockRecorder) DeleteMovieByID(movieID any) *gomock.Call {
+func (mr *MockMoviesUsecaseMockRecorder) DeleteMovieByID(ctx, movieID, version any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMovieByID", reflect.TypeOf((*MockMoviesUsecase)(nil).DeleteMovieByID), movieID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMovieByID", reflect.TypeOf((*MockMoviesUsecase)(nil).DeleteMovieByID), ctx, movieID, version)
 }
 
 // GetMovieByID mocks base method.
-func (m *MockMoviesUsecase) GetMovieByID(movieID uint64) (httpModels.MovieResponse, error) {
+func (m *MockMoviesUsecase) GetMovieByID(ctx context.Context, movieID uint64) (httpModels.MovieResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetMovieByID", movieID)
+	ret := m.ctrl.Call(m, "GetMovieByID", ctx, movieID)
 	ret0, _ := ret[0].(httpModels.MovieResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetMovieByID indicates an expected call of GetMovieByID.
-func (mr *MockMoviesUsecaseMockRecorder) GetMovieByID(movieID any) *gomock.Call {
+func (mr *MockMoviesUsecaseMockRecorder) GetMovieByID(ctx, movieID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMovieByID", reflect.TypeOf((*MockMoviesUsecase)(nil).GetMovieByID), movieID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMovieByID", reflect.TypeOf((*MockMoviesUsecase)(nil).GetMovieByID), ctx, movieID)
 }
 
 // GetMovies mocks base method.
-func (m *MockMoviesUsecase) GetMovies(title, actorName string, sortBy httpModels.SortBy, order bool) ([]httpModels.MovieResponse, error) {
+func (m *MockMoviesUsecase) GetMovies(ctx context.Context, title, actorName string, sortBy httpModels.SortBy, order bool) ([]httpModels.MovieResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetMovies", title, actorName, sortBy, order)
+	ret := m.ctrl.Call(m, "GetMovies", ctx, title, actorName, sortBy, order)
 	ret0, _ := ret[0].([]httpModels.MovieResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetMovies indicates an expected call of GetMovies.
-func (mr *MockMoviesUsecaseMockRecorder) GetMovies(title, actorName, sortBy, order any) *gomock.Call {
+func (mr *MockMoviesUsecaseMockRecorder) GetMovies(ctx, title, actorName, sortBy, order any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMovies", reflect.TypeOf((*MockMoviesUsecase)(nil).GetMovies), ctx, title, actorName, sortBy, order)
+}
+
+// PatchMovie mocks base method.
+func (m *MockMoviesUsecase) PatchMovie(ctx context.Context, movieID, version uint64, patch domain.Patch) (httpModels.MovieResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchMovie", ctx, movieID, version, patch)
+	ret0, _ := ret[0].(httpModels.MovieResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchMovie indicates an expected call of PatchMovie.
+func (mr *MockMoviesUsecaseMockRecorder) PatchMovie(ctx, movieID, version, patch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchMovie", reflect.TypeOf((*MockMoviesUsecase)(nil).PatchMovie), ctx, movieID, version, patch)
+}
+
+// ReplaceCast mocks base method.
+func (m *MockMoviesUsecase) ReplaceCast(ctx context.Context, movieID, version uint64, cast httpModels.Cast) (httpModels.CastDiff, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceCast", ctx, movieID, version, cast)
+	ret0, _ := ret[0].(httpModels.CastDiff)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReplaceCast indicates an expected call of ReplaceCast.
+func (mr *MockMoviesUsecaseMockRecorder) ReplaceCast(ctx, movieID, version, cast any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMovies", reflect.TypeOf((*MockMoviesUsecase)(nil).GetMovies), title, actorName, sortBy, order)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceCast", reflect.TypeOf((*MockMoviesUsecase)(nil).ReplaceCast), ctx, movieID, version, cast)
 }
 
 // UpdateMovie mocks base method.
-func (m *MockMoviesUsecase) UpdateMovie(movie httpModels.MovieWithoutCastList, movieID uint64) (httpModels.MovieResponse, error) {
+func (m *MockMoviesUsecase) UpdateMovie(ctx context.Context, movie httpModels.MovieWithoutCastList, movieID, version uint64) (httpModels.MovieResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateMovie", movie, movieID)
+	ret := m.ctrl.Call(m, "UpdateMovie", ctx, movie, movieID, version)
 	ret0, _ := ret[0].(httpModels.MovieResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateMovie indicates an expected call of UpdateMovie.
-func (mr *MockMoviesUsecaseMockRecorder) UpdateMovie(movie, movieID any) *gomock.Call {
+func (mr *MockMoviesUsecaseMockRecorder) UpdateMovie(ctx, movie, movieID, version any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMovie", reflect.TypeOf((*MockMoviesUsecase)(nil).UpdateMovie), movie, movieID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMovie", reflect.TypeOf((*MockMoviesUsecase)(nil).UpdateMovie), ctx, movie, movieID, version)
 }
 
 // MockMoviesRepository is a mock of MoviesRepository interface.
@@ -166,133 +213,163 @@ func (m *MockMoviesRepository) EXPECT() *MockMoviesRepositoryMockRecorder {
 }
 
 // AddActorToMovie mocks base method.
-func (m *MockMoviesRepository) AddActorToMovie(movieID, actorID uint64) error {
+func (m *MockMoviesRepository) AddActorToMovie(ctx context.Context, movieID, actorID uint64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddActorToMovie", movieID, actorID)
+	ret := m.ctrl.Call(m, "AddActorToMovie", ctx, movieID, actorID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AddActorToMovie indicates an expected call of AddActorToMovie.
-func (mr *MockMoviesRepositoryMockRecorder) AddActorToMovie(movieID, actorID any) *gomock.Call {
+func (mr *MockMoviesRepositoryMockRecorder) AddActorToMovie(ctx, movieID, actorID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddActorToMovie", reflect.TypeOf((*MockMoviesRepository)(nil).AddActorToMovie), movieID, actorID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddActorToMovie", reflect.TypeOf((*MockMoviesRepository)(nil).AddActorToMovie), ctx, movieID, actorID)
+}
+
+// ChangeCast mocks base method.
+func (m *MockMoviesRepository) ChangeCast(ctx context.Context, movieID, version uint64, change func([]uint64) []uint64) (gormModels.CastChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeCast", ctx, movieID, version, change)
+	ret0, _ := ret[0].(gormModels.CastChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangeCast indicates an expected call of ChangeCast.
+func (mr *MockMoviesRepositoryMockRecorder) ChangeCast(ctx, movieID, version, change any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeCast", reflect.TypeOf((*MockMoviesRepository)(nil).ChangeCast), ctx, movieID, version, change)
 }
 
 // CreateMovieWithCastList mocks base method.
-func (m *MockMoviesRepository) CreateMovieWithCastList(movie gormModels.Movie, castList []uint64) (uint64, error) {
+func (m *MockMoviesRepository) CreateMovieWithCastList(ctx context.Context, movie gormModels.Movie, castList []uint64) (uint64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateMovieWithCastList", movie, castList)
+	ret := m.ctrl.Call(m, "CreateMovieWithCastList", ctx, movie, castList)
 	ret0, _ := ret[0].(uint64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateMovieWithCastList indicates an expected call of CreateMovieWithCastList.
-func (mr *MockMoviesRepositoryMockRecorder) CreateMovieWithCastList(movie, castList any) *gomock.Call {
+func (mr *MockMoviesRepositoryMockRecorder) CreateMovieWithCastList(ctx, movie, castList any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMovieWithCastList", reflect.TypeOf((*MockMoviesRepository)(nil).CreateMovieWithCastList), movie, castList)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMovieWithCastList", reflect.TypeOf((*MockMoviesRepository)(nil).CreateMovieWithCastList), ctx, movie, castList)
 }
 
 // CreateMovieWithoutCastList mocks base method.
-func (m *MockMoviesRepository) CreateMovieWithoutCastList(movie gormModels.Movie) (uint64, error) {
+func (m *MockMoviesRepository) CreateMovieWithoutCastList(ctx context.Context, movie gormModels.Movie) (uint64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateMovieWithoutCastList", movie)
+	ret := m.ctrl.Call(m, "CreateMovieWithoutCastList", ctx, movie)
 	ret0, _ := ret[0].(uint64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateMovieWithoutCastList indicates an expected call of CreateMovieWithoutCastList.
-func (mr *MockMoviesRepositoryMockRecorder) CreateMovieWithoutCastList(movie any) *gomock.Call {
+func (mr *MockMoviesRepositoryMockRecorder) CreateMovieWithoutCastList(ctx, movie any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMovieWithoutCastList", reflect.TypeOf((*MockMoviesRepository)(nil).CreateMovieWithoutCastList), movie)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMovieWithoutCastList", reflect.TypeOf((*MockMoviesRepository)(nil).CreateMovieWithoutCastList), ctx, movie)
 }
 
 // DeleteActorFromMovie mocks base method.
-func (m *MockMoviesRepository) DeleteActorFromMovie(movieID, actorID uint64) error {
+func (m *MockMoviesRepository) DeleteActorFromMovie(ctx context.Context, movieID, actorID uint64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteActorFromMovie", movieID, actorID)
+	ret := m.ctrl.Call(m, "DeleteActorFromMovie", ctx, movieID, actorID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteActorFromMovie indicates an expected call of DeleteActorFromMovie.
-func (mr *MockMoviesRepositoryMockRecorder) DeleteActorFromMovie(movieID, actorID any) *gomock.Call {
+func (mr *MockMoviesRepositoryMockRecorder) DeleteActorFromMovie(ctx, movieID, actorID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteActorFromMovie", reflect.TypeOf((*MockMoviesRepository)(nil).DeleteActorFromMovie), movieID, actorID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteActorFromMovie", reflect.TypeOf((*MockMoviesRepository)(nil).DeleteActorFromMovie), ctx, movieID, actorID)
 }
 
 // DeleteMovieByID mocks base method.
-func (m *MockMoviesRepository) DeleteMovieByID(movieID uint64) error {
+func (m *MockMoviesRepository) DeleteMovieByID(ctx context.Context, movieID, version uint64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteMovieByID", movieID)
+	ret := m.ctrl.Call(m, "DeleteMovieByID", ctx, movieID, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteMovieByID indicates an expected call of DeleteMovieByID.
-func (mr *MockMoviesRepositoryMockRecorder) DeleteMovieByID(movieID any) *gomock.Call {
+func (mr *MockMoviesRepositoryMockRecorder) DeleteMovieByID(ctx, movieID, version any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMovieByID", reflect.TypeOf((*MockMoviesRepository)(nil).DeleteMovieByID), movieID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMovieByID", reflect.TypeOf((*MockMoviesRepository)(nil).DeleteMovieByID), ctx, movieID, version)
 }
 
 // GetMovieByID mocks base method.
-func (m *MockMoviesRepository) GetMovieByID(movieID uint64) (gormModels.Movie, error) {
+func (m *MockMoviesRepository) GetMovieByID(ctx context.Context, movieID uint64) (gormModels.Movie, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetMovieByID", movieID)
+	ret := m.ctrl.Call(m, "GetMovieByID", ctx, movieID)
 	ret0, _ := ret[0].(gormModels.Movie)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetMovieByID indicates an expected call of GetMovieByID.
-func (mr *MockMoviesRepositoryMockRecorder) GetMovieByID(movieID any) *gomock.Call {
+func (mr *MockMoviesRepositoryMockRecorder) GetMovieByID(ctx, movieID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMovieByID", reflect.TypeOf((*MockMoviesRepository)(nil).GetMovieByID), movieID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMovieByID", reflect.TypeOf((*MockMoviesRepository)(nil).GetMovieByID), ctx, movieID)
 }
 
 // GetMovies mocks base method.
-func (m *MockMoviesRepository) GetMovies(title, actorName string, sortBy httpModels.SortBy, order bool) ([]gormModels.Movie, error) {
+func (m *MockMoviesRepository) GetMovies(ctx context.Context, title, actorName string, sortBy httpModels.SortBy, order bool) ([]gormModels.Movie, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetMovies", title, actorName, sortBy, order)
+	ret := m.ctrl.Call(m, "GetMovies", ctx, title, actorName, sortBy, order)
 	ret0, _ := ret[0].([]gormModels.Movie)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetMovies indicates an expected call of GetMovies.
-func (mr *MockMoviesRepositoryMockRecorder) GetMovies(title, actorName, sortBy, order any) *gomock.Call {
+func (mr *MockMoviesRepositoryMockRecorder) GetMovies(ctx, title, actorName, sortBy, order any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMovies", reflect.TypeOf((*MockMoviesRepository)(nil).GetMovies), title, actorName, sortBy, order)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMovies", reflect.TypeOf((*MockMoviesRepository)(nil).GetMovies), ctx, title, actorName, sortBy, order)
 }
 
 // GetMoviesOfActor mocks base method.
-func (m *MockMoviesRepository) GetMoviesOfActor(actorID uint64) ([]gormModels.Movie, error) {
+func (m *MockMoviesRepository) GetMoviesOfActor(ctx context.Context, actorID uint64) ([]gormModels.Movie, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetMoviesOfActor", actorID)
+	ret := m.ctrl.Call(m, "GetMoviesOfActor", ctx, actorID)
 	ret0, _ := ret[0].([]gormModels.Movie)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetMoviesOfActor indicates an expected call of GetMoviesOfActor.
-func (mr *MockMoviesRepositoryMockRecorder) GetMoviesOfActor(actorID any) *gomock.Call {
+func (mr *MockMoviesRepositoryMockRecorder) GetMoviesOfActor(ctx, actorID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMoviesOfActor", reflect.TypeOf((*MockMoviesRepository)(nil).GetMoviesOfActor), ctx, actorID)
+}
+
+// PatchMovie mocks base method.
+func (m *MockMoviesRepository) PatchMovie(ctx context.Context, movieID, version uint64, patch func(*gormModels.Movie) error) (gormModels.Movie, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchMovie", ctx, movieID, version, patch)
+	ret0, _ := ret[0].(gormModels.Movie)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchMovie indicates an expected call of PatchMovie.
+func (mr *MockMoviesRepositoryMockRecorder) PatchMovie(ctx, movieID, version, patch any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMoviesOfActor", reflect.TypeOf((*MockMoviesRepository)(nil).GetMoviesOfActor), actorID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchMovie", reflect.TypeOf((*MockMoviesRepository)(nil).PatchMovie), ctx, movieID, version, patch)
 }
 
 // UpdateMovie mocks base method.
-func (m *MockMoviesRepository) UpdateMovie(movie gormModels.Movie) (gormModels.Movie, error) {
+func (m *MockMoviesRepository) UpdateMovie(ctx context.Context, movie gormModels.Movie) (gormModels.Movie, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateMovie", movie)
+	ret := m.ctrl.Call(m, "UpdateMovie", ctx, movie)
 	ret0, _ := ret[0].(gormModels.Movie)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateMovie indicates an expected call of UpdateMovie.
-func (mr *MockMoviesRepositoryMockRecorder) UpdateMovie(movie any) *gomock.Call {
+func (mr *MockMoviesRepositoryMockRecorder) UpdateMovie(ctx, movie any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMovie", reflect.TypeOf((*MockMoviesRepository)(nil).UpdateMovie), movie)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMovie", reflect.TypeOf((*MockMoviesRepository)(nil).UpdateMovie), ctx, movie)
 }
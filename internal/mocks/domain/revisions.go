@@ -0,0 +1,171 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/domain/revisions.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/domain/revisions.go -destination=internal/mocks/domain/revisions.go
+//
+
+// Package mock_domain is a generated GoMock package.
+package mock_domain
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gormModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/gorm"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRevisionsUsecase is a mock of RevisionsUsecase interface.
+type MockRevisionsUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockRevisionsUsecaseMockRecorder
+}
+
+// MockRevisionsUsecaseMockRecorder is the mock recorder for MockRevisionsUsecase.
+type MockRevisionsUsecaseMockRecorder struct {
+	mock *MockRevisionsUsecase
+}
+
+// NewMockRevisionsUsecase creates a new mock instance.
+func NewMockRevisionsUsecase(ctrl *gomock.Controller) *MockRevisionsUsecase {
+	mock := &MockRevisionsUsecase{ctrl: ctrl}
+	mock.recorder = &MockRevisionsUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRevisionsUsecase) EXPECT() *MockRevisionsUsecaseMockRecorder {
+	return m.recorder
+}
+
+// GetCatalogAt mocks base method.
+func (m *MockRevisionsUsecase) GetCatalogAt(ctx context.Context, at time.Time) (httpModels.Catalog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCatalogAt", ctx, at)
+	ret0, _ := ret[0].(httpModels.Catalog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCatalogAt indicates an expected call of GetCatalogAt.
+func (mr *MockRevisionsUsecaseMockRecorder) GetCatalogAt(ctx, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCatalogAt", reflect.TypeOf((*MockRevisionsUsecase)(nil).GetCatalogAt), ctx, at)
+}
+
+// GetRevisions mocks base method.
+func (m *MockRevisionsUsecase) GetRevisions(ctx context.Context, entity string, entityID uint64) ([]httpModels.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevisions", ctx, entity, entityID)
+	ret0, _ := ret[0].([]httpModels.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevisions indicates an expected call of GetRevisions.
+func (mr *MockRevisionsUsecaseMockRecorder) GetRevisions(ctx, entity, entityID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevisions", reflect.TypeOf((*MockRevisionsUsecase)(nil).GetRevisions), ctx, entity, entityID)
+}
+
+// RevertActor mocks base method.
+func (m *MockRevisionsUsecase) RevertActor(ctx context.Context, actorID, number uint64) (httpModels.ActorResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevertActor", ctx, actorID, number)
+	ret0, _ := ret[0].(httpModels.ActorResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevertActor indicates an expected call of RevertActor.
+func (mr *MockRevisionsUsecaseMockRecorder) RevertActor(ctx, actorID, number any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevertActor", reflect.TypeOf((*MockRevisionsUsecase)(nil).RevertActor), ctx, actorID, number)
+}
+
+// RevertMovie mocks base method.
+func (m *MockRevisionsUsecase) RevertMovie(ctx context.Context, movieID, number uint64) (httpModels.MovieResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevertMovie", ctx, movieID, number)
+	ret0, _ := ret[0].(httpModels.MovieResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevertMovie indicates an expected call of RevertMovie.
+func (mr *MockRevisionsUsecaseMockRecorder) RevertMovie(ctx, movieID, number any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevertMovie", reflect.TypeOf((*MockRevisionsUsecase)(nil).RevertMovie), ctx, movieID, number)
+}
+
+// MockRevisionsRepository is a mock of RevisionsRepository interface.
+type MockRevisionsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRevisionsRepositoryMockRecorder
+}
+
+// MockRevisionsRepositoryMockRecorder is the mock recorder for MockRevisionsRepository.
+type MockRevisionsRepositoryMockRecorder struct {
+	mock *MockRevisionsRepository
+}
+
+// NewMockRevisionsRepository creates a new mock instance.
+func NewMockRevisionsRepository(ctrl *gomock.Controller) *MockRevisionsRepository {
+	mock := &MockRevisionsRepository{ctrl: ctrl}
+	mock.recorder = &MockRevisionsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRevisionsRepository) EXPECT() *MockRevisionsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetRevision mocks base method.
+func (m *MockRevisionsRepository) GetRevision(ctx context.Context, entity string, entityID, number uint64) (gormModels.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevision", ctx, entity, entityID, number)
+	ret0, _ := ret[0].(gormModels.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevision indicates an expected call of GetRevision.
+func (mr *MockRevisionsRepositoryMockRecorder) GetRevision(ctx, entity, entityID, number any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevision", reflect.TypeOf((*MockRevisionsRepository)(nil).GetRevision), ctx, entity, entityID, number)
+}
+
+// GetRevisions mocks base method.
+func (m *MockRevisionsRepository) GetRevisions(ctx context.Context, entity string, entityID uint64) ([]gormModels.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevisions", ctx, entity, entityID)
+	ret0, _ := ret[0].([]gormModels.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevisions indicates an expected call of GetRevisions.
+func (mr *MockRevisionsRepositoryMockRecorder) GetRevisions(ctx, entity, entityID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevisions", reflect.TypeOf((*MockRevisionsRepository)(nil).GetRevisions), ctx, entity, entityID)
+}
+
+// GetRevisionsAt mocks base method.
+func (m *MockRevisionsRepository) GetRevisionsAt(ctx context.Context, entity string, at time.Time) ([]gormModels.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevisionsAt", ctx, entity, at)
+	ret0, _ := ret[0].([]gormModels.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevisionsAt indicates an expected call of GetRevisionsAt.
+func (mr *MockRevisionsRepositoryMockRecorder) GetRevisionsAt(ctx, entity, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevisionsAt", reflect.TypeOf((*MockRevisionsRepository)(nil).GetRevisionsAt), ctx, entity, at)
+}
@@ -0,0 +1,168 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/domain/trash.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/domain/trash.go -destination=internal/mocks/domain/trash.go
+//
+
+// Package mock_domain is a generated GoMock package.
+package mock_domain
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gormModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/gorm"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTrashUsecase is a mock of TrashUsecase interface.
+type MockTrashUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockTrashUsecaseMockRecorder
+}
+
+// MockTrashUsecaseMockRecorder is the mock recorder for MockTrashUsecase.
+type MockTrashUsecaseMockRecorder struct {
+	mock *MockTrashUsecase
+}
+
+// NewMockTrashUsecase creates a new mock instance.
+func NewMockTrashUsecase(ctrl *gomock.Controller) *MockTrashUsecase {
+	mock := &MockTrashUsecase{ctrl: ctrl}
+	mock.recorder = &MockTrashUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTrashUsecase) EXPECT() *MockTrashUsecaseMockRecorder {
+	return m.recorder
+}
+
+// GetTrash mocks base method.
+func (m *MockTrashUsecase) GetTrash(ctx context.Context, entity string, page uint64) ([]httpModels.TrashItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrash", ctx, entity, page)
+	ret0, _ := ret[0].([]httpModels.TrashItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrash indicates an expected call of GetTrash.
+func (mr *MockTrashUsecaseMockRecorder) GetTrash(ctx, entity, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrash", reflect.TypeOf((*MockTrashUsecase)(nil).GetTrash), ctx, entity, page)
+}
+
+// Purge mocks base method.
+func (m *MockTrashUsecase) Purge(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockTrashUsecaseMockRecorder) Purge(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockTrashUsecase)(nil).Purge), ctx)
+}
+
+// Restore mocks base method.
+func (m *MockTrashUsecase) Restore(ctx context.Context, entity string, entityID uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, entity, entityID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockTrashUsecaseMockRecorder) Restore(ctx, entity, entityID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockTrashUsecase)(nil).Restore), ctx, entity, entityID)
+}
+
+// MockTrashRepository is a mock of TrashRepository interface.
+type MockTrashRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTrashRepositoryMockRecorder
+}
+
+// MockTrashRepositoryMockRecorder is the mock recorder for MockTrashRepository.
+type MockTrashRepositoryMockRecorder struct {
+	mock *MockTrashRepository
+}
+
+// NewMockTrashRepository creates a new mock instance.
+func NewMockTrashRepository(ctrl *gomock.Controller) *MockTrashRepository {
+	mock := &MockTrashRepository{ctrl: ctrl}
+	mock.recorder = &MockTrashRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTrashRepository) EXPECT() *MockTrashRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetTrash mocks base method.
+func (m *MockTrashRepository) GetTrash(ctx context.Context, entity string, page uint64) ([]gormModels.TrashItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrash", ctx, entity, page)
+	ret0, _ := ret[0].([]gormModels.TrashItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrash indicates an expected call of GetTrash.
+func (mr *MockTrashRepositoryMockRecorder) GetTrash(ctx, entity, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrash", reflect.TypeOf((*MockTrashRepository)(nil).GetTrash), ctx, entity, page)
+}
+
+// Purge mocks base method.
+func (m *MockTrashRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, deletedBefore)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockTrashRepositoryMockRecorder) Purge(ctx, deletedBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockTrashRepository)(nil).Purge), ctx, deletedBefore)
+}
+
+// RestoreActor mocks base method.
+func (m *MockTrashRepository) RestoreActor(ctx context.Context, actorID uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreActor", ctx, actorID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreActor indicates an expected call of RestoreActor.
+func (mr *MockTrashRepositoryMockRecorder) RestoreActor(ctx, actorID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreActor", reflect.TypeOf((*MockTrashRepository)(nil).RestoreActor), ctx, actorID)
+}
+
+// RestoreMovie mocks base method.
+func (m *MockTrashRepository) RestoreMovie(ctx context.Context, movieID uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreMovie", ctx, movieID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreMovie indicates an expected call of RestoreMovie.
+func (mr *MockTrashRepositoryMockRecorder) RestoreMovie(ctx, movieID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreMovie", reflect.TypeOf((*MockTrashRepository)(nil).RestoreMovie), ctx, movieID)
+}
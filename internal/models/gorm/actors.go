@@ -13,6 +13,8 @@ type Actor struct {
 	Name      string `gorm:"unique"`
 	Gender    bool
 	BirthDate time.Time
+	// Version grows with every change of the actor and is sent as the ETag.
+	Version uint64 `gorm:"not null;default:1"`
 }
 
 func (a Actor) ToHTTPModel() httpModels.ActorResponse {
@@ -21,6 +23,7 @@ func (a Actor) ToHTTPModel() httpModels.ActorResponse {
 		Name:      a.Name,
 		Gender:    a.Gender,
 		BirthDate: a.BirthDate.Format(time.DateOnly),
+		Version:   a.Version,
 	}
 }
 
@@ -29,8 +32,3 @@ type ActorMovieRelation struct {
 	MovieID uint64 `gorm:"uniqueIndex:idx_movie_actor"`
 	ActorID uint64 `gorm:"uniqueIndex:idx_movie_actor"`
 }
-
-func (a *Actor) AfterDelete(tx *gorm.DB) (err error) {
-	tx.Where("actor_id = ?", a.ID).Unscoped().Delete(&ActorMovieRelation{})
-	return
-}
@@ -0,0 +1,34 @@
+package gormModels
+
+import (
+	"encoding/json"
+	"time"
+
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+)
+
+// AuditEntry is written in the same transaction as the change it describes
+// and is never updated afterwards.
+type AuditEntry struct {
+	ID        uint64    `gorm:"primaryKey"`
+	CreatedAt time.Time `gorm:"index;not null"`
+	UserID    uint64    `gorm:"index"`
+	RequestID string
+	Entity    string `gorm:"index:idx_audit_entity;not null"`
+	EntityID  uint64 `gorm:"index:idx_audit_entity"`
+	Action    string `gorm:"not null"`
+	Diff      string `gorm:"type:jsonb;not null"`
+}
+
+func (a AuditEntry) ToHTTPModel() httpModels.AuditEntry {
+	return httpModels.AuditEntry{
+		ID:        a.ID,
+		CreatedAt: a.CreatedAt.Format(time.RFC3339),
+		UserID:    a.UserID,
+		RequestID: a.RequestID,
+		Entity:    a.Entity,
+		EntityID:  a.EntityID,
+		Action:    a.Action,
+		Diff:      json.RawMessage(a.Diff),
+	}
+}
@@ -0,0 +1,18 @@
+package gormModels
+
+// ImportRow is one checked row of an import: a movie with the names of its
+// cast, or an actor. Line is where the row is in the file.
+type ImportRow struct {
+	Line  int
+	Movie *Movie
+	Cast  []string
+	Actor *Actor
+}
+
+// ImportResult is what became of the row with the same index. Created is
+// false for rows that updated an existing item.
+type ImportResult struct {
+	ID      uint64
+	Created bool
+	Err     error
+}
@@ -14,6 +14,9 @@ type Movie struct {
 	Description string    `gorm:"type:text;not null"`
 	ReleaseDate time.Time `gorm:"not null"`
 	Rating      float32   `gorm:"check:rating >= 0 and rating <= 10;not null"`
+	// Version grows with every change of the movie or its cast and is sent
+	// as the ETag.
+	Version uint64 `gorm:"not null;default:1"`
 }
 
 func (m Movie) ToHTTPResponse() httpModels.MovieResponse {
@@ -23,6 +26,7 @@ func (m Movie) ToHTTPResponse() httpModels.MovieResponse {
 		Description: m.Description,
 		ReleaseDate: m.ReleaseDate.Format(time.DateOnly),
 		Rating:      m.Rating,
+		Version:     m.Version,
 	}
 }
 
@@ -36,7 +40,11 @@ func (m Movie) ToHTTPMovies() httpModels.MovieWithoutCastList {
 	}
 }
 
-func (m *Movie) AfterDelete(tx *gorm.DB) (err error) {
-	tx.Where("movie_id = ?", m.ID).Unscoped().Delete(&ActorMovieRelation{})
-	return
+// CastChange is what a change of a cast did. Version is the version of the
+// movie after the change.
+type CastChange struct {
+	Added     []uint64
+	Removed   []uint64
+	Unchanged []uint64
+	Version   uint64
 }
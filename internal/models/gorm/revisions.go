@@ -0,0 +1,31 @@
+package gormModels
+
+import (
+	"encoding/json"
+	"time"
+
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+)
+
+// Revision is an immutable snapshot of a movie or an actor. Numbers start at
+// 1 with the creation and grow with every update, a delete is the last one.
+type Revision struct {
+	ID        uint64    `gorm:"primaryKey"`
+	CreatedAt time.Time `gorm:"index;not null"`
+	Entity    string    `gorm:"uniqueIndex:idx_revision;not null"`
+	EntityID  uint64    `gorm:"uniqueIndex:idx_revision"`
+	Number    uint64    `gorm:"uniqueIndex:idx_revision"`
+	UserID    uint64
+	Deleted   bool
+	Snapshot  string `gorm:"type:jsonb;not null"`
+}
+
+func (r Revision) ToHTTPModel() httpModels.Revision {
+	return httpModels.Revision{
+		Number:    r.Number,
+		CreatedAt: r.CreatedAt.Format(time.RFC3339),
+		UserID:    r.UserID,
+		Deleted:   r.Deleted,
+		Snapshot:  json.RawMessage(r.Snapshot),
+	}
+}
@@ -0,0 +1,24 @@
+package gormModels
+
+import (
+	"time"
+
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+)
+
+// TrashItem is a soft deleted movie or actor, Name holds the movie title.
+type TrashItem struct {
+	Entity    string
+	ID        uint64
+	Name      string
+	DeletedAt time.Time
+}
+
+func (t TrashItem) ToHTTPModel() httpModels.TrashItem {
+	return httpModels.TrashItem{
+		Type:      t.Entity,
+		ID:        t.ID,
+		Name:      t.Name,
+		DeletedAt: t.DeletedAt.Format(time.RFC3339),
+	}
+}
@@ -13,6 +13,10 @@ type User struct {
 	Username string `gorm:"unique"`
 	Password string
 	Role     string
+
+	TOTPSecret   string
+	TOTPEnabled  bool
+	TOTPLastStep int64
 }
 
 func (u User) ToHTTPModel() httpModels.AuthUser {
@@ -20,6 +24,8 @@ func (u User) ToHTTPModel() httpModels.AuthUser {
 		Username: u.Username,
 		Password: u.Password,
 		Role:     u.Role,
+
+		TwoFactorEnabled: u.TOTPEnabled,
 	}
 }
 
@@ -29,3 +35,56 @@ type Session struct {
 	SessionID  string
 	ExpireDate time.Time
 }
+
+type PasswordResetToken struct {
+	gorm.Model
+	ID         uint64
+	UserID     uint64
+	TokenHash  string `gorm:"uniqueIndex"`
+	ExpireDate time.Time
+	Used       bool
+}
+
+type LoginAttempt struct {
+	gorm.Model
+	Key          string `gorm:"uniqueIndex"`
+	Failures     uint64
+	BlockedUntil time.Time
+}
+
+type RecoveryCode struct {
+	gorm.Model
+	UserID   uint64 `gorm:"index"`
+	CodeHash string
+	Used     bool
+}
+
+type TwoFactorChallenge struct {
+	gorm.Model
+	ID         uint64
+	UserID     uint64
+	TokenHash  string `gorm:"uniqueIndex"`
+	ExpireDate time.Time
+	Attempts   uint64
+	// IP is the address the password came from, wrong codes count against
+	// it as wrong passwords do.
+	IP string
+}
+
+type ExternalIdentity struct {
+	gorm.Model
+	Provider string `gorm:"uniqueIndex:idx_provider_subject"`
+	Subject  string `gorm:"uniqueIndex:idx_provider_subject"`
+	UserID   uint64 `gorm:"index"`
+}
+
+type OIDCState struct {
+	gorm.Model
+	ID         uint64
+	StateHash  string `gorm:"uniqueIndex"`
+	Provider   string
+	Nonce      string
+	Verifier   string
+	LinkUserID uint64
+	ExpireDate time.Time
+}
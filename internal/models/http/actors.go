@@ -6,9 +6,9 @@ type GetActorsResponse struct {
 }
 
 type Actor struct {
-	Name      string `json:"name"`
+	Name      string `json:"name" validate:"required,max=150"`
 	Gender    bool   `json:"gender"`
-	BirthDate string `json:"birthDate"`
+	BirthDate string `json:"birthDate" validate:"required,date,past"`
 }
 
 type ActorResponse struct {
@@ -16,6 +16,7 @@ type ActorResponse struct {
 	Name      string `json:"name,omitempty"`
 	Gender    bool   `json:"gender"`
 	BirthDate string `json:"birthDate,omitempty"`
+	Version   uint64 `json:"-"`
 }
 
 type ActorID struct {
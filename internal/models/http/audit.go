@@ -0,0 +1,27 @@
+package httpModels
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type AuditEntry struct {
+	ID        uint64          `json:"id"`
+	CreatedAt string          `json:"createdAt"`
+	UserID    uint64          `json:"userId"`
+	RequestID string          `json:"requestId,omitempty"`
+	Entity    string          `json:"entity"`
+	EntityID  uint64          `json:"entityId"`
+	Action    string          `json:"action"`
+	Diff      json.RawMessage `json:"diff"`
+}
+
+// AuditFilter narrows the audit log down, zero values match everything.
+type AuditFilter struct {
+	Entity   string
+	EntityID uint64
+	UserID   uint64
+	From     time.Time
+	To       time.Time
+	Page     uint64
+}
@@ -0,0 +1,36 @@
+package httpModels
+
+import "encoding/json"
+
+// BatchRequest runs several API requests in one round trip. An atomic batch
+// stops at the first failed request and rolls back what the others did.
+type BatchRequest struct {
+	Atomic   bool        `json:"atomic"`
+	Requests []BatchItem `json:"requests" validate:"min=1"`
+}
+
+// BatchItem is one request of a batch. Path is the full path with the query,
+// e.g. /api/v1/movies?title=god. Headers are added to the headers of the
+// batch request, so the session cookie is shared.
+type BatchItem struct {
+	ID      string            `json:"id,omitempty"`
+	Method  string            `json:"method" validate:"required"`
+	Path    string            `json:"path" validate:"required"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    json.RawMessage   `json:"body,omitempty"`
+}
+
+// BatchResponse lists the responses in the order of the requests.
+type BatchResponse struct {
+	RolledBack bool                `json:"rolledBack,omitempty"`
+	Responses  []BatchItemResponse `json:"responses"`
+}
+
+// BatchItemResponse is the response to one request. A JSON body is kept as
+// is, any other body becomes a string.
+type BatchItemResponse struct {
+	ID      string            `json:"id,omitempty"`
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    json.RawMessage   `json:"body,omitempty"`
+}
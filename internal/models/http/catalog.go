@@ -0,0 +1,18 @@
+package httpModels
+
+// ImportRow is a movie or an actor as it comes in an import file. Movies
+// name their cast, so a file can bring the actors along.
+type ImportRow struct {
+	Type        string   `json:"type"`
+	Title       string   `json:"title,omitempty"`
+	Description string   `json:"description,omitempty"`
+	ReleaseDate string   `json:"releaseDate,omitempty"`
+	Rating      float32  `json:"rating,omitempty"`
+	Cast        []string `json:"cast,omitempty"`
+	Name        string   `json:"name,omitempty"`
+	Gender      bool     `json:"gender,omitempty"`
+	BirthDate   string   `json:"birthDate,omitempty"`
+
+	Line int   `json:"-"`
+	Err  error `json:"-"`
+}
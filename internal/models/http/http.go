@@ -7,3 +7,12 @@ var EmptyModel = []byte("{}")
 type ID struct {
 	ID uint64 `json:"id"`
 }
+
+// MoviesFilter picks and orders movies the way the query of GET /movies
+// does.
+type MoviesFilter struct {
+	Title  string
+	Actor  string
+	SortBy SortBy
+	Order  bool
+}
@@ -19,17 +19,17 @@ func (m Movie) ToMovieWithoutCastList() MovieWithoutCastList {
 
 type MovieWithoutCastList struct {
 	ID          uint64  `json:"id"`
-	Title       string  `json:"title"`
+	Title       string  `json:"title" validate:"required,max=150"`
 	Description string  `json:"description"`
-	ReleaseDate string  `json:"releaseDate"`
-	Rating      float32 `json:"rating"`
+	ReleaseDate string  `json:"releaseDate" validate:"required,date"`
+	Rating      float32 `json:"rating" validate:"min=0,max=10"`
 }
 
 type MovieWithIDCast struct {
-	Title       string   `json:"title"`
+	Title       string   `json:"title" validate:"required,max=150"`
 	Description string   `json:"description"`
-	ReleaseDate string   `json:"releaseDate"`
-	Rating      float32  `json:"rating"`
+	ReleaseDate string   `json:"releaseDate" validate:"required,date"`
+	Rating      float32  `json:"rating" validate:"min=0,max=10"`
 	CastIDList  []uint64 `json:"castIDList"`
 }
 
@@ -40,8 +40,29 @@ type MovieResponse struct {
 	ReleaseDate string          `json:"releaseDate,omitempty"`
 	Rating      float32         `json:"rating,omitempty"`
 	CastList    []ActorResponse `json:"castList,omitempty"`
+	Version     uint64          `json:"-"`
 }
 
 type MovieID struct {
 	ID uint64 `json:"id,omitempty"`
 }
+
+// Cast is the whole new cast of a movie.
+type Cast struct {
+	ActorIDs []uint64 `json:"actorIDs"`
+}
+
+// CastBatch lists the actors to add to and remove from the cast of a movie.
+type CastBatch struct {
+	Add    []uint64 `json:"add"`
+	Remove []uint64 `json:"remove"`
+}
+
+// CastDiff tells how the cast of a movie has changed. Unchanged are the
+// actors that were in the cast and stay there.
+type CastDiff struct {
+	Added     []uint64 `json:"added"`
+	Removed   []uint64 `json:"removed"`
+	Unchanged []uint64 `json:"unchanged"`
+	Version   uint64   `json:"-"`
+}
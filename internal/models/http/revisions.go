@@ -0,0 +1,18 @@
+package httpModels
+
+import "encoding/json"
+
+type Revision struct {
+	Number    uint64          `json:"number"`
+	CreatedAt string          `json:"createdAt"`
+	UserID    uint64          `json:"userId"`
+	Deleted   bool            `json:"deleted"`
+	Snapshot  json.RawMessage `json:"snapshot"`
+}
+
+// Catalog is the library as it looked at a point in time.
+type Catalog struct {
+	At     string                 `json:"at"`
+	Movies []MovieWithoutCastList `json:"movies"`
+	Actors []ActorResponse        `json:"actors"`
+}
@@ -0,0 +1,9 @@
+package httpModels
+
+type TrashItem struct {
+	Type      string `json:"type"`
+	ID        uint64 `json:"id"`
+	Name      string `json:"name"`
+	DeletedAt string `json:"deletedAt"`
+	PurgeAt   string `json:"purgeAt,omitempty"`
+}
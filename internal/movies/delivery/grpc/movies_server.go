@@ -0,0 +1,199 @@
+package grpcMovies
+
+import (
+	"context"
+
+	grpcActors "github.com/themilchenko/vk-tech_internship-problem_2024/internal/actors/delivery/grpc"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/validate"
+	filmlibraryv1 "github.com/themilchenko/vk-tech_internship-problem_2024/pkg/api/filmlibrary/v1"
+)
+
+// sortFields are the filter values of GET /movies by the MovieSort values.
+var sortFields = map[filmlibraryv1.MovieSort]httpModels.SortBy{
+	filmlibraryv1.MovieSort_MOVIE_SORT_UNSPECIFIED:  "rating",
+	filmlibraryv1.MovieSort_MOVIE_SORT_TITLE:        "title",
+	filmlibraryv1.MovieSort_MOVIE_SORT_RATING:       "rating",
+	filmlibraryv1.MovieSort_MOVIE_SORT_RELEASE_DATE: "releaseDate",
+}
+
+type MoviesServer struct {
+	filmlibraryv1.UnimplementedMoviesServiceServer
+
+	moviesUsecase domain.MoviesUsecase
+}
+
+func NewMoviesServer(m domain.MoviesUsecase) *MoviesServer {
+	return &MoviesServer{moviesUsecase: m}
+}
+
+func (s *MoviesServer) CreateMovie(
+	ctx context.Context,
+	req *filmlibraryv1.CreateMovieRequest,
+) (*filmlibraryv1.CreateMovieResponse, error) {
+	movie := httpModels.MovieWithIDCast{
+		Title:       req.GetTitle(),
+		Description: req.GetDescription(),
+		ReleaseDate: req.GetReleaseDate(),
+		Rating:      req.GetRating(),
+		CastIDList:  req.GetCastIds(),
+	}
+	if err := validate.Check(movie); err != nil {
+		return nil, err
+	}
+
+	movieID, err := s.moviesUsecase.CreateMovie(ctx, movie)
+	if err != nil {
+		return nil, err
+	}
+	return &filmlibraryv1.CreateMovieResponse{Id: movieID}, nil
+}
+
+func (s *MoviesServer) GetMovie(
+	ctx context.Context,
+	req *filmlibraryv1.GetMovieRequest,
+) (*filmlibraryv1.GetMovieResponse, error) {
+	movie, err := s.moviesUsecase.GetMovieByID(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return &filmlibraryv1.GetMovieResponse{Movie: toMovie(movie)}, nil
+}
+
+func (s *MoviesServer) ListMovies(
+	ctx context.Context,
+	req *filmlibraryv1.ListMoviesRequest,
+) (*filmlibraryv1.ListMoviesResponse, error) {
+	sortBy, ok := sortFields[req.GetSortBy()]
+	if !ok {
+		return nil, domain.ErrBadRequest
+	}
+
+	movies, err := s.moviesUsecase.GetMovies(
+		ctx,
+		req.GetTitle(),
+		req.GetActor(),
+		sortBy,
+		!req.GetDescending(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &filmlibraryv1.ListMoviesResponse{
+		Movies: make([]*filmlibraryv1.Movie, len(movies)),
+	}
+	for i, movie := range movies {
+		response.Movies[i] = toMovie(movie)
+	}
+	return response, nil
+}
+
+func (s *MoviesServer) UpdateMovie(
+	ctx context.Context,
+	req *filmlibraryv1.UpdateMovieRequest,
+) (*filmlibraryv1.UpdateMovieResponse, error) {
+	movie := httpModels.MovieWithoutCastList{
+		Title:       req.GetTitle(),
+		Description: req.GetDescription(),
+		ReleaseDate: req.GetReleaseDate(),
+		Rating:      req.GetRating(),
+	}
+	if err := validate.Check(movie); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.moviesUsecase.UpdateMovie(ctx, movie, req.GetId(), req.GetVersion())
+	if err != nil {
+		return nil, err
+	}
+	return &filmlibraryv1.UpdateMovieResponse{Movie: toMovie(updated)}, nil
+}
+
+func (s *MoviesServer) DeleteMovie(
+	ctx context.Context,
+	req *filmlibraryv1.DeleteMovieRequest,
+) (*filmlibraryv1.DeleteMovieResponse, error) {
+	if err := s.moviesUsecase.DeleteMovieByID(ctx, req.GetId(), req.GetVersion()); err != nil {
+		return nil, err
+	}
+	return &filmlibraryv1.DeleteMovieResponse{}, nil
+}
+
+func (s *MoviesServer) AddActorToMovie(
+	ctx context.Context,
+	req *filmlibraryv1.AddActorToMovieRequest,
+) (*filmlibraryv1.AddActorToMovieResponse, error) {
+	if err := s.moviesUsecase.AddActorFromMovie(ctx, req.GetMovieId(), req.GetActorId()); err != nil {
+		return nil, err
+	}
+	return &filmlibraryv1.AddActorToMovieResponse{}, nil
+}
+
+func (s *MoviesServer) RemoveActorFromMovie(
+	ctx context.Context,
+	req *filmlibraryv1.RemoveActorFromMovieRequest,
+) (*filmlibraryv1.RemoveActorFromMovieResponse, error) {
+	if err := s.moviesUsecase.DeleteActorFromMovie(ctx, req.GetMovieId(), req.GetActorId()); err != nil {
+		return nil, err
+	}
+	return &filmlibraryv1.RemoveActorFromMovieResponse{}, nil
+}
+
+func (s *MoviesServer) ReplaceCast(
+	ctx context.Context,
+	req *filmlibraryv1.ReplaceCastRequest,
+) (*filmlibraryv1.ReplaceCastResponse, error) {
+	diff, err := s.moviesUsecase.ReplaceCast(
+		ctx,
+		req.GetMovieId(),
+		req.GetVersion(),
+		httpModels.Cast{ActorIDs: req.GetActorIds()},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &filmlibraryv1.ReplaceCastResponse{Diff: toCastDiff(diff)}, nil
+}
+
+func (s *MoviesServer) BatchCast(
+	ctx context.Context,
+	req *filmlibraryv1.BatchCastRequest,
+) (*filmlibraryv1.BatchCastResponse, error) {
+	diff, err := s.moviesUsecase.BatchCast(
+		ctx,
+		req.GetMovieId(),
+		req.GetVersion(),
+		httpModels.CastBatch{Add: req.GetAdd(), Remove: req.GetRemove()},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &filmlibraryv1.BatchCastResponse{Diff: toCastDiff(diff)}, nil
+}
+
+func toMovie(movie httpModels.MovieResponse) *filmlibraryv1.Movie {
+	cast := make([]*filmlibraryv1.Actor, len(movie.CastList))
+	for i, actor := range movie.CastList {
+		cast[i] = grpcActors.ToActor(actor)
+	}
+	return &filmlibraryv1.Movie{
+		Id:          movie.ID,
+		Title:       movie.Title,
+		Description: movie.Description,
+		ReleaseDate: movie.ReleaseDate,
+		Rating:      movie.Rating,
+		Cast:        cast,
+		Version:     movie.Version,
+	}
+}
+
+func toCastDiff(diff httpModels.CastDiff) *filmlibraryv1.CastDiff {
+	return &filmlibraryv1.CastDiff{
+		Added:     diff.Added,
+		Removed:   diff.Removed,
+		Unchanged: diff.Unchanged,
+		Version:   diff.Version,
+	}
+}
@@ -0,0 +1,147 @@
+package grpcMovies
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	mockDomain "github.com/themilchenko/vk-tech_internship-problem_2024/internal/mocks/domain"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/grpcstatus"
+	filmlibraryv1 "github.com/themilchenko/vk-tech_internship-problem_2024/pkg/api/filmlibrary/v1"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+// newClient serves s over an in-memory connection.
+func newClient(t *testing.T, s *MoviesServer) filmlibraryv1.MoviesServiceClient {
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(grpc.UnaryInterceptor(grpcstatus.UnaryServerInterceptor))
+	filmlibraryv1.RegisterMoviesServiceServer(server, s)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return filmlibraryv1.NewMoviesServiceClient(conn)
+}
+
+func TestServer_GetMovie(t *testing.T) {
+	type mockBehavior func(m *mockDomain.MockMoviesUsecase)
+
+	tests := []struct {
+		name             string
+		movieID          uint64
+		mockBehavior     mockBehavior
+		expectedResponse *filmlibraryv1.GetMovieResponse
+		expectedCode     codes.Code
+		expectedReason   string
+	}{
+		{
+			name:    "OK",
+			movieID: 1,
+			mockBehavior: func(m *mockDomain.MockMoviesUsecase) {
+				m.EXPECT().GetMovieByID(gomock.Any(), uint64(1)).Return(httpModels.MovieResponse{
+					ID:          1,
+					Title:       "The Godfather",
+					ReleaseDate: "1972-03-24",
+					Rating:      9.2,
+					CastList: []httpModels.ActorResponse{
+						{ID: 3, Name: "Marlon Brando", Gender: true, BirthDate: "1924-04-03", Version: 1},
+					},
+					Version: 2,
+				}, nil)
+			},
+			expectedResponse: &filmlibraryv1.GetMovieResponse{Movie: &filmlibraryv1.Movie{
+				Id:          1,
+				Title:       "The Godfather",
+				ReleaseDate: "1972-03-24",
+				Rating:      9.2,
+				Cast: []*filmlibraryv1.Actor{
+					{Id: 3, Name: "Marlon Brando", Gender: true, BirthDate: "1924-04-03", Version: 1},
+				},
+				Version: 2,
+			}},
+			expectedCode: codes.OK,
+		},
+		{
+			name:    "Not found",
+			movieID: 7,
+			mockBehavior: func(m *mockDomain.MockMoviesUsecase) {
+				m.EXPECT().GetMovieByID(gomock.Any(), uint64(7)).Return(httpModels.MovieResponse{}, domain.ErrNotFound)
+			},
+			expectedCode:   codes.NotFound,
+			expectedReason: "not_found",
+		},
+		{
+			name:    "Internal error",
+			movieID: 1,
+			mockBehavior: func(m *mockDomain.MockMoviesUsecase) {
+				m.EXPECT().GetMovieByID(gomock.Any(), uint64(1)).Return(httpModels.MovieResponse{}, domain.ErrCreate)
+			},
+			expectedCode:   codes.Internal,
+			expectedReason: "internal_error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cntx := gomock.NewController(t)
+			defer cntx.Finish()
+
+			movies := mockDomain.NewMockMoviesUsecase(cntx)
+			tt.mockBehavior(movies)
+
+			client := newClient(t, NewMoviesServer(movies))
+			response, err := client.GetMovie(context.Background(), &filmlibraryv1.GetMovieRequest{Id: tt.movieID})
+
+			s := status.Convert(err)
+			assert.Equal(t, tt.expectedCode, s.Code())
+			if tt.expectedResponse != nil {
+				assert.True(t, proto.Equal(tt.expectedResponse, response), response.String())
+			}
+			if tt.expectedReason != "" {
+				require.Len(t, s.Details(), 1)
+				assert.Equal(t, tt.expectedReason, s.Details()[0].(*errdetails.ErrorInfo).GetReason())
+			}
+		})
+	}
+}
+
+func TestServer_CreateMovie(t *testing.T) {
+	cntx := gomock.NewController(t)
+	defer cntx.Finish()
+
+	client := newClient(t, NewMoviesServer(mockDomain.NewMockMoviesUsecase(cntx)))
+	_, err := client.CreateMovie(context.Background(), &filmlibraryv1.CreateMovieRequest{
+		ReleaseDate: "1972-03-24",
+		Rating:      11,
+	})
+
+	s := status.Convert(err)
+	assert.Equal(t, codes.InvalidArgument, s.Code())
+	assert.Equal(t, "validation failed", s.Message())
+	require.Len(t, s.Details(), 2)
+	assert.Equal(t, "validation_failed", s.Details()[0].(*errdetails.ErrorInfo).GetReason())
+	assert.True(t, proto.Equal(&errdetails.BadRequest{FieldViolations: []*errdetails.BadRequest_FieldViolation{
+		{Field: "title", Description: "is required"},
+		{Field: "rating", Description: "must be at most 10"},
+	}}, s.Details()[1].(*errdetails.BadRequest)))
+}
@@ -2,13 +2,16 @@ package httpMovies
 
 import (
 	"encoding/json"
-	"errors"
 	"net/http"
+	"net/url"
 	"strconv"
 
 	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
 	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
-	"github.com/themilchenko/vk-tech_internship-problem_2024/pkg"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/etag"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/jsonpatch"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/problem"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/validate"
 )
 
 type ActorsHandler struct {
@@ -21,22 +24,30 @@ func NewActorsUsecase(a domain.MoviesUsecase) ActorsHandler {
 	}
 }
 
+// movieVersion looks up the version an If-Match list is matched against.
+func (h ActorsHandler) movieVersion(r *http.Request, movieID uint64) func() (uint64, error) {
+	return func() (uint64, error) {
+		movie, err := h.moviesUsecase.GetMovieByID(r.Context(), movieID)
+		return movie.Version, err
+	}
+}
+
 func (h ActorsHandler) CreateMovie(w http.ResponseWriter, r *http.Request) {
 	var receivedMovie httpModels.MovieWithIDCast
-	if err := json.NewDecoder(r.Body).Decode(&receivedMovie); err != nil {
-		pkg.HandleError(w, err.Error(), http.StatusBadRequest)
+	if err := validate.DecodeJSON(r, &receivedMovie); err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
-	movieID, err := h.moviesUsecase.CreateMovie(receivedMovie)
+	movieID, err := h.moviesUsecase.CreateMovie(r.Context(), receivedMovie)
 	if err != nil {
-		pkg.HandleError(w, err.Error(), http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
 
 	responseDate, err := json.Marshal(httpModels.ID{ID: movieID})
 	if err != nil {
-		pkg.HandleError(w, err.Error(), http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -48,16 +59,18 @@ func (h ActorsHandler) CreateMovie(w http.ResponseWriter, r *http.Request) {
 func (h ActorsHandler) DeleteMovie(w http.ResponseWriter, r *http.Request) {
 	movieID, err := strconv.ParseUint(r.PathValue("id"), 10, 64)
 	if err != nil {
-		pkg.HandleError(w, err.Error(), http.StatusBadRequest)
+		problem.Write(w, r, domain.ErrBadRequest.Wrap(err))
 		return
 	}
 
-	if err := h.moviesUsecase.DeleteMovieByID(movieID); err != nil {
-		if errors.Is(err, domain.ErrNotFound) {
-			pkg.HandleError(w, err.Error(), http.StatusNotFound)
-		} else {
-			pkg.HandleError(w, err.Error(), http.StatusInternalServerError)
-		}
+	version, err := etag.IfMatch(r, h.movieVersion(r, movieID))
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	if err := h.moviesUsecase.DeleteMovieByID(r.Context(), movieID, version); err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -69,23 +82,25 @@ func (h ActorsHandler) DeleteMovie(w http.ResponseWriter, r *http.Request) {
 func (h ActorsHandler) GetMovie(w http.ResponseWriter, r *http.Request) {
 	movieID, err := strconv.ParseUint(r.PathValue("id"), 10, 64)
 	if err != nil {
-		pkg.HandleError(w, err.Error(), http.StatusBadRequest)
+		problem.Write(w, r, domain.ErrBadRequest.Wrap(err))
 		return
 	}
 
-	movie, err := h.moviesUsecase.GetMovieByID(movieID)
+	movie, err := h.moviesUsecase.GetMovieByID(r.Context(), movieID)
 	if err != nil {
-		if errors.Is(err, domain.ErrNotFound) {
-			pkg.HandleError(w, err.Error(), http.StatusNotFound)
-		} else {
-			pkg.HandleError(w, err.Error(), http.StatusInternalServerError)
-		}
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("ETag", etag.Format(movie.Version))
+	if !etag.NoneMatch(r, movie.Version) {
+		w.WriteHeader(http.StatusNotModified)
 		return
 	}
 
 	responseData, err := json.Marshal(movie)
 	if err != nil {
-		pkg.HandleError(w, err.Error(), http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -95,39 +110,27 @@ func (h ActorsHandler) GetMovie(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h ActorsHandler) GetMovies(w http.ResponseWriter, r *http.Request) {
-	title := r.URL.Query().Get("title")
-	actor := r.URL.Query().Get("actor")
-
-	order := r.URL.Query().Get("order")
-	isOrder := true
-	if len(order) != 0 {
-		var err error
-		isOrder, err = strconv.ParseBool(order)
-		if err != nil {
-			pkg.HandleError(w, err.Error(), http.StatusBadRequest)
-			return
-		}
-	}
-
-	filter := httpModels.SortBy(r.URL.Query().Get("filter"))
-	if len(filter) == 0 {
-		filter = "rating"
-	} else {
-		if filter != "title" && filter != "rating" && filter != "releaseDate" {
-			pkg.HandleError(w, domain.ErrBadRequest.Error(), http.StatusBadRequest)
-			return
-		}
+	filter, err := ParseMoviesFilter(r.URL.Query())
+	if err != nil {
+		problem.Write(w, r, err)
+		return
 	}
 
-	movies, err := h.moviesUsecase.GetMovies(title, actor, filter, isOrder)
+	movies, err := h.moviesUsecase.GetMovies(
+		r.Context(),
+		filter.Title,
+		filter.Actor,
+		filter.SortBy,
+		filter.Order,
+	)
 	if err != nil {
-		pkg.HandleError(w, err.Error(), http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
 
 	responseData, err := json.Marshal(movies)
 	if err != nil {
-		pkg.HandleError(w, err.Error(), http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -139,28 +142,72 @@ func (h ActorsHandler) GetMovies(w http.ResponseWriter, r *http.Request) {
 func (h ActorsHandler) UpdateMovie(w http.ResponseWriter, r *http.Request) {
 	movieID, err := strconv.ParseUint(r.PathValue("id"), 10, 64)
 	if err != nil {
-		pkg.HandleError(w, err.Error(), http.StatusBadRequest)
+		problem.Write(w, r, domain.ErrBadRequest.Wrap(err))
+		return
+	}
+
+	version, err := etag.IfMatch(r, h.movieVersion(r, movieID))
+	if err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
 	var receivedMovie httpModels.MovieWithoutCastList
-	if err := json.NewDecoder(r.Body).Decode(&receivedMovie); err != nil {
-		pkg.HandleError(w, err.Error(), http.StatusBadRequest)
+	if err := validate.DecodeJSON(r, &receivedMovie); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	movie, err := h.moviesUsecase.UpdateMovie(r.Context(), receivedMovie, movieID, version)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	responseDate, err := json.Marshal(&movie)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("ETag", etag.Format(movie.Version))
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(responseDate)
+}
+
+func (h ActorsHandler) PatchMovie(w http.ResponseWriter, r *http.Request) {
+	movieID, err := strconv.ParseUint(r.PathValue("id"), 10, 64)
+	if err != nil {
+		problem.Write(w, r, domain.ErrBadRequest.Wrap(err))
+		return
+	}
+
+	version, err := etag.IfMatch(r, h.movieVersion(r, movieID))
+	if err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
-	movie, err := h.moviesUsecase.UpdateMovie(receivedMovie, movieID)
+	patch, err := jsonpatch.FromRequest(r)
 	if err != nil {
-		pkg.HandleError(w, err.Error(), http.StatusInternalServerError)
+		problem.Write(w, r, err)
+		return
+	}
+
+	movie, err := h.moviesUsecase.PatchMovie(r.Context(), movieID, version, patch)
+	if err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
 	responseDate, err := json.Marshal(&movie)
 	if err != nil {
-		pkg.HandleError(w, err.Error(), http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
 
+	w.Header().Set("ETag", etag.Format(movie.Version))
 	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
 	w.WriteHeader(http.StatusOK)
 	w.Write(responseDate)
@@ -169,17 +216,17 @@ func (h ActorsHandler) UpdateMovie(w http.ResponseWriter, r *http.Request) {
 func (h ActorsHandler) AddActorToMovie(w http.ResponseWriter, r *http.Request) {
 	movieID, err := strconv.ParseUint(r.PathValue("movieID"), 10, 64)
 	if err != nil {
-		pkg.HandleError(w, err.Error(), http.StatusBadRequest)
+		problem.Write(w, r, domain.ErrBadRequest.Wrap(err))
 		return
 	}
 	actorID, err := strconv.ParseUint(r.PathValue("actorID"), 10, 64)
 	if err != nil {
-		pkg.HandleError(w, err.Error(), http.StatusBadRequest)
+		problem.Write(w, r, domain.ErrBadRequest.Wrap(err))
 		return
 	}
 
-	if err := h.moviesUsecase.AddActorFromMovie(movieID, actorID); err != nil {
-		pkg.HandleError(w, err.Error(), http.StatusInternalServerError)
+	if err := h.moviesUsecase.AddActorFromMovie(r.Context(), movieID, actorID); err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -191,17 +238,17 @@ func (h ActorsHandler) AddActorToMovie(w http.ResponseWriter, r *http.Request) {
 func (h ActorsHandler) DeleteActorFromMoive(w http.ResponseWriter, r *http.Request) {
 	movieID, err := strconv.ParseUint(r.PathValue("movieID"), 10, 64)
 	if err != nil {
-		pkg.HandleError(w, err.Error(), http.StatusBadRequest)
+		problem.Write(w, r, domain.ErrBadRequest.Wrap(err))
 		return
 	}
 	actorID, err := strconv.ParseUint(r.PathValue("actorID"), 10, 64)
 	if err != nil {
-		pkg.HandleError(w, err.Error(), http.StatusBadRequest)
+		problem.Write(w, r, domain.ErrBadRequest.Wrap(err))
 		return
 	}
 
-	if err := h.moviesUsecase.DeleteActorFromMovie(movieID, actorID); err != nil {
-		pkg.HandleError(w, err.Error(), http.StatusInternalServerError)
+	if err := h.moviesUsecase.DeleteActorFromMovie(r.Context(), movieID, actorID); err != nil {
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -209,3 +256,110 @@ func (h ActorsHandler) DeleteActorFromMoive(w http.ResponseWriter, r *http.Reque
 	w.WriteHeader(http.StatusOK)
 	w.Write(httpModels.EmptyModel)
 }
+
+// ReplaceCast makes the actors of the body the whole cast of the movie and
+// answers with what has changed.
+func (h ActorsHandler) ReplaceCast(w http.ResponseWriter, r *http.Request) {
+	movieID, err := strconv.ParseUint(r.PathValue("id"), 10, 64)
+	if err != nil {
+		problem.Write(w, r, domain.ErrBadRequest.Wrap(err))
+		return
+	}
+
+	version, err := etag.IfMatch(r, h.movieVersion(r, movieID))
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	var cast httpModels.Cast
+	if err := validate.DecodeJSON(r, &cast); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	diff, err := h.moviesUsecase.ReplaceCast(r.Context(), movieID, version, cast)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	writeCastDiff(w, r, diff)
+}
+
+// BatchCast adds and removes the actors of the body in one go and answers
+// with what has changed.
+func (h ActorsHandler) BatchCast(w http.ResponseWriter, r *http.Request) {
+	movieID, err := strconv.ParseUint(r.PathValue("id"), 10, 64)
+	if err != nil {
+		problem.Write(w, r, domain.ErrBadRequest.Wrap(err))
+		return
+	}
+
+	version, err := etag.IfMatch(r, h.movieVersion(r, movieID))
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	var batch httpModels.CastBatch
+	if err := validate.DecodeJSON(r, &batch); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	diff, err := h.moviesUsecase.BatchCast(r.Context(), movieID, version, batch)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	writeCastDiff(w, r, diff)
+}
+
+// writeCastDiff answers with the diff, empty lists are [] and not null.
+func writeCastDiff(w http.ResponseWriter, r *http.Request, diff httpModels.CastDiff) {
+	for _, ids := range []*[]uint64{&diff.Added, &diff.Removed, &diff.Unchanged} {
+		if *ids == nil {
+			*ids = []uint64{}
+		}
+	}
+
+	responseData, err := json.Marshal(diff)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("ETag", etag.Format(diff.Version))
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(responseData)
+}
+
+// ParseMoviesFilter reads the title, actor, filter and order parameters of
+// GET /movies. Movies are sorted by rating in ascending order by default.
+func ParseMoviesFilter(query url.Values) (httpModels.MoviesFilter, error) {
+	filter := httpModels.MoviesFilter{
+		Title:  query.Get("title"),
+		Actor:  query.Get("actor"),
+		SortBy: httpModels.SortBy(query.Get("filter")),
+		Order:  true,
+	}
+
+	if order := query.Get("order"); len(order) != 0 {
+		var err error
+		filter.Order, err = strconv.ParseBool(order)
+		if err != nil {
+			return httpModels.MoviesFilter{}, domain.ErrBadRequest.Wrap(err)
+		}
+	}
+
+	if len(filter.SortBy) == 0 {
+		filter.SortBy = "rating"
+	} else {
+		if filter.SortBy != "title" && filter.SortBy != "rating" && filter.SortBy != "releaseDate" {
+			return httpModels.MoviesFilter{}, domain.ErrBadRequest
+		}
+	}
+
+	return filter, nil
+}
@@ -6,15 +6,32 @@ import (
 	"net/http/httptest"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/docs"
 	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
 	mockDomain "github.com/themilchenko/vk-tech_internship-problem_2024/internal/mocks/domain"
 	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/openapi"
 	"go.uber.org/mock/gomock"
 )
 
+var loadSpec = sync.OnceValues(func() (*openapi.Spec, error) {
+	return openapi.Load(docs.Swagger)
+})
+
+// strict checks the handlers behind mux against the spec, as the API is
+// checked in tests.
+func strict(t *testing.T, mux http.Handler) http.Handler {
+	spec, err := loadSpec()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return spec.Strict(mux)
+}
+
 func TestHandler_CreateMovie(t *testing.T) {
 	type mockBehavior func(r *mockDomain.MockMoviesUsecase, movie httpModels.MovieWithIDCast)
 
@@ -37,7 +54,7 @@ func TestHandler_CreateMovie(t *testing.T) {
 			},
 			mockBehavior: func(m *mockDomain.MockMoviesUsecase, movie httpModels.MovieWithIDCast) {
 				m.EXPECT().
-					CreateMovie(movie).
+					CreateMovie(gomock.Any(), movie).
 					Return(uint64(1), nil)
 			},
 			expectedStatusCode:   http.StatusOK,
@@ -54,11 +71,11 @@ func TestHandler_CreateMovie(t *testing.T) {
 			},
 			mockBehavior: func(m *mockDomain.MockMoviesUsecase, movie httpModels.MovieWithIDCast) {
 				m.EXPECT().
-					CreateMovie(movie).
+					CreateMovie(gomock.Any(), movie).
 					Return(uint64(0), domain.ErrInternal)
 			},
 			expectedStatusCode:   http.StatusInternalServerError,
-			expectedResponseBody: `{"error":"server error"}`,
+			expectedResponseBody: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"server error","code":"internal_error"}`,
 		},
 		{
 			name:                 "Bad request",
@@ -66,7 +83,14 @@ func TestHandler_CreateMovie(t *testing.T) {
 			inputMovie:           httpModels.MovieWithIDCast{},
 			mockBehavior:         func(m *mockDomain.MockMoviesUsecase, movie httpModels.MovieWithIDCast) {},
 			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: `{"error":"invalid character 'd' looking for beginning of value"}`,
+			expectedResponseBody: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"failed to unmarshal json: invalid character 'd' looking for beginning of value","code":"invalid_json"}`,
+		},
+		{
+			name:                 "Validation failed",
+			inputBody:            `{"title":"","releaseDate":"24.03.1972","rating":11}`,
+			mockBehavior:         func(m *mockDomain.MockMoviesUsecase, movie httpModels.MovieWithIDCast) {},
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"validation failed","code":"validation_failed","violations":[{"field":"title","rule":"required","message":"is required"},{"field":"releaseDate","rule":"date","message":"must be a date in YYYY-MM-DD format"},{"field":"rating","rule":"max","message":"must be at most 10"}]}`,
 		},
 	}
 
@@ -86,7 +110,7 @@ func TestHandler_CreateMovie(t *testing.T) {
 			w := httptest.NewRecorder()
 			req := httptest.NewRequest("POST", "/movies", strings.NewReader(tt.inputBody))
 
-			mux.ServeHTTP(w, req)
+			strict(t, mux).ServeHTTP(w, req)
 
 			assert.Equal(t, tt.expectedStatusCode, w.Code)
 			assert.Equal(t, tt.expectedResponseBody, strings.Trim(w.Body.String(), "\n"))
@@ -105,9 +129,11 @@ func TestHandler_DeleteMovie(t *testing.T) {
 		expectedResponseBody string
 	}{
 		{
-			name:                 "Successful movie deletion",
-			inputID:              "1",
-			mockBehavior:         func(m *mockDomain.MockMoviesUsecase, id uint64) { m.EXPECT().DeleteMovieByID(id).Return(nil) },
+			name:    "Successful movie deletion",
+			inputID: "1",
+			mockBehavior: func(m *mockDomain.MockMoviesUsecase, id uint64) {
+				m.EXPECT().DeleteMovieByID(gomock.Any(), id, uint64(0)).Return(nil)
+			},
 			expectedStatusCode:   http.StatusOK,
 			expectedResponseBody: "{}",
 		},
@@ -116,25 +142,25 @@ func TestHandler_DeleteMovie(t *testing.T) {
 			inputID:              "abs",
 			mockBehavior:         func(m *mockDomain.MockMoviesUsecase, id uint64) {},
 			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: `{"error":"strconv.ParseUint: parsing \"abs\": invalid syntax"}`,
+			expectedResponseBody: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"bad request: strconv.ParseUint: parsing \"abs\": invalid syntax","code":"bad_request"}`,
 		},
 		{
 			name:    "Err delete",
 			inputID: "2",
 			mockBehavior: func(m *mockDomain.MockMoviesUsecase, id uint64) {
-				m.EXPECT().DeleteMovieByID(id).Return(domain.ErrNotFound)
+				m.EXPECT().DeleteMovieByID(gomock.Any(), id, uint64(0)).Return(domain.ErrNotFound)
 			},
 			expectedStatusCode:   http.StatusNotFound,
-			expectedResponseBody: `{"error":"failed to find item"}`,
+			expectedResponseBody: `{"type":"about:blank","title":"Not Found","status":404,"detail":"failed to find item","code":"not_found"}`,
 		},
 		{
 			name:    "Internal error",
 			inputID: "2",
 			mockBehavior: func(m *mockDomain.MockMoviesUsecase, id uint64) {
-				m.EXPECT().DeleteMovieByID(id).Return(domain.ErrInternal)
+				m.EXPECT().DeleteMovieByID(gomock.Any(), id, uint64(0)).Return(domain.ErrInternal)
 			},
 			expectedStatusCode:   http.StatusInternalServerError,
-			expectedResponseBody: `{"error":"server error"}`,
+			expectedResponseBody: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"server error","code":"internal_error"}`,
 		},
 	}
 
@@ -155,7 +181,7 @@ func TestHandler_DeleteMovie(t *testing.T) {
 			w := httptest.NewRecorder()
 			req := httptest.NewRequest("DELETE", "/movies/"+tt.inputID, nil)
 
-			mux.ServeHTTP(w, req)
+			strict(t, mux).ServeHTTP(w, req)
 
 			assert.Equal(t, tt.expectedStatusCode, w.Code)
 			assert.Equal(t, tt.expectedResponseBody, strings.Trim(w.Body.String(), "\n"))
@@ -177,7 +203,7 @@ func TestHandler_GetMovie(t *testing.T) {
 			name:    "Successful movie get",
 			inputID: "1",
 			mockBehavior: func(m *mockDomain.MockMoviesUsecase, id uint64) {
-				m.EXPECT().GetMovieByID(id).Return(httpModels.MovieResponse{
+				m.EXPECT().GetMovieByID(gomock.Any(), id).Return(httpModels.MovieResponse{
 					ID:          1,
 					Title:       "The Godfather",
 					Description: "description",
@@ -194,25 +220,25 @@ func TestHandler_GetMovie(t *testing.T) {
 			inputID:              "a",
 			mockBehavior:         func(m *mockDomain.MockMoviesUsecase, id uint64) {},
 			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: `{"error":"strconv.ParseUint: parsing \"a\": invalid syntax"}`,
+			expectedResponseBody: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"bad request: strconv.ParseUint: parsing \"a\": invalid syntax","code":"bad_request"}`,
 		},
 		{
 			name:    "Error Not Found",
 			inputID: "1",
 			mockBehavior: func(m *mockDomain.MockMoviesUsecase, id uint64) {
-				m.EXPECT().GetMovieByID(id).Return(httpModels.MovieResponse{}, domain.ErrNotFound)
+				m.EXPECT().GetMovieByID(gomock.Any(), id).Return(httpModels.MovieResponse{}, domain.ErrNotFound)
 			},
 			expectedStatusCode:   http.StatusNotFound,
-			expectedResponseBody: `{"error":"failed to find item"}`,
+			expectedResponseBody: `{"type":"about:blank","title":"Not Found","status":404,"detail":"failed to find item","code":"not_found"}`,
 		},
 		{
 			name:    "Intenal Error",
 			inputID: "1",
 			mockBehavior: func(m *mockDomain.MockMoviesUsecase, id uint64) {
-				m.EXPECT().GetMovieByID(id).Return(httpModels.MovieResponse{}, domain.ErrInternal)
+				m.EXPECT().GetMovieByID(gomock.Any(), id).Return(httpModels.MovieResponse{}, domain.ErrInternal)
 			},
 			expectedStatusCode:   http.StatusInternalServerError,
-			expectedResponseBody: `{"error":"server error"}`,
+			expectedResponseBody: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"server error","code":"internal_error"}`,
 		},
 	}
 
@@ -234,7 +260,7 @@ func TestHandler_GetMovie(t *testing.T) {
 			w := httptest.NewRecorder()
 			req := httptest.NewRequest("GET", "/movies/"+tt.inputID, nil)
 
-			mux.ServeHTTP(w, req)
+			strict(t, mux).ServeHTTP(w, req)
 
 			assert.Equal(t, tt.expectedStatusCode, w.Code)
 			assert.Equal(t, tt.expectedResponseBody, strings.Trim(w.Body.String(), "\n"))
@@ -265,7 +291,7 @@ func TestHandler_GetMovies(t *testing.T) {
 			order:        "",
 			mockBehavior: func(m *mockDomain.MockMoviesUsecase, title, actor string, filter httpModels.SortBy, isOrder bool) {
 				m.EXPECT().
-					GetMovies(title, actor, filter, isOrder).
+					GetMovies(gomock.Any(), title, actor, filter, isOrder).
 					Return([]httpModels.MovieResponse{
 						{
 							ID:          1,
@@ -289,7 +315,7 @@ func TestHandler_GetMovies(t *testing.T) {
 			order:                "a",
 			mockBehavior:         func(m *mockDomain.MockMoviesUsecase, title, actor string, filter httpModels.SortBy, isOrder bool) {},
 			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: `{"error":"strconv.ParseBool: parsing \"a\": invalid syntax"}`,
+			expectedResponseBody: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"bad request: strconv.ParseBool: parsing \"a\": invalid syntax","code":"bad_request"}`,
 		},
 		{
 			name:                 "Error Bad Request Filter",
@@ -300,7 +326,7 @@ func TestHandler_GetMovies(t *testing.T) {
 			order:                "",
 			mockBehavior:         func(m *mockDomain.MockMoviesUsecase, title, actor string, filter httpModels.SortBy, isOrder bool) {},
 			expectedStatusCode:   http.StatusBadRequest,
-			expectedResponseBody: `{"error":"bad request"}`,
+			expectedResponseBody: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"bad request","code":"bad_request"}`,
 		},
 		{
 			name:         "Internal Error",
@@ -311,11 +337,11 @@ func TestHandler_GetMovies(t *testing.T) {
 			order:        "",
 			mockBehavior: func(m *mockDomain.MockMoviesUsecase, title, actor string, filter httpModels.SortBy, isOrder bool) {
 				m.EXPECT().
-					GetMovies(title, actor, filter, isOrder).
+					GetMovies(gomock.Any(), title, actor, filter, isOrder).
 					Return([]httpModels.MovieResponse{}, domain.ErrInternal)
 			},
 			expectedStatusCode:   http.StatusInternalServerError,
-			expectedResponseBody: `{"error":"server error"}`,
+			expectedResponseBody: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"server error","code":"internal_error"}`,
 		},
 	}
 
@@ -357,7 +383,7 @@ func TestHandler_GetMovies(t *testing.T) {
 			v.Add("filter", fmt.Sprint(tt.sortBy))
 			req.URL.RawQuery = v.Encode()
 
-			mux.ServeHTTP(w, req)
+			strict(t, mux).ServeHTTP(w, req)
 
 			assert.Equal(t, tt.expectedStatusCode, w.Code)
 			assert.Equal(t, tt.expectedResponseBody, strings.Trim(w.Body.String(), "\n"))
@@ -389,7 +415,7 @@ func TestHandler_UpdateMovie(t *testing.T) {
 			},
 			mockBehavior: func(m *mockDomain.MockMoviesUsecase, movie httpModels.MovieWithoutCastList, id uint64) {
 				m.EXPECT().
-					UpdateMovie(movie, id).
+					UpdateMovie(gomock.Any(), movie, id, uint64(0)).
 					Return(httpModels.MovieResponse{}, nil)
 			},
 			expectedStatusCode:   http.StatusOK,
@@ -413,7 +439,7 @@ func TestHandler_UpdateMovie(t *testing.T) {
 			w := httptest.NewRecorder()
 			req := httptest.NewRequest("PUT", "/movies/1", strings.NewReader(tt.inputBody))
 
-			mux.ServeHTTP(w, req)
+			strict(t, mux).ServeHTTP(w, req)
 
 			assert.Equal(t, tt.expectedStatusCode, w.Code)
 			assert.Equal(t, tt.expectedResponseBody, strings.Trim(w.Body.String(), "\n"))
@@ -437,7 +463,7 @@ func TestHandler_AddActorToMovie(t *testing.T) {
 			inputMovieID: 1,
 			inputActorID: 1,
 			mockBehavior: func(m *mockDomain.MockMoviesUsecase, movieID, actorID uint64) {
-				m.EXPECT().AddActorFromMovie(movieID, actorID).Return(nil)
+				m.EXPECT().AddActorFromMovie(gomock.Any(), movieID, actorID).Return(nil)
 			},
 			expectedStatusCode:   http.StatusOK,
 			expectedResponseBody: "{}",
@@ -460,7 +486,7 @@ func TestHandler_AddActorToMovie(t *testing.T) {
 			w := httptest.NewRecorder()
 			req := httptest.NewRequest("POST", "/movies/1/actors/1", nil)
 
-			mux.ServeHTTP(w, req)
+			strict(t, mux).ServeHTTP(w, req)
 
 			assert.Equal(t, tt.expectedStatusCode, w.Code)
 			assert.Equal(t, tt.expectedResponseBody, strings.Trim(w.Body.String(), "\n"))
@@ -484,7 +510,7 @@ func TestHandler_DeleteActorFromMovie(t *testing.T) {
 			inputMovieID: 1,
 			inputActorID: 1,
 			mockBehavior: func(m *mockDomain.MockMoviesUsecase, movieID, actorID uint64) {
-				m.EXPECT().DeleteActorFromMovie(movieID, actorID).Return(nil)
+				m.EXPECT().DeleteActorFromMovie(gomock.Any(), movieID, actorID).Return(nil)
 			},
 			expectedStatusCode:   http.StatusOK,
 			expectedResponseBody: "{}",
@@ -510,9 +536,129 @@ func TestHandler_DeleteActorFromMovie(t *testing.T) {
 			w := httptest.NewRecorder()
 			req := httptest.NewRequest("DELETE", "/movies/1/actors/1", nil)
 
-			mux.ServeHTTP(w, req)
+			strict(t, mux).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, strings.Trim(w.Body.String(), "\n"))
+		})
+	}
+}
+
+func TestHandler_CastBatch(t *testing.T) {
+	type mockBehavior func(r *mockDomain.MockMoviesUsecase)
+
+	tests := []struct {
+		name                 string
+		method               string
+		path                 string
+		ifMatch              string
+		inputBody            string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedETag         string
+		expectedResponseBody string
+	}{
+		{
+			name:      "Cast replaced",
+			method:    http.MethodPut,
+			path:      "/movies/1/cast",
+			ifMatch:   `"4"`,
+			inputBody: `{"actorIDs":[3,1]}`,
+			mockBehavior: func(m *mockDomain.MockMoviesUsecase) {
+				m.EXPECT().
+					ReplaceCast(gomock.Any(), uint64(1), uint64(4), httpModels.Cast{ActorIDs: []uint64{3, 1}}).
+					Return(httpModels.CastDiff{
+						Added:     []uint64{3},
+						Removed:   []uint64{2},
+						Unchanged: []uint64{1},
+						Version:   5,
+					}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedETag:         `"5"`,
+			expectedResponseBody: `{"added":[3],"removed":[2],"unchanged":[1]}`,
+		},
+		{
+			name:      "Batch applied",
+			method:    http.MethodPost,
+			path:      "/movies/1/cast:batch",
+			inputBody: `{"add":[4],"remove":[2]}`,
+			mockBehavior: func(m *mockDomain.MockMoviesUsecase) {
+				m.EXPECT().
+					BatchCast(gomock.Any(), uint64(1), uint64(0), httpModels.CastBatch{
+						Add:    []uint64{4},
+						Remove: []uint64{2},
+					}).
+					Return(httpModels.CastDiff{
+						Added:     []uint64{4},
+						Removed:   []uint64{2},
+						Unchanged: []uint64{},
+						Version:   2,
+					}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedETag:         `"2"`,
+			expectedResponseBody: `{"added":[4],"removed":[2],"unchanged":[]}`,
+		},
+		{
+			name:      "Unknown actor",
+			method:    http.MethodPost,
+			path:      "/movies/1/cast:batch",
+			inputBody: `{"add":[8]}`,
+			mockBehavior: func(m *mockDomain.MockMoviesUsecase) {
+				m.EXPECT().
+					BatchCast(gomock.Any(), uint64(1), uint64(0), httpModels.CastBatch{Add: []uint64{8}}).
+					Return(httpModels.CastDiff{}, domain.ValidationError{Violations: []domain.FieldError{
+						{Field: "add[0]", Rule: "exists", Message: "actor 8 not found"},
+					}})
+			},
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"validation failed","code":"validation_failed","violations":[{"field":"add[0]","rule":"exists","message":"actor 8 not found"}]}`,
+		},
+		{
+			name:                 "Wrong type",
+			method:               http.MethodPut,
+			path:                 "/movies/1/cast",
+			inputBody:            `{"actorIDs":["John"]}`,
+			mockBehavior:         func(m *mockDomain.MockMoviesUsecase) {},
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedResponseBody: `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"validation failed","code":"validation_failed","violations":[{"field":"actorIDs.0","rule":"type","message":"must be an integer"}]}`,
+		},
+		{
+			name:                 "Bad movie id",
+			method:               http.MethodPost,
+			path:                 "/movies/abc/cast:batch",
+			inputBody:            `{}`,
+			mockBehavior:         func(m *mockDomain.MockMoviesUsecase) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"bad request: strconv.ParseUint: parsing \"abc\": invalid syntax","code":"bad_request"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cntx := gomock.NewController(t)
+			defer cntx.Finish()
+
+			mockMoviesUsecase := mockDomain.NewMockMoviesUsecase(cntx)
+			tt.mockBehavior(mockMoviesUsecase)
+
+			handler := NewActorsUsecase(mockMoviesUsecase)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("PUT /movies/{id}/cast", handler.ReplaceCast)
+			mux.HandleFunc("POST /movies/{id}/cast:batch", handler.BatchCast)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.inputBody))
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+
+			strict(t, mux).ServeHTTP(w, req)
 
 			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedETag, w.Header().Get("ETag"))
 			assert.Equal(t, tt.expectedResponseBody, strings.Trim(w.Body.String(), "\n"))
 		})
 	}
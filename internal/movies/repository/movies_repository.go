@@ -1,25 +1,60 @@
 package moviesRepository
 
 import (
+	"context"
+	"log/slog"
+
+	auditRepository "github.com/themilchenko/vk-tech_internship-problem_2024/internal/audit/repository"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
 	gormModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/gorm"
 	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	revisionsRepository "github.com/themilchenko/vk-tech_internship-problem_2024/internal/revisions/repository"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/dbtx"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/metrics"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/pgerrors"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/sqlcomment"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/tracing"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	gormLogger "gorm.io/gorm/logger"
+)
+
+const (
+	movieEntity = "movie"
+	castEntity  = "cast"
 )
 
 type Postgres struct {
 	DB *gorm.DB
 }
 
-func NewPostgres(url string) (*Postgres, error) {
-	db, err := gorm.Open(postgres.Open(url), &gorm.Config{})
+func NewPostgres(url string, logger gormLogger.Interface) (*Postgres, error) {
+	db, err := gorm.Open(postgres.Open(url), &gorm.Config{Logger: logger})
 	if err != nil {
 		return nil, err
 	}
+	if err = db.Use(pgerrors.Plugin{}); err != nil {
+		return nil, err
+	}
+	if err = db.Use(dbtx.Plugin{}); err != nil {
+		return nil, err
+	}
+	if err = db.Use(sqlcomment.Plugin{}); err != nil {
+		return nil, err
+	}
+	if err = db.Use(metrics.Plugin{DB: "movies"}); err != nil {
+		return nil, err
+	}
+	if err = db.Use(tracing.Plugin{DB: "movies"}); err != nil {
+		return nil, err
+	}
 
 	db.AutoMigrate(
 		gormModels.Movie{},
 		gormModels.ActorMovieRelation{},
+		gormModels.AuditEntry{},
+		gormModels.Revision{},
 	)
 
 	return &Postgres{
@@ -27,53 +62,109 @@ func NewPostgres(url string) (*Postgres, error) {
 	}, nil
 }
 
-func (db Postgres) CreateMovieWithoutCastList(movie gormModels.Movie) (uint64, error) {
-	var recievedMovie gormModels.Movie
-	if err := db.DB.Create(&movie).Scan(&recievedMovie).Error; err != nil {
-		return 0, err
-	}
-	return recievedMovie.ID, nil
+func (db Postgres) CreateMovieWithoutCastList(
+	ctx context.Context,
+	movie gormModels.Movie,
+) (uint64, error) {
+	return db.CreateMovieWithCastList(ctx, movie, nil)
 }
 
 func (db Postgres) CreateMovieWithCastList(
+	ctx context.Context,
 	movie gormModels.Movie,
 	castList []uint64,
 ) (uint64, error) {
-	tx := db.DB.Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
+	if err := db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&movie).Error; err != nil {
+			return err
+		}
+		if err := auditRepository.Record(ctx, tx, movieEntity, movie.ID, nil, movie); err != nil {
+			return err
+		}
+		if err := revisionsRepository.Record(
+			ctx, tx, domain.MovieEntity, movie.ID, movie.ToHTTPMovies(), false,
+		); err != nil {
+			return err
 		}
-	}()
-
-	movieID, err := db.CreateMovieWithoutCastList(movie)
-	if err != nil {
-		return 0, err
-	}
 
-	for _, actorID := range castList {
-		if err := db.AddActorToMovie(movieID, actorID); err != nil {
-			return 0, err
+		for _, actorID := range castList {
+			if err := addActorToMovie(ctx, tx, movie.ID, actorID); err != nil {
+				return err
+			}
 		}
+		return nil
+	}); err != nil {
+		return 0, err
 	}
+	return movie.ID, nil
+}
 
-	return movieID, tx.Commit().Error
+func (db Postgres) UpdateMovie(
+	ctx context.Context,
+	movie gormModels.Movie,
+) (gormModels.Movie, error) {
+	return db.PatchMovie(ctx, movie.ID, movie.Version, func(m *gormModels.Movie) error {
+		m.Title = movie.Title
+		m.Description = movie.Description
+		m.ReleaseDate = movie.ReleaseDate
+		m.Rating = movie.Rating
+		return nil
+	})
 }
 
-func (db Postgres) UpdateMovie(movie gormModels.Movie) (gormModels.Movie, error) {
+// PatchMovie locks the movie and saves what patch made of it, both in one
+// transaction. Every column is written, so zero values are saved too. A
+// non-zero version has to be the current one.
+func (db Postgres) PatchMovie(
+	ctx context.Context,
+	movieID, version uint64,
+	patch func(movie *gormModels.Movie) error,
+) (gormModels.Movie, error) {
 	var recievedMovie gormModels.Movie
-	if err := db.DB.Model(&gormModels.Movie{ID: movie.ID}).
-		Updates(movie).
-		Scan(&recievedMovie).
-		Error; err != nil {
+	if err := db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// The row lock keeps concurrent updates from racing for the
+		// same revision number.
+		var before gormModels.Movie
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&before, "id = ?", movieID).
+			Error; err != nil {
+			return err
+		}
+		if version != 0 && before.Version != version {
+			slog.DebugContext(ctx, "version mismatch", "movie_id", movieID, "expected", version, "current", before.Version)
+			return domain.ErrVersionMismatch
+		}
+
+		after := before
+		if err := patch(&after); err != nil {
+			return err
+		}
+		after.Version = before.Version + 1
+		if err := tx.Model(&gormModels.Movie{ID: movieID}).
+			Select("title", "description", "release_date", "rating", "version").
+			Updates(&after).
+			Error; err != nil {
+			return err
+		}
+
+		if err := tx.First(&recievedMovie, "id = ?", movieID).Error; err != nil {
+			return err
+		}
+		if err := auditRepository.Record(ctx, tx, movieEntity, movieID, before, recievedMovie); err != nil {
+			return err
+		}
+		return revisionsRepository.Record(
+			ctx, tx, domain.MovieEntity, movieID, recievedMovie.ToHTTPMovies(), false,
+		)
+	}); err != nil {
 		return gormModels.Movie{}, err
 	}
 	return recievedMovie, nil
 }
 
-func (db Postgres) GetMovieByID(movieID uint64) (gormModels.Movie, error) {
+func (db Postgres) GetMovieByID(ctx context.Context, movieID uint64) (gormModels.Movie, error) {
 	var recievedMovie gormModels.Movie
-	if err := db.DB.First(&gormModels.Movie{ID: movieID}).
+	if err := db.DB.WithContext(ctx).First(&gormModels.Movie{ID: movieID}).
 		Scan(&recievedMovie).
 		Error; err != nil {
 		return gormModels.Movie{}, err
@@ -81,40 +172,177 @@ func (db Postgres) GetMovieByID(movieID uint64) (gormModels.Movie, error) {
 	return recievedMovie, nil
 }
 
-func (db Postgres) DeleteMovieByID(movieID uint64) error {
-	if err := db.DB.Unscoped().
-		Delete(&gormModels.Movie{}, "id = ?", movieID).
-		Error; err != nil {
-		return err
+func (db Postgres) DeleteMovieByID(ctx context.Context, movieID, version uint64) error {
+	return db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var before gormModels.Movie
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&before, "id = ?", movieID).
+			Error; err != nil {
+			return err
+		}
+		if version != 0 && before.Version != version {
+			slog.DebugContext(ctx, "version mismatch", "movie_id", movieID, "expected", version, "current", before.Version)
+			return domain.ErrVersionMismatch
+		}
+
+		// The cast links go to the trash with the movie, they are recorded too.
+		var relations []gormModels.ActorMovieRelation
+		if err := tx.Where("movie_id = ?", movieID).Find(&relations).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&before).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("movie_id = ?", movieID).
+			Delete(&gormModels.ActorMovieRelation{}).
+			Error; err != nil {
+			return err
+		}
+
+		for _, relation := range relations {
+			if err := auditRepository.Record(ctx, tx, castEntity, movieID, relation, nil); err != nil {
+				return err
+			}
+		}
+		if err := auditRepository.Record(ctx, tx, movieEntity, movieID, before, nil); err != nil {
+			return err
+		}
+		return revisionsRepository.Record(ctx, tx, domain.MovieEntity, movieID, before.ToHTTPMovies(), true)
+	})
+}
+
+func (db Postgres) AddActorToMovie(ctx context.Context, movieID, actorID uint64) error {
+	return db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := addActorToMovie(ctx, tx, movieID, actorID); err != nil {
+			return err
+		}
+		return TouchMovies(tx, movieID)
+	})
+}
+
+func (db Postgres) DeleteActorFromMovie(ctx context.Context, movieID, actorID uint64) error {
+	return db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var relation gormModels.ActorMovieRelation
+		if err := tx.First(&relation, "movie_id = ? AND actor_id = ?", movieID, actorID).
+			Error; err != nil {
+			return err
+		}
+
+		if err := tx.Unscoped().Delete(&relation).Error; err != nil {
+			return err
+		}
+		if err := TouchMovies(tx, movieID); err != nil {
+			return err
+		}
+		return auditRepository.Record(ctx, tx, castEntity, movieID, relation, nil)
+	})
+}
+
+func (db Postgres) ChangeCast(
+	ctx context.Context,
+	movieID, version uint64,
+	change func(cast []uint64) []uint64,
+) (gormModels.CastChange, error) {
+	var castChange gormModels.CastChange
+	if err := db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// The row lock keeps concurrent changes of the cast from adding
+		// the same actor twice.
+		var movie gormModels.Movie
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&movie, "id = ?", movieID).
+			Error; err != nil {
+			return err
+		}
+		if version != 0 && movie.Version != version {
+			slog.DebugContext(ctx, "version mismatch", "movie_id", movieID, "expected", version, "current", movie.Version)
+			return domain.ErrVersionMismatch
+		}
+
+		var relations []gormModels.ActorMovieRelation
+		if err := tx.Where("movie_id = ?", movieID).Order("id").Find(&relations).Error; err != nil {
+			return err
+		}
+		cast := make([]uint64, len(relations))
+		current := make(map[uint64]bool, len(relations))
+		for i, relation := range relations {
+			cast[i] = relation.ActorID
+			current[relation.ActorID] = true
+		}
+
+		wanted := make(map[uint64]bool)
+		for _, actorID := range change(cast) {
+			if !wanted[actorID] && !current[actorID] {
+				castChange.Added = append(castChange.Added, actorID)
+			}
+			wanted[actorID] = true
+		}
+
+		for _, relation := range relations {
+			if wanted[relation.ActorID] {
+				castChange.Unchanged = append(castChange.Unchanged, relation.ActorID)
+				continue
+			}
+			if err := tx.Unscoped().Delete(&relation).Error; err != nil {
+				return err
+			}
+			if err := auditRepository.Record(ctx, tx, castEntity, movieID, relation, nil); err != nil {
+				return err
+			}
+			castChange.Removed = append(castChange.Removed, relation.ActorID)
+		}
+		for _, actorID := range castChange.Added {
+			if err := addActorToMovie(ctx, tx, movieID, actorID); err != nil {
+				return err
+			}
+		}
+
+		castChange.Version = movie.Version
+		if len(castChange.Added) == 0 && len(castChange.Removed) == 0 {
+			return nil
+		}
+		castChange.Version++
+		return TouchMovies(tx, movieID)
+	}); err != nil {
+		return gormModels.CastChange{}, err
 	}
-	return nil
+	return castChange, nil
 }
 
-func (db Postgres) AddActorToMovie(movieID, actorID uint64) error {
-	if err := db.DB.Create(&gormModels.ActorMovieRelation{
-		MovieID: movieID,
-		ActorID: actorID,
-	}).Error; err != nil {
-		return err
+// TouchMovies bumps the version of movies whose cast or cast members have
+// changed, so their old ETags stop matching. It has to be called with the transaction of the
+// change.
+func TouchMovies(tx *gorm.DB, movieIDs ...uint64) error {
+	if len(movieIDs) == 0 {
+		return nil
 	}
-	return nil
+	return tx.Session(&gorm.Session{NewDB: true}).
+		Unscoped().
+		Model(&gormModels.Movie{}).
+		Where("id IN ?", movieIDs).
+		UpdateColumn("version", gorm.Expr("version + 1")).
+		Error
 }
 
-func (db Postgres) DeleteActorFromMovie(movieID, actorID uint64) error {
-	if err := db.DB.Unscoped().Delete(&gormModels.ActorMovieRelation{
+func addActorToMovie(ctx context.Context, tx *gorm.DB, movieID, actorID uint64) error {
+	relation := gormModels.ActorMovieRelation{
 		MovieID: movieID,
 		ActorID: actorID,
-	}).Error; err != nil {
+	}
+	if err := tx.Create(&relation).Error; err != nil {
 		return err
 	}
-	return nil
+	return auditRepository.Record(ctx, tx, castEntity, movieID, nil, relation)
 }
 
-func (db Postgres) GetMoviesOfActor(actorID uint64) ([]gormModels.Movie, error) {
+func (db Postgres) GetMoviesOfActor(
+	ctx context.Context,
+	actorID uint64,
+) ([]gormModels.Movie, error) {
 	var recievedMovies []gormModels.Movie
-	if err := db.DB.Model(&gormModels.Movie{}).
+	if err := db.DB.WithContext(ctx).Model(&gormModels.Movie{}).
 		Joins("JOIN actor_movie_relations ON actor_movie_relations.movie_id=movies.id").
-		Where("actor_id = ?", actorID).
+		Where("actor_id = ? AND actor_movie_relations.deleted_at IS NULL", actorID).
 		Select("movies.id, movies.title, movies.description, movies.release_date, movies.rating").
 		Find(&recievedMovies).
 		Error; err != nil {
@@ -124,39 +352,59 @@ func (db Postgres) GetMoviesOfActor(actorID uint64) ([]gormModels.Movie, error)
 }
 
 func (db Postgres) GetMovies(
+	ctx context.Context,
 	title, actorName string,
 	sortBy httpModels.SortBy,
 	order bool,
 ) ([]gormModels.Movie, error) {
 	var movies []gormModels.Movie
 
-	query := db.DB.Model(&gormModels.Movie{})
+	query := FilterMovies(
+		db.DB.WithContext(ctx).Model(&gormModels.Movie{}),
+		title,
+		actorName,
+		sortBy,
+		order,
+	)
+
+	if err := query.Find(&movies).Error; err != nil {
+		return nil, err
+	}
+
+	return movies, nil
+}
+
+// sortColumns are the columns of the filter values of GET /movies.
+var sortColumns = map[httpModels.SortBy]string{
+	"title":       "movies.title",
+	"rating":      "movies.rating",
+	"releaseDate": "movies.release_date",
+}
 
+// FilterMovies narrows and orders a query of movies by the parameters of
+// GET /movies.
+func FilterMovies(
+	query *gorm.DB,
+	title, actorName string,
+	sortBy httpModels.SortBy,
+	order bool,
+) *gorm.DB {
 	if title != "" {
 		query = query.Where("title LIKE ?", "%"+title+"%")
 	}
 	if actorName != "" {
 		query = query.Joins("JOIN actor_movie_relations ON movies.id = actor_movie_relations.movie_id").
 			Joins("JOIN actors ON actor_movie_relations.actor_id = actors.id").
+			Where("actor_movie_relations.deleted_at IS NULL AND actors.deleted_at IS NULL").
 			Where("actors.name LIKE ?", "%"+actorName+"%")
 	}
 
-	switch sortBy {
-	case "title":
-		query = query.Order("title")
-	case "rating":
-		query = query.Order("rating")
-	case "release_date":
-		query = query.Order("release_date")
-	}
-
-	if !order {
-		query = query.Order(sortBy + " DESC")
-	}
-
-	if err := query.Find(&movies).Error; err != nil {
-		return nil, err
+	if column, ok := sortColumns[sortBy]; ok {
+		if !order {
+			column += " DESC"
+		}
+		query = query.Order(column)
 	}
 
-	return movies, nil
+	return query
 }
@@ -1,10 +1,13 @@
 package moviesRepository
 
 import (
+	"context"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	gormModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/gorm"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
 	sqlmock "github.com/zhashkevych/go-sqlxmock"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -58,7 +61,7 @@ func TestRepository_CreateMovieWithCastList(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			test.mockBehavior(test.movie, test.castList, test.movieID)
 
-			_, err := r.CreateMovieWithCastList(test.movie, test.castList)
+			_, err := r.CreateMovieWithCastList(context.Background(), test.movie, test.castList)
 			if test.isResponseError {
 				assert.Error(t, err)
 			} else {
@@ -67,3 +70,56 @@ func TestRepository_CreateMovieWithCastList(t *testing.T) {
 		})
 	}
 }
+
+func TestRepository_FilterMovies(t *testing.T) {
+	db, _, err := sqlmock.New()
+	assert.NoError(t, err)
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{
+		Conn: db,
+	}), &gorm.Config{DryRun: true})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		sortBy        httpModels.SortBy
+		order         bool
+		expectedOrder string
+	}{
+		{
+			name:          "Ascending",
+			sortBy:        "rating",
+			order:         true,
+			expectedOrder: "ORDER BY movies.rating",
+		},
+		{
+			name:          "Descending release date",
+			sortBy:        "releaseDate",
+			order:         false,
+			expectedOrder: "ORDER BY movies.release_date DESC",
+		},
+		{
+			name:          "Unsorted",
+			sortBy:        "",
+			order:         false,
+			expectedOrder: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var movies []gormModels.Movie
+			statement := FilterMovies(gormDB.Model(&gormModels.Movie{}), "", "", tt.sortBy, tt.order).
+				Find(&movies).
+				Statement
+
+			sql := statement.SQL.String()
+			_, orderBy, _ := strings.Cut(sql, "ORDER BY")
+			if tt.expectedOrder == "" {
+				assert.NotContains(t, sql, "ORDER BY")
+			} else {
+				assert.Equal(t, tt.expectedOrder, "ORDER BY"+orderBy)
+			}
+		})
+	}
+}
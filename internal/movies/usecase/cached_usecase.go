@@ -0,0 +1,171 @@
+package moviesUsecase
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/cache"
+)
+
+// CachedMoviesUsecase serves movie reads from the cache and drops the
+// entries a write makes stale. Movie lists filter by actor names and actor
+// lists show the films, so writes of movies drop both kinds of lists.
+type CachedMoviesUsecase struct {
+	moviesUsecase domain.MoviesUsecase
+	cache         domain.Cache
+}
+
+func NewCachedMoviesUsecase(u domain.MoviesUsecase, c domain.Cache) CachedMoviesUsecase {
+	return CachedMoviesUsecase{
+		moviesUsecase: u,
+		cache:         c,
+	}
+}
+
+// cachedMovie keeps the version, which the JSON form of a movie leaves out.
+type cachedMovie struct {
+	Movie   httpModels.MovieResponse `json:"movie"`
+	Version uint64                   `json:"version"`
+}
+
+func (u CachedMoviesUsecase) GetMovieByID(
+	ctx context.Context,
+	movieID uint64,
+) (httpModels.MovieResponse, error) {
+	cached, err := cache.Load(
+		ctx,
+		u.cache,
+		"movie:"+strconv.FormatUint(movieID, 10),
+		func(m cachedMovie) []string {
+			tags := []string{cache.MovieTag(movieID), cache.AnyMovieTag}
+			for _, actor := range m.Movie.CastList {
+				tags = append(tags, cache.ActorTag(actor.ID))
+			}
+			return tags
+		},
+		func() (cachedMovie, error) {
+			movie, err := u.moviesUsecase.GetMovieByID(ctx, movieID)
+			return cachedMovie{Movie: movie, Version: movie.Version}, err
+		},
+	)
+	if err != nil {
+		return httpModels.MovieResponse{}, err
+	}
+	cached.Movie.Version = cached.Version
+	return cached.Movie, nil
+}
+
+func (u CachedMoviesUsecase) GetMovies(
+	ctx context.Context,
+	title, actorName string,
+	sortBy httpModels.SortBy,
+	order bool,
+) ([]httpModels.MovieResponse, error) {
+	// url.Values sorts the parameters, so equal queries get equal keys.
+	key := "movies?" + url.Values{
+		"title": {title},
+		"actor": {actorName},
+		"sort":  {string(sortBy)},
+		"order": {strconv.FormatBool(order)},
+	}.Encode()
+
+	return cache.Load(
+		ctx,
+		u.cache,
+		key,
+		func([]httpModels.MovieResponse) []string { return []string{cache.MoviesTag} },
+		func() ([]httpModels.MovieResponse, error) {
+			return u.moviesUsecase.GetMovies(ctx, title, actorName, sortBy, order)
+		},
+	)
+}
+
+func (u CachedMoviesUsecase) CreateMovie(
+	ctx context.Context,
+	movie httpModels.MovieWithIDCast,
+) (uint64, error) {
+	movieID, err := u.moviesUsecase.CreateMovie(ctx, movie)
+	if err == nil {
+		u.cache.Invalidate(ctx, cache.MoviesTag, cache.ActorsTag)
+	}
+	return movieID, err
+}
+
+func (u CachedMoviesUsecase) UpdateMovie(
+	ctx context.Context,
+	movie httpModels.MovieWithoutCastList,
+	movieID, version uint64,
+) (httpModels.MovieResponse, error) {
+	updatedMovie, err := u.moviesUsecase.UpdateMovie(ctx, movie, movieID, version)
+	if err == nil {
+		u.invalidate(ctx, movieID)
+	}
+	return updatedMovie, err
+}
+
+func (u CachedMoviesUsecase) PatchMovie(
+	ctx context.Context,
+	movieID, version uint64,
+	patch domain.Patch,
+) (httpModels.MovieResponse, error) {
+	updatedMovie, err := u.moviesUsecase.PatchMovie(ctx, movieID, version, patch)
+	if err == nil {
+		u.invalidate(ctx, movieID)
+	}
+	return updatedMovie, err
+}
+
+func (u CachedMoviesUsecase) DeleteMovieByID(ctx context.Context, movieID, version uint64) error {
+	err := u.moviesUsecase.DeleteMovieByID(ctx, movieID, version)
+	if err == nil {
+		u.invalidate(ctx, movieID)
+	}
+	return err
+}
+
+func (u CachedMoviesUsecase) DeleteActorFromMovie(ctx context.Context, movieID, actorID uint64) error {
+	err := u.moviesUsecase.DeleteActorFromMovie(ctx, movieID, actorID)
+	if err == nil {
+		u.invalidate(ctx, movieID)
+	}
+	return err
+}
+
+func (u CachedMoviesUsecase) AddActorFromMovie(ctx context.Context, movieID, actorID uint64) error {
+	err := u.moviesUsecase.AddActorFromMovie(ctx, movieID, actorID)
+	if err == nil {
+		u.invalidate(ctx, movieID)
+	}
+	return err
+}
+
+func (u CachedMoviesUsecase) ReplaceCast(
+	ctx context.Context,
+	movieID, version uint64,
+	cast httpModels.Cast,
+) (httpModels.CastDiff, error) {
+	diff, err := u.moviesUsecase.ReplaceCast(ctx, movieID, version, cast)
+	if err == nil {
+		u.invalidate(ctx, movieID)
+	}
+	return diff, err
+}
+
+func (u CachedMoviesUsecase) BatchCast(
+	ctx context.Context,
+	movieID, version uint64,
+	batch httpModels.CastBatch,
+) (httpModels.CastDiff, error) {
+	diff, err := u.moviesUsecase.BatchCast(ctx, movieID, version, batch)
+	if err == nil {
+		u.invalidate(ctx, movieID)
+	}
+	return diff, err
+}
+
+func (u CachedMoviesUsecase) invalidate(ctx context.Context, movieID uint64) {
+	u.cache.Invalidate(ctx, cache.MovieTag(movieID), cache.MoviesTag, cache.ActorsTag)
+}
@@ -0,0 +1,90 @@
+package moviesUsecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	mockDomain "github.com/themilchenko/vk-tech_internship-problem_2024/internal/mocks/domain"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/cache"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCachedUsecase_GetMovieByID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	movie := httpModels.MovieResponse{
+		ID:       1,
+		Title:    "Title",
+		CastList: []httpModels.ActorResponse{{ID: 7, Name: "John"}},
+		Version:  3,
+	}
+
+	mockUsecase := mockDomain.NewMockMoviesUsecase(ctrl)
+	mockUsecase.EXPECT().GetMovieByID(gomock.Any(), uint64(1)).Return(movie, nil).Times(2)
+	mockUsecase.EXPECT().GetMovieByID(gomock.Any(), uint64(2)).Return(
+		httpModels.MovieResponse{},
+		domain.ErrNotFound,
+	).Times(2)
+
+	lru := cache.NewLRU(10, 0)
+	u := NewCachedMoviesUsecase(mockUsecase, lru)
+
+	// The second read is a hit and keeps the version.
+	for i := 0; i < 2; i++ {
+		cached, err := u.GetMovieByID(ctx, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, movie, cached)
+	}
+
+	// Errors are not cached.
+	for i := 0; i < 2; i++ {
+		_, err := u.GetMovieByID(ctx, 2)
+		assert.Equal(t, domain.ErrNotFound, err)
+	}
+
+	// A change of an actor of the cast drops the movie.
+	lru.Invalidate(ctx, cache.ActorTag(7))
+	cached, err := u.GetMovieByID(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, movie, cached)
+
+	stats := lru.Stats(ctx)
+	assert.Equal(t, uint64(1), stats.Hits)
+	assert.Equal(t, uint64(4), stats.Misses)
+}
+
+func TestCachedUsecase_GetMovies(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	movies := []httpModels.MovieResponse{{ID: 1, Title: "Title"}}
+
+	mockUsecase := mockDomain.NewMockMoviesUsecase(ctrl)
+	mockUsecase.EXPECT().
+		GetMovies(gomock.Any(), "Ti", "", httpModels.SortBy("rating"), true).
+		Return(movies, nil).
+		Times(2)
+	mockUsecase.EXPECT().
+		DeleteMovieByID(gomock.Any(), uint64(1), uint64(0)).
+		Return(nil)
+
+	u := NewCachedMoviesUsecase(mockUsecase, cache.NewLRU(10, 0))
+
+	for i := 0; i < 2; i++ {
+		cached, err := u.GetMovies(ctx, "Ti", "", "rating", true)
+		assert.NoError(t, err)
+		assert.Equal(t, movies, cached)
+	}
+
+	// Any movie write drops the lists.
+	assert.NoError(t, u.DeleteMovieByID(ctx, 1, 0))
+	cached, err := u.GetMovies(ctx, "Ti", "", "rating", true)
+	assert.NoError(t, err)
+	assert.Equal(t, movies, cached)
+}
@@ -1,6 +1,10 @@
 package moviesUsecase
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/pkg/errors"
@@ -8,6 +12,7 @@ import (
 	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
 	gormModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/gorm"
 	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/validate"
 	"gorm.io/gorm"
 )
 
@@ -23,70 +28,158 @@ func NewMoviesUsecase(m domain.MoviesRepository, a domain.ActorsRepository) Movi
 	}
 }
 
-func (u MoviesUsecase) CreateMovie(movie httpModels.MovieWithIDCast) (uint64, error) {
+// NewMovie checks a new movie by the rules of its fields and converts it
+// for the repository. Every way of creating movies goes through it.
+func NewMovie(movie httpModels.MovieWithIDCast) (gormModels.Movie, error) {
+	if violations := validate.Struct(movie); len(violations) > 0 {
+		return gormModels.Movie{}, domain.ValidationError{Violations: violations}
+	}
+
 	t, err := time.Parse(time.DateOnly, movie.ReleaseDate)
 	if err != nil {
-		return 0, err
+		return gormModels.Movie{}, domain.ErrValidation.Wrap(err)
 	}
 
-	gormMovie := gormModels.Movie{
+	return gormModels.Movie{
 		Title:       movie.Title,
 		Description: movie.Description,
 		ReleaseDate: t,
 		Rating:      movie.Rating,
+	}, nil
+}
+
+func (u MoviesUsecase) CreateMovie(
+	ctx context.Context,
+	movie httpModels.MovieWithIDCast,
+) (uint64, error) {
+	gormMovie, err := NewMovie(movie)
+	if err != nil {
+		return 0, err
 	}
 	var movieID uint64
 
 	if len(movie.CastIDList) == 0 {
-		movieID, err = u.moviesRepository.CreateMovieWithoutCastList(gormMovie)
+		movieID, err = u.moviesRepository.CreateMovieWithoutCastList(ctx, gormMovie)
 		if err != nil {
-			return 0, domain.ErrCreate
+			return 0, err
 		}
 	} else {
 		for _, actorID := range movie.CastIDList {
-			if _, err := u.actorsRepository.GetActorByID(actorID); err != nil {
-				return 0, domain.ErrNotFound
+			if _, err := u.actorsRepository.GetActorByID(ctx, actorID); err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return 0, domain.ErrNotFound
+				}
+				return 0, err
 			}
 		}
 
-		movieID, err = u.moviesRepository.CreateMovieWithCastList(gormMovie, movie.CastIDList)
+		movieID, err = u.moviesRepository.CreateMovieWithCastList(ctx, gormMovie, movie.CastIDList)
 		if err != nil {
-			return 0, domain.ErrCreate
+			return 0, err
 		}
 	}
 
+	slog.InfoContext(ctx, "movie created", "movie_id", movieID, "cast", len(movie.CastIDList))
 	return movieID, nil
 }
 
 func (u MoviesUsecase) UpdateMovie(
+	ctx context.Context,
 	movie httpModels.MovieWithoutCastList,
-	movieID uint64,
+	movieID, version uint64,
 ) (httpModels.MovieResponse, error) {
 	t, err := time.Parse(time.DateOnly, movie.ReleaseDate)
 	if err != nil {
-		return httpModels.MovieResponse{}, err
+		return httpModels.MovieResponse{}, domain.ErrValidation.Wrap(err)
 	}
 
-	updatedMovie, err := u.moviesRepository.UpdateMovie(gormModels.Movie{
+	updatedMovie, err := u.moviesRepository.UpdateMovie(ctx, gormModels.Movie{
 		ID:          movieID,
 		Title:       movie.Title,
 		Description: movie.Description,
 		ReleaseDate: t,
 		Rating:      movie.Rating,
+		Version:     version,
 	})
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return httpModels.MovieResponse{}, domain.ErrNotFound
+		}
+		return httpModels.MovieResponse{}, err
+	}
+	slog.InfoContext(ctx, "movie updated", "movie_id", movieID, "version", updatedMovie.Version)
+	return updatedMovie.ToHTTPResponse(), nil
+}
+
+// PatchMovie applies the patch to the JSON form of the movie. Members the
+// patch leaves out are kept, the result is checked like a PUT body.
+func (u MoviesUsecase) PatchMovie(
+	ctx context.Context,
+	movieID, version uint64,
+	patch domain.Patch,
+) (httpModels.MovieResponse, error) {
+	updatedMovie, err := u.moviesRepository.PatchMovie(
+		ctx,
+		movieID,
+		version,
+		func(movie *gormModels.Movie) error {
+			doc, err := json.Marshal(movie.ToHTTPMovies())
+			if err != nil {
+				return err
+			}
+			if doc, err = patch.Apply(doc); err != nil {
+				return err
+			}
+
+			var patched httpModels.MovieWithoutCastList
+			if err = validate.Unmarshal(doc, &patched); err != nil {
+				return err
+			}
+			if err = validate.NotNull(doc, "rating"); err != nil {
+				return err
+			}
+			if patched.ID != movieID {
+				return domain.ValidationError{Violations: []domain.FieldError{{
+					Field:   "id",
+					Rule:    "immutable",
+					Message: "can't be changed",
+				}}}
+			}
+
+			t, err := time.Parse(time.DateOnly, patched.ReleaseDate)
+			if err != nil {
+				return domain.ErrValidation.Wrap(err)
+			}
+			movie.Title = patched.Title
+			movie.Description = patched.Description
+			movie.ReleaseDate = t
+			movie.Rating = patched.Rating
+			return nil
+		},
+	)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return httpModels.MovieResponse{}, domain.ErrNotFound
+		}
 		return httpModels.MovieResponse{}, err
 	}
+	slog.InfoContext(ctx, "movie updated", "movie_id", movieID, "version", updatedMovie.Version)
 	return updatedMovie.ToHTTPResponse(), nil
 }
 
-func (u MoviesUsecase) GetMovieByID(movieID uint64) (httpModels.MovieResponse, error) {
-	movie, err := u.moviesRepository.GetMovieByID(movieID)
+func (u MoviesUsecase) GetMovieByID(
+	ctx context.Context,
+	movieID uint64,
+) (httpModels.MovieResponse, error) {
+	movie, err := u.moviesRepository.GetMovieByID(ctx, movieID)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return httpModels.MovieResponse{}, domain.ErrNotFound
+		}
 		return httpModels.MovieResponse{}, err
 	}
 
-	castList, err := u.actorsRepository.GetActorsFromMovie(movieID)
+	castList, err := u.actorsRepository.GetActorsFromMovie(ctx, movieID)
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		return httpModels.MovieResponse{}, err
 	}
@@ -100,24 +193,181 @@ func (u MoviesUsecase) GetMovieByID(movieID uint64) (httpModels.MovieResponse, e
 	return httpMovie, nil
 }
 
-func (u MoviesUsecase) DeleteMovieByID(movieID uint64) error {
-	return u.moviesRepository.DeleteMovieByID(movieID)
+func (u MoviesUsecase) DeleteMovieByID(ctx context.Context, movieID, version uint64) error {
+	if err := u.moviesRepository.DeleteMovieByID(ctx, movieID, version); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.ErrNotFound
+		}
+		return err
+	}
+	slog.InfoContext(ctx, "movie deleted", "movie_id", movieID)
+	return nil
 }
 
-func (u MoviesUsecase) DeleteActorFromMovie(movieID, actorID uint64) error {
-	return u.moviesRepository.DeleteActorFromMovie(movieID, actorID)
+func (u MoviesUsecase) DeleteActorFromMovie(ctx context.Context, movieID, actorID uint64) error {
+	if err := u.moviesRepository.DeleteActorFromMovie(ctx, movieID, actorID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.ErrNotFound
+		}
+		return err
+	}
+	slog.InfoContext(ctx, "actor removed from cast", "movie_id", movieID, "actor_id", actorID)
+	return nil
+}
+
+func (u MoviesUsecase) AddActorFromMovie(ctx context.Context, movieID, actorID uint64) error {
+	if err := u.moviesRepository.AddActorToMovie(ctx, movieID, actorID); err != nil {
+		return err
+	}
+	slog.InfoContext(ctx, "actor added to cast", "movie_id", movieID, "actor_id", actorID)
+	return nil
 }
 
-func (u MoviesUsecase) AddActorFromMovie(movieID, actorID uint64) error {
-	return u.moviesRepository.AddActorToMovie(movieID, actorID)
+// ReplaceCast makes the actors the whole cast of the movie.
+func (u MoviesUsecase) ReplaceCast(
+	ctx context.Context,
+	movieID, version uint64,
+	cast httpModels.Cast,
+) (httpModels.CastDiff, error) {
+	violations := uniqueActors("actorIDs", cast.ActorIDs, nil)
+	unknown, err := u.unknownActors(ctx, "actorIDs", cast.ActorIDs)
+	if err != nil {
+		return httpModels.CastDiff{}, err
+	}
+	if violations = append(violations, unknown...); len(violations) > 0 {
+		return httpModels.CastDiff{}, domain.ValidationError{Violations: violations}
+	}
+
+	return u.changeCast(ctx, movieID, version, func([]uint64) []uint64 {
+		return cast.ActorIDs
+	})
+}
+
+// BatchCast adds actors to the cast of the movie and removes others from
+// it. Adding a cast member or removing someone who isn't one changes nothing.
+func (u MoviesUsecase) BatchCast(
+	ctx context.Context,
+	movieID, version uint64,
+	batch httpModels.CastBatch,
+) (httpModels.CastDiff, error) {
+	violations := uniqueActors("add", batch.Add, nil)
+	violations = append(violations, uniqueActors("remove", batch.Remove, batch.Add)...)
+	unknown, err := u.unknownActors(ctx, "add", batch.Add)
+	if err != nil {
+		return httpModels.CastDiff{}, err
+	}
+	if violations = append(violations, unknown...); len(violations) > 0 {
+		return httpModels.CastDiff{}, domain.ValidationError{Violations: violations}
+	}
+
+	removed := make(map[uint64]bool, len(batch.Remove))
+	for _, actorID := range batch.Remove {
+		removed[actorID] = true
+	}
+	return u.changeCast(ctx, movieID, version, func(cast []uint64) []uint64 {
+		wanted := make([]uint64, 0, len(cast)+len(batch.Add))
+		for _, actorID := range cast {
+			if !removed[actorID] {
+				wanted = append(wanted, actorID)
+			}
+		}
+		return append(wanted, batch.Add...)
+	})
+}
+
+func (u MoviesUsecase) changeCast(
+	ctx context.Context,
+	movieID, version uint64,
+	change func(cast []uint64) []uint64,
+) (httpModels.CastDiff, error) {
+	castChange, err := u.moviesRepository.ChangeCast(ctx, movieID, version, change)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return httpModels.CastDiff{}, domain.ErrNotFound
+		}
+		return httpModels.CastDiff{}, err
+	}
+	slog.InfoContext(ctx, "cast changed",
+		"movie_id", movieID,
+		"added", len(castChange.Added),
+		"removed", len(castChange.Removed),
+		"version", castChange.Version,
+	)
+
+	// Empty lists are sent as [] rather than null.
+	return httpModels.CastDiff{
+		Added:     append([]uint64{}, castChange.Added...),
+		Removed:   append([]uint64{}, castChange.Removed...),
+		Unchanged: append([]uint64{}, castChange.Unchanged...),
+		Version:   castChange.Version,
+	}, nil
+}
+
+// uniqueActors reports the ids of the field that are listed twice or are
+// also listed in other.
+func uniqueActors(field string, actorIDs, other []uint64) []domain.FieldError {
+	inOther := make(map[uint64]bool, len(other))
+	for _, actorID := range other {
+		inOther[actorID] = true
+	}
+
+	var violations []domain.FieldError
+	seen := make(map[uint64]bool, len(actorIDs))
+	for i, actorID := range actorIDs {
+		switch {
+		case seen[actorID]:
+			violations = append(violations, domain.FieldError{
+				Field:   fmt.Sprintf("%s[%d]", field, i),
+				Rule:    "unique",
+				Message: "is listed twice",
+			})
+		case inOther[actorID]:
+			violations = append(violations, domain.FieldError{
+				Field:   fmt.Sprintf("%s[%d]", field, i),
+				Rule:    "unique",
+				Message: "is listed in add too",
+			})
+		}
+		seen[actorID] = true
+	}
+	return violations
+}
+
+// unknownActors reports the ids of the field that aren't actors.
+func (u MoviesUsecase) unknownActors(
+	ctx context.Context,
+	field string,
+	actorIDs []uint64,
+) ([]domain.FieldError, error) {
+	actors, err := u.actorsRepository.GetActorsByIDs(ctx, actorIDs)
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[uint64]bool, len(actors))
+	for _, actor := range actors {
+		found[actor.ID] = true
+	}
+
+	var violations []domain.FieldError
+	for i, actorID := range actorIDs {
+		if !found[actorID] {
+			violations = append(violations, domain.FieldError{
+				Field:   fmt.Sprintf("%s[%d]", field, i),
+				Rule:    "exists",
+				Message: fmt.Sprintf("actor %d not found", actorID),
+			})
+		}
+	}
+	return violations, nil
 }
 
 func (u MoviesUsecase) GetMovies(
+	ctx context.Context,
 	title, actorName string,
 	sortBy httpModels.SortBy,
 	order bool,
 ) ([]httpModels.MovieResponse, error) {
-	movies, err := u.moviesRepository.GetMovies(title, actorName, sortBy, order)
+	movies, err := u.moviesRepository.GetMovies(ctx, title, actorName, sortBy, order)
 	if err != nil {
 		return []httpModels.MovieResponse{}, err
 	}
@@ -125,7 +375,7 @@ func (u MoviesUsecase) GetMovies(
 	httpMovies := make([]httpModels.MovieResponse, len(movies))
 	for i, v := range movies {
 		httpMovies[i] = v.ToHTTPResponse()
-		actors, err := u.actorsRepository.GetActorsFromMovie(v.ID)
+		actors, err := u.actorsRepository.GetActorsFromMovie(ctx, v.ID)
 		if err != nil {
 			return []httpModels.MovieResponse{}, err
 		}
@@ -1,6 +1,7 @@
 package moviesUsecase
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -10,7 +11,9 @@ import (
 	mockDomain "github.com/themilchenko/vk-tech_internship-problem_2024/internal/mocks/domain"
 	gormModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/gorm"
 	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/jsonpatch"
 	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
 )
 
 func TestUsecase_CreateMovie(t *testing.T) {
@@ -38,11 +41,11 @@ func TestUsecase_CreateMovie(t *testing.T) {
 			},
 			mockBehaviorCreateMovie: func(m *mockDomain.MockMoviesRepository, movie gormModels.Movie, castIDList []uint64) {
 				m.EXPECT().
-					CreateMovieWithCastList(movie, castIDList).
+					CreateMovieWithCastList(gomock.Any(), movie, castIDList).
 					Return(uint64(1), nil)
 			},
 			mockBehaviorGetActor: func(m *mockDomain.MockActorsRepository, actorID uint64) {
-				m.EXPECT().GetActorByID(actorID).Return(gormModels.Actor{}, nil)
+				m.EXPECT().GetActorByID(gomock.Any(), actorID).Return(gormModels.Actor{}, nil)
 			},
 			mockBehaviorCreateMovieWithoutCastList: func(m *mockDomain.MockMoviesRepository, movie gormModels.Movie) {},
 			expectedMovieID:                        uint64(1),
@@ -59,7 +62,7 @@ func TestUsecase_CreateMovie(t *testing.T) {
 			},
 			mockBehaviorCreateMovie: func(m *mockDomain.MockMoviesRepository, movie gormModels.Movie, castIDList []uint64) {},
 			mockBehaviorCreateMovieWithoutCastList: func(m *mockDomain.MockMoviesRepository, movie gormModels.Movie) {
-				m.EXPECT().CreateMovieWithoutCastList(movie).Return(uint64(1), nil)
+				m.EXPECT().CreateMovieWithoutCastList(gomock.Any(), movie).Return(uint64(1), nil)
 			},
 			mockBehaviorGetActor: func(m *mockDomain.MockActorsRepository, actorID uint64) {},
 			expectedMovieID:      uint64(1),
@@ -76,15 +79,15 @@ func TestUsecase_CreateMovie(t *testing.T) {
 			},
 			mockBehaviorCreateMovie: func(m *mockDomain.MockMoviesRepository, movie gormModels.Movie, castIDList []uint64) {
 				m.EXPECT().
-					CreateMovieWithCastList(movie, castIDList).
+					CreateMovieWithCastList(gomock.Any(), movie, castIDList).
 					Return(uint64(0), errors.New("failed to create item"))
 			},
 			mockBehaviorCreateMovieWithoutCastList: func(m *mockDomain.MockMoviesRepository, movie gormModels.Movie) {},
 			mockBehaviorGetActor: func(m *mockDomain.MockActorsRepository, actorID uint64) {
-				m.EXPECT().GetActorByID(actorID).Return(gormModels.Actor{}, nil)
+				m.EXPECT().GetActorByID(gomock.Any(), actorID).Return(gormModels.Actor{}, nil)
 			},
 			expectedMovieID: uint64(0),
-			expectedError:   domain.ErrCreate,
+			expectedError:   errors.New("failed to create item"),
 		},
 		{
 			name: "CreateMovie error with cast list",
@@ -98,12 +101,12 @@ func TestUsecase_CreateMovie(t *testing.T) {
 			mockBehaviorCreateMovie: func(m *mockDomain.MockMoviesRepository, movie gormModels.Movie, castIDList []uint64) {},
 			mockBehaviorCreateMovieWithoutCastList: func(m *mockDomain.MockMoviesRepository, movie gormModels.Movie) {
 				m.EXPECT().
-					CreateMovieWithoutCastList(movie).
+					CreateMovieWithoutCastList(gomock.Any(), movie).
 					Return(uint64(0), errors.New("failed to create item"))
 			},
 			mockBehaviorGetActor: func(m *mockDomain.MockActorsRepository, actorID uint64) {},
 			expectedMovieID:      uint64(0),
-			expectedError:        domain.ErrCreate,
+			expectedError:        errors.New("failed to create item"),
 		},
 		{
 			name: "CreateMovie error parse date",
@@ -118,13 +121,11 @@ func TestUsecase_CreateMovie(t *testing.T) {
 			mockBehaviorCreateMovieWithoutCastList: func(m *mockDomain.MockMoviesRepository, movie gormModels.Movie) {},
 			mockBehaviorGetActor:                   func(m *mockDomain.MockActorsRepository, actorID uint64) {},
 			expectedMovieID:                        uint64(0),
-			expectedError: &time.ParseError{
-				Layout:     "2006-01-02",
-				Value:      "2006=01-02",
-				LayoutElem: "-",
-				ValueElem:  "=01-02",
-				Message:    "",
-			},
+			expectedError: domain.ValidationError{Violations: []domain.FieldError{{
+				Field:   "releaseDate",
+				Rule:    "date",
+				Message: "must be a date in YYYY-MM-DD format",
+			}}},
 		},
 	}
 
@@ -153,7 +154,7 @@ func TestUsecase_CreateMovie(t *testing.T) {
 				tt.mockBehaviorGetActor(mockActorRepo, tt.inputMovie.CastIDList[0])
 			}
 
-			movieID, err := u.CreateMovie(tt.inputMovie)
+			movieID, err := u.CreateMovie(context.Background(), tt.inputMovie)
 			assert.Equal(t, tt.expectedMovieID, movieID)
 			assert.Equal(t, tt.expectedError, err)
 		})
@@ -182,7 +183,7 @@ func TestUsecase_UpdateMovie(t *testing.T) {
 			inputMovieID: uint64(1),
 			mockBehaviorUpdateMovie: func(m *mockDomain.MockMoviesRepository, movie gormModels.Movie, movieID uint64) {
 				m.EXPECT().
-					UpdateMovie(movie).
+					UpdateMovie(gomock.Any(), movie).
 					Return(gormModels.Movie{
 						ID:          1,
 						Title:       "Title",
@@ -224,7 +225,176 @@ func TestUsecase_UpdateMovie(t *testing.T) {
 
 			tt.mockBehaviorUpdateMovie(mockRepo, movie, tt.inputMovieID)
 
-			movieResponse, err := u.UpdateMovie(tt.inputMovie, tt.inputMovieID)
+			movieResponse, err := u.UpdateMovie(context.Background(), tt.inputMovie, tt.inputMovieID, 0)
+			assert.Equal(t, tt.expectedMovieResponse, movieResponse)
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestUsecase_PatchMovie(t *testing.T) {
+	stored := gormModels.Movie{
+		ID:          1,
+		Title:       "Title",
+		Description: "Description",
+		ReleaseDate: time.Date(2006, 1, 2, 0, 0, 0, 0, time.UTC),
+		Rating:      5.0,
+	}
+
+	mergePatch := func(doc string) domain.Patch {
+		p, err := jsonpatch.NewMergePatch([]byte(doc))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	jsonPatch := func(doc string) domain.Patch {
+		p, err := jsonpatch.NewJSONPatch([]byte(doc))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+
+	tests := []struct {
+		name                  string
+		version               uint64
+		patch                 domain.Patch
+		repositoryError       error
+		expectedMovie         gormModels.Movie
+		expectedMovieResponse httpModels.MovieResponse
+		expectedError         error
+	}{
+		{
+			name:  "Zero rating",
+			patch: mergePatch(`{"rating":0}`),
+			expectedMovie: gormModels.Movie{
+				ID:          1,
+				Title:       "Title",
+				Description: "Description",
+				ReleaseDate: time.Date(2006, 1, 2, 0, 0, 0, 0, time.UTC),
+			},
+			expectedMovieResponse: httpModels.MovieResponse{
+				ID:          1,
+				Title:       "Title",
+				Description: "Description",
+				ReleaseDate: "2006-01-02",
+			},
+		},
+		{
+			name:  "Null description",
+			patch: mergePatch(`{"description":null}`),
+			expectedMovie: gormModels.Movie{
+				ID:          1,
+				Title:       "Title",
+				ReleaseDate: time.Date(2006, 1, 2, 0, 0, 0, 0, time.UTC),
+				Rating:      5.0,
+			},
+			expectedMovieResponse: httpModels.MovieResponse{
+				ID:          1,
+				Title:       "Title",
+				ReleaseDate: "2006-01-02",
+				Rating:      5.0,
+			},
+		},
+		{
+			name:  "JSON patch",
+			patch: jsonPatch(`[{"op":"test","path":"/rating","value":5},{"op":"replace","path":"/title","value":"New"}]`),
+			expectedMovie: gormModels.Movie{
+				ID:          1,
+				Title:       "New",
+				Description: "Description",
+				ReleaseDate: time.Date(2006, 1, 2, 0, 0, 0, 0, time.UTC),
+				Rating:      5.0,
+			},
+			expectedMovieResponse: httpModels.MovieResponse{
+				ID:          1,
+				Title:       "New",
+				Description: "Description",
+				ReleaseDate: "2006-01-02",
+				Rating:      5.0,
+			},
+		},
+		{
+			name:  "Null title",
+			patch: mergePatch(`{"title":null}`),
+			expectedError: domain.ValidationError{Violations: []domain.FieldError{{
+				Field:   "title",
+				Rule:    "required",
+				Message: "is required",
+			}}},
+		},
+		{
+			name:  "Null rating",
+			patch: mergePatch(`{"rating":null}`),
+			expectedError: domain.ValidationError{Violations: []domain.FieldError{{
+				Field:   "rating",
+				Rule:    "not_null",
+				Message: "can't be null",
+			}}},
+		},
+		{
+			name:  "Removed rating",
+			patch: jsonPatch(`[{"op":"remove","path":"/rating"}]`),
+			expectedError: domain.ValidationError{Violations: []domain.FieldError{{
+				Field:   "rating",
+				Rule:    "not_null",
+				Message: "can't be null",
+			}}},
+		},
+		{
+			name:  "Changed id",
+			patch: mergePatch(`{"id":2}`),
+			expectedError: domain.ValidationError{Violations: []domain.FieldError{{
+				Field:   "id",
+				Rule:    "immutable",
+				Message: "can't be changed",
+			}}},
+		},
+		{
+			name:            "Not found",
+			patch:           mergePatch(`{"rating":0}`),
+			repositoryError: gorm.ErrRecordNotFound,
+			expectedError:   domain.ErrNotFound,
+		},
+		{
+			name:            "Version mismatch",
+			version:         3,
+			patch:           mergePatch(`{"rating":0}`),
+			repositoryError: domain.ErrVersionMismatch,
+			expectedError:   domain.ErrVersionMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mockDomain.NewMockMoviesRepository(ctrl)
+			mockActorRepo := mockDomain.NewMockActorsRepository(ctrl)
+
+			u := NewMoviesUsecase(mockRepo, mockActorRepo)
+
+			mockRepo.EXPECT().
+				PatchMovie(gomock.Any(), uint64(1), tt.version, gomock.Any()).
+				DoAndReturn(func(
+					_ context.Context,
+					_, _ uint64,
+					patch func(*gormModels.Movie) error,
+				) (gormModels.Movie, error) {
+					if tt.repositoryError != nil {
+						return gormModels.Movie{}, tt.repositoryError
+					}
+					movie := stored
+					if err := patch(&movie); err != nil {
+						return gormModels.Movie{}, err
+					}
+					assert.Equal(t, tt.expectedMovie, movie)
+					return movie, nil
+				})
+
+			movieResponse, err := u.PatchMovie(context.Background(), 1, tt.version, tt.patch)
 			assert.Equal(t, tt.expectedMovieResponse, movieResponse)
 			assert.Equal(t, tt.expectedError, err)
 		})
@@ -248,7 +418,7 @@ func TestUsecase_GetMovieByID(t *testing.T) {
 			inputMovieID: uint64(1),
 			mockBehaviorGetMovieByID: func(m *mockDomain.MockMoviesRepository, movieID uint64) {
 				m.EXPECT().
-					GetMovieByID(movieID).
+					GetMovieByID(gomock.Any(), movieID).
 					Return(gormModels.Movie{
 						ID:          1,
 						Title:       "Title",
@@ -259,7 +429,7 @@ func TestUsecase_GetMovieByID(t *testing.T) {
 			},
 			mockBehaviorGetActorsFromMovie: func(m *mockDomain.MockActorsRepository, movieID uint64) {
 				m.EXPECT().
-					GetActorsFromMovie(movieID).
+					GetActorsFromMovie(gomock.Any(), movieID).
 					Return([]gormModels.Actor{
 						{
 							ID:        1,
@@ -301,7 +471,7 @@ func TestUsecase_GetMovieByID(t *testing.T) {
 			tt.mockBehaviorGetMovieByID(mockRepo, tt.inputMovieID)
 			tt.mockBehaviorGetActorsFromMovie(mockActorRepo, tt.inputMovieID)
 
-			movieResponse, err := u.GetMovieByID(tt.inputMovieID)
+			movieResponse, err := u.GetMovieByID(context.Background(), tt.inputMovieID)
 			assert.Equal(t, tt.expectedMovieResponse, movieResponse)
 			assert.Equal(t, tt.expectedError, err)
 		})
@@ -331,7 +501,7 @@ func TestUsecase_GetMovies(t *testing.T) {
 			order:     true,
 			mockBehaviorGetMovies: func(m *mockDomain.MockMoviesRepository, title, actor string, sortBy httpModels.SortBy, order bool) {
 				m.EXPECT().
-					GetMovies(title, actor, sortBy, order).
+					GetMovies(gomock.Any(), title, actor, sortBy, order).
 					Return([]gormModels.Movie{
 						{
 							ID:          1,
@@ -344,7 +514,7 @@ func TestUsecase_GetMovies(t *testing.T) {
 			},
 			mockBehaviorGetActorsFromMovie: func(m *mockDomain.MockActorsRepository, movieID uint64) {
 				m.EXPECT().
-					GetActorsFromMovie(movieID).
+					GetActorsFromMovie(gomock.Any(), movieID).
 					Return([]gormModels.Actor{
 						{
 							ID:        1,
@@ -388,9 +558,187 @@ func TestUsecase_GetMovies(t *testing.T) {
 			tt.mockBehaviorGetMovies(mockRepo, tt.title, tt.actorName, tt.sortBy, tt.order)
 			tt.mockBehaviorGetActorsFromMovie(mockActorRepo, uint64(1))
 
-			movieList, err := u.GetMovies(tt.title, tt.actorName, tt.sortBy, tt.order)
+			movieList, err := u.GetMovies(context.Background(), tt.title, tt.actorName, tt.sortBy, tt.order)
 			assert.Equal(t, tt.expectedMovieList, movieList)
 			assert.Equal(t, tt.expectedError, err)
 		})
 	}
 }
+
+func TestUsecase_ReplaceCast(t *testing.T) {
+	type mockBehavior func(m *mockDomain.MockMoviesRepository, a *mockDomain.MockActorsRepository)
+
+	tests := []struct {
+		name          string
+		cast          httpModels.Cast
+		mockBehavior  mockBehavior
+		expectedDiff  httpModels.CastDiff
+		expectedError error
+	}{
+		{
+			name: "Replaced",
+			cast: httpModels.Cast{ActorIDs: []uint64{3, 1}},
+			mockBehavior: func(m *mockDomain.MockMoviesRepository, a *mockDomain.MockActorsRepository) {
+				a.EXPECT().
+					GetActorsByIDs(gomock.Any(), []uint64{3, 1}).
+					Return([]gormModels.Actor{{ID: 1}, {ID: 3}}, nil)
+				m.EXPECT().
+					ChangeCast(gomock.Any(), uint64(1), uint64(4), gomock.Any()).
+					DoAndReturn(func(_ context.Context, _, _ uint64, change func([]uint64) []uint64) (gormModels.CastChange, error) {
+						assert.Equal(t, []uint64{3, 1}, change([]uint64{1, 2}))
+						return gormModels.CastChange{
+							Added:     []uint64{3},
+							Removed:   []uint64{2},
+							Unchanged: []uint64{1},
+							Version:   5,
+						}, nil
+					})
+			},
+			expectedDiff: httpModels.CastDiff{
+				Added:     []uint64{3},
+				Removed:   []uint64{2},
+				Unchanged: []uint64{1},
+				Version:   5,
+			},
+		},
+		{
+			name: "Emptied",
+			cast: httpModels.Cast{},
+			mockBehavior: func(m *mockDomain.MockMoviesRepository, a *mockDomain.MockActorsRepository) {
+				a.EXPECT().GetActorsByIDs(gomock.Any(), nil).Return(nil, nil)
+				m.EXPECT().
+					ChangeCast(gomock.Any(), uint64(1), uint64(4), gomock.Any()).
+					Return(gormModels.CastChange{Removed: []uint64{2}, Version: 5}, nil)
+			},
+			expectedDiff: httpModels.CastDiff{
+				Added:     []uint64{},
+				Removed:   []uint64{2},
+				Unchanged: []uint64{},
+				Version:   5,
+			},
+		},
+		{
+			name: "Unknown and repeated actors",
+			cast: httpModels.Cast{ActorIDs: []uint64{1, 7, 1}},
+			mockBehavior: func(m *mockDomain.MockMoviesRepository, a *mockDomain.MockActorsRepository) {
+				a.EXPECT().
+					GetActorsByIDs(gomock.Any(), []uint64{1, 7, 1}).
+					Return([]gormModels.Actor{{ID: 1}}, nil)
+			},
+			expectedError: domain.ValidationError{Violations: []domain.FieldError{
+				{Field: "actorIDs[2]", Rule: "unique", Message: "is listed twice"},
+				{Field: "actorIDs[1]", Rule: "exists", Message: "actor 7 not found"},
+			}},
+		},
+		{
+			name: "Movie not found",
+			cast: httpModels.Cast{ActorIDs: []uint64{1}},
+			mockBehavior: func(m *mockDomain.MockMoviesRepository, a *mockDomain.MockActorsRepository) {
+				a.EXPECT().GetActorsByIDs(gomock.Any(), []uint64{1}).Return([]gormModels.Actor{{ID: 1}}, nil)
+				m.EXPECT().
+					ChangeCast(gomock.Any(), uint64(1), uint64(4), gomock.Any()).
+					Return(gormModels.CastChange{}, gorm.ErrRecordNotFound)
+			},
+			expectedError: domain.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mockDomain.NewMockMoviesRepository(ctrl)
+			mockActorRepo := mockDomain.NewMockActorsRepository(ctrl)
+			tt.mockBehavior(mockRepo, mockActorRepo)
+
+			u := NewMoviesUsecase(mockRepo, mockActorRepo)
+
+			diff, err := u.ReplaceCast(context.Background(), 1, 4, tt.cast)
+			assert.Equal(t, tt.expectedDiff, diff)
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestUsecase_BatchCast(t *testing.T) {
+	type mockBehavior func(m *mockDomain.MockMoviesRepository, a *mockDomain.MockActorsRepository)
+
+	tests := []struct {
+		name          string
+		batch         httpModels.CastBatch
+		mockBehavior  mockBehavior
+		expectedDiff  httpModels.CastDiff
+		expectedError error
+	}{
+		{
+			name:  "Added and removed",
+			batch: httpModels.CastBatch{Add: []uint64{4, 1}, Remove: []uint64{2, 9}},
+			mockBehavior: func(m *mockDomain.MockMoviesRepository, a *mockDomain.MockActorsRepository) {
+				a.EXPECT().
+					GetActorsByIDs(gomock.Any(), []uint64{4, 1}).
+					Return([]gormModels.Actor{{ID: 1}, {ID: 4}}, nil)
+				m.EXPECT().
+					ChangeCast(gomock.Any(), uint64(1), uint64(0), gomock.Any()).
+					DoAndReturn(func(_ context.Context, _, _ uint64, change func([]uint64) []uint64) (gormModels.CastChange, error) {
+						assert.Equal(t, []uint64{1, 3, 4, 1}, change([]uint64{1, 2, 3}))
+						return gormModels.CastChange{
+							Added:     []uint64{4},
+							Removed:   []uint64{2},
+							Unchanged: []uint64{1, 3},
+							Version:   2,
+						}, nil
+					})
+			},
+			expectedDiff: httpModels.CastDiff{
+				Added:     []uint64{4},
+				Removed:   []uint64{2},
+				Unchanged: []uint64{1, 3},
+				Version:   2,
+			},
+		},
+		{
+			name:  "Invalid batch",
+			batch: httpModels.CastBatch{Add: []uint64{4, 8, 4}, Remove: []uint64{4, 2, 2}},
+			mockBehavior: func(m *mockDomain.MockMoviesRepository, a *mockDomain.MockActorsRepository) {
+				a.EXPECT().
+					GetActorsByIDs(gomock.Any(), []uint64{4, 8, 4}).
+					Return([]gormModels.Actor{{ID: 4}}, nil)
+			},
+			expectedError: domain.ValidationError{Violations: []domain.FieldError{
+				{Field: "add[2]", Rule: "unique", Message: "is listed twice"},
+				{Field: "remove[0]", Rule: "unique", Message: "is listed in add too"},
+				{Field: "remove[2]", Rule: "unique", Message: "is listed twice"},
+				{Field: "add[1]", Rule: "exists", Message: "actor 8 not found"},
+			}},
+		},
+		{
+			name:  "Version mismatch",
+			batch: httpModels.CastBatch{Remove: []uint64{2}},
+			mockBehavior: func(m *mockDomain.MockMoviesRepository, a *mockDomain.MockActorsRepository) {
+				a.EXPECT().GetActorsByIDs(gomock.Any(), nil).Return(nil, nil)
+				m.EXPECT().
+					ChangeCast(gomock.Any(), uint64(1), uint64(0), gomock.Any()).
+					Return(gormModels.CastChange{}, domain.ErrVersionMismatch)
+			},
+			expectedError: domain.ErrVersionMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mockDomain.NewMockMoviesRepository(ctrl)
+			mockActorRepo := mockDomain.NewMockActorsRepository(ctrl)
+			tt.mockBehavior(mockRepo, mockActorRepo)
+
+			u := NewMoviesUsecase(mockRepo, mockActorRepo)
+
+			diff, err := u.BatchCast(context.Background(), 1, 0, tt.batch)
+			assert.Equal(t, tt.expectedDiff, diff)
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
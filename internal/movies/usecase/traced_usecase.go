@@ -0,0 +1,115 @@
+package moviesUsecase
+
+import (
+	"context"
+
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/tracing"
+)
+
+// TracedMoviesUsecase makes a span of every call. A slow GET /movies shows
+// whether the time went to the list query or the cast lookups after it.
+type TracedMoviesUsecase struct {
+	moviesUsecase domain.MoviesUsecase
+}
+
+func NewTracedMoviesUsecase(u domain.MoviesUsecase) TracedMoviesUsecase {
+	return TracedMoviesUsecase{moviesUsecase: u}
+}
+
+func (u TracedMoviesUsecase) CreateMovie(
+	ctx context.Context,
+	movie httpModels.MovieWithIDCast,
+) (_ uint64, err error) {
+	ctx, span := tracing.Start(ctx, "MoviesUsecase.CreateMovie")
+	defer func() { tracing.End(span, err) }()
+	return u.moviesUsecase.CreateMovie(ctx, movie)
+}
+
+func (u TracedMoviesUsecase) UpdateMovie(
+	ctx context.Context,
+	movie httpModels.MovieWithoutCastList,
+	movieID, version uint64,
+) (_ httpModels.MovieResponse, err error) {
+	ctx, span := tracing.Start(ctx, "MoviesUsecase.UpdateMovie")
+	defer func() { tracing.End(span, err) }()
+	return u.moviesUsecase.UpdateMovie(ctx, movie, movieID, version)
+}
+
+func (u TracedMoviesUsecase) PatchMovie(
+	ctx context.Context,
+	movieID, version uint64,
+	patch domain.Patch,
+) (_ httpModels.MovieResponse, err error) {
+	ctx, span := tracing.Start(ctx, "MoviesUsecase.PatchMovie")
+	defer func() { tracing.End(span, err) }()
+	return u.moviesUsecase.PatchMovie(ctx, movieID, version, patch)
+}
+
+func (u TracedMoviesUsecase) GetMovieByID(
+	ctx context.Context,
+	movieID uint64,
+) (_ httpModels.MovieResponse, err error) {
+	ctx, span := tracing.Start(ctx, "MoviesUsecase.GetMovieByID")
+	defer func() { tracing.End(span, err) }()
+	return u.moviesUsecase.GetMovieByID(ctx, movieID)
+}
+
+func (u TracedMoviesUsecase) DeleteMovieByID(
+	ctx context.Context,
+	movieID, version uint64,
+) (err error) {
+	ctx, span := tracing.Start(ctx, "MoviesUsecase.DeleteMovieByID")
+	defer func() { tracing.End(span, err) }()
+	return u.moviesUsecase.DeleteMovieByID(ctx, movieID, version)
+}
+
+func (u TracedMoviesUsecase) DeleteActorFromMovie(
+	ctx context.Context,
+	movieID, actorID uint64,
+) (err error) {
+	ctx, span := tracing.Start(ctx, "MoviesUsecase.DeleteActorFromMovie")
+	defer func() { tracing.End(span, err) }()
+	return u.moviesUsecase.DeleteActorFromMovie(ctx, movieID, actorID)
+}
+
+func (u TracedMoviesUsecase) AddActorFromMovie(
+	ctx context.Context,
+	movieID, actorID uint64,
+) (err error) {
+	ctx, span := tracing.Start(ctx, "MoviesUsecase.AddActorFromMovie")
+	defer func() { tracing.End(span, err) }()
+	return u.moviesUsecase.AddActorFromMovie(ctx, movieID, actorID)
+}
+
+func (u TracedMoviesUsecase) ReplaceCast(
+	ctx context.Context,
+	movieID, version uint64,
+	cast httpModels.Cast,
+) (_ httpModels.CastDiff, err error) {
+	ctx, span := tracing.Start(ctx, "MoviesUsecase.ReplaceCast")
+	defer func() { tracing.End(span, err) }()
+	return u.moviesUsecase.ReplaceCast(ctx, movieID, version, cast)
+}
+
+func (u TracedMoviesUsecase) BatchCast(
+	ctx context.Context,
+	movieID, version uint64,
+	batch httpModels.CastBatch,
+) (_ httpModels.CastDiff, err error) {
+	ctx, span := tracing.Start(ctx, "MoviesUsecase.BatchCast")
+	defer func() { tracing.End(span, err) }()
+	return u.moviesUsecase.BatchCast(ctx, movieID, version, batch)
+}
+
+func (u TracedMoviesUsecase) GetMovies(
+	ctx context.Context,
+	title, actorName string,
+	sortBy httpModels.SortBy,
+	order bool,
+) (_ []httpModels.MovieResponse, err error) {
+	ctx, span := tracing.Start(ctx, "MoviesUsecase.GetMovies")
+	defer func() { tracing.End(span, err) }()
+	return u.moviesUsecase.GetMovies(ctx, title, actorName, sortBy, order)
+}
@@ -0,0 +1,120 @@
+package httpRevisions
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/problem"
+)
+
+type RevisionsHandler struct {
+	revisionsUsecase domain.RevisionsUsecase
+}
+
+func NewRevisionsHandler(r domain.RevisionsUsecase) RevisionsHandler {
+	return RevisionsHandler{
+		revisionsUsecase: r,
+	}
+}
+
+func (h RevisionsHandler) GetMovieRevisions(w http.ResponseWriter, r *http.Request) {
+	h.getRevisions(w, r, domain.MovieEntity)
+}
+
+func (h RevisionsHandler) GetActorRevisions(w http.ResponseWriter, r *http.Request) {
+	h.getRevisions(w, r, domain.ActorEntity)
+}
+
+func (h RevisionsHandler) getRevisions(w http.ResponseWriter, r *http.Request, entity string) {
+	entityID, err := strconv.ParseUint(r.PathValue("id"), 10, 64)
+	if err != nil {
+		problem.Write(w, r, domain.ErrBadRequest.Wrap(err))
+		return
+	}
+
+	revisions, err := h.revisionsUsecase.GetRevisions(r.Context(), entity, entityID)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	writeJSON(w, r, revisions)
+}
+
+func (h RevisionsHandler) RevertMovie(w http.ResponseWriter, r *http.Request) {
+	movieID, number, err := parseRevisionPath(r)
+	if err != nil {
+		problem.Write(w, r, domain.ErrBadRequest.Wrap(err))
+		return
+	}
+
+	movie, err := h.revisionsUsecase.RevertMovie(r.Context(), movieID, number)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	writeJSON(w, r, movie)
+}
+
+func (h RevisionsHandler) RevertActor(w http.ResponseWriter, r *http.Request) {
+	actorID, number, err := parseRevisionPath(r)
+	if err != nil {
+		problem.Write(w, r, domain.ErrBadRequest.Wrap(err))
+		return
+	}
+
+	actor, err := h.revisionsUsecase.RevertActor(r.Context(), actorID, number)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	writeJSON(w, r, actor)
+}
+
+func (h RevisionsHandler) GetCatalog(w http.ResponseWriter, r *http.Request) {
+	at := time.Now()
+	if atStr := r.URL.Query().Get("at"); atStr != "" {
+		var err error
+		if at, err = time.Parse(time.RFC3339, atStr); err != nil {
+			problem.Write(w, r, domain.ErrBadRequest.Wrap(err))
+			return
+		}
+	}
+
+	catalog, err := h.revisionsUsecase.GetCatalogAt(r.Context(), at)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	writeJSON(w, r, catalog)
+}
+
+func parseRevisionPath(r *http.Request) (uint64, uint64, error) {
+	entityID, err := strconv.ParseUint(r.PathValue("id"), 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	number, err := strconv.ParseUint(r.PathValue("number"), 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	return entityID, number, nil
+}
+
+func writeJSON(w http.ResponseWriter, r *http.Request, data interface{}) {
+	responseData, err := json.Marshal(data)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(responseData)
+}
@@ -0,0 +1,203 @@
+package httpRevisions
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/docs"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	mockDomain "github.com/themilchenko/vk-tech_internship-problem_2024/internal/mocks/domain"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/openapi"
+	"go.uber.org/mock/gomock"
+)
+
+var loadSpec = sync.OnceValues(func() (*openapi.Spec, error) {
+	return openapi.Load(docs.Swagger)
+})
+
+// strict checks the handlers behind mux against the spec, as the API is
+// checked in tests.
+func strict(t *testing.T, mux http.Handler) http.Handler {
+	spec, err := loadSpec()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return spec.Strict(mux)
+}
+
+func TestHandler_GetMovieRevisions(t *testing.T) {
+	type mockBehavior func(r *mockDomain.MockRevisionsUsecase)
+
+	tests := []struct {
+		name                 string
+		movieID              string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:    "OK",
+			movieID: "1",
+			mockBehavior: func(m *mockDomain.MockRevisionsUsecase) {
+				m.EXPECT().
+					GetRevisions(gomock.Any(), domain.MovieEntity, uint64(1)).
+					Return([]httpModels.Revision{{
+						Number:    1,
+						CreatedAt: "2024-03-01T10:00:00Z",
+						UserID:    2,
+						Snapshot:  []byte(`{"id":1,"title":"Dune"}`),
+					}}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `[{"number":1,"createdAt":"2024-03-01T10:00:00Z","userId":2,"deleted":false,"snapshot":{"id":1,"title":"Dune"}}]`,
+		},
+		{
+			name:                 "Bad id",
+			movieID:              "abc",
+			mockBehavior:         func(m *mockDomain.MockRevisionsUsecase) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"bad request: strconv.ParseUint: parsing \"abc\": invalid syntax","code":"bad_request"}`,
+		},
+		{
+			name:    "No history",
+			movieID: "1",
+			mockBehavior: func(m *mockDomain.MockRevisionsUsecase) {
+				m.EXPECT().
+					GetRevisions(gomock.Any(), domain.MovieEntity, uint64(1)).
+					Return(nil, domain.ErrNotFound)
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"type":"about:blank","title":"Not Found","status":404,"detail":"` + domain.ErrNotFound.Error() + `","code":"not_found"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cntx := gomock.NewController(t)
+			defer cntx.Finish()
+
+			mockRevisionsUsecase := mockDomain.NewMockRevisionsUsecase(cntx)
+
+			tt.mockBehavior(mockRevisionsUsecase)
+
+			handler := NewRevisionsHandler(mockRevisionsUsecase)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /movies/{id}/revisions", handler.GetMovieRevisions)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/movies/"+tt.movieID+"/revisions", nil)
+
+			strict(t, mux).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, strings.Trim(w.Body.String(), "\n"))
+		})
+	}
+}
+
+func TestHandler_RevertActor(t *testing.T) {
+	type mockBehavior func(r *mockDomain.MockRevisionsUsecase)
+
+	tests := []struct {
+		name                 string
+		path                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "OK",
+			path: "/actors/3/revisions/2/revert",
+			mockBehavior: func(m *mockDomain.MockRevisionsUsecase) {
+				m.EXPECT().
+					RevertActor(gomock.Any(), uint64(3), uint64(2)).
+					Return(httpModels.ActorResponse{
+						ID:        3,
+						Name:      "John",
+						Gender:    true,
+						BirthDate: "1990-01-01",
+					}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"id":3,"name":"John","gender":true,"birthDate":"1990-01-01"}`,
+		},
+		{
+			name:                 "Bad revision",
+			path:                 "/actors/3/revisions/last/revert",
+			mockBehavior:         func(m *mockDomain.MockRevisionsUsecase) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"bad request: strconv.ParseUint: parsing \"last\": invalid syntax","code":"bad_request"}`,
+		},
+		{
+			name: "Delete revision",
+			path: "/actors/3/revisions/2/revert",
+			mockBehavior: func(m *mockDomain.MockRevisionsUsecase) {
+				m.EXPECT().
+					RevertActor(gomock.Any(), uint64(3), uint64(2)).
+					Return(httpModels.ActorResponse{}, domain.ErrConflict)
+			},
+			expectedStatusCode:   http.StatusConflict,
+			expectedResponseBody: `{"type":"about:blank","title":"Conflict","status":409,"detail":"` + domain.ErrConflict.Error() + `","code":"conflict"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cntx := gomock.NewController(t)
+			defer cntx.Finish()
+
+			mockRevisionsUsecase := mockDomain.NewMockRevisionsUsecase(cntx)
+
+			tt.mockBehavior(mockRevisionsUsecase)
+
+			handler := NewRevisionsHandler(mockRevisionsUsecase)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("POST /actors/{id}/revisions/{number}/revert", handler.RevertActor)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, tt.path, nil)
+
+			strict(t, mux).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, strings.Trim(w.Body.String(), "\n"))
+		})
+	}
+}
+
+func TestHandler_GetCatalog(t *testing.T) {
+	cntx := gomock.NewController(t)
+	defer cntx.Finish()
+
+	at := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	mockRevisionsUsecase := mockDomain.NewMockRevisionsUsecase(cntx)
+	mockRevisionsUsecase.EXPECT().
+		GetCatalogAt(gomock.Any(), at).
+		Return(httpModels.Catalog{
+			At:     "2024-03-01T10:00:00Z",
+			Movies: []httpModels.MovieWithoutCastList{},
+			Actors: []httpModels.ActorResponse{},
+		}, nil)
+
+	handler := NewRevisionsHandler(mockRevisionsUsecase)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /catalog", handler.GetCatalog)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/catalog?at=2024-03-01T10:00:00Z", nil)
+
+	strict(t, mux).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"at":"2024-03-01T10:00:00Z","movies":[],"actors":[]}`, w.Body.String())
+}
@@ -0,0 +1,52 @@
+package revisionsRepository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	gormModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/gorm"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/requestctx"
+	"gorm.io/gorm"
+)
+
+// Record appends the next revision of the entity. Like the audit log it has
+// to run in the transaction of the change. The caller is expected to hold a
+// lock on the entity row, otherwise two concurrent updates race for the same
+// number and one of them fails on the unique index.
+func Record(
+	ctx context.Context,
+	tx *gorm.DB,
+	entity string,
+	entityID uint64,
+	snapshot interface{},
+	deleted bool,
+) error {
+	encodedSnapshot, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	var last gormModels.Revision
+	number := uint64(1)
+	err = tx.Session(&gorm.Session{NewDB: true}).
+		Where("entity = ? AND entity_id = ?", entity, entityID).
+		Order("number DESC").
+		Take(&last).
+		Error
+	switch {
+	case err == nil:
+		number = last.Number + 1
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return err
+	}
+
+	return tx.Session(&gorm.Session{NewDB: true}).Create(&gormModels.Revision{
+		Entity:   entity,
+		EntityID: entityID,
+		Number:   number,
+		UserID:   requestctx.UserID(ctx),
+		Deleted:  deleted,
+		Snapshot: string(encodedSnapshot),
+	}).Error
+}
@@ -0,0 +1,105 @@
+package revisionsRepository
+
+import (
+	"context"
+	"time"
+
+	gormModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/gorm"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/dbtx"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/metrics"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/pgerrors"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/sqlcomment"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/tracing"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+)
+
+type Postgres struct {
+	DB *gorm.DB
+}
+
+func NewPostgres(url string, logger gormLogger.Interface) (*Postgres, error) {
+	db, err := gorm.Open(postgres.Open(url), &gorm.Config{Logger: logger})
+	if err != nil {
+		return nil, err
+	}
+	if err = db.Use(pgerrors.Plugin{}); err != nil {
+		return nil, err
+	}
+	if err = db.Use(dbtx.Plugin{}); err != nil {
+		return nil, err
+	}
+	if err = db.Use(sqlcomment.Plugin{}); err != nil {
+		return nil, err
+	}
+	if err = db.Use(metrics.Plugin{DB: "revisions"}); err != nil {
+		return nil, err
+	}
+	if err = db.Use(tracing.Plugin{DB: "revisions"}); err != nil {
+		return nil, err
+	}
+
+	db.AutoMigrate(
+		gormModels.Revision{},
+	)
+
+	return &Postgres{
+		DB: db,
+	}, nil
+}
+
+func (db Postgres) GetRevisions(
+	ctx context.Context,
+	entity string,
+	entityID uint64,
+) ([]gormModels.Revision, error) {
+	var recievedRevisions []gormModels.Revision
+	if err := db.DB.WithContext(ctx).
+		Where("entity = ? AND entity_id = ?", entity, entityID).
+		Order("number DESC").
+		Find(&recievedRevisions).
+		Error; err != nil {
+		return nil, err
+	}
+	return recievedRevisions, nil
+}
+
+func (db Postgres) GetRevision(
+	ctx context.Context,
+	entity string,
+	entityID, number uint64,
+) (gormModels.Revision, error) {
+	var recievedRevision gormModels.Revision
+	if err := db.DB.WithContext(ctx).
+		Where("entity = ? AND entity_id = ? AND number = ?", entity, entityID, number).
+		First(&recievedRevision).
+		Error; err != nil {
+		return gormModels.Revision{}, err
+	}
+	return recievedRevision, nil
+}
+
+// GetRevisionsAt returns the last revision of every entity of the type made
+// up to the given time, deleted entities excluded.
+func (db Postgres) GetRevisionsAt(
+	ctx context.Context,
+	entity string,
+	at time.Time,
+) ([]gormModels.Revision, error) {
+	latest := db.DB.Model(&gormModels.Revision{}).
+		Select("DISTINCT ON (entity_id) *").
+		Where("entity = ? AND created_at <= ?", entity, at).
+		Order("entity_id, number DESC")
+
+	var recievedRevisions []gormModels.Revision
+	if err := db.DB.WithContext(ctx).
+		Table("(?) AS latest", latest).
+		Where("deleted = ?", false).
+		Order("entity_id").
+		Find(&recievedRevisions).
+		Error; err != nil {
+		return nil, err
+	}
+	return recievedRevisions, nil
+}
@@ -0,0 +1,60 @@
+package revisionsUsecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/cache"
+)
+
+// CachedRevisionsUsecase drops the cached reads a revert makes stale.
+type CachedRevisionsUsecase struct {
+	revisionsUsecase domain.RevisionsUsecase
+	cache            domain.Cache
+}
+
+func NewCachedRevisionsUsecase(u domain.RevisionsUsecase, c domain.Cache) CachedRevisionsUsecase {
+	return CachedRevisionsUsecase{
+		revisionsUsecase: u,
+		cache:            c,
+	}
+}
+
+func (u CachedRevisionsUsecase) GetRevisions(
+	ctx context.Context,
+	entity string,
+	entityID uint64,
+) ([]httpModels.Revision, error) {
+	return u.revisionsUsecase.GetRevisions(ctx, entity, entityID)
+}
+
+func (u CachedRevisionsUsecase) RevertMovie(
+	ctx context.Context,
+	movieID, number uint64,
+) (httpModels.MovieResponse, error) {
+	movie, err := u.revisionsUsecase.RevertMovie(ctx, movieID, number)
+	if err == nil {
+		u.cache.Invalidate(ctx, cache.MovieTag(movieID), cache.MoviesTag, cache.ActorsTag)
+	}
+	return movie, err
+}
+
+func (u CachedRevisionsUsecase) RevertActor(
+	ctx context.Context,
+	actorID, number uint64,
+) (httpModels.ActorResponse, error) {
+	actor, err := u.revisionsUsecase.RevertActor(ctx, actorID, number)
+	if err == nil {
+		u.cache.Invalidate(ctx, cache.ActorTag(actorID), cache.ActorsTag, cache.MoviesTag)
+	}
+	return actor, err
+}
+
+func (u CachedRevisionsUsecase) GetCatalogAt(
+	ctx context.Context,
+	at time.Time,
+) (httpModels.Catalog, error) {
+	return u.revisionsUsecase.GetCatalogAt(ctx, at)
+}
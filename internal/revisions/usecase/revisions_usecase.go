@@ -0,0 +1,173 @@
+package revisionsUsecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	gormModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/gorm"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	"gorm.io/gorm"
+)
+
+type RevisionsUsecase struct {
+	revisionsRepository domain.RevisionsRepository
+	moviesRepository    domain.MoviesRepository
+	actorsRepository    domain.ActorsRepository
+}
+
+func NewRevisionsUsecase(
+	r domain.RevisionsRepository,
+	m domain.MoviesRepository,
+	a domain.ActorsRepository,
+) RevisionsUsecase {
+	return RevisionsUsecase{
+		revisionsRepository: r,
+		moviesRepository:    m,
+		actorsRepository:    a,
+	}
+}
+
+func (u RevisionsUsecase) GetRevisions(
+	ctx context.Context,
+	entity string,
+	entityID uint64,
+) ([]httpModels.Revision, error) {
+	revisions, err := u.revisionsRepository.GetRevisions(ctx, entity, entityID)
+	if err != nil {
+		return nil, err
+	}
+	if len(revisions) == 0 {
+		return nil, domain.ErrNotFound
+	}
+
+	httpRevisions := make([]httpModels.Revision, len(revisions))
+	for i, r := range revisions {
+		httpRevisions[i] = r.ToHTTPModel()
+	}
+	return httpRevisions, nil
+}
+
+// RevertMovie writes the state of an old revision back. This is a regular
+// update, so it makes a new revision and leaves the history untouched.
+func (u RevisionsUsecase) RevertMovie(
+	ctx context.Context,
+	movieID, number uint64,
+) (httpModels.MovieResponse, error) {
+	var snapshot httpModels.MovieWithoutCastList
+	if err := u.loadSnapshot(ctx, domain.MovieEntity, movieID, number, &snapshot); err != nil {
+		return httpModels.MovieResponse{}, err
+	}
+
+	t, err := time.Parse(time.DateOnly, snapshot.ReleaseDate)
+	if err != nil {
+		return httpModels.MovieResponse{}, err
+	}
+
+	movie, err := u.moviesRepository.UpdateMovie(ctx, gormModels.Movie{
+		ID:          movieID,
+		Title:       snapshot.Title,
+		Description: snapshot.Description,
+		ReleaseDate: t,
+		Rating:      snapshot.Rating,
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return httpModels.MovieResponse{}, domain.ErrNotFound
+		}
+		return httpModels.MovieResponse{}, err
+	}
+	slog.InfoContext(ctx, "movie reverted", "movie_id", movieID, "revision", number)
+	return movie.ToHTTPResponse(), nil
+}
+
+func (u RevisionsUsecase) RevertActor(
+	ctx context.Context,
+	actorID, number uint64,
+) (httpModels.ActorResponse, error) {
+	var snapshot httpModels.ActorResponse
+	if err := u.loadSnapshot(ctx, domain.ActorEntity, actorID, number, &snapshot); err != nil {
+		return httpModels.ActorResponse{}, err
+	}
+
+	t, err := time.Parse(time.DateOnly, snapshot.BirthDate)
+	if err != nil {
+		return httpModels.ActorResponse{}, err
+	}
+
+	actor, err := u.actorsRepository.UpdateActor(ctx, gormModels.Actor{
+		ID:        actorID,
+		Name:      snapshot.Name,
+		Gender:    snapshot.Gender,
+		BirthDate: t,
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return httpModels.ActorResponse{}, domain.ErrNotFound
+		}
+		return httpModels.ActorResponse{}, err
+	}
+	slog.InfoContext(ctx, "actor reverted", "actor_id", actorID, "revision", number)
+	return actor.ToHTTPModel(), nil
+}
+
+func (u RevisionsUsecase) loadSnapshot(
+	ctx context.Context,
+	entity string,
+	entityID, number uint64,
+	snapshot interface{},
+) error {
+	revision, err := u.revisionsRepository.GetRevision(ctx, entity, entityID, number)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.ErrNotFound
+		}
+		return err
+	}
+	// A delete revision holds the last state before the delete, but
+	// bringing deleted entities back is what the trash is for.
+	if revision.Deleted {
+		return domain.ErrConflict
+	}
+
+	return json.Unmarshal([]byte(revision.Snapshot), snapshot)
+}
+
+// GetCatalogAt rebuilds movies and actors from their revisions. Cast links
+// are not versioned, so they are not part of the result.
+func (u RevisionsUsecase) GetCatalogAt(ctx context.Context, at time.Time) (httpModels.Catalog, error) {
+	catalog := httpModels.Catalog{
+		At:     at.Format(time.RFC3339),
+		Movies: []httpModels.MovieWithoutCastList{},
+		Actors: []httpModels.ActorResponse{},
+	}
+
+	movies, err := u.revisionsRepository.GetRevisionsAt(ctx, domain.MovieEntity, at)
+	if err != nil {
+		return httpModels.Catalog{}, err
+	}
+	for _, r := range movies {
+		var movie httpModels.MovieWithoutCastList
+		if err = json.Unmarshal([]byte(r.Snapshot), &movie); err != nil {
+			return httpModels.Catalog{}, err
+		}
+		catalog.Movies = append(catalog.Movies, movie)
+	}
+
+	actors, err := u.revisionsRepository.GetRevisionsAt(ctx, domain.ActorEntity, at)
+	if err != nil {
+		return httpModels.Catalog{}, err
+	}
+	for _, r := range actors {
+		var actor httpModels.ActorResponse
+		if err = json.Unmarshal([]byte(r.Snapshot), &actor); err != nil {
+			return httpModels.Catalog{}, err
+		}
+		catalog.Actors = append(catalog.Actors, actor)
+	}
+
+	return catalog, nil
+}
@@ -0,0 +1,206 @@
+package revisionsUsecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	mockDomain "github.com/themilchenko/vk-tech_internship-problem_2024/internal/mocks/domain"
+	gormModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/gorm"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestUsecase_GetRevisions(t *testing.T) {
+	type mockBehavior func(r *mockDomain.MockRevisionsRepository)
+
+	createdAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name              string
+		mockBehavior      mockBehavior
+		expectedRevisions []httpModels.Revision
+		expectedError     error
+	}{
+		{
+			name: "OK",
+			mockBehavior: func(m *mockDomain.MockRevisionsRepository) {
+				m.EXPECT().
+					GetRevisions(gomock.Any(), domain.MovieEntity, uint64(1)).
+					Return([]gormModels.Revision{{
+						ID:        5,
+						CreatedAt: createdAt,
+						Entity:    domain.MovieEntity,
+						EntityID:  1,
+						Number:    2,
+						UserID:    3,
+						Snapshot:  `{"id":1,"title":"Dune"}`,
+					}}, nil)
+			},
+			expectedRevisions: []httpModels.Revision{{
+				Number:    2,
+				CreatedAt: "2024-03-01T10:00:00Z",
+				UserID:    3,
+				Snapshot:  []byte(`{"id":1,"title":"Dune"}`),
+			}},
+			expectedError: nil,
+		},
+		{
+			name: "No history",
+			mockBehavior: func(m *mockDomain.MockRevisionsRepository) {
+				m.EXPECT().
+					GetRevisions(gomock.Any(), domain.MovieEntity, uint64(1)).
+					Return([]gormModels.Revision{}, nil)
+			},
+			expectedRevisions: nil,
+			expectedError:     domain.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mockDomain.NewMockRevisionsRepository(ctrl)
+			tt.mockBehavior(mockRepo)
+
+			u := NewRevisionsUsecase(mockRepo, nil, nil)
+
+			revisions, err := u.GetRevisions(context.Background(), domain.MovieEntity, 1)
+			assert.Equal(t, tt.expectedRevisions, revisions)
+			assert.ErrorIs(t, err, tt.expectedError)
+		})
+	}
+}
+
+func TestUsecase_RevertMovie(t *testing.T) {
+	type mockBehavior func(r *mockDomain.MockRevisionsRepository, m *mockDomain.MockMoviesRepository)
+
+	releaseDate := time.Date(2021, 10, 22, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		mockBehavior  mockBehavior
+		expectedMovie httpModels.MovieResponse
+		expectedError error
+	}{
+		{
+			name: "OK",
+			mockBehavior: func(r *mockDomain.MockRevisionsRepository, m *mockDomain.MockMoviesRepository) {
+				r.EXPECT().
+					GetRevision(gomock.Any(), domain.MovieEntity, uint64(1), uint64(2)).
+					Return(gormModels.Revision{
+						Snapshot: `{"id":1,"title":"Dune","description":"Sand","releaseDate":"2021-10-22","rating":8}`,
+					}, nil)
+				m.EXPECT().
+					UpdateMovie(gomock.Any(), gormModels.Movie{
+						ID:          1,
+						Title:       "Dune",
+						Description: "Sand",
+						ReleaseDate: releaseDate,
+						Rating:      8,
+					}).
+					DoAndReturn(func(_ context.Context, movie gormModels.Movie) (gormModels.Movie, error) {
+						return movie, nil
+					})
+			},
+			expectedMovie: httpModels.MovieResponse{
+				ID:          1,
+				Title:       "Dune",
+				Description: "Sand",
+				ReleaseDate: "2021-10-22",
+				Rating:      8,
+			},
+			expectedError: nil,
+		},
+		{
+			name: "Unknown revision",
+			mockBehavior: func(r *mockDomain.MockRevisionsRepository, m *mockDomain.MockMoviesRepository) {
+				r.EXPECT().
+					GetRevision(gomock.Any(), domain.MovieEntity, uint64(1), uint64(2)).
+					Return(gormModels.Revision{}, gorm.ErrRecordNotFound)
+			},
+			expectedMovie: httpModels.MovieResponse{},
+			expectedError: domain.ErrNotFound,
+		},
+		{
+			name: "Delete revision",
+			mockBehavior: func(r *mockDomain.MockRevisionsRepository, m *mockDomain.MockMoviesRepository) {
+				r.EXPECT().
+					GetRevision(gomock.Any(), domain.MovieEntity, uint64(1), uint64(2)).
+					Return(gormModels.Revision{Deleted: true, Snapshot: `{"id":1}`}, nil)
+			},
+			expectedMovie: httpModels.MovieResponse{},
+			expectedError: domain.ErrConflict,
+		},
+		{
+			name: "Movie is gone",
+			mockBehavior: func(r *mockDomain.MockRevisionsRepository, m *mockDomain.MockMoviesRepository) {
+				r.EXPECT().
+					GetRevision(gomock.Any(), domain.MovieEntity, uint64(1), uint64(2)).
+					Return(gormModels.Revision{
+						Snapshot: `{"id":1,"title":"Dune","releaseDate":"2021-10-22"}`,
+					}, nil)
+				m.EXPECT().
+					UpdateMovie(gomock.Any(), gomock.Any()).
+					Return(gormModels.Movie{}, gorm.ErrRecordNotFound)
+			},
+			expectedMovie: httpModels.MovieResponse{},
+			expectedError: domain.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRevisionsRepo := mockDomain.NewMockRevisionsRepository(ctrl)
+			mockMoviesRepo := mockDomain.NewMockMoviesRepository(ctrl)
+			tt.mockBehavior(mockRevisionsRepo, mockMoviesRepo)
+
+			u := NewRevisionsUsecase(mockRevisionsRepo, mockMoviesRepo, nil)
+
+			movie, err := u.RevertMovie(context.Background(), 1, 2)
+			assert.Equal(t, tt.expectedMovie, movie)
+			assert.ErrorIs(t, err, tt.expectedError)
+		})
+	}
+}
+
+func TestUsecase_GetCatalogAt(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	at := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	mockRepo := mockDomain.NewMockRevisionsRepository(ctrl)
+	mockRepo.EXPECT().
+		GetRevisionsAt(gomock.Any(), domain.MovieEntity, at).
+		Return([]gormModels.Revision{{
+			Snapshot: `{"id":1,"title":"Dune","description":"Sand","releaseDate":"2021-10-22","rating":8}`,
+		}}, nil)
+	mockRepo.EXPECT().
+		GetRevisionsAt(gomock.Any(), domain.ActorEntity, at).
+		Return([]gormModels.Revision{}, nil)
+
+	u := NewRevisionsUsecase(mockRepo, nil, nil)
+
+	catalog, err := u.GetCatalogAt(context.Background(), at)
+	assert.NoError(t, err)
+	assert.Equal(t, httpModels.Catalog{
+		At: "2024-03-01T10:00:00Z",
+		Movies: []httpModels.MovieWithoutCastList{{
+			ID:          1,
+			Title:       "Dune",
+			Description: "Sand",
+			ReleaseDate: "2021-10-22",
+			Rating:      8,
+		}},
+		Actors: []httpModels.ActorResponse{},
+	}, catalog)
+}
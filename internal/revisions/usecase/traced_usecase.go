@@ -0,0 +1,57 @@
+package revisionsUsecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/tracing"
+)
+
+// TracedRevisionsUsecase makes a span of every call, reverts show the
+// reads of the revision and the write of the entity under it.
+type TracedRevisionsUsecase struct {
+	revisionsUsecase domain.RevisionsUsecase
+}
+
+func NewTracedRevisionsUsecase(u domain.RevisionsUsecase) TracedRevisionsUsecase {
+	return TracedRevisionsUsecase{revisionsUsecase: u}
+}
+
+func (u TracedRevisionsUsecase) GetRevisions(
+	ctx context.Context,
+	entity string,
+	entityID uint64,
+) (_ []httpModels.Revision, err error) {
+	ctx, span := tracing.Start(ctx, "RevisionsUsecase.GetRevisions")
+	defer func() { tracing.End(span, err) }()
+	return u.revisionsUsecase.GetRevisions(ctx, entity, entityID)
+}
+
+func (u TracedRevisionsUsecase) RevertMovie(
+	ctx context.Context,
+	movieID, number uint64,
+) (_ httpModels.MovieResponse, err error) {
+	ctx, span := tracing.Start(ctx, "RevisionsUsecase.RevertMovie")
+	defer func() { tracing.End(span, err) }()
+	return u.revisionsUsecase.RevertMovie(ctx, movieID, number)
+}
+
+func (u TracedRevisionsUsecase) RevertActor(
+	ctx context.Context,
+	actorID, number uint64,
+) (_ httpModels.ActorResponse, err error) {
+	ctx, span := tracing.Start(ctx, "RevisionsUsecase.RevertActor")
+	defer func() { tracing.End(span, err) }()
+	return u.revisionsUsecase.RevertActor(ctx, actorID, number)
+}
+
+func (u TracedRevisionsUsecase) GetCatalogAt(
+	ctx context.Context,
+	at time.Time,
+) (_ httpModels.Catalog, err error) {
+	ctx, span := tracing.Start(ctx, "RevisionsUsecase.GetCatalogAt")
+	defer func() { tracing.End(span, err) }()
+	return u.revisionsUsecase.GetCatalogAt(ctx, at)
+}
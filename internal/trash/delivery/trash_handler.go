@@ -0,0 +1,65 @@
+package httpTrash
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/problem"
+)
+
+type TrashHandler struct {
+	trashUsecase domain.TrashUsecase
+}
+
+func NewTrashHandler(t domain.TrashUsecase) TrashHandler {
+	return TrashHandler{
+		trashUsecase: t,
+	}
+}
+
+func (h TrashHandler) GetTrash(w http.ResponseWriter, r *http.Request) {
+	var page uint64
+	if pageStr := r.URL.Query().Get("page"); pageStr != "" {
+		var err error
+		if page, err = strconv.ParseUint(pageStr, 10, 64); err != nil {
+			problem.Write(w, r, domain.ErrBadRequest.Wrap(err))
+			return
+		}
+	}
+
+	items, err := h.trashUsecase.GetTrash(r.Context(), r.URL.Query().Get("type"), page)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	responseData, err := json.Marshal(items)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(responseData)
+}
+
+func (h TrashHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	entityID, err := strconv.ParseUint(r.PathValue("id"), 10, 64)
+	if err != nil {
+		problem.Write(w, r, domain.ErrBadRequest.Wrap(err))
+		return
+	}
+
+	if err := h.trashUsecase.Restore(r.Context(), r.PathValue("type"), entityID); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(httpModels.EmptyModel)
+}
@@ -0,0 +1,173 @@
+package httpTrash
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/docs"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	mockDomain "github.com/themilchenko/vk-tech_internship-problem_2024/internal/mocks/domain"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/openapi"
+	"go.uber.org/mock/gomock"
+)
+
+var loadSpec = sync.OnceValues(func() (*openapi.Spec, error) {
+	return openapi.Load(docs.Swagger)
+})
+
+// strict checks the handlers behind mux against the spec, as the API is
+// checked in tests.
+func strict(t *testing.T, mux http.Handler) http.Handler {
+	spec, err := loadSpec()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return spec.Strict(mux)
+}
+
+func TestHandler_GetTrash(t *testing.T) {
+	type mockBehavior func(r *mockDomain.MockTrashUsecase)
+
+	tests := []struct {
+		name                 string
+		query                string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:  "OK",
+			query: "?type=movie&page=2",
+			mockBehavior: func(m *mockDomain.MockTrashUsecase) {
+				m.EXPECT().
+					GetTrash(gomock.Any(), "movie", uint64(2)).
+					Return([]httpModels.TrashItem{{
+						Type:      "movie",
+						ID:        1,
+						Name:      "Dune",
+						DeletedAt: "2024-03-01T10:00:00Z",
+						PurgeAt:   "2024-03-31T10:00:00Z",
+					}}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `[{"type":"movie","id":1,"name":"Dune","deletedAt":"2024-03-01T10:00:00Z","purgeAt":"2024-03-31T10:00:00Z"}]`,
+		},
+		{
+			name:                 "Bad page",
+			query:                "?page=first",
+			mockBehavior:         func(m *mockDomain.MockTrashUsecase) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"bad request: strconv.ParseUint: parsing \"first\": invalid syntax","code":"bad_request"}`,
+		},
+		{
+			name:  "Unknown type",
+			query: "?type=user",
+			mockBehavior: func(m *mockDomain.MockTrashUsecase) {
+				m.EXPECT().GetTrash(gomock.Any(), "user", uint64(0)).Return(nil, domain.ErrBadRequest)
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"` + domain.ErrBadRequest.Error() + `","code":"bad_request"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cntx := gomock.NewController(t)
+			defer cntx.Finish()
+
+			mockTrashUsecase := mockDomain.NewMockTrashUsecase(cntx)
+
+			tt.mockBehavior(mockTrashUsecase)
+
+			handler := NewTrashHandler(mockTrashUsecase)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /trash", handler.GetTrash)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/trash"+tt.query, nil)
+
+			strict(t, mux).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, strings.Trim(w.Body.String(), "\n"))
+		})
+	}
+}
+
+func TestHandler_Restore(t *testing.T) {
+	type mockBehavior func(r *mockDomain.MockTrashUsecase)
+
+	tests := []struct {
+		name                 string
+		path                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "OK",
+			path: "/trash/actor/3/restore",
+			mockBehavior: func(m *mockDomain.MockTrashUsecase) {
+				m.EXPECT().Restore(gomock.Any(), "actor", uint64(3)).Return(nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{}`,
+		},
+		{
+			name:                 "Bad id",
+			path:                 "/trash/actor/abc/restore",
+			mockBehavior:         func(m *mockDomain.MockTrashUsecase) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"bad request: strconv.ParseUint: parsing \"abc\": invalid syntax","code":"bad_request"}`,
+		},
+		{
+			name: "Not in trash",
+			path: "/trash/movie/3/restore",
+			mockBehavior: func(m *mockDomain.MockTrashUsecase) {
+				m.EXPECT().Restore(gomock.Any(), "movie", uint64(3)).Return(domain.ErrNotFound)
+			},
+			expectedStatusCode:   http.StatusNotFound,
+			expectedResponseBody: `{"type":"about:blank","title":"Not Found","status":404,"detail":"` + domain.ErrNotFound.Error() + `","code":"not_found"}`,
+		},
+		{
+			name: "Internal server error",
+			path: "/trash/movie/3/restore",
+			mockBehavior: func(m *mockDomain.MockTrashUsecase) {
+				m.EXPECT().Restore(gomock.Any(), "movie", uint64(3)).Return(errors.New("db is down"))
+			},
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"server error","code":"internal_error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cntx := gomock.NewController(t)
+			defer cntx.Finish()
+
+			mockTrashUsecase := mockDomain.NewMockTrashUsecase(cntx)
+
+			tt.mockBehavior(mockTrashUsecase)
+
+			handler := NewTrashHandler(mockTrashUsecase)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("POST /trash/{type}/{id}/restore", handler.Restore)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, tt.path, nil)
+
+			strict(t, mux).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, strings.Trim(w.Body.String(), "\n"))
+		})
+	}
+}
@@ -0,0 +1,254 @@
+package trashRepository
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	auditRepository "github.com/themilchenko/vk-tech_internship-problem_2024/internal/audit/repository"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	gormModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/gorm"
+	moviesRepository "github.com/themilchenko/vk-tech_internship-problem_2024/internal/movies/repository"
+	revisionsRepository "github.com/themilchenko/vk-tech_internship-problem_2024/internal/revisions/repository"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/dbtx"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/metrics"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/pgerrors"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/sqlcomment"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/tracing"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	gormLogger "gorm.io/gorm/logger"
+)
+
+const castEntity = "cast"
+
+type Postgres struct {
+	DB *gorm.DB
+
+	pageSize uint64
+}
+
+func NewPostgres(url string, ps uint64, logger gormLogger.Interface) (*Postgres, error) {
+	db, err := gorm.Open(postgres.Open(url), &gorm.Config{Logger: logger})
+	if err != nil {
+		return nil, err
+	}
+	if err = db.Use(pgerrors.Plugin{}); err != nil {
+		return nil, err
+	}
+	if err = db.Use(dbtx.Plugin{}); err != nil {
+		return nil, err
+	}
+	if err = db.Use(sqlcomment.Plugin{}); err != nil {
+		return nil, err
+	}
+	if err = db.Use(metrics.Plugin{DB: "trash"}); err != nil {
+		return nil, err
+	}
+	if err = db.Use(tracing.Plugin{DB: "trash"}); err != nil {
+		return nil, err
+	}
+
+	db.AutoMigrate(
+		gormModels.Movie{},
+		gormModels.Actor{},
+		gormModels.ActorMovieRelation{},
+		gormModels.AuditEntry{},
+		gormModels.Revision{},
+	)
+
+	return &Postgres{
+		DB:       db,
+		pageSize: ps,
+	}, nil
+}
+
+// GetTrash lists deleted movies and actors, most recently deleted first. An
+// empty entity lists both.
+func (db Postgres) GetTrash(
+	ctx context.Context,
+	entity string,
+	page uint64,
+) ([]gormModels.TrashItem, error) {
+	tx := db.DB.WithContext(ctx)
+
+	movies := tx.Unscoped().Model(&gormModels.Movie{}).
+		Select("? AS entity, id, title AS name, deleted_at", domain.MovieEntity).
+		Where("deleted_at IS NOT NULL")
+	actors := tx.Unscoped().Model(&gormModels.Actor{}).
+		Select("? AS entity, id, name, deleted_at", domain.ActorEntity).
+		Where("deleted_at IS NOT NULL")
+
+	query := tx.Table("(?) AS trash", tx.Raw("? UNION ALL ?", movies, actors))
+	if entity != "" {
+		query = query.Where("entity = ?", entity)
+	}
+
+	offset := db.pageSize * (page - 1)
+	var recievedItems []gormModels.TrashItem
+	if err := query.Order("deleted_at DESC, id").
+		Offset(int(offset)).
+		Limit(int(db.pageSize)).
+		Find(&recievedItems).
+		Error; err != nil {
+		return nil, err
+	}
+	return recievedItems, nil
+}
+
+func (db Postgres) RestoreMovie(ctx context.Context, movieID uint64) error {
+	return db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var movie gormModels.Movie
+		if err := tx.Unscoped().
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("deleted_at IS NOT NULL").
+			First(&movie, "id = ?", movieID).
+			Error; err != nil {
+			return err
+		}
+
+		if err := tx.Unscoped().Model(&gormModels.Movie{}).
+			Where("id = ?", movieID).
+			UpdateColumns(map[string]interface{}{
+				"deleted_at": nil,
+				"version":    gorm.Expr("version + 1"),
+			}).
+			Error; err != nil {
+			return err
+		}
+		if err := restoreCast(
+			ctx, tx, "movie_id = ? AND actor_id IN (SELECT id FROM actors WHERE deleted_at IS NULL)",
+			movieID,
+		); err != nil {
+			return err
+		}
+
+		if err := auditRepository.RecordRestore(
+			ctx, tx, domain.MovieEntity, movieID, movie,
+		); err != nil {
+			return err
+		}
+		return revisionsRepository.Record(
+			ctx, tx, domain.MovieEntity, movieID, movie.ToHTTPMovies(), false,
+		)
+	})
+}
+
+func (db Postgres) RestoreActor(ctx context.Context, actorID uint64) error {
+	return db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var actor gormModels.Actor
+		if err := tx.Unscoped().
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("deleted_at IS NOT NULL").
+			First(&actor, "id = ?", actorID).
+			Error; err != nil {
+			return err
+		}
+
+		if err := tx.Unscoped().Model(&gormModels.Actor{}).
+			Where("id = ?", actorID).
+			UpdateColumns(map[string]interface{}{
+				"deleted_at": nil,
+				"version":    gorm.Expr("version + 1"),
+			}).
+			Error; err != nil {
+			return err
+		}
+		if err := restoreCast(
+			ctx, tx, "actor_id = ? AND movie_id IN (SELECT id FROM movies WHERE deleted_at IS NULL)",
+			actorID,
+		); err != nil {
+			return err
+		}
+
+		if err := auditRepository.RecordRestore(
+			ctx, tx, domain.ActorEntity, actorID, actor,
+		); err != nil {
+			return err
+		}
+		return revisionsRepository.Record(
+			ctx, tx, domain.ActorEntity, actorID, actor.ToHTTPModel(), false,
+		)
+	})
+}
+
+// restoreCast brings back the deleted cast links picked by the condition.
+// Links are only soft deleted together with a movie or an actor, so every
+// deleted link whose other side is alive belongs back. The movies that get
+// their cast back get a new version.
+func restoreCast(ctx context.Context, tx *gorm.DB, condition string, entityID uint64) error {
+	var relations []gormModels.ActorMovieRelation
+	if err := tx.Unscoped().
+		Where("deleted_at IS NOT NULL").
+		Where(condition, entityID).
+		Find(&relations).
+		Error; err != nil {
+		return err
+	}
+
+	movieIDs := make([]uint64, 0, len(relations))
+	for _, relation := range relations {
+		if err := tx.Unscoped().Model(&gormModels.ActorMovieRelation{}).
+			Where("id = ?", relation.ID).
+			UpdateColumn("deleted_at", nil).
+			Error; err != nil {
+			return err
+		}
+		if err := auditRepository.RecordRestore(
+			ctx, tx, castEntity, relation.MovieID, relation,
+		); err != nil {
+			return err
+		}
+		movieIDs = append(movieIDs, relation.MovieID)
+	}
+	return moviesRepository.TouchMovies(tx, movieIDs...)
+}
+
+// Purge removes movies and actors that were deleted before the given time
+// for good, together with their cast links. It returns how many movies and
+// actors were removed.
+func (db Postgres) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	var purged int64
+	if err := db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var movieIDs, actorIDs []uint64
+		if err := tx.Unscoped().Model(&gormModels.Movie{}).
+			Where("deleted_at < ?", deletedBefore).
+			Pluck("id", &movieIDs).
+			Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Model(&gormModels.Actor{}).
+			Where("deleted_at < ?", deletedBefore).
+			Pluck("id", &actorIDs).
+			Error; err != nil {
+			return err
+		}
+		if len(movieIDs) == 0 && len(actorIDs) == 0 {
+			return nil
+		}
+		slog.DebugContext(ctx, "purging trash", "movies", len(movieIDs), "actors", len(actorIDs))
+
+		if err := tx.Unscoped().
+			Where("movie_id IN ? OR actor_id IN ?", movieIDs, actorIDs).
+			Delete(&gormModels.ActorMovieRelation{}).
+			Error; err != nil {
+			return err
+		}
+
+		movies := tx.Unscoped().Where("id IN ?", movieIDs).Delete(&gormModels.Movie{})
+		if movies.Error != nil {
+			return movies.Error
+		}
+		actors := tx.Unscoped().Where("id IN ?", actorIDs).Delete(&gormModels.Actor{})
+		if actors.Error != nil {
+			return actors.Error
+		}
+
+		purged = movies.RowsAffected + actors.RowsAffected
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+	return purged, nil
+}
@@ -0,0 +1,53 @@
+package trashUsecase
+
+import (
+	"context"
+
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/cache"
+)
+
+// CachedTrashUsecase drops the cached reads a restore makes stale. A
+// restored actor gets its cast links back in movies it doesn't know, so
+// every single movie entry goes.
+type CachedTrashUsecase struct {
+	trashUsecase domain.TrashUsecase
+	cache        domain.Cache
+}
+
+func NewCachedTrashUsecase(u domain.TrashUsecase, c domain.Cache) CachedTrashUsecase {
+	return CachedTrashUsecase{
+		trashUsecase: u,
+		cache:        c,
+	}
+}
+
+func (u CachedTrashUsecase) GetTrash(
+	ctx context.Context,
+	entity string,
+	page uint64,
+) ([]httpModels.TrashItem, error) {
+	return u.trashUsecase.GetTrash(ctx, entity, page)
+}
+
+func (u CachedTrashUsecase) Restore(ctx context.Context, entity string, entityID uint64) error {
+	if err := u.trashUsecase.Restore(ctx, entity, entityID); err != nil {
+		return err
+	}
+
+	switch entity {
+	case domain.MovieEntity:
+		u.cache.Invalidate(ctx, cache.MovieTag(entityID), cache.MoviesTag, cache.ActorsTag)
+	case domain.ActorEntity:
+		u.cache.Invalidate(
+			ctx, cache.ActorTag(entityID), cache.AnyMovieTag, cache.ActorsTag, cache.MoviesTag,
+		)
+	}
+	return nil
+}
+
+// Purge only removes items that are already gone from every read.
+func (u CachedTrashUsecase) Purge(ctx context.Context) (int64, error) {
+	return u.trashUsecase.Purge(ctx)
+}
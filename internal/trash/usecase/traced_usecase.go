@@ -0,0 +1,45 @@
+package trashUsecase
+
+import (
+	"context"
+
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/tracing"
+)
+
+// TracedTrashUsecase makes a span of every call, including the periodic
+// Purge, which starts a trace of its own.
+type TracedTrashUsecase struct {
+	trashUsecase domain.TrashUsecase
+}
+
+func NewTracedTrashUsecase(u domain.TrashUsecase) TracedTrashUsecase {
+	return TracedTrashUsecase{trashUsecase: u}
+}
+
+func (u TracedTrashUsecase) GetTrash(
+	ctx context.Context,
+	entity string,
+	page uint64,
+) (_ []httpModels.TrashItem, err error) {
+	ctx, span := tracing.Start(ctx, "TrashUsecase.GetTrash")
+	defer func() { tracing.End(span, err) }()
+	return u.trashUsecase.GetTrash(ctx, entity, page)
+}
+
+func (u TracedTrashUsecase) Restore(
+	ctx context.Context,
+	entity string,
+	entityID uint64,
+) (err error) {
+	ctx, span := tracing.Start(ctx, "TrashUsecase.Restore")
+	defer func() { tracing.End(span, err) }()
+	return u.trashUsecase.Restore(ctx, entity, entityID)
+}
+
+func (u TracedTrashUsecase) Purge(ctx context.Context) (_ int64, err error) {
+	ctx, span := tracing.Start(ctx, "TrashUsecase.Purge")
+	defer func() { tracing.End(span, err) }()
+	return u.trashUsecase.Purge(ctx)
+}
@@ -0,0 +1,86 @@
+package trashUsecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/config"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	"gorm.io/gorm"
+)
+
+const defaultPage = 1
+
+type TrashUsecase struct {
+	trashRepository domain.TrashRepository
+	settings        config.TrashSettings
+}
+
+func NewTrashUsecase(t domain.TrashRepository, s config.TrashSettings) TrashUsecase {
+	return TrashUsecase{
+		trashRepository: t,
+		settings:        s,
+	}
+}
+
+func (u TrashUsecase) GetTrash(
+	ctx context.Context,
+	entity string,
+	page uint64,
+) ([]httpModels.TrashItem, error) {
+	if entity != "" && entity != domain.MovieEntity && entity != domain.ActorEntity {
+		return nil, domain.ErrBadRequest
+	}
+	if page == 0 {
+		page = defaultPage
+	}
+
+	items, err := u.trashRepository.GetTrash(ctx, entity, page)
+	if err != nil {
+		return nil, err
+	}
+
+	httpItems := make([]httpModels.TrashItem, len(items))
+	for i, item := range items {
+		httpItems[i] = item.ToHTTPModel()
+		if u.settings.PurgeAfter > 0 {
+			httpItems[i].PurgeAt = item.DeletedAt.Add(u.settings.PurgeAfter).Format(time.RFC3339)
+		}
+	}
+	return httpItems, nil
+}
+
+// Restore brings a movie or an actor back together with the cast links that
+// were deleted with it.
+func (u TrashUsecase) Restore(ctx context.Context, entity string, entityID uint64) error {
+	var err error
+	switch entity {
+	case domain.MovieEntity:
+		err = u.trashRepository.RestoreMovie(ctx, entityID)
+	case domain.ActorEntity:
+		err = u.trashRepository.RestoreActor(ctx, entityID)
+	default:
+		return domain.ErrNotFound
+	}
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	slog.InfoContext(ctx, "restored from trash", "entity", entity, "id", entityID)
+	return nil
+}
+
+// Purge removes everything that has been in the trash for longer than the
+// retention period.
+func (u TrashUsecase) Purge(ctx context.Context) (int64, error) {
+	if u.settings.PurgeAfter <= 0 {
+		return 0, nil
+	}
+	return u.trashRepository.Purge(ctx, time.Now().Add(-u.settings.PurgeAfter))
+}
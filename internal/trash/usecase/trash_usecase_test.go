@@ -0,0 +1,174 @@
+package trashUsecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/config"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	mockDomain "github.com/themilchenko/vk-tech_internship-problem_2024/internal/mocks/domain"
+	gormModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/gorm"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestUsecase_GetTrash(t *testing.T) {
+	type mockBehavior func(r *mockDomain.MockTrashRepository)
+
+	deletedAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		entity        string
+		settings      config.TrashSettings
+		mockBehavior  mockBehavior
+		expectedItems []httpModels.TrashItem
+		expectedError error
+	}{
+		{
+			name:     "With retention",
+			entity:   "movie",
+			settings: config.TrashSettings{PurgeAfter: 24 * time.Hour},
+			mockBehavior: func(m *mockDomain.MockTrashRepository) {
+				m.EXPECT().
+					GetTrash(gomock.Any(), "movie", uint64(1)).
+					Return([]gormModels.TrashItem{{
+						Entity:    "movie",
+						ID:        1,
+						Name:      "Dune",
+						DeletedAt: deletedAt,
+					}}, nil)
+			},
+			expectedItems: []httpModels.TrashItem{{
+				Type:      "movie",
+				ID:        1,
+				Name:      "Dune",
+				DeletedAt: "2024-03-01T10:00:00Z",
+				PurgeAt:   "2024-03-02T10:00:00Z",
+			}},
+			expectedError: nil,
+		},
+		{
+			name:     "Kept forever",
+			settings: config.TrashSettings{},
+			mockBehavior: func(m *mockDomain.MockTrashRepository) {
+				m.EXPECT().
+					GetTrash(gomock.Any(), "", uint64(1)).
+					Return([]gormModels.TrashItem{{
+						Entity:    "actor",
+						ID:        2,
+						Name:      "John",
+						DeletedAt: deletedAt,
+					}}, nil)
+			},
+			expectedItems: []httpModels.TrashItem{{
+				Type:      "actor",
+				ID:        2,
+				Name:      "John",
+				DeletedAt: "2024-03-01T10:00:00Z",
+			}},
+			expectedError: nil,
+		},
+		{
+			name:          "Unknown type",
+			entity:        "user",
+			mockBehavior:  func(m *mockDomain.MockTrashRepository) {},
+			expectedItems: nil,
+			expectedError: domain.ErrBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mockDomain.NewMockTrashRepository(ctrl)
+			tt.mockBehavior(mockRepo)
+
+			u := NewTrashUsecase(mockRepo, tt.settings)
+
+			items, err := u.GetTrash(context.Background(), tt.entity, 0)
+			assert.Equal(t, tt.expectedItems, items)
+			assert.ErrorIs(t, err, tt.expectedError)
+		})
+	}
+}
+
+func TestUsecase_Restore(t *testing.T) {
+	type mockBehavior func(r *mockDomain.MockTrashRepository)
+
+	tests := []struct {
+		name          string
+		entity        string
+		mockBehavior  mockBehavior
+		expectedError error
+	}{
+		{
+			name:   "Movie",
+			entity: "movie",
+			mockBehavior: func(m *mockDomain.MockTrashRepository) {
+				m.EXPECT().RestoreMovie(gomock.Any(), uint64(1)).Return(nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:   "Actor not in trash",
+			entity: "actor",
+			mockBehavior: func(m *mockDomain.MockTrashRepository) {
+				m.EXPECT().RestoreActor(gomock.Any(), uint64(1)).Return(gorm.ErrRecordNotFound)
+			},
+			expectedError: domain.ErrNotFound,
+		},
+		{
+			name:          "Unknown type",
+			entity:        "user",
+			mockBehavior:  func(m *mockDomain.MockTrashRepository) {},
+			expectedError: domain.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mockDomain.NewMockTrashRepository(ctrl)
+			tt.mockBehavior(mockRepo)
+
+			u := NewTrashUsecase(mockRepo, config.TrashSettings{})
+
+			err := u.Restore(context.Background(), tt.entity, 1)
+			assert.ErrorIs(t, err, tt.expectedError)
+		})
+	}
+}
+
+func TestUsecase_Purge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mockDomain.NewMockTrashRepository(ctrl)
+
+	// Without retention nothing is ever purged.
+	u := NewTrashUsecase(mockRepo, config.TrashSettings{})
+	purged, err := u.Purge(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), purged)
+
+	start := time.Now()
+	mockRepo.EXPECT().
+		Purge(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, deletedBefore time.Time) (int64, error) {
+			assert.WithinDuration(t, start.Add(-time.Hour), deletedBefore, time.Second)
+			return 3, nil
+		})
+
+	u = NewTrashUsecase(mockRepo, config.TrashSettings{PurgeAfter: time.Hour})
+	purged, err = u.Purge(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), purged)
+}
@@ -0,0 +1,96 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/dbtx"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/problem"
+)
+
+// Tags of cached reads. Movie and actor tags mark entries built from one
+// item, the list tags mark lists that any write of that kind can change.
+const (
+	MoviesTag = "movies"
+	ActorsTag = "actors"
+	// AnyMovieTag marks every single movie entry, for writes that change
+	// the cast of movies they don't know.
+	AnyMovieTag = "movie"
+)
+
+func MovieTag(movieID uint64) string {
+	return fmt.Sprintf("movie:%d", movieID)
+}
+
+func ActorTag(actorID uint64) string {
+	return fmt.Sprintf("actor:%d", actorID)
+}
+
+// Load returns the value cached under key, or loads it and caches it with
+// the tags. Errors are never cached, and a broken entry is loaded again. A
+// value whose tags were invalidated while it was loading may be stale and is
+// not cached.
+// Reads in a shared transaction may see writes that are rolled back later,
+// so they are not cached either.
+func Load[T any](
+	ctx context.Context,
+	c domain.Cache,
+	key string,
+	tags func(T) []string,
+	load func() (T, error),
+) (T, error) {
+	if data, ok := c.Get(ctx, key); ok {
+		var cached T
+		if err := json.Unmarshal(data, &cached); err == nil {
+			return cached, nil
+		}
+	}
+
+	generation := c.Generation(ctx)
+	value, err := load()
+	if err != nil || dbtx.Active(ctx) {
+		return value, err
+	}
+	if data, err := json.Marshal(value); err == nil {
+		c.Set(ctx, key, data, generation, tags(value)...)
+	}
+	return value, nil
+}
+
+// Control sets Cache-Control on successful reads. Responses depend on the
+// session, so they are private. With a zero maxAge clients have to
+// revalidate, which is cheap with the ETags of single items.
+func Control(maxAge time.Duration) func(http.HandlerFunc) http.HandlerFunc {
+	value := "private, no-cache"
+	if maxAge > 0 {
+		value = "private, max-age=" + strconv.Itoa(int(maxAge.Seconds()))
+	}
+
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Cache-Control", value)
+			next(w, r)
+		}
+	}
+}
+
+// StatsHandler reports the hit and miss counters of the cache.
+func StatsHandler(c domain.Cache) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		responseData, err := json.Marshal(c.Stats(r.Context()))
+		if err != nil {
+			problem.Write(w, r, err)
+			return
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		w.WriteHeader(http.StatusOK)
+		w.Write(responseData)
+	}
+}
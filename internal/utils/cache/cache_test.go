@@ -0,0 +1,60 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad_InvalidatedWhileLoading(t *testing.T) {
+	tests := []struct {
+		name           string
+		invalidate     []string
+		expectedCached bool
+	}{
+		{
+			name:           "Not invalidated",
+			expectedCached: true,
+		},
+		{
+			name:       "Own tag invalidated",
+			invalidate: []string{MovieTag(1)},
+		},
+		{
+			name:           "Other tag invalidated",
+			invalidate:     []string{MovieTag(2)},
+			expectedCached: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			c := NewLRU(10, 0)
+
+			started := make(chan struct{})
+			release := make(chan struct{})
+			done := make(chan string)
+			go func() {
+				value, _ := Load(ctx, c, "movie:1", func(string) []string {
+					return []string{MovieTag(1)}
+				}, func() (string, error) {
+					close(started)
+					<-release
+					return "Heat", nil
+				})
+				done <- value
+			}()
+
+			// The write lands after the read, but before its result is cached.
+			<-started
+			c.Invalidate(ctx, tt.invalidate...)
+			close(release)
+			assert.Equal(t, "Heat", <-done)
+
+			_, ok := c.Get(ctx, "movie:1")
+			assert.Equal(t, tt.expectedCached, ok)
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/dbtx"
+)
+
+type entry struct {
+	key       string
+	value     []byte
+	tags      []string
+	expiresAt time.Time
+}
+
+// LRU is an in-process domain.Cache holding at most capacity entries. The
+// least recently used entry goes first when it's full, entries older than
+// ttl are never served. A zero ttl keeps entries until they are evicted or
+// invalidated.
+type LRU struct {
+	mu       sync.Mutex
+	capacity int
+	ttl      time.Duration
+	order    *list.List
+	byKey    map[string]*list.Element
+	byTag    map[string]map[string]struct{}
+
+	// generation counts invalidations, invalidated keeps the generation of
+	// the last one of every tag.
+	generation  uint64
+	invalidated map[string]uint64
+
+	hits      uint64
+	misses    uint64
+	evictions uint64
+
+	now func() time.Time
+}
+
+func NewLRU(capacity int, ttl time.Duration) *LRU {
+	return &LRU{
+		capacity: capacity,
+		ttl:      ttl,
+		order:    list.New(),
+		byKey:    make(map[string]*list.Element),
+		byTag:    make(map[string]map[string]struct{}),
+		now:      time.Now,
+
+		invalidated: make(map[string]uint64),
+	}
+}
+
+func (c *LRU) Get(_ context.Context, key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.byKey[key]
+	if !ok {
+		c.misses++
+		return nil, false
+	}
+	e := element.Value.(*entry)
+	if !e.expiresAt.IsZero() && !c.now().Before(e.expiresAt) {
+		c.remove(element)
+		c.misses++
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+	c.hits++
+	return e.value, true
+}
+
+func (c *LRU) Generation(_ context.Context) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.generation
+}
+
+func (c *LRU) Set(
+	_ context.Context,
+	key string,
+	value []byte,
+	generation uint64,
+	tags ...string,
+) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.capacity <= 0 {
+		return
+	}
+	for _, tag := range tags {
+		if c.invalidated[tag] > generation {
+			return
+		}
+	}
+	if element, ok := c.byKey[key]; ok {
+		c.remove(element)
+	}
+
+	e := &entry{key: key, value: value, tags: tags}
+	if c.ttl > 0 {
+		e.expiresAt = c.now().Add(c.ttl)
+	}
+	c.byKey[key] = c.order.PushFront(e)
+	for _, tag := range tags {
+		keys, ok := c.byTag[tag]
+		if !ok {
+			keys = make(map[string]struct{})
+			c.byTag[tag] = keys
+		}
+		keys[key] = struct{}{}
+	}
+
+	for c.order.Len() > c.capacity {
+		c.remove(c.order.Back())
+		c.evictions++
+	}
+}
+
+// Invalidate drops the entries now and, for a write in a shared transaction,
+// once more when it ends, since other requests may have cached what the
+// write replaces before it was committed.
+func (c *LRU) Invalidate(ctx context.Context, tags ...string) {
+	dbtx.AfterEnd(ctx, func() {
+		c.invalidate(tags)
+	})
+	c.invalidate(tags)
+}
+
+func (c *LRU) invalidate(tags []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	for _, tag := range tags {
+		c.invalidated[tag] = c.generation
+		for key := range c.byTag[tag] {
+			if element, ok := c.byKey[key]; ok {
+				c.remove(element)
+			}
+		}
+	}
+}
+
+func (c *LRU) Stats(_ context.Context) httpModels.CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return httpModels.CacheStats{
+		Hits:      c.hits,
+		Misses:    c.misses,
+		Evictions: c.evictions,
+		Entries:   c.order.Len(),
+		Capacity:  c.capacity,
+	}
+}
+
+// remove drops the entry from the list and both indexes. The caller holds
+// the lock.
+func (c *LRU) remove(element *list.Element) {
+	e := c.order.Remove(element).(*entry)
+	delete(c.byKey, e.key)
+	for _, tag := range e.tags {
+		delete(c.byTag[tag], e.key)
+		if len(c.byTag[tag]) == 0 {
+			delete(c.byTag, tag)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+)
+
+func TestLRU_Evict(t *testing.T) {
+	ctx := context.Background()
+	c := NewLRU(2, 0)
+
+	c.Set(ctx, "a", []byte("1"), c.Generation(ctx))
+	c.Set(ctx, "b", []byte("2"), c.Generation(ctx))
+	c.Get(ctx, "a")
+	c.Set(ctx, "c", []byte("3"), c.Generation(ctx))
+
+	_, ok := c.Get(ctx, "b")
+	assert.False(t, ok, "least recently used entry must be evicted")
+	value, ok := c.Get(ctx, "a")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("1"), value)
+
+	assert.Equal(t, httpModels.CacheStats{
+		Hits:      2,
+		Misses:    1,
+		Evictions: 1,
+		Entries:   2,
+		Capacity:  2,
+	}, c.Stats(ctx))
+}
+
+func TestLRU_TTL(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	c := NewLRU(10, time.Minute)
+	c.now = func() time.Time { return now }
+
+	c.Set(ctx, "a", []byte("1"), c.Generation(ctx))
+	now = now.Add(59 * time.Second)
+	_, ok := c.Get(ctx, "a")
+	assert.True(t, ok)
+
+	now = now.Add(time.Second)
+	_, ok = c.Get(ctx, "a")
+	assert.False(t, ok)
+	assert.Equal(t, 0, c.Stats(ctx).Entries)
+}
+
+func TestLRU_Invalidate(t *testing.T) {
+	ctx := context.Background()
+	c := NewLRU(10, 0)
+
+	c.Set(ctx, "movie:1", []byte("1"), c.Generation(ctx), MovieTag(1), ActorTag(7))
+	c.Set(ctx, "movie:2", []byte("2"), c.Generation(ctx), MovieTag(2))
+	c.Set(ctx, "movies", []byte("[]"), c.Generation(ctx), MoviesTag)
+
+	c.Invalidate(ctx, ActorTag(7))
+
+	_, ok := c.Get(ctx, "movie:1")
+	assert.False(t, ok)
+	_, ok = c.Get(ctx, "movie:2")
+	assert.True(t, ok)
+	_, ok = c.Get(ctx, "movies")
+	assert.True(t, ok)
+
+	// Replacing an entry drops its old tags.
+	c.Set(ctx, "movie:2", []byte("2"), c.Generation(ctx), MovieTag(2), ActorTag(8))
+	c.Set(ctx, "movie:2", []byte("2"), c.Generation(ctx), MovieTag(2))
+	c.Invalidate(ctx, ActorTag(8))
+	_, ok = c.Get(ctx, "movie:2")
+	assert.True(t, ok)
+}
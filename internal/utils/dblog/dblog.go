@@ -0,0 +1,87 @@
+// Package dblog makes gorm log through slog. Queries are debug records,
+// slow queries are warnings and failed ones are errors, unless they failed
+// for the client, like a missing row or a broken unique constraint. Queries
+// are logged with placeholders, never with their arguments.
+package dblog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+)
+
+type Logger struct {
+	slowThreshold time.Duration
+	silent        bool
+}
+
+// New makes the logger of gorm.Config. Queries slower than slowThreshold
+// are warnings, 0 turns it off.
+func New(slowThreshold time.Duration) *Logger {
+	return &Logger{slowThreshold: slowThreshold}
+}
+
+// LogMode only tells apart silent sessions, levels are up to slog.
+func (l *Logger) LogMode(level gormLogger.LogLevel) gormLogger.Interface {
+	logger := *l
+	logger.silent = level == gormLogger.Silent
+	return &logger
+}
+
+func (l *Logger) Info(ctx context.Context, msg string, args ...interface{}) {
+	l.log(ctx, slog.LevelInfo, fmt.Sprintf(msg, args...))
+}
+
+func (l *Logger) Warn(ctx context.Context, msg string, args ...interface{}) {
+	l.log(ctx, slog.LevelWarn, fmt.Sprintf(msg, args...))
+}
+
+func (l *Logger) Error(ctx context.Context, msg string, args ...interface{}) {
+	l.log(ctx, slog.LevelError, fmt.Sprintf(msg, args...))
+}
+
+// ParamsFilter keeps the bind values out of the logged SQL: they carry
+// session ids, password hashes and TOTP secrets. gorm skips it for Scan,
+// which logs through a recorder of its own, so secrets are not queried with
+// Scan.
+func (l *Logger) ParamsFilter(ctx context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	return sql, nil
+}
+
+func (l *Logger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	if l.silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	level, msg := slog.LevelDebug, "query"
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound) && domain.AsError(err).Kind == domain.KindInternal:
+		level, msg = slog.LevelError, "query failed"
+	case l.slowThreshold > 0 && elapsed > l.slowThreshold:
+		level, msg = slog.LevelWarn, "slow query"
+	}
+	if !slog.Default().Enabled(ctx, level) {
+		return
+	}
+
+	sql, rows := fc()
+	attrs := []interface{}{"sql", sql, "rows", rows, "duration", elapsed}
+	if err != nil {
+		attrs = append(attrs, "error", err)
+	}
+	slog.Log(ctx, level, msg, attrs...)
+}
+
+func (l *Logger) log(ctx context.Context, level slog.Level, msg string) {
+	if l.silent {
+		return
+	}
+	slog.Log(ctx, level, msg, "component", "gorm")
+}
@@ -0,0 +1,45 @@
+package dblog
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestLogger_HidesArguments(t *testing.T) {
+	var out bytes.Buffer
+	defaultLogger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&out, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	defer slog.SetDefault(defaultLogger)
+
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	db, err := gorm.Open(postgres.New(postgres.Config{
+		Conn: sqlDB,
+	}), &gorm.Config{Logger: New(0), DisableAutomaticPing: true})
+	require.NoError(t, err)
+
+	mock.ExpectQuery(`SELECT (.+) FROM "sessions"`).
+		WithArgs("cc982d23-e3e0-439e-a8b7-7fff4eeeb2f2", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+
+	var session struct{ UserID uint64 }
+	require.NoError(t, db.WithContext(context.Background()).
+		Table("sessions").
+		Where("session_id = ?", "cc982d23-e3e0-439e-a8b7-7fff4eeeb2f2").
+		Take(&session).
+		Error)
+
+	assert.Equal(t, uint64(1), session.UserID)
+	assert.Contains(t, out.String(), "session_id = $1")
+	assert.NotContains(t, out.String(), "cc982d23-e3e0-439e-a8b7-7fff4eeeb2f2")
+}
@@ -0,0 +1,222 @@
+// Package dbtx runs the statements of several repositories in one database
+// transaction carried by the context. Repositories opt in with Plugin and
+// stay unaware of it: their statements go to the transaction of the
+// context, if there is one, and their own transactions become savepoints
+// of it.
+package dbtx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/metrics"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type contextKey struct{}
+
+// Tx is a transaction shared through the context.
+type Tx struct {
+	tx         *sql.Tx
+	savepoints int
+
+	mu       sync.Mutex
+	afterEnd []func()
+}
+
+// DB begins the shared transactions.
+type DB struct {
+	db *sql.DB
+}
+
+func NewPostgres(url string) (*DB, error) {
+	db, err := gorm.Open(postgres.Open(url), &gorm.Config{})
+	if err != nil {
+		return nil, err
+	}
+	if err = db.Use(metrics.Plugin{DB: "transactions"}); err != nil {
+		return nil, err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+
+	return &DB{
+		db: sqlDB,
+	}, nil
+}
+
+// Begin starts a transaction and returns the context that carries it.
+func (d *DB) Begin(ctx context.Context) (context.Context, domain.Transaction, error) {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return ctx, nil, err
+	}
+
+	shared := &Tx{tx: tx}
+	return context.WithValue(ctx, contextKey{}, shared), shared, nil
+}
+
+func (t *Tx) Commit() error {
+	defer t.end()
+	return t.tx.Commit()
+}
+
+func (t *Tx) Rollback() error {
+	defer t.end()
+	return t.tx.Rollback()
+}
+
+func (t *Tx) end() {
+	t.mu.Lock()
+	afterEnd := t.afterEnd
+	t.afterEnd = nil
+	t.mu.Unlock()
+
+	for _, fn := range afterEnd {
+		fn()
+	}
+}
+
+// Active tells whether the context carries a transaction.
+func Active(ctx context.Context) bool {
+	return from(ctx) != nil
+}
+
+// AfterEnd makes fn run once the transaction of the context is committed or
+// rolled back. It reports false and does nothing without a transaction.
+func AfterEnd(ctx context.Context, fn func()) bool {
+	t := from(ctx)
+	if t == nil {
+		return false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.afterEnd = append(t.afterEnd, fn)
+	return true
+}
+
+func from(ctx context.Context) *Tx {
+	if ctx == nil {
+		return nil
+	}
+	t, _ := ctx.Value(contextKey{}).(*Tx)
+	return t
+}
+
+// Plugin sends the statements of a gorm connection to the transaction of
+// their context.
+type Plugin struct{}
+
+func (Plugin) Name() string {
+	return "dbtx"
+}
+
+func (Plugin) Initialize(db *gorm.DB) error {
+	if _, ok := db.ConnPool.(pool); ok {
+		return nil
+	}
+	db.ConnPool = pool{ConnPool: db.ConnPool}
+	db.Statement.ConnPool = db.ConnPool
+	return nil
+}
+
+// pool is the connection pool of gorm that defers to the transaction of the
+// context.
+type pool struct {
+	gorm.ConnPool
+}
+
+func (p pool) conn(ctx context.Context) gorm.ConnPool {
+	if t := from(ctx); t != nil {
+		return t.tx
+	}
+	return p.ConnPool
+}
+
+func (p pool) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return p.conn(ctx).PrepareContext(ctx, query)
+}
+
+func (p pool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return p.conn(ctx).ExecContext(ctx, query, args...)
+}
+
+func (p pool) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return p.conn(ctx).QueryContext(ctx, query, args...)
+}
+
+func (p pool) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return p.conn(ctx).QueryRowContext(ctx, query, args...)
+}
+
+// BeginTx starts a transaction of a repository, a savepoint when the
+// context already has a transaction.
+func (p pool) BeginTx(ctx context.Context, opts *sql.TxOptions) (gorm.ConnPool, error) {
+	if t := from(ctx); t != nil {
+		t.mu.Lock()
+		t.savepoints++
+		name := fmt.Sprintf("dbtx_%d", t.savepoints)
+		t.mu.Unlock()
+
+		if _, err := t.tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+			return nil, err
+		}
+		return &savepoint{tx: t.tx, name: name}, nil
+	}
+
+	beginner, ok := p.ConnPool.(gorm.TxBeginner)
+	if !ok {
+		return nil, errors.New("connection pool can't begin transactions")
+	}
+	return beginner.BeginTx(ctx, opts)
+}
+
+// GetDBConn keeps gorm.DB.DB working.
+func (p pool) GetDBConn() (*sql.DB, error) {
+	if db, ok := p.ConnPool.(*sql.DB); ok {
+		return db, nil
+	}
+	return nil, gorm.ErrInvalidDB
+}
+
+// savepoint is a repository transaction inside the shared one. Committing it
+// releases the savepoint, rolling it back undoes only its own statements.
+// It is passed by pointer, gorm checks transactions for nil with reflect.
+type savepoint struct {
+	tx   *sql.Tx
+	name string
+}
+
+func (s *savepoint) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return s.tx.PrepareContext(ctx, query)
+}
+
+func (s *savepoint) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return s.tx.ExecContext(ctx, query, args...)
+}
+
+func (s *savepoint) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return s.tx.QueryContext(ctx, query, args...)
+}
+
+func (s *savepoint) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return s.tx.QueryRowContext(ctx, query, args...)
+}
+
+func (s *savepoint) Commit() error {
+	_, err := s.tx.Exec("RELEASE SAVEPOINT " + s.name)
+	return err
+}
+
+func (s *savepoint) Rollback() error {
+	_, err := s.tx.Exec("ROLLBACK TO SAVEPOINT " + s.name)
+	return err
+}
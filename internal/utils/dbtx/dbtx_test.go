@@ -0,0 +1,207 @@
+package dbtx
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func openDB(t *testing.T) (*DB, *gorm.DB, sqlmock.Sqlmock) {
+	sqlDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	db, err := gorm.Open(postgres.New(postgres.Config{
+		Conn: sqlDB,
+	}), &gorm.Config{DisableAutomaticPing: true})
+	require.NoError(t, err)
+	require.NoError(t, db.Use(Plugin{}))
+	return &DB{db: sqlDB}, db, mock
+}
+
+func TestPlugin_Savepoints(t *testing.T) {
+	tests := []struct {
+		name         string
+		mockBehavior func(mock sqlmock.Sqlmock)
+		inner        error
+	}{
+		{
+			name: "Released",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("SAVEPOINT dbtx_1").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("DELETE FROM movies").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("RELEASE SAVEPOINT dbtx_1").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Rolled back",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("SAVEPOINT dbtx_1").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("DELETE FROM movies").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("ROLLBACK TO SAVEPOINT dbtx_1").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+			inner: errors.New("cast is gone"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shared, db, mock := openDB(t)
+			tt.mockBehavior(mock)
+
+			ctx, tx, err := shared.Begin(context.Background())
+			require.NoError(t, err)
+
+			err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+				if err := tx.Exec("DELETE FROM movies").Error; err != nil {
+					return err
+				}
+				return tt.inner
+			})
+			assert.Equal(t, tt.inner, err)
+
+			assert.NoError(t, tx.Commit())
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPlugin_Statements(t *testing.T) {
+	tests := []struct {
+		name         string
+		shared       bool
+		mockBehavior func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name:   "Shared transaction",
+			shared: true,
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("DELETE FROM movies").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT title FROM movies").
+					WillReturnRows(sqlmock.NewRows([]string{"title"}).AddRow("Heat"))
+				mock.ExpectRollback()
+			},
+		},
+		{
+			name: "No transaction",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("DELETE FROM movies").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT title FROM movies").
+					WillReturnRows(sqlmock.NewRows([]string{"title"}).AddRow("Heat"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shared, db, mock := openDB(t)
+			tt.mockBehavior(mock)
+
+			ctx := context.Background()
+			var tx domain.Transaction
+			if tt.shared {
+				var err error
+				ctx, tx, err = shared.Begin(ctx)
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.shared, Active(ctx))
+
+			assert.NoError(t, db.WithContext(ctx).Exec("DELETE FROM movies").Error)
+			var titles []string
+			assert.NoError(t, db.WithContext(ctx).Raw("SELECT title FROM movies").Scan(&titles).Error)
+			assert.Equal(t, []string{"Heat"}, titles)
+
+			if tx != nil {
+				assert.NoError(t, tx.Rollback())
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPlugin_OwnTransaction(t *testing.T) {
+	_, db, mock := openDB(t)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM movies").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, db.WithContext(context.Background()).Transaction(func(tx *gorm.DB) error {
+		return tx.Exec("DELETE FROM movies").Error
+	}))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPlugin_DB(t *testing.T) {
+	_, db, _ := openDB(t)
+
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NotNil(t, sqlDB)
+}
+
+func TestAfterEnd(t *testing.T) {
+	tests := []struct {
+		name         string
+		mockBehavior func(mock sqlmock.Sqlmock)
+		end          func(tx domain.Transaction) error
+	}{
+		{
+			name: "Commit",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectCommit()
+			},
+			end: func(tx domain.Transaction) error {
+				return tx.Commit()
+			},
+		},
+		{
+			name: "Rollback",
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectRollback()
+			},
+			end: func(tx domain.Transaction) error {
+				return tx.Rollback()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shared, _, mock := openDB(t)
+			tt.mockBehavior(mock)
+
+			ctx, tx, err := shared.Begin(context.Background())
+			require.NoError(t, err)
+
+			var calls int
+			assert.True(t, AfterEnd(ctx, func() { calls++ }))
+			assert.Equal(t, 0, calls)
+
+			assert.NoError(t, tt.end(tx))
+			assert.Equal(t, 1, calls)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAfterEnd_NoTransaction(t *testing.T) {
+	var called bool
+	assert.False(t, AfterEnd(context.Background(), func() { called = true }))
+	assert.False(t, called)
+	assert.False(t, Active(context.Background()))
+}
@@ -0,0 +1,110 @@
+package etag
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/problem"
+)
+
+// Format makes the strong ETag of an item version.
+func Format(version uint64) string {
+	return `"` + strconv.FormatUint(version, 10) + `"`
+}
+
+// IfMatch returns the version the If-Match header asks for. Zero means any
+// version, the header is missing or "*". Weak and foreign ETags never match.
+// A list matches if any of its ETags does, so only then current is asked for
+// the version of the item. The write checks the version it gets as usual.
+func IfMatch(r *http.Request, current func() (uint64, error)) (uint64, error) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+
+	tags := strings.Split(header, ",")
+	if len(tags) == 1 {
+		version, ok := parse(header)
+		if !ok {
+			return 0, domain.ErrVersionMismatch
+		}
+		return version, nil
+	}
+
+	var versions []uint64
+	for _, tag := range tags {
+		if version, ok := parse(strings.TrimSpace(tag)); ok {
+			versions = append(versions, version)
+		}
+	}
+	if len(versions) == 0 {
+		return 0, domain.ErrVersionMismatch
+	}
+
+	version, err := current()
+	if err != nil {
+		return 0, err
+	}
+	if !slices.Contains(versions, version) {
+		return 0, domain.ErrVersionMismatch
+	}
+	return version, nil
+}
+
+// parse reads a strong ETag made by Format.
+func parse(tag string) (uint64, bool) {
+	unquoted, ok := strings.CutPrefix(tag, `"`)
+	if !ok {
+		return 0, false
+	}
+	unquoted, ok = strings.CutSuffix(unquoted, `"`)
+	if !ok {
+		return 0, false
+	}
+	version, err := strconv.ParseUint(unquoted, 10, 64)
+	if err != nil || version == 0 {
+		return 0, false
+	}
+	return version, true
+}
+
+// NoneMatch reports whether none of the ETags in the If-None-Match header is
+// the one of version, so the item has to be sent.
+func NoneMatch(r *http.Request, version uint64) bool {
+	header := strings.TrimSpace(r.Header.Get("If-None-Match"))
+	if header == "" {
+		return true
+	}
+	if header == "*" {
+		return false
+	}
+
+	// If-None-Match uses the weak comparison.
+	for _, tag := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(tag), "W/") == Format(version) {
+			return false
+		}
+	}
+	return true
+}
+
+// Required rejects requests without If-Match with 428, so clients can't
+// overwrite changes they haven't seen. It does nothing when required is
+// false.
+func Required(required bool) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		if !required {
+			return next
+		}
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("If-Match") == "" {
+				problem.Write(w, r, domain.ErrIfMatchRequired)
+				return
+			}
+			next(w, r)
+		}
+	}
+}
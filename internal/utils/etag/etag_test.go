@@ -0,0 +1,83 @@
+package etag
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+)
+
+func TestIfMatch(t *testing.T) {
+	tests := []struct {
+		name            string
+		header          string
+		current         uint64
+		expectedVersion uint64
+		expectedError   error
+	}{
+		{name: "Missing", header: ""},
+		{name: "Any", header: "*"},
+		{name: "Strong", header: `"7"`, expectedVersion: 7},
+		{name: "Weak", header: `W/"7"`, expectedError: domain.ErrVersionMismatch},
+		{name: "Unquoted", header: `7`, expectedError: domain.ErrVersionMismatch},
+		{name: "Foreign", header: `"abc"`, expectedError: domain.ErrVersionMismatch},
+		{name: "List", header: `"6", "7"`, current: 7, expectedVersion: 7},
+		{name: "List with foreign tags", header: `W/"7", "abc",  "7"`, current: 7, expectedVersion: 7},
+		{name: "List, no match", header: `"3", "4"`, current: 7, expectedError: domain.ErrVersionMismatch},
+		{name: "List, none is ours", header: `W/"7", "abc"`, expectedError: domain.ErrVersionMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/", nil)
+			if tt.header != "" {
+				req.Header.Set("If-Match", tt.header)
+			}
+
+			version, err := IfMatch(req, func() (uint64, error) {
+				if tt.current == 0 {
+					t.Error("the current version is not needed")
+				}
+				return tt.current, nil
+			})
+			assert.Equal(t, tt.expectedVersion, version)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestIfMatch_ItemNotFound(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPut, "/", nil)
+	req.Header.Set("If-Match", `"3", "4"`)
+
+	_, err := IfMatch(req, func() (uint64, error) {
+		return 0, domain.ErrNotFound
+	})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestRequired(t *testing.T) {
+	next := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	w := httptest.NewRecorder()
+	Required(true)(next)(w, httptest.NewRequest(http.MethodDelete, "/", nil))
+	assert.Equal(t, http.StatusPreconditionRequired, w.Code)
+
+	w = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodDelete, "/", nil)
+	req.Header.Set("If-Match", `"1"`)
+	Required(true)(next)(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	w = httptest.NewRecorder()
+	Required(false)(next)(w, httptest.NewRequest(http.MethodDelete, "/", nil))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
@@ -0,0 +1,115 @@
+package grpcstatus
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// Domain is the domain of the ErrorInfo details, the reason is the code of
+// the domain error.
+const Domain = "filmlibrary"
+
+var statusCodes = map[domain.Kind]codes.Code{
+	domain.KindInternal:             codes.Internal,
+	domain.KindBadRequest:           codes.InvalidArgument,
+	domain.KindValidation:           codes.InvalidArgument,
+	domain.KindUnauthenticated:      codes.Unauthenticated,
+	domain.KindForbidden:            codes.PermissionDenied,
+	domain.KindNotFound:             codes.NotFound,
+	domain.KindConflict:             codes.AlreadyExists,
+	domain.KindTooManyRequests:      codes.ResourceExhausted,
+	domain.KindPayloadTooLarge:      codes.ResourceExhausted,
+	domain.KindUnsupportedMediaType: codes.InvalidArgument,
+	domain.KindPreconditionFailed:   codes.FailedPrecondition,
+	domain.KindPreconditionRequired: codes.FailedPrecondition,
+	domain.KindFailedDependency:     codes.Aborted,
+}
+
+// New is problem.New for gRPC: err as a status with the code of the domain
+// error in an ErrorInfo and the violations of a failed validation in a
+// BadRequest. Details of internal errors are not shown to clients.
+func New(err error) *status.Status {
+	if s, ok := status.FromError(err); ok {
+		return s
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err)
+	}
+
+	domainErr := domain.AsError(err)
+	code, ok := statusCodes[domainErr.Kind]
+	if !ok {
+		code = codes.Internal
+	}
+
+	message := err.Error()
+	if code == codes.Internal {
+		message = domain.ErrInternal.Error()
+	}
+
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{
+		Reason: domainErr.Code,
+		Domain: Domain,
+	}}
+	var validationErr domain.ValidationError
+	if errors.As(err, &validationErr) {
+		badRequest := &errdetails.BadRequest{}
+		for _, v := range validationErr.Violations {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       v.Field,
+				Description: v.Message,
+			})
+		}
+		details = append(details, badRequest)
+	}
+
+	s := status.New(code, message)
+	if withDetails, err := s.WithDetails(details...); err == nil {
+		return withDetails
+	}
+	return s
+}
+
+// UnaryServerInterceptor is the one place where errors of unary calls become
+// statuses, it goes first so it sees the errors of the other interceptors.
+func UnaryServerInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	resp, err := handler(ctx, req)
+	if err != nil {
+		return nil, toStatus(ctx, info.FullMethod, err)
+	}
+	return resp, nil
+}
+
+// StreamServerInterceptor is UnaryServerInterceptor for streams.
+func StreamServerInterceptor(
+	srv interface{},
+	ss grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	if err := handler(srv, ss); err != nil {
+		return toStatus(ss.Context(), info.FullMethod, err)
+	}
+	return nil
+}
+
+func toStatus(ctx context.Context, method string, err error) error {
+	s := New(err)
+	if s.Code() == codes.Internal {
+		slog.ErrorContext(ctx, "call failed", "method", method, "error", err)
+	}
+	return s.Err()
+}
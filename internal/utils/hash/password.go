@@ -5,9 +5,11 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+var ErrEmptyPassword = errors.New("empty password")
+
 func HashPassword(password string) (string, error) {
 	if len(password) == 0 {
-		return "", errors.New("empty password")
+		return "", ErrEmptyPassword
 	}
 
 	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
@@ -0,0 +1,187 @@
+package password
+
+import (
+	_ "embed"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/config"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	"golang.org/x/crypto/bcrypt"
+)
+
+//go:embed common_passwords.txt
+var commonPasswordsList string
+
+var commonPasswords = func() map[string]struct{} {
+	passwords := make(map[string]struct{})
+	for _, p := range strings.Split(commonPasswordsList, "\n") {
+		if p = strings.TrimSpace(p); p != "" {
+			passwords[p] = struct{}{}
+		}
+	}
+	return passwords
+}()
+
+// Policy checks credentials against the configured rules and hashes
+// passwords with the configured bcrypt cost. The zero value accepts anything
+// and hashes with the default cost.
+type Policy struct {
+	settings        config.CredentialsSettings
+	usernamePattern *regexp.Regexp
+}
+
+func NewPolicy(s config.CredentialsSettings) (Policy, error) {
+	p := Policy{settings: s}
+
+	if s.UsernamePattern != "" {
+		pattern, err := regexp.Compile(s.UsernamePattern)
+		if err != nil {
+			return Policy{}, err
+		}
+		p.usernamePattern = pattern
+	}
+
+	if s.BcryptCost != 0 && (s.BcryptCost < bcrypt.MinCost || s.BcryptCost > bcrypt.MaxCost) {
+		return Policy{}, fmt.Errorf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+	}
+
+	return p, nil
+}
+
+func (p Policy) cost() int {
+	if p.settings.BcryptCost == 0 {
+		return bcrypt.DefaultCost
+	}
+	return p.settings.BcryptCost
+}
+
+func (p Policy) HashPassword(password string) (string, error) {
+	if len(password) == 0 {
+		return "", ErrEmptyPassword
+	}
+
+	bytes, err := bcrypt.GenerateFromPassword([]byte(password), p.cost())
+
+	return string(bytes), err
+}
+
+// NeedsRehash reports whether the hash was made with another cost than the
+// configured one.
+func (p Policy) NeedsRehash(hash string) bool {
+	cost, err := bcrypt.Cost([]byte(hash))
+	if err != nil {
+		return false
+	}
+	return cost != p.cost()
+}
+
+func (p Policy) ValidateUsername(field, username string) []domain.FieldError {
+	var violations []domain.FieldError
+	length := utf8.RuneCountInString(username)
+
+	if p.settings.UsernameMinLength > 0 && length < p.settings.UsernameMinLength {
+		violations = append(violations, domain.FieldError{
+			Field:   field,
+			Rule:    "min_length",
+			Message: fmt.Sprintf("must be at least %d characters long", p.settings.UsernameMinLength),
+		})
+	}
+	if p.settings.UsernameMaxLength > 0 && length > p.settings.UsernameMaxLength {
+		violations = append(violations, domain.FieldError{
+			Field:   field,
+			Rule:    "max_length",
+			Message: fmt.Sprintf("must be at most %d characters long", p.settings.UsernameMaxLength),
+		})
+	}
+	if p.usernamePattern != nil && !p.usernamePattern.MatchString(username) {
+		violations = append(violations, domain.FieldError{
+			Field:   field,
+			Rule:    "pattern",
+			Message: fmt.Sprintf("must match %s", p.usernamePattern.String()),
+		})
+	}
+
+	return violations
+}
+
+// ValidatePassword returns every rule the password breaks. The username is
+// optional and only used to reject passwords equal to it.
+func (p Policy) ValidatePassword(field, password, username string) []domain.FieldError {
+	var violations []domain.FieldError
+	length := utf8.RuneCountInString(password)
+
+	if p.settings.PasswordMinLength > 0 && length < p.settings.PasswordMinLength {
+		violations = append(violations, domain.FieldError{
+			Field:   field,
+			Rule:    "min_length",
+			Message: fmt.Sprintf("must be at least %d characters long", p.settings.PasswordMinLength),
+		})
+	}
+	// bcrypt ignores everything after 72 bytes, so the limit is in bytes.
+	if p.settings.PasswordMaxLength > 0 && len(password) > p.settings.PasswordMaxLength {
+		violations = append(violations, domain.FieldError{
+			Field:   field,
+			Rule:    "max_length",
+			Message: fmt.Sprintf("must be at most %d bytes long", p.settings.PasswordMaxLength),
+		})
+	}
+
+	var hasLower, hasUpper, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+
+	classes := []struct {
+		required bool
+		present  bool
+		rule     string
+		message  string
+	}{
+		{p.settings.RequireLower, hasLower, "lowercase", "must contain a lowercase letter"},
+		{p.settings.RequireUpper, hasUpper, "uppercase", "must contain an uppercase letter"},
+		{p.settings.RequireDigit, hasDigit, "digit", "must contain a digit"},
+		{p.settings.RequireSymbol, hasSymbol, "symbol", "must contain a symbol"},
+	}
+	for _, c := range classes {
+		if c.required && !c.present {
+			violations = append(violations, domain.FieldError{
+				Field:   field,
+				Rule:    c.rule,
+				Message: c.message,
+			})
+		}
+	}
+
+	if p.settings.RejectCommonPasswords {
+		if _, ok := commonPasswords[strings.ToLower(password)]; ok {
+			violations = append(violations, domain.FieldError{
+				Field:   field,
+				Rule:    "common",
+				Message: "is too common",
+			})
+		}
+	}
+
+	if username != "" && strings.EqualFold(password, username) {
+		violations = append(violations, domain.FieldError{
+			Field:   field,
+			Rule:    "not_username",
+			Message: "must differ from the username",
+		})
+	}
+
+	return violations
+}
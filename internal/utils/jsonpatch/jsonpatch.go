@@ -0,0 +1,424 @@
+package jsonpatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+)
+
+const (
+	MergePatchType = "application/merge-patch+json"
+	JSONPatchType  = "application/json-patch+json"
+)
+
+// FromRequest reads a patch of the type named by the Content-Type header.
+func FromRequest(r *http.Request) (domain.Patch, error) {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, domain.ErrMediaType.Wrap(err)
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return nil, domain.ErrBodyTooLarge.Wrap(err)
+		}
+		return nil, err
+	}
+
+	switch mediaType {
+	case MergePatchType:
+		return NewMergePatch(body)
+	case JSONPatchType:
+		return NewJSONPatch(body)
+	}
+	return nil, domain.ErrMediaType.Wrap(
+		fmt.Errorf("use %s or %s", MergePatchType, JSONPatchType),
+	)
+}
+
+// MergePatch is an RFC 7396 merge patch: members set to null are removed,
+// objects are merged recursively and anything else replaces the target.
+type MergePatch struct {
+	patch interface{}
+}
+
+func NewMergePatch(data []byte) (MergePatch, error) {
+	patch, err := decode(data)
+	if err != nil {
+		return MergePatch{}, domain.ErrInvalidPatch.Wrap(err)
+	}
+	return MergePatch{patch: patch}, nil
+}
+
+func (p MergePatch) Apply(doc []byte) ([]byte, error) {
+	target, err := decode(doc)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(merge(target, p.patch))
+}
+
+func merge(target, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = make(map[string]interface{})
+	}
+
+	for name, value := range patchObject {
+		if value == nil {
+			delete(targetObject, name)
+			continue
+		}
+		targetObject[name] = merge(targetObject[name], value)
+	}
+	return targetObject
+}
+
+// Operation is one step of a JSON patch. Value is kept raw, so a null value
+// can be told apart from a missing one.
+type Operation struct {
+	Op    string
+	Path  string
+	From  string
+	Value json.RawMessage
+}
+
+// JSONPatch is an RFC 6902 JSON patch. The operations are applied in order
+// and the patch fails as a whole if any of them fails.
+type JSONPatch []Operation
+
+func NewJSONPatch(data []byte) (JSONPatch, error) {
+	var rawOperations []map[string]json.RawMessage
+	if err := json.Unmarshal(data, &rawOperations); err != nil {
+		return nil, domain.ErrInvalidPatch.Wrap(err)
+	}
+
+	patch := make(JSONPatch, 0, len(rawOperations))
+	for i, raw := range rawOperations {
+		var op Operation
+		if err := unmarshalMember(raw, "op", &op.Op); err != nil {
+			return nil, domain.ErrInvalidPatch.Wrap(fmt.Errorf("operation %d: %w", i, err))
+		}
+		if err := unmarshalMember(raw, "path", &op.Path); err != nil {
+			return nil, domain.ErrInvalidPatch.Wrap(fmt.Errorf("operation %d: %w", i, err))
+		}
+
+		switch op.Op {
+		case "add", "replace", "test":
+			value, ok := raw["value"]
+			if !ok {
+				return nil, domain.ErrInvalidPatch.Wrap(
+					fmt.Errorf("operation %d: %s needs a value", i, op.Op),
+				)
+			}
+			op.Value = value
+		case "move", "copy":
+			if err := unmarshalMember(raw, "from", &op.From); err != nil {
+				return nil, domain.ErrInvalidPatch.Wrap(fmt.Errorf("operation %d: %w", i, err))
+			}
+		case "remove":
+		default:
+			return nil, domain.ErrInvalidPatch.Wrap(
+				fmt.Errorf("operation %d: unknown op %q", i, op.Op),
+			)
+		}
+		patch = append(patch, op)
+	}
+	return patch, nil
+}
+
+func unmarshalMember(raw map[string]json.RawMessage, name string, v *string) error {
+	value, ok := raw[name]
+	if !ok {
+		return fmt.Errorf("%s is missing", name)
+	}
+	return json.Unmarshal(value, v)
+}
+
+func (p JSONPatch) Apply(doc []byte) ([]byte, error) {
+	target, err := decode(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, op := range p {
+		if target, err = op.apply(target); err != nil {
+			return nil, domain.ErrPatchFailed.Wrap(fmt.Errorf("operation %d: %w", i, err))
+		}
+	}
+	return json.Marshal(target)
+}
+
+func (op Operation) apply(doc interface{}) (interface{}, error) {
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op.Op {
+	case "add":
+		value, err := decode(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, path, value)
+	case "remove":
+		doc, _, err = remove(doc, path)
+		return doc, err
+	case "replace":
+		value, err := decode(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		if len(path) == 0 {
+			return value, nil
+		}
+		if doc, _, err = remove(doc, path); err != nil {
+			return nil, err
+		}
+		return add(doc, path, value)
+	case "move":
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		if op.Path != op.From && strings.HasPrefix(op.Path, op.From+"/") {
+			return nil, fmt.Errorf("can't move %s into itself", op.From)
+		}
+		var value interface{}
+		if doc, value, err = remove(doc, from); err != nil {
+			return nil, err
+		}
+		return add(doc, path, value)
+	case "copy":
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		value, err := get(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		// The copy must not share maps or slices with the original.
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		if value, err = decode(encoded); err != nil {
+			return nil, err
+		}
+		return add(doc, path, value)
+	case "test":
+		expected, err := decode(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		actual, err := get(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !equal(expected, actual) {
+			return nil, fmt.Errorf("test of %s failed", op.Path)
+		}
+		return doc, nil
+	}
+	return nil, fmt.Errorf("unknown op %q", op.Op)
+}
+
+// parsePointer splits an RFC 6901 JSON pointer into unescaped tokens.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("pointer %q must start with /", pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+	return tokens, nil
+}
+
+func get(node interface{}, path []string) (interface{}, error) {
+	for _, token := range path {
+		switch n := node.(type) {
+		case map[string]interface{}:
+			child, ok := n[token]
+			if !ok {
+				return nil, fmt.Errorf("member %q not found", token)
+			}
+			node = child
+		case []interface{}:
+			i, err := index(token, len(n)-1)
+			if err != nil {
+				return nil, err
+			}
+			node = n[i]
+		default:
+			return nil, fmt.Errorf("%q is not in an object or array", token)
+		}
+	}
+	return node, nil
+}
+
+// add returns node with value added at path, containers are changed in
+// place where possible.
+func add(node interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	token, rest := path[0], path[1:]
+
+	switch n := node.(type) {
+	case map[string]interface{}:
+		if len(rest) == 0 {
+			n[token] = value
+			return n, nil
+		}
+		child, ok := n[token]
+		if !ok {
+			return nil, fmt.Errorf("member %q not found", token)
+		}
+		child, err := add(child, rest, value)
+		n[token] = child
+		return n, err
+	case []interface{}:
+		if len(rest) == 0 {
+			i := len(n)
+			if token != "-" {
+				var err error
+				if i, err = index(token, len(n)); err != nil {
+					return nil, err
+				}
+			}
+			n = append(n, nil)
+			copy(n[i+1:], n[i:])
+			n[i] = value
+			return n, nil
+		}
+		i, err := index(token, len(n)-1)
+		if err != nil {
+			return nil, err
+		}
+		n[i], err = add(n[i], rest, value)
+		return n, err
+	}
+	return nil, fmt.Errorf("%q is not in an object or array", token)
+}
+
+// remove returns node without the value at path and the removed value.
+func remove(node interface{}, path []string) (interface{}, interface{}, error) {
+	if len(path) == 0 {
+		return nil, nil, errors.New("can't remove the whole document")
+	}
+	token, rest := path[0], path[1:]
+
+	switch n := node.(type) {
+	case map[string]interface{}:
+		child, ok := n[token]
+		if !ok {
+			return nil, nil, fmt.Errorf("member %q not found", token)
+		}
+		if len(rest) == 0 {
+			delete(n, token)
+			return n, child, nil
+		}
+		child, removed, err := remove(child, rest)
+		n[token] = child
+		return n, removed, err
+	case []interface{}:
+		i, err := index(token, len(n)-1)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(rest) == 0 {
+			removed := n[i]
+			return append(n[:i], n[i+1:]...), removed, nil
+		}
+		var removed interface{}
+		n[i], removed, err = remove(n[i], rest)
+		return n, removed, err
+	}
+	return nil, nil, fmt.Errorf("%q is not in an object or array", token)
+}
+
+func index(token string, max int) (int, error) {
+	// Leading zeros and signs are not allowed by RFC 6901.
+	if token == "" || (len(token) > 1 && token[0] == '0') || token[0] == '+' {
+		return 0, fmt.Errorf("bad array index %q", token)
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || i > max {
+		return 0, fmt.Errorf("array index %q out of range", token)
+	}
+	return i, nil
+}
+
+// decode keeps numbers as json.Number, so they survive the round trip
+// unchanged.
+func decode(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, errors.New("document must contain a single JSON value")
+	}
+	return v, nil
+}
+
+func equal(a, b interface{}) bool {
+	switch a := a.(type) {
+	case json.Number:
+		b, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		x, errA := a.Float64()
+		y, errB := b.Float64()
+		return errA == nil && errB == nil && x == y
+	case map[string]interface{}:
+		b, ok := b.(map[string]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for name, value := range a {
+			other, ok := b[name]
+			if !ok || !equal(value, other) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		b, ok := b.([]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !equal(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return a == b
+}
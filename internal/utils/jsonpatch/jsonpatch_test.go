@@ -0,0 +1,140 @@
+package jsonpatch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+)
+
+func TestMergePatch_Apply(t *testing.T) {
+	tests := []struct {
+		name     string
+		doc      string
+		patch    string
+		expected string
+	}{
+		{
+			name:     "Absent members are kept",
+			doc:      `{"title":"Title","rating":5}`,
+			patch:    `{"rating":0}`,
+			expected: `{"rating":0,"title":"Title"}`,
+		},
+		{
+			name:     "Null removes a member",
+			doc:      `{"title":"Title","rating":5}`,
+			patch:    `{"title":null}`,
+			expected: `{"rating":5}`,
+		},
+		{
+			name:     "Objects are merged",
+			doc:      `{"a":{"b":"c","d":"e"}}`,
+			patch:    `{"a":{"d":null,"f":"g"}}`,
+			expected: `{"a":{"b":"c","f":"g"}}`,
+		},
+		{
+			name:     "Arrays are replaced",
+			doc:      `{"a":[1,2]}`,
+			patch:    `{"a":[3]}`,
+			expected: `{"a":[3]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patch, err := NewMergePatch([]byte(tt.patch))
+			assert.NoError(t, err)
+
+			result, err := patch.Apply([]byte(tt.doc))
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.expected, string(result))
+		})
+	}
+}
+
+func TestJSONPatch_Apply(t *testing.T) {
+	tests := []struct {
+		name          string
+		doc           string
+		patch         string
+		expected      string
+		expectedError error
+	}{
+		{
+			name:     "Add to array",
+			doc:      `{"a":[1,3]}`,
+			patch:    `[{"op":"add","path":"/a/1","value":2},{"op":"add","path":"/a/-","value":4}]`,
+			expected: `{"a":[1,2,3,4]}`,
+		},
+		{
+			name:     "Replace with null",
+			doc:      `{"a":1}`,
+			patch:    `[{"op":"replace","path":"/a","value":null}]`,
+			expected: `{"a":null}`,
+		},
+		{
+			name:     "Remove, move and copy",
+			doc:      `{"a":{"b":1},"c":2,"d~e/f":3}`,
+			patch:    `[{"op":"remove","path":"/c"},{"op":"move","from":"/a/b","path":"/b"},{"op":"copy","from":"/d~0e~1f","path":"/g"}]`,
+			expected: `{"a":{},"b":1,"d~e/f":3,"g":3}`,
+		},
+		{
+			name:     "Passed test",
+			doc:      `{"rating":5.0}`,
+			patch:    `[{"op":"test","path":"/rating","value":5},{"op":"replace","path":"/rating","value":0}]`,
+			expected: `{"rating":0}`,
+		},
+		{
+			name:          "Failed test",
+			doc:           `{"rating":5}`,
+			patch:         `[{"op":"test","path":"/rating","value":4}]`,
+			expectedError: domain.ErrPatchFailed,
+		},
+		{
+			name:          "Missing member",
+			doc:           `{"a":1}`,
+			patch:         `[{"op":"replace","path":"/b","value":2}]`,
+			expectedError: domain.ErrPatchFailed,
+		},
+		{
+			name:          "Index out of range",
+			doc:           `{"a":[1]}`,
+			patch:         `[{"op":"add","path":"/a/2","value":2}]`,
+			expectedError: domain.ErrPatchFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patch, err := NewJSONPatch([]byte(tt.patch))
+			assert.NoError(t, err)
+
+			result, err := patch.Apply([]byte(tt.doc))
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.expected, string(result))
+		})
+	}
+}
+
+func TestNewJSONPatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		patch string
+	}{
+		{name: "Not an array", patch: `{"op":"remove","path":"/a"}`},
+		{name: "Unknown op", patch: `[{"op":"rename","path":"/a"}]`},
+		{name: "Missing value", patch: `[{"op":"add","path":"/a"}]`},
+		{name: "Missing from", patch: `[{"op":"move","path":"/a"}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewJSONPatch([]byte(tt.patch))
+			assert.ErrorIs(t, err, domain.ErrInvalidPatch)
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"gorm.io/gorm"
+)
+
+const startKey = "metrics:start"
+
+var (
+	poolsMu sync.Mutex
+	pools   = map[string]prometheus.Collector{}
+)
+
+// Plugin times the statements of a gorm connection and exports the stats
+// of its pool. DB names the connection in the labels, e.g. "movies".
+type Plugin struct {
+	DB string
+}
+
+func (Plugin) Name() string {
+	return "metrics"
+}
+
+func (p Plugin) Initialize(db *gorm.DB) error {
+	type registerer interface {
+		Register(name string, fn func(*gorm.DB)) error
+	}
+
+	callbacks := db.Callback()
+	processors := []struct {
+		operation string
+		before    registerer
+		after     registerer
+	}{
+		{"create", callbacks.Create().Before("*"), callbacks.Create().After("*")},
+		{"query", callbacks.Query().Before("*"), callbacks.Query().After("*")},
+		{"update", callbacks.Update().Before("*"), callbacks.Update().After("*")},
+		{"delete", callbacks.Delete().Before("*"), callbacks.Delete().After("*")},
+		{"row", callbacks.Row().Before("*"), callbacks.Row().After("*")},
+		{"raw", callbacks.Raw().Before("*"), callbacks.Raw().After("*")},
+	}
+	for _, proc := range processors {
+		if err := proc.before.Register("metrics:start", start); err != nil {
+			return err
+		}
+		if err := proc.after.Register("metrics:observe", p.observe(proc.operation)); err != nil {
+			return err
+		}
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return registerPool(p.DB, collectors.NewDBStatsCollector(sqlDB, p.DB))
+}
+
+func start(db *gorm.DB) {
+	db.InstanceSet(startKey, time.Now())
+}
+
+func (p Plugin) observe(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(startKey)
+		if !ok {
+			return
+		}
+		begin, ok := value.(time.Time)
+		if !ok {
+			return
+		}
+		queryDuration.WithLabelValues(p.DB, operation, db.Statement.Table).
+			Observe(time.Since(begin).Seconds())
+	}
+}
+
+// registerPool replaces the pool stats of a connection made again under the
+// same name.
+func registerPool(name string, collector prometheus.Collector) error {
+	poolsMu.Lock()
+	defer poolsMu.Unlock()
+
+	if old, ok := pools[name]; ok {
+		Registry.Unregister(old)
+	}
+	if err := Registry.Register(collector); err != nil {
+		return err
+	}
+	pools[name] = collector
+	return nil
+}
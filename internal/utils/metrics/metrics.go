@@ -0,0 +1,177 @@
+// Package metrics keeps the Prometheus metrics of the service: HTTP
+// requests by route pattern, database queries and pools, logins, sessions
+// and the Go runtime. Handler serves them in the text format.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/requestctx"
+)
+
+const namespace = "filmlibrary"
+
+// unmatchedRoute labels requests no route took, e.g. 404s. Raw paths would
+// make a series per URL.
+const unmatchedRoute = "unmatched"
+
+// Registry holds the metrics of the service, it is apart from the default
+// one of the prometheus package so that nothing else ends up there.
+var Registry = prometheus.NewRegistry()
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, route pattern and status.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Time to answer HTTP requests by method, route pattern and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	queryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Time of gorm statements by connection, operation and table.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"db", "operation", "table"})
+
+	logins = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "logins_total",
+		Help:      "Login attempts by method and result.",
+	}, []string{"method", "result"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests,
+		httpDuration,
+		queryDuration,
+		logins,
+	)
+}
+
+// Handler serves the metrics of Registry.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+type responseWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *responseWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *responseWriter) Write(data []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Middleware counts and times requests. It goes behind
+// requestctx.Middleware, the route is read from its scope once the handler
+// returned, and in front of the access log, which answers panics with 500.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rw := &responseWriter{ResponseWriter: w}
+		next.ServeHTTP(rw, r)
+
+		status := rw.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		route := requestctx.Route(r.Context())
+		if route == "" {
+			route = unmatchedRoute
+		}
+
+		labels := prometheus.Labels{"method": r.Method, "route": route, "status": strconv.Itoa(status)}
+		httpRequests.With(labels).Inc()
+		httpDuration.With(labels).Observe(time.Since(start).Seconds())
+	})
+}
+
+// Login counts a login attempt of method, e.g. "password" or "oidc", by the
+// error it ended with.
+func Login(method string, err error) {
+	logins.WithLabelValues(method, loginResult(err)).Inc()
+}
+
+func loginResult(err error) string {
+	switch {
+	case err == nil:
+		return "success"
+	case errors.Is(err, domain.ErrTwoFactorRequired):
+		return "second_factor"
+	case domain.AsError(err).Kind == domain.KindInternal:
+		return "error"
+	default:
+		return "failure"
+	}
+}
+
+// sessionsTimeout bounds the query behind the sessions gauge, a scrape
+// must not hang on the database.
+const sessionsTimeout = 2 * time.Second
+
+var (
+	sessionsMu sync.Mutex
+	sessions   prometheus.Collector
+)
+
+// Sessions makes the active sessions gauge read count on every scrape. A
+// later call replaces the function.
+func Sessions(count func(ctx context.Context) (int64, error)) {
+	gauge := prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "active_sessions",
+		Help:      "Sessions that have not expired.",
+	}, func() float64 {
+		ctx, cancel := context.WithTimeout(context.Background(), sessionsTimeout)
+		defer cancel()
+
+		n, err := count(ctx)
+		if err != nil {
+			slog.Warn("failed to count sessions", "error", err)
+			return 0
+		}
+		return float64(n)
+	})
+
+	sessionsMu.Lock()
+	defer sessionsMu.Unlock()
+	if sessions != nil {
+		Registry.Unregister(sessions)
+	}
+	sessions = gauge
+	Registry.MustRegister(gauge)
+}
@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/requestctx"
+)
+
+func scrape(t *testing.T) string {
+	w := httptest.NewRecorder()
+	Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	return w.Body.String()
+}
+
+func TestMiddleware(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/movies/{id}", func(w http.ResponseWriter, r *http.Request) {
+		requestctx.WithRoute(r.Context(), "GET /api/v1/movies/{id}")
+		w.WriteHeader(http.StatusNotFound)
+	})
+	handler := requestctx.Middleware(Middleware(mux))
+
+	for _, path := range []string{"/api/v1/movies/1", "/api/v1/movies/2", "/api/v1/unknown"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	body := scrape(t)
+	assert.Contains(t, body, `filmlibrary_http_requests_total{method="GET",route="GET /api/v1/movies/{id}",status="404"} 2`)
+	assert.Contains(t, body, `filmlibrary_http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+	assert.Contains(t, body,
+		`filmlibrary_http_request_duration_seconds_count{method="GET",route="GET /api/v1/movies/{id}",status="404"} 2`)
+	assert.NotContains(t, body, "/api/v1/movies/1")
+	assert.Contains(t, body, "go_goroutines")
+}
+
+func TestLogin(t *testing.T) {
+	Login("password", nil)
+	Login("password", domain.ErrInvalidLoginOrPassword)
+	Login("password", domain.TwoFactorRequiredError{Challenge: "challenge"})
+	Login("oidc", errors.New("connection refused"))
+
+	body := scrape(t)
+	assert.Contains(t, body, `filmlibrary_logins_total{method="password",result="success"} 1`)
+	assert.Contains(t, body, `filmlibrary_logins_total{method="password",result="failure"} 1`)
+	assert.Contains(t, body, `filmlibrary_logins_total{method="password",result="second_factor"} 1`)
+	assert.Contains(t, body, `filmlibrary_logins_total{method="oidc",result="error"} 1`)
+}
+
+func TestSessions(t *testing.T) {
+	Sessions(func(ctx context.Context) (int64, error) { return 3, nil })
+	Sessions(func(ctx context.Context) (int64, error) { return 5, nil })
+
+	assert.Contains(t, scrape(t), "filmlibrary_active_sessions 5")
+}
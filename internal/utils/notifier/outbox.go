@@ -0,0 +1,64 @@
+package notifier
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// Outbox is the default notifier. Instead of sending anything over the
+// network it appends messages to a local file, so the reset flow can be used
+// without a mail server. With an empty path messages go to the log.
+type Outbox struct {
+	path string
+	mu   *sync.Mutex
+}
+
+type outboxMessage struct {
+	Kind     string    `json:"kind"`
+	Username string    `json:"username"`
+	Token    string    `json:"token"`
+	SentAt   time.Time `json:"sentAt"`
+}
+
+func NewOutbox(path string) Outbox {
+	return Outbox{
+		path: path,
+		mu:   &sync.Mutex{},
+	}
+}
+
+func (o Outbox) SendPasswordReset(username, token string) error {
+	return o.write(outboxMessage{
+		Kind:     "password_reset",
+		Username: username,
+		Token:    token,
+		SentAt:   time.Now(),
+	})
+}
+
+func (o Outbox) write(msg outboxMessage) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	if o.path == "" {
+		slog.Info("outbox", "message", string(data))
+		return nil
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	file, err := os.OpenFile(o.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(data, '\n'))
+	return err
+}
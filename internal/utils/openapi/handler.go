@@ -0,0 +1,52 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+
+	swaggerFiles "github.com/swaggo/files/v2"
+)
+
+// uiInitializer replaces the one of the Swagger UI dist, which points to
+// the petstore.
+const uiInitializer = `window.onload = function() {
+  window.ui = SwaggerUIBundle({
+    url: %q,
+    dom_id: '#swagger-ui',
+    deepLinking: true,
+    presets: [
+      SwaggerUIBundle.presets.apis,
+      SwaggerUIStandalonePreset
+    ],
+    plugins: [
+      SwaggerUIBundle.plugins.DownloadUrl
+    ],
+    layout: "StandaloneLayout"
+  });
+};
+`
+
+// SpecHandler serves the spec as it is in the repository.
+func SpecHandler(data []byte) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/yaml; charset=UTF-8")
+		w.WriteHeader(http.StatusOK)
+		w.Write(data)
+	}
+}
+
+// UIHandler serves Swagger UI under prefix, showing the spec at specURL.
+func UIHandler(prefix, specURL string) http.Handler {
+	initializer := fmt.Sprintf(uiInitializer, specURL)
+	files := http.StripPrefix(prefix, http.FileServerFS(swaggerFiles.FS))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == prefix+"swagger-initializer.js" {
+			w.Header().Set("Content-Type", "text/javascript; charset=UTF-8")
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(initializer))
+			return
+		}
+		files.ServeHTTP(w, r)
+	})
+}
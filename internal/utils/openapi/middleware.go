@@ -0,0 +1,184 @@
+package openapi
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/problem"
+)
+
+// Mode is what Middleware does with traffic that doesn't match the spec.
+type Mode string
+
+const (
+	// ModeOff turns the checks off.
+	ModeOff Mode = "off"
+	// ModeLog logs mismatches and lets the traffic through, for production.
+	ModeLog Mode = "log"
+	// ModeStrict answers 500 instead of responses that don't match, for
+	// tests.
+	ModeStrict Mode = "strict"
+)
+
+// maxCheckedBody caps the JSON responses held back for the check. Larger
+// ones are sent on unchecked from the point they outgrow it.
+const maxCheckedBody = 1 << 20
+
+// Middleware checks the routes in the spec: requests the spec rejects must
+// be rejected by the handler too, JSON responses must match the spec.
+// Routes missing in the spec are left to the router. Response bodies that
+// are not JSON, downloads like exports and bodies over maxCheckedBody are
+// streamed unchecked.
+func (s *Spec) Middleware(mode Mode) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if mode != ModeLog && mode != ModeStrict {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route, pathParams, routeErr := s.router.FindRoute(r)
+			if routeErr != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			input := &openapi3filter.RequestValidationInput{
+				Request:    r,
+				PathParams: pathParams,
+				Route:      route,
+				Options: &openapi3filter.Options{
+					// Sessions are checked by the auth middleware.
+					AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+					ExcludeRequestBody: !isJSON(r.Header.Get("Content-Type")),
+					MultiError:         true,
+					// The check only looks, defaults are the handler's.
+					SkipSettingDefaults: true,
+				},
+			}
+			requestErr := openapi3filter.ValidateRequest(r.Context(), input)
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(requestErr, &maxBytesErr) {
+				problem.Write(w, r, domain.ErrBodyTooLarge)
+				return
+			}
+
+			rw := &responseWriter{ResponseWriter: w}
+			next.ServeHTTP(rw, r)
+
+			// Requests the spec rejects are the handler's to answer, with
+			// its own problem details. It only drifted when it took one.
+			var err error
+			if requestErr != nil && rw.status < http.StatusBadRequest {
+				err = fmt.Errorf("openapi: request does not match the spec, got %d: %w", rw.status, requestErr)
+			} else if rw.body != nil {
+				err = openapi3filter.ValidateResponse(r.Context(), &openapi3filter.ResponseValidationInput{
+					RequestValidationInput: input,
+					Status:                 rw.status,
+					Header:                 rw.Header(),
+					Body:                   io.NopCloser(bytes.NewReader(rw.body.Bytes())),
+					Options: &openapi3filter.Options{
+						IncludeResponseStatus: true,
+						MultiError:            true,
+					},
+				})
+				if err != nil {
+					err = fmt.Errorf("openapi: response %d does not match the spec: %w", rw.status, err)
+				}
+			}
+
+			if err != nil {
+				if mode == ModeStrict && rw.body != nil {
+					problem.Write(w, r, err)
+					return
+				}
+				slog.WarnContext(r.Context(), "traffic does not match the spec", "method", r.Method, "path", r.URL.Path, "error", err)
+			}
+			if rw.body == nil {
+				return
+			}
+
+			w.WriteHeader(rw.status)
+			w.Write(rw.body.Bytes())
+		})
+	}
+}
+
+// Strict is Middleware in ModeStrict for handler tests, which register their
+// routes without the base path, e.g. "GET /movies/{id}". The base path is put
+// in front for the check and taken off again on the way to next.
+func (s *Spec) Strict(next http.Handler) http.Handler {
+	checked := s.Middleware(ModeStrict)(http.StripPrefix(s.basePath, next))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r = r.Clone(r.Context())
+		r.URL.Path = s.basePath + r.URL.Path
+		if r.URL.RawPath != "" {
+			r.URL.RawPath = s.basePath + r.URL.RawPath
+		}
+		checked.ServeHTTP(w, r)
+	})
+}
+
+// responseWriter holds JSON responses back until they are checked, other
+// responses go straight through.
+type responseWriter struct {
+	http.ResponseWriter
+	status int
+	body   *bytes.Buffer
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if w.status != 0 {
+		return
+	}
+	w.status = status
+	if isJSON(w.Header().Get("Content-Type")) && !isAttachment(w.Header().Get("Content-Disposition")) {
+		w.body = &bytes.Buffer{}
+		return
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(data []byte) (int, error) {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.body == nil {
+		return w.ResponseWriter.Write(data)
+	}
+	if w.body.Len()+len(data) <= maxCheckedBody {
+		return w.body.Write(data)
+	}
+
+	// Too large to hold back, what is held goes out first.
+	held := w.body
+	w.body = nil
+	w.ResponseWriter.WriteHeader(w.status)
+	if _, err := w.ResponseWriter.Write(held.Bytes()); err != nil {
+		return 0, err
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || mediaType == "application/problem+json"
+}
+
+func isAttachment(contentDisposition string) bool {
+	disposition, _, err := mime.ParseMediaType(contentDisposition)
+	return err == nil && disposition == "attachment"
+}
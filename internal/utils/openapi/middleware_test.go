@@ -0,0 +1,184 @@
+package openapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/docs"
+	mockDomain "github.com/themilchenko/vk-tech_internship-problem_2024/internal/mocks/domain"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	httpMovies "github.com/themilchenko/vk-tech_internship-problem_2024/internal/movies/delivery"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSpec_Middleware(t *testing.T) {
+	type mockBehavior func(r *mockDomain.MockMoviesUsecase)
+
+	spec, err := Load(docs.Swagger)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name                 string
+		mode                 Mode
+		target               string
+		mockBehavior         mockBehavior
+		actor                string
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:   "OK",
+			mode:   ModeStrict,
+			target: "/api/v1/movies/1",
+			mockBehavior: func(r *mockDomain.MockMoviesUsecase) {
+				r.EXPECT().GetMovieByID(gomock.Any(), uint64(1)).Return(httpModels.MovieResponse{
+					ID:          1,
+					Title:       "The Godfather",
+					ReleaseDate: "1972-03-24",
+					Rating:      9.2,
+				}, nil)
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"id":1,"title":"The Godfather","releaseDate":"1972-03-24","rating":9.2}`,
+		},
+		{
+			name:                 "Request not in the spec, rejected",
+			mode:                 ModeStrict,
+			target:               "/api/v1/movies?filter=release_date",
+			mockBehavior:         func(r *mockDomain.MockMoviesUsecase) {},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedResponseBody: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"bad request","code":"bad_request"}`,
+		},
+		{
+			name:                 "Request not in the spec, accepted",
+			mode:                 ModeStrict,
+			target:               "/api/v1/actors/brando",
+			mockBehavior:         func(r *mockDomain.MockMoviesUsecase) {},
+			actor:                `{"name":"Marlon Brando"}`,
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"server error","code":"internal_error"}`,
+		},
+		{
+			name:                 "Request not in the spec, logged",
+			mode:                 ModeLog,
+			target:               "/api/v1/actors/brando",
+			mockBehavior:         func(r *mockDomain.MockMoviesUsecase) {},
+			actor:                `{"name":"Marlon Brando"}`,
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"name":"Marlon Brando"}`,
+		},
+		{
+			name:                 "Response not in the spec",
+			mode:                 ModeStrict,
+			target:               "/api/v1/actors/1",
+			mockBehavior:         func(r *mockDomain.MockMoviesUsecase) {},
+			actor:                `{"id":"1"}`,
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedResponseBody: `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"server error","code":"internal_error"}`,
+		},
+		{
+			name:                 "Response not in the spec, logged",
+			mode:                 ModeLog,
+			target:               "/api/v1/actors/1",
+			mockBehavior:         func(r *mockDomain.MockMoviesUsecase) {},
+			actor:                `{"id":"1"}`,
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"id":"1"}`,
+		},
+		{
+			name:                 "Route not in the spec",
+			mode:                 ModeStrict,
+			target:               "/api/v1/health",
+			mockBehavior:         func(r *mockDomain.MockMoviesUsecase) {},
+			expectedStatusCode:   http.StatusOK,
+			expectedResponseBody: `{"status":"ok"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cntx := gomock.NewController(t)
+			defer cntx.Finish()
+
+			mockMoviesUsecase := mockDomain.NewMockMoviesUsecase(cntx)
+			handler := httpMovies.NewActorsUsecase(mockMoviesUsecase)
+
+			tt.mockBehavior(mockMoviesUsecase)
+
+			writeJSON := func(body string) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+					w.WriteHeader(http.StatusOK)
+					w.Write([]byte(body + "\n"))
+				}
+			}
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /api/v1/movies/{id}", handler.GetMovie)
+			mux.HandleFunc("GET /api/v1/movies", handler.GetMovies)
+			mux.HandleFunc("GET /api/v1/actors/{id}", writeJSON(tt.actor))
+			mux.HandleFunc("GET /api/v1/health", writeJSON(`{"status":"ok"}`))
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+
+			spec.Middleware(tt.mode)(mux).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, strings.Trim(w.Body.String(), "\n"))
+		})
+	}
+}
+
+func TestSpec_MiddlewareStreams(t *testing.T) {
+	spec, err := Load(docs.Swagger)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		target      string
+		disposition string
+		body        string
+	}{
+		{
+			name:        "Download",
+			target:      "/api/v1/export?format=json",
+			disposition: `attachment; filename="catalog.json"`,
+			body:        `{"movies":"none"}`,
+		},
+		{
+			name:   "Body over the cap",
+			target: "/api/v1/actors/1",
+			body:   `{"id":"` + strings.Repeat("1", maxCheckedBody) + `"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET "+strings.Split(tt.target, "?")[0], func(rw http.ResponseWriter, r *http.Request) {
+				rw.Header().Set("Content-Type", "application/json; charset=UTF-8")
+				if tt.disposition != "" {
+					rw.Header().Set("Content-Disposition", tt.disposition)
+				}
+				rw.Write([]byte(tt.body))
+
+				// Sent before the handler is done, not held back.
+				assert.Equal(t, tt.body, w.Body.String())
+			})
+
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+
+			spec.Middleware(ModeStrict)(mux).ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.body, w.Body.String())
+		})
+	}
+}
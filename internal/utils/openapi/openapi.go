@@ -0,0 +1,102 @@
+package openapi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi2conv"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/invopop/yaml"
+)
+
+func init() {
+	// Mismatches end up in problem details and logs, the schemas they broke
+	// are in the spec.
+	openapi3.SchemaErrorDetailsDisabled = true
+}
+
+// Spec is the Swagger 2.0 spec of the API, ready to check requests and
+// responses against.
+type Spec struct {
+	doc      *openapi3.T
+	router   routers.Router
+	basePath string
+}
+
+// Load parses and checks a Swagger 2.0 spec in YAML or JSON.
+func Load(data []byte) (*Spec, error) {
+	var doc2 openapi2.T
+	if err := yaml.Unmarshal(data, &doc2); err != nil {
+		return nil, fmt.Errorf("openapi: parse spec: %w", err)
+	}
+
+	// The conversion only looks at the media types of operations.
+	for _, pathItem := range doc2.Paths {
+		for _, operation := range pathItem.Operations() {
+			if len(operation.Consumes) == 0 {
+				operation.Consumes = doc2.Consumes
+			}
+			if len(operation.Produces) == 0 {
+				operation.Produces = doc2.Produces
+			}
+		}
+	}
+
+	doc, err := openapi2conv.ToV3(&doc2)
+	if err != nil {
+		return nil, fmt.Errorf("openapi: convert spec: %w", err)
+	}
+	// The conversion keeps allowEmptyValue on the schema of a parameter, the
+	// request check looks for it on the parameter.
+	for _, pathItem := range doc.Paths.Map() {
+		for _, operation := range pathItem.Operations() {
+			for _, parameter := range operation.Parameters {
+				schema := parameter.Value.Schema
+				if schema != nil && schema.Value != nil && schema.Value.AllowEmptyValue {
+					parameter.Value.AllowEmptyValue = true
+				}
+			}
+		}
+	}
+	if err = doc.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("openapi: invalid spec: %w", err)
+	}
+
+	// Without a host the spec has no servers, the base path makes the one
+	// requests are routed by.
+	basePath := strings.TrimSuffix(doc2.BasePath, "/")
+	if len(doc.Servers) == 0 && basePath != "" {
+		doc.Servers = openapi3.Servers{{URL: basePath}}
+	}
+
+	router, err := legacy.NewRouter(doc)
+	if err != nil {
+		return nil, fmt.Errorf("openapi: route spec: %w", err)
+	}
+
+	return &Spec{
+		doc:      doc,
+		router:   router,
+		basePath: basePath,
+	}, nil
+}
+
+// HasOperation tells whether the spec describes a route of http.ServeMux,
+// e.g. "GET /api/v1/movies/{id}".
+func (s *Spec) HasOperation(pattern string) bool {
+	method, path, ok := strings.Cut(pattern, " ")
+	if !ok {
+		return false
+	}
+	path, ok = strings.CutPrefix(path, s.basePath)
+	if !ok {
+		return false
+	}
+
+	pathItem := s.doc.Paths.Value(path)
+	return pathItem != nil && pathItem.GetOperation(method) != nil
+}
@@ -0,0 +1,68 @@
+package pgerrors
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	"gorm.io/gorm"
+)
+
+const (
+	foreignKeyViolation = "23503"
+	uniqueViolation     = "23505"
+	checkViolation      = "23514"
+)
+
+var constraintErrors = map[string]*domain.Error{
+	foreignKeyViolation: domain.ErrReferenceViolation,
+	uniqueViolation:     domain.ErrAlreadyExists,
+	checkViolation:      domain.ErrCheckViolation,
+}
+
+// Translate turns constraint violations into domain errors, anything else is
+// returned as is.
+func Translate(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+	if domainErr, ok := constraintErrors[pgErr.Code]; ok {
+		return domainErr.Wrap(errors.New(pgErr.ConstraintName))
+	}
+	return err
+}
+
+// Plugin translates the errors of every statement, so repositories don't
+// have to do it on each return.
+type Plugin struct{}
+
+func (Plugin) Name() string {
+	return "pgerrors"
+}
+
+func (Plugin) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	processors := []interface {
+		Register(name string, fn func(*gorm.DB)) error
+	}{
+		callbacks.Create().After("*"),
+		callbacks.Query().After("*"),
+		callbacks.Update().After("*"),
+		callbacks.Delete().After("*"),
+		callbacks.Row().After("*"),
+		callbacks.Raw().After("*"),
+	}
+	for _, p := range processors {
+		if err := p.Register("pgerrors:translate", translate); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func translate(db *gorm.DB) {
+	if db.Error != nil {
+		db.Error = Translate(db.Error)
+	}
+}
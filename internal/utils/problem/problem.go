@@ -0,0 +1,91 @@
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/requestctx"
+)
+
+const ContentType = "application/problem+json"
+
+var statuses = map[domain.Kind]int{
+	domain.KindInternal:             http.StatusInternalServerError,
+	domain.KindBadRequest:           http.StatusBadRequest,
+	domain.KindValidation:           http.StatusUnprocessableEntity,
+	domain.KindUnauthenticated:      http.StatusUnauthorized,
+	domain.KindForbidden:            http.StatusForbidden,
+	domain.KindNotFound:             http.StatusNotFound,
+	domain.KindConflict:             http.StatusConflict,
+	domain.KindTooManyRequests:      http.StatusTooManyRequests,
+	domain.KindPayloadTooLarge:      http.StatusRequestEntityTooLarge,
+	domain.KindUnsupportedMediaType: http.StatusUnsupportedMediaType,
+	domain.KindPreconditionFailed:   http.StatusPreconditionFailed,
+	domain.KindPreconditionRequired: http.StatusPreconditionRequired,
+	domain.KindFailedDependency:     http.StatusFailedDependency,
+}
+
+// Problem is an RFC 7807 problem detail. Code is the stable code of the
+// domain error, Violations lists broken rules of a failed validation and
+// RequestID tells which request failed, e.g. to find it in the logs.
+type Problem struct {
+	Type       string              `json:"type"`
+	Title      string              `json:"title"`
+	Status     int                 `json:"status"`
+	Detail     string              `json:"detail,omitempty"`
+	Code       string              `json:"code"`
+	Violations []domain.FieldError `json:"violations,omitempty"`
+	RequestID  string              `json:"requestId,omitempty"`
+}
+
+// New describes err as a problem. Details of internal errors are not
+// shown to clients.
+func New(err error) Problem {
+	domainErr := domain.AsError(err)
+	status, ok := statuses[domainErr.Kind]
+	if !ok {
+		status = http.StatusInternalServerError
+	}
+
+	p := Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: err.Error(),
+		Code:   domainErr.Code,
+	}
+	if status == http.StatusInternalServerError {
+		p.Detail = domain.ErrInternal.Error()
+	}
+
+	var validationErr domain.ValidationError
+	if errors.As(err, &validationErr) {
+		p.Violations = validationErr.Violations
+	}
+	return p
+}
+
+// Write is the one place where errors become responses.
+func Write(w http.ResponseWriter, r *http.Request, err error) {
+	p := New(err)
+	p.RequestID = requestctx.RequestID(r.Context())
+	if p.Status == http.StatusInternalServerError {
+		slog.ErrorContext(r.Context(), "request failed", "method", r.Method, "path", r.URL.Path, "error", err)
+	}
+
+	responseData, marshalErr := json.Marshal(p)
+	if marshalErr != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	// Errors must not be cached or mistaken for a version of the item.
+	w.Header().Del("ETag")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(p.Status)
+	w.Write(responseData)
+}
@@ -0,0 +1,120 @@
+package requestctx
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+type contextKey int
+
+const (
+	userIDKey contextKey = iota
+	requestIDKey
+	routeKey
+	scopeKey
+)
+
+// scope is what the handlers learn about a request on the way, e.g. who
+// made it. Middlewares in front of them, like the access log, read it back
+// after the handler returned.
+type scope struct {
+	userID uint64
+	route  string
+}
+
+func withScope(ctx context.Context) context.Context {
+	return context.WithValue(ctx, scopeKey, &scope{})
+}
+
+func getScope(ctx context.Context) *scope {
+	s, _ := ctx.Value(scopeKey).(*scope)
+	return s
+}
+
+func WithUserID(ctx context.Context, userID uint64) context.Context {
+	if s := getScope(ctx); s != nil {
+		s.userID = userID
+	}
+	return context.WithValue(ctx, userIDKey, userID)
+}
+
+// UserID returns the id of the logged in user, 0 for anonymous requests.
+func UserID(ctx context.Context) uint64 {
+	if userID, ok := ctx.Value(userIDKey).(uint64); ok {
+		return userID
+	}
+	if s := getScope(ctx); s != nil {
+		return s.userID
+	}
+	return 0
+}
+
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
+
+// WithRoute keeps the pattern the request was routed by, e.g.
+// "GET /api/v1/movies/{id}". The scope keeps the first one, requests of a
+// batch don't replace the route of the batch.
+func WithRoute(ctx context.Context, route string) context.Context {
+	if s := getScope(ctx); s != nil && s.route == "" {
+		s.route = route
+	}
+	return context.WithValue(ctx, routeKey, route)
+}
+
+func Route(ctx context.Context) string {
+	if route, ok := ctx.Value(routeKey).(string); ok {
+		return route
+	}
+	if s := getScope(ctx); s != nil {
+		return s.route
+	}
+	return ""
+}
+
+// maxRequestIDLength bounds the ids taken from clients, they end up in
+// logs and SQL comments.
+const maxRequestIDLength = 128
+
+// Middleware takes the request id from the client or makes a new one and
+// sends it back in the response.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = uuid.NewString()
+		}
+		w.Header().Set(RequestIDHeader, requestID)
+
+		ctx := withScope(WithRequestID(r.Context(), requestID))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// validRequestID allows letters, digits and the few signs tracing systems
+// put in their ids, nothing that could break out of a log line or a comment.
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range requestID {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case strings.ContainsRune("-_.:", c):
+		default:
+			return false
+		}
+	}
+	return true
+}
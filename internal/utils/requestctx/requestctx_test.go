@@ -0,0 +1,46 @@
+package requestctx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	tests := []struct {
+		name      string
+		requestID string
+		kept      bool
+	}{
+		{name: "From the client", requestID: "4bf92f35-77b3-4da6:1", kept: true},
+		{name: "No id", requestID: ""},
+		{name: "Too long", requestID: strings.Repeat("a", maxRequestIDLength+1)},
+		{name: "Breaks out of a comment", requestID: "a*/ DROP TABLE movies; /*"},
+		{name: "Breaks a log line", requestID: "a\nb"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var seen string
+			handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				seen = RequestID(r.Context())
+			}))
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/movies", nil)
+			req.Header.Set(RequestIDHeader, tt.requestID)
+			handler.ServeHTTP(w, req)
+
+			assert.NotEmpty(t, seen)
+			assert.Equal(t, seen, w.Header().Get(RequestIDHeader))
+			if tt.kept {
+				assert.Equal(t, tt.requestID, seen)
+			} else {
+				assert.NotEqual(t, tt.requestID, seen)
+			}
+		})
+	}
+}
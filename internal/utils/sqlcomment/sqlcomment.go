@@ -0,0 +1,128 @@
+// Package sqlcomment tags the statements of a request with its id, e.g.
+// "SELECT ... /*request_id='1b4e28ba'*/", so that pg_stat_activity and the
+// database logs can be tied to the request logs. Comments follow the
+// sqlcommenter format.
+package sqlcomment
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/url"
+
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/requestctx"
+	"gorm.io/gorm"
+)
+
+// Comment is the comment of the statements sent with ctx, empty outside of
+// requests.
+func Comment(ctx context.Context) string {
+	requestID := requestctx.RequestID(ctx)
+	if requestID == "" {
+		return ""
+	}
+	return "/*request_id='" + url.QueryEscape(requestID) + "'*/"
+}
+
+// Tag appends the comment of ctx to query.
+func Tag(ctx context.Context, query string) string {
+	if comment := Comment(ctx); comment != "" {
+		return query + " " + comment
+	}
+	return query
+}
+
+// Plugin tags the statements of a gorm connection. It goes after
+// dbtx.Plugin, so that statements of shared transactions are tagged too.
+type Plugin struct{}
+
+func (Plugin) Name() string {
+	return "sqlcomment"
+}
+
+func (Plugin) Initialize(db *gorm.DB) error {
+	if _, ok := db.ConnPool.(pool); ok {
+		return nil
+	}
+	db.ConnPool = pool{conn{db.ConnPool}}
+	db.Statement.ConnPool = db.ConnPool
+	return nil
+}
+
+// conn tags the statements it passes on.
+type conn struct {
+	gorm.ConnPool
+}
+
+func (c conn) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return c.ConnPool.PrepareContext(ctx, Tag(ctx, query))
+}
+
+func (c conn) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return c.ConnPool.ExecContext(ctx, Tag(ctx, query), args...)
+}
+
+func (c conn) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return c.ConnPool.QueryContext(ctx, Tag(ctx, query), args...)
+}
+
+func (c conn) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return c.ConnPool.QueryRowContext(ctx, Tag(ctx, query), args...)
+}
+
+// pool is the connection pool of gorm. It is apart from tx, gorm takes
+// anything that commits for a transaction.
+type pool struct {
+	conn
+}
+
+// BeginTx keeps tagging the statements of the transaction.
+func (p pool) BeginTx(ctx context.Context, opts *sql.TxOptions) (gorm.ConnPool, error) {
+	switch beginner := p.ConnPool.(type) {
+	case gorm.ConnPoolBeginner:
+		t, err := beginner.BeginTx(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &tx{conn{t}}, nil
+	case gorm.TxBeginner:
+		t, err := beginner.BeginTx(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &tx{conn{t}}, nil
+	}
+	return nil, errors.New("connection pool can't begin transactions")
+}
+
+// GetDBConn keeps gorm.DB.DB working.
+func (p pool) GetDBConn() (*sql.DB, error) {
+	switch db := p.ConnPool.(type) {
+	case *sql.DB:
+		return db, nil
+	case gorm.GetDBConnector:
+		return db.GetDBConn()
+	}
+	return nil, gorm.ErrInvalidDB
+}
+
+// tx is passed by pointer, gorm checks transactions for nil with reflect.
+type tx struct {
+	conn
+}
+
+func (t *tx) Commit() error {
+	committer, ok := t.ConnPool.(gorm.TxCommitter)
+	if !ok {
+		return gorm.ErrInvalidTransaction
+	}
+	return committer.Commit()
+}
+
+func (t *tx) Rollback() error {
+	committer, ok := t.ConnPool.(gorm.TxCommitter)
+	if !ok {
+		return gorm.ErrInvalidTransaction
+	}
+	return committer.Rollback()
+}
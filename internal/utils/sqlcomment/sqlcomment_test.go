@@ -0,0 +1,176 @@
+package sqlcomment
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/requestctx"
+	sqlmock "github.com/zhashkevych/go-sqlxmock"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestTag(t *testing.T) {
+	tests := []struct {
+		name          string
+		requestID     string
+		expectedQuery string
+	}{
+		{
+			name:          "No request id",
+			expectedQuery: "SELECT 1",
+		},
+		{
+			name:          "Request id",
+			requestID:     "4bf92f35-77b3-4da6:1",
+			expectedQuery: "SELECT 1 /*request_id='4bf92f35-77b3-4da6%3A1'*/",
+		},
+		{
+			name:          "Breaks out of the comment",
+			requestID:     "a'*/ DROP TABLE movies; /*",
+			expectedQuery: "SELECT 1 /*request_id='a%27%2A%2F+DROP+TABLE+movies%3B+%2F%2A'*/",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.requestID != "" {
+				ctx = requestctx.WithRequestID(ctx, tt.requestID)
+			}
+
+			assert.Equal(t, tt.expectedQuery, Tag(ctx, "SELECT 1"))
+		})
+	}
+}
+
+func TestComment_NoContext(t *testing.T) {
+	assert.Equal(t, "", Comment(context.Background()))
+}
+
+func openDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	sqlDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	db, err := gorm.Open(postgres.New(postgres.Config{
+		Conn: sqlDB,
+	}), &gorm.Config{DisableAutomaticPing: true})
+	require.NoError(t, err)
+	require.NoError(t, db.Use(Plugin{}))
+	return db, mock
+}
+
+func TestPlugin(t *testing.T) {
+	requestCtx := requestctx.WithRequestID(context.Background(), "1b4e28ba")
+
+	tests := []struct {
+		name         string
+		ctx          context.Context
+		mockBehavior func(mock sqlmock.Sqlmock)
+		run          func(db *gorm.DB) error
+	}{
+		{
+			name: "Pool",
+			ctx:  requestCtx,
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("DELETE FROM movies WHERE id = $1 /*request_id='1b4e28ba'*/").
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT title FROM movies /*request_id='1b4e28ba'*/").
+					WillReturnRows(sqlmock.NewRows([]string{"title"}).AddRow("Heat"))
+			},
+			run: func(db *gorm.DB) error {
+				if err := db.Exec("DELETE FROM movies WHERE id = ?", 1).Error; err != nil {
+					return err
+				}
+				var titles []string
+				return db.Raw("SELECT title FROM movies").Scan(&titles).Error
+			},
+		},
+		{
+			name: "No request id",
+			ctx:  context.Background(),
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("DELETE FROM movies WHERE id = $1").
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			run: func(db *gorm.DB) error {
+				return db.Exec("DELETE FROM movies WHERE id = ?", 1).Error
+			},
+		},
+		{
+			name: "Transaction",
+			ctx:  requestCtx,
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("DELETE FROM movies WHERE id = $1 /*request_id='1b4e28ba'*/").
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			run: func(db *gorm.DB) error {
+				return db.Transaction(func(tx *gorm.DB) error {
+					return tx.Exec("DELETE FROM movies WHERE id = ?", 1).Error
+				})
+			},
+		},
+		{
+			name: "Transaction rolled back",
+			ctx:  requestCtx,
+			mockBehavior: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("DELETE FROM movies WHERE id = $1 /*request_id='1b4e28ba'*/").
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectRollback()
+			},
+			run: func(db *gorm.DB) error {
+				err := db.Transaction(func(tx *gorm.DB) error {
+					if err := tx.Exec("DELETE FROM movies WHERE id = ?", 1).Error; err != nil {
+						return err
+					}
+					return errors.New("cast is gone")
+				})
+				if err == nil {
+					return errors.New("transaction is committed")
+				}
+				return nil
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := openDB(t)
+			tt.mockBehavior(mock)
+
+			assert.NoError(t, tt.run(db.WithContext(tt.ctx)))
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPlugin_DB(t *testing.T) {
+	db, _ := openDB(t)
+
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	assert.NotNil(t, sqlDB)
+}
+
+func TestPlugin_Twice(t *testing.T) {
+	db, mock := openDB(t)
+	require.NoError(t, Plugin{}.Initialize(db))
+
+	mock.ExpectExec("DELETE FROM movies /*request_id='1b4e28ba'*/").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	ctx := requestctx.WithRequestID(context.Background(), "1b4e28ba")
+	assert.NoError(t, db.WithContext(ctx).Exec("DELETE FROM movies").Error)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,114 @@
+package totp
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rfcSecret is the SHA-1 key of the test vectors of RFC 6238, appendix B.
+var rfcSecret = encoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestCode(t *testing.T) {
+	// The RFC gives 8 digits, the codes here are their last 6.
+	tests := []struct {
+		name         string
+		time         int64
+		expectedCode string
+	}{
+		{name: "1970-01-01 00:00:59", time: 59, expectedCode: "287082"},
+		{name: "2005-03-18 01:58:29", time: 1111111109, expectedCode: "081804"},
+		{name: "2005-03-18 01:58:31", time: 1111111111, expectedCode: "050471"},
+		{name: "2009-02-13 23:31:30", time: 1234567890, expectedCode: "005924"},
+		{name: "2033-05-18 03:33:20", time: 2000000000, expectedCode: "279037"},
+		{name: "2603-10-11 11:33:20", time: 20000000000, expectedCode: "353130"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := Code(rfcSecret, Step(time.Unix(tt.time, 0)))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedCode, code)
+		})
+	}
+}
+
+func TestCode_LowerCaseSecret(t *testing.T) {
+	code, err := Code(strings.ToLower(rfcSecret), Step(time.Unix(59, 0)))
+	require.NoError(t, err)
+	assert.Equal(t, "287082", code)
+}
+
+func TestCode_BadSecret(t *testing.T) {
+	_, err := Code("not base32!", 1)
+	assert.Error(t, err)
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	current := Step(now)
+
+	codeAt := func(step int64) string {
+		code, err := Code(rfcSecret, step)
+		require.NoError(t, err)
+		return code
+	}
+
+	tests := []struct {
+		name         string
+		code         string
+		expectedStep int64
+		expectedOK   bool
+	}{
+		{name: "Current step", code: codeAt(current), expectedStep: current, expectedOK: true},
+		{name: "Step behind", code: codeAt(current - 1), expectedStep: current - 1, expectedOK: true},
+		{name: "Step ahead", code: codeAt(current + 1), expectedStep: current + 1, expectedOK: true},
+		{name: "Two steps behind", code: codeAt(current - 2)},
+		{name: "Two steps ahead", code: codeAt(current + 2)},
+		{name: "Wrong code", code: "000000"},
+		{name: "Short code", code: codeAt(current)[:5]},
+		{name: "Long code", code: codeAt(current) + "0"},
+		{name: "Empty code", code: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := Validate(rfcSecret, tt.code, now)
+			assert.Equal(t, tt.expectedOK, ok)
+			assert.Equal(t, tt.expectedStep, step)
+		})
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	require.NoError(t, err)
+
+	key, err := encoding.DecodeString(secret)
+	require.NoError(t, err)
+	assert.Len(t, key, secretSize)
+
+	other, err := GenerateSecret()
+	require.NoError(t, err)
+	assert.NotEqual(t, secret, other)
+}
+
+func TestURI(t *testing.T) {
+	uri, err := url.Parse(URI("Film Library", "jane@example.com", rfcSecret))
+	require.NoError(t, err)
+
+	assert.Equal(t, "otpauth", uri.Scheme)
+	assert.Equal(t, "totp", uri.Host)
+	assert.Equal(t, "/Film Library:jane@example.com", uri.Path)
+	assert.Equal(t, url.Values{
+		"secret":    {rfcSecret},
+		"issuer":    {"Film Library"},
+		"algorithm": {"SHA1"},
+		"digits":    {"6"},
+		"period":    {"30"},
+	}, uri.Query())
+}
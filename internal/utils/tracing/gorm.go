@@ -0,0 +1,113 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const spanKey = "tracing:span"
+
+// statementSpan is the span of a statement and the context it replaced,
+// chains reused for more statements must not make them its children.
+type statementSpan struct {
+	span   trace.Span
+	parent context.Context
+}
+
+// Plugin makes a client span of every statement of a gorm connection, a
+// child of the span of the statement context. DB names the connection,
+// e.g. "movies".
+type Plugin struct {
+	DB string
+}
+
+func (Plugin) Name() string {
+	return "tracing"
+}
+
+func (p Plugin) Initialize(db *gorm.DB) error {
+	type registerer interface {
+		Register(name string, fn func(*gorm.DB)) error
+	}
+
+	callbacks := db.Callback()
+	processors := []struct {
+		operation string
+		before    registerer
+		after     registerer
+	}{
+		{"INSERT", callbacks.Create().Before("*"), callbacks.Create().After("*")},
+		{"SELECT", callbacks.Query().Before("*"), callbacks.Query().After("*")},
+		{"UPDATE", callbacks.Update().Before("*"), callbacks.Update().After("*")},
+		{"DELETE", callbacks.Delete().Before("*"), callbacks.Delete().After("*")},
+		{"", callbacks.Row().Before("*"), callbacks.Row().After("*")},
+		{"", callbacks.Raw().Before("*"), callbacks.Raw().After("*")},
+	}
+	for _, proc := range processors {
+		if err := proc.before.Register("tracing:start", p.start); err != nil {
+			return err
+		}
+		if err := proc.after.Register("tracing:end", end(proc.operation)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p Plugin) start(db *gorm.DB) {
+	parent := db.Statement.Context
+	ctx, span := tracer().Start(parent, "db",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemPostgreSQL,
+			attribute.String("db.connection", p.DB),
+		),
+	)
+	db.Statement.Context = ctx
+	db.InstanceSet(spanKey, statementSpan{span: span, parent: parent})
+}
+
+// end names the span like "SELECT movies". Raw statements are named by
+// their first keyword.
+func end(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(spanKey)
+		if !ok {
+			return
+		}
+		current, ok := value.(statementSpan)
+		if !ok {
+			return
+		}
+		span := current.span
+		defer span.End()
+		db.Statement.Context = current.parent
+
+		query := db.Statement.SQL.String()
+		operation := operation
+		if operation == "" {
+			operation = strings.ToUpper(strings.SplitN(strings.TrimSpace(query), " ", 2)[0])
+		}
+		name := operation
+		if table := db.Statement.Table; table != "" {
+			name += " " + table
+			span.SetAttributes(semconv.DBCollectionName(table))
+		}
+		span.SetName(name)
+		span.SetAttributes(
+			semconv.DBOperationName(operation),
+			semconv.DBQueryText(query),
+			attribute.Int64("db.rows_affected", db.RowsAffected),
+		)
+
+		if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+			Fail(span, db.Error)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package tracing
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	grpcCodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// metadataCarrier reads the trace context from gRPC metadata, where it
+// travels under the same keys as in HTTP headers.
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	values := metadata.MD(c).Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// UnaryServerInterceptor is Middleware for unary gRPC calls, the span is
+// named by the full method.
+func UnaryServerInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md))
+
+	service, method := path.Split(info.FullMethod)
+	ctx, span := tracer().Start(ctx, strings.TrimPrefix(info.FullMethod, "/"),
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			semconv.RPCSystemGRPC,
+			semconv.RPCService(strings.Trim(service, "/")),
+			semconv.RPCMethod(method),
+		),
+	)
+	defer span.End()
+
+	resp, err := handler(ctx, req)
+	code := status.Code(err)
+	span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(code)))
+	if err != nil && serverFault(code) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, code.String())
+	}
+	return resp, err
+}
+
+// serverFault tells the codes that fail the span of a call, the others are
+// the fault of the client.
+func serverFault(code grpcCodes.Code) bool {
+	switch code {
+	case grpcCodes.Unknown, grpcCodes.DeadlineExceeded, grpcCodes.Unimplemented,
+		grpcCodes.Internal, grpcCodes.Unavailable, grpcCodes.DataLoss:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+// Package tracing sets up OpenTelemetry: spans of HTTP requests, gRPC
+// calls, usecase calls and gorm statements, sent to the exporter of the
+// tracing settings. Trace context travels in W3C traceparent headers.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/config"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	ServiceName = "film-library"
+
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+
+	instrumentation = "github.com/themilchenko/vk-tech_internship-problem_2024"
+)
+
+// tracer is read from the global provider on every span: a tracer taken
+// once would stick to the first provider set, tests set one each.
+func tracer() trace.Tracer {
+	return otel.Tracer(instrumentation)
+}
+
+// Setup installs the propagator and, unless the exporter is "none", a
+// provider exporting spans. The returned function flushes and stops it.
+func Setup(ctx context.Context, settings config.TracingSettings) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	var exporter sdktrace.SpanExporter
+	switch settings.Exporter {
+	case ExporterNone, "":
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		var err error
+		if exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout)); err != nil {
+			return nil, err
+		}
+	case ExporterOTLP:
+		options := []otlptracehttp.Option{}
+		if settings.Endpoint != "" {
+			options = append(options, otlptracehttp.WithEndpoint(settings.Endpoint))
+		}
+		if settings.Insecure {
+			options = append(options, otlptracehttp.WithInsecure())
+		}
+		var err error
+		if exporter, err = otlptracehttp.New(ctx, options...); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("tracing exporter %q is none of none, stdout and otlp", settings.Exporter)
+	}
+
+	provider := NewProvider(sdktrace.WithBatcher(exporter), settings.SampleRatio)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// NewProvider makes the tracer provider of the service. Tests pass a
+// syncer of an in-memory exporter.
+func NewProvider(exporter sdktrace.TracerProviderOption, sampleRatio float64) *sdktrace.TracerProvider {
+	return sdktrace.NewTracerProvider(
+		exporter,
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceName(ServiceName),
+		)),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+	)
+}
+
+// Start starts an internal span, e.g. of a usecase call.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End ends span with the outcome of err. Only internal errors fail the
+// span, client errors like a missing movie are recorded by their code.
+func End(span trace.Span, err error) {
+	Fail(span, err)
+	span.End()
+}
+
+// Fail marks span with err, if any.
+func Fail(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+
+	domainErr := domain.AsError(err)
+	if domainErr.Kind != domain.KindInternal {
+		span.SetAttributes(attribute.String("error.code", domainErr.Code))
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, domainErr.Code)
+}
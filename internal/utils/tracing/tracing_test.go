@@ -0,0 +1,110 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/config"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/requestctx"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type movie struct {
+	ID    uint64
+	Title string
+}
+
+func setUp(t *testing.T) *tracetest.InMemoryExporter {
+	_, err := Setup(context.Background(), config.TracingSettings{Exporter: ExporterNone})
+	require.NoError(t, err)
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := NewProvider(sdktrace.WithSyncer(exporter), 1)
+	defaultProvider := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(defaultProvider) })
+	return exporter
+}
+
+func spansByName(spans tracetest.SpanStubs) map[string]tracetest.SpanStub {
+	byName := make(map[string]tracetest.SpanStub)
+	for _, span := range spans {
+		byName[span.Name] = span
+	}
+	return byName
+}
+
+func TestMiddleware(t *testing.T) {
+	exporter := setUp(t)
+
+	// DryRun runs the callbacks without a database.
+	db, err := gorm.Open(
+		postgres.New(postgres.Config{DSN: "host=localhost"}),
+		&gorm.Config{DryRun: true, DisableAutomaticPing: true},
+	)
+	require.NoError(t, err)
+	require.NoError(t, db.Use(Plugin{DB: "movies"}))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/movies/{id}", func(w http.ResponseWriter, r *http.Request) {
+		ctx := requestctx.WithRoute(r.Context(), "GET /api/v1/movies/{id}")
+		ctx, span := Start(ctx, "MoviesUsecase.GetMovieByID")
+		var m movie
+		db.WithContext(ctx).Table("movies").First(&m, 1)
+		End(span, domain.ErrNotFound)
+
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/movies/1", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	requestctx.Middleware(Middleware(mux)).ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := spansByName(exporter.GetSpans())
+	require.Len(t, spans, 3)
+
+	server := spans["GET /api/v1/movies/{id}"]
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", server.SpanContext.TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", server.Parent.SpanID().String())
+	assert.Equal(t, codes.Unset, server.Status.Code)
+
+	usecase := spans["MoviesUsecase.GetMovieByID"]
+	assert.Equal(t, server.SpanContext.SpanID(), usecase.Parent.SpanID())
+	assert.Equal(t, codes.Unset, usecase.Status.Code)
+
+	query := spans["SELECT movies"]
+	assert.Equal(t, usecase.SpanContext.SpanID(), query.Parent.SpanID())
+	assert.Contains(t, query.Attributes, semconv.DBQueryText(`SELECT * FROM "movies" WHERE "movies"."id" = $1 ORDER BY "movies"."id" LIMIT $2`))
+}
+
+func TestEnd(t *testing.T) {
+	exporter := setUp(t)
+
+	_, span := Start(context.Background(), "client error")
+	End(span, domain.ErrNotFound)
+	_, span = Start(context.Background(), "internal error")
+	End(span, errors.New("connection refused"))
+
+	spans := spansByName(exporter.GetSpans())
+	assert.Equal(t, codes.Unset, spans["client error"].Status.Code)
+	assert.Empty(t, spans["client error"].Events)
+	assert.Equal(t, codes.Error, spans["internal error"].Status.Code)
+	assert.Len(t, spans["internal error"].Events, 1)
+}
+
+func TestSetup(t *testing.T) {
+	_, err := Setup(context.Background(), config.TracingSettings{Exporter: "jaeger"})
+	assert.Error(t, err)
+}
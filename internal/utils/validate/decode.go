@@ -0,0 +1,132 @@
+package validate
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+)
+
+const unknownFieldPrefix = "json: unknown field "
+
+// DecodeJSON reads exactly one JSON value from the body into v and checks
+// it. Broken JSON is a bad request, unknown fields, wrong types and broken
+// rules are a domain.ValidationError.
+func DecodeJSON(r *http.Request, v interface{}) error {
+	return decode(r.Body, v)
+}
+
+// Unmarshal is DecodeJSON for a document that is already read, e.g. the
+// result of a patch.
+func Unmarshal(data []byte, v interface{}) error {
+	return decode(bytes.NewReader(data), v)
+}
+
+// NotNull checks that the members of the JSON object doc are there and not
+// null. A patch drops members with null and decoding turns them into zero
+// values, so it is for fields where the zero value would pass the rules.
+func NotNull(doc []byte, members ...string) error {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(doc, &object); err != nil {
+		return decodeError(err)
+	}
+
+	var violations []domain.FieldError
+	for _, member := range members {
+		if value, ok := object[member]; !ok || string(value) == "null" {
+			violations = append(violations, domain.FieldError{
+				Field:   member,
+				Rule:    "not_null",
+				Message: "can't be null",
+			})
+		}
+	}
+	if len(violations) > 0 {
+		return domain.ValidationError{Violations: violations}
+	}
+	return nil
+}
+
+func decode(r io.Reader, v interface{}) error {
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(v); err != nil {
+		return decodeError(err)
+	}
+	if decoder.More() {
+		return domain.ErrJSONUnmarshal.Wrap(errors.New("body must contain a single JSON value"))
+	}
+
+	return Check(v)
+}
+
+func decodeError(err error) error {
+	var typeErr *json.UnmarshalTypeError
+	var maxBytesErr *http.MaxBytesError
+
+	switch {
+	case errors.As(err, &maxBytesErr):
+		return domain.ErrBodyTooLarge.Wrap(err)
+	case errors.Is(err, io.EOF):
+		return domain.ErrJSONUnmarshal.Wrap(errors.New("body is empty"))
+	case errors.As(err, &typeErr):
+		return domain.ValidationError{Violations: []domain.FieldError{{
+			Field:   typeErr.Field,
+			Rule:    "type",
+			Message: "must be " + typeName(typeErr.Type),
+		}}}
+	case strings.HasPrefix(err.Error(), unknownFieldPrefix):
+		// encoding/json has no typed error for this one.
+		return domain.ValidationError{Violations: []domain.FieldError{{
+			Field:   strings.Trim(strings.TrimPrefix(err.Error(), unknownFieldPrefix), `"`),
+			Rule:    "unknown",
+			Message: "is not allowed",
+		}}}
+	}
+	return domain.ErrJSONUnmarshal.Wrap(err)
+}
+
+func typeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.Struct, reflect.Map:
+		return "an object"
+	}
+	return fmt.Sprintf("a %s", t.Kind())
+}
+
+// LimitBody rejects bodies longer than limit bytes. Reading past the limit
+// fails with *http.MaxBytesError, which DecodeJSON turns into a 413.
+// routeLimits replaces the limit for requests like "POST /api/v1/import",
+// matched by method and exact path.
+func LimitBody(limit int64, routeLimits map[string]int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			limit := limit
+			if routeLimit, ok := routeLimits[r.Method+" "+r.URL.Path]; ok {
+				limit = routeLimit
+			}
+			if limit > 0 && r.Body != nil {
+				r.Body = http.MaxBytesReader(w, r.Body, limit)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package validate
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/problem"
+)
+
+type cast struct {
+	Name  string `json:"name" validate:"required"`
+	Count int    `json:"count"`
+}
+
+type body struct {
+	Title string `json:"title" validate:"required"`
+	Cast  cast   `json:"cast"`
+}
+
+func TestDecodeJSON(t *testing.T) {
+	tests := []struct {
+		name               string
+		inputBody          string
+		expectedBody       body
+		expectedStatusCode int
+		expectedCode       string
+		expectedViolations []domain.FieldError
+	}{
+		{
+			name:         "OK",
+			inputBody:    `{"title":"Heat","cast":{"name":"Al Pacino","count":1}}`,
+			expectedBody: body{Title: "Heat", Cast: cast{Name: "Al Pacino", Count: 1}},
+		},
+		{
+			name:               "Empty body",
+			inputBody:          "",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedCode:       "invalid_json",
+		},
+		{
+			name:               "Broken JSON",
+			inputBody:          `{"title":`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedCode:       "invalid_json",
+		},
+		{
+			name:               "Trailing data",
+			inputBody:          `{"title":"Heat","cast":{"name":"Al Pacino"}} {"title":"Ronin"}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedCode:       "invalid_json",
+		},
+		{
+			name:               "Unknown field",
+			inputBody:          `{"title":"Heat","year":1995}`,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedCode:       "validation_failed",
+			expectedViolations: []domain.FieldError{
+				{Field: "year", Rule: "unknown", Message: "is not allowed"},
+			},
+		},
+		{
+			name:               "Wrong type",
+			inputBody:          `{"title":"Heat","cast":{"name":"Al Pacino","count":"one"}}`,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedCode:       "validation_failed",
+			expectedViolations: []domain.FieldError{
+				{Field: "cast.count", Rule: "type", Message: "must be an integer"},
+			},
+		},
+		{
+			name:               "Broken rule",
+			inputBody:          `{"title":"Heat","cast":{"name":" "}}`,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedCode:       "validation_failed",
+			expectedViolations: []domain.FieldError{
+				{Field: "cast.name", Rule: "required", Message: "is required"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.inputBody))
+
+			var b body
+			err := DecodeJSON(req, &b)
+			if tt.expectedStatusCode == 0 {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedBody, b)
+				return
+			}
+
+			p := problem.New(err)
+			assert.Equal(t, tt.expectedStatusCode, p.Status)
+			assert.Equal(t, tt.expectedCode, p.Code)
+			assert.Equal(t, tt.expectedViolations, p.Violations)
+		})
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	var b body
+	assert.NoError(t, Unmarshal([]byte(`{"title":"Heat","cast":{"name":"Al Pacino"}}`), &b))
+	assert.Equal(t, body{Title: "Heat", Cast: cast{Name: "Al Pacino"}}, b)
+
+	assert.ErrorIs(t, Unmarshal([]byte(`{"title":"Heat"}[]`), &b), domain.ErrJSONUnmarshal)
+}
+
+func TestNotNull(t *testing.T) {
+	tests := []struct {
+		name          string
+		doc           string
+		expectedError error
+	}{
+		{
+			name: "OK",
+			doc:  `{"title":"Heat","rating":0}`,
+		},
+		{
+			name: "Null",
+			doc:  `{"title":"Heat","rating":null}`,
+			expectedError: domain.ValidationError{Violations: []domain.FieldError{
+				{Field: "rating", Rule: "not_null", Message: "can't be null"},
+			}},
+		},
+		{
+			name: "Missing",
+			doc:  `{}`,
+			expectedError: domain.ValidationError{Violations: []domain.FieldError{
+				{Field: "title", Rule: "not_null", Message: "can't be null"},
+				{Field: "rating", Rule: "not_null", Message: "can't be null"},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedError, NotNull([]byte(tt.doc), "title", "rating"))
+		})
+	}
+}
+
+func TestLimitBody(t *testing.T) {
+	tests := []struct {
+		name                 string
+		limit                int64
+		target               string
+		inputBody            string
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:               "Under the limit",
+			limit:              64,
+			target:             "/api/v1/movies",
+			inputBody:          `{"title":"Heat","cast":{"name":"Al Pacino"}}`,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:                 "Over the limit",
+			limit:                16,
+			target:               "/api/v1/movies",
+			inputBody:            `{"title":"Heat","cast":{"name":"Al Pacino"}}`,
+			expectedStatusCode:   http.StatusRequestEntityTooLarge,
+			expectedResponseBody: `{"type":"about:blank","title":"Request Entity Too Large","status":413,"detail":"request body is too large: http: request body too large","code":"body_too_large"}`,
+		},
+		{
+			name:               "Route limit",
+			limit:              16,
+			target:             "/api/v1/import",
+			inputBody:          `{"title":"Heat","cast":{"name":"Al Pacino"}}`,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "No limit",
+			limit:              0,
+			target:             "/api/v1/movies",
+			inputBody:          `{"title":"Heat","cast":{"name":"Al Pacino"}}`,
+			expectedStatusCode: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := LimitBody(tt.limit, map[string]int64{
+				"POST /api/v1/import": 1 << 10,
+			})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var b body
+				if err := DecodeJSON(r, &b); err != nil {
+					problem.Write(w, r, err)
+					return
+				}
+			}))
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, tt.target, strings.NewReader(tt.inputBody))
+
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatusCode, w.Code)
+			assert.Equal(t, tt.expectedResponseBody, strings.Trim(w.Body.String(), "\n"))
+		})
+	}
+}
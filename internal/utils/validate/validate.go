@@ -0,0 +1,181 @@
+package validate
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+)
+
+// TagName is the struct tag with the rules of a field, e.g.
+// `validate:"required,max=150"`. Rules:
+//
+//	required  not empty, whitespace only strings are empty too
+//	min=N     at least N characters, items or, for numbers, N
+//	max=N     at most N characters, items or, for numbers, N
+//	date      a YYYY-MM-DD date
+//	past      a date that is not in the future
+//
+// Structs and slices of structs are checked recursively.
+const TagName = "validate"
+
+// Struct returns every rule v breaks, each with the JSON path of the field.
+func Struct(v interface{}) []domain.FieldError {
+	return check(reflect.Indirect(reflect.ValueOf(v)), "")
+}
+
+// Check is Struct as an error for bodies that don't come as JSON, e.g. gRPC
+// messages. It is nil when v breaks no rule.
+func Check(v interface{}) error {
+	if violations := Struct(v); len(violations) > 0 {
+		return domain.ValidationError{Violations: violations}
+	}
+	return nil
+}
+
+func check(value reflect.Value, path string) []domain.FieldError {
+	var violations []domain.FieldError
+
+	switch value.Kind() {
+	case reflect.Struct:
+		t := value.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, ok := jsonName(field)
+			if !ok {
+				continue
+			}
+			fieldPath := join(path, name)
+			fieldValue := value.Field(i)
+
+			if rules := field.Tag.Get(TagName); rules != "" {
+				violations = append(violations, checkRules(fieldValue, fieldPath, rules)...)
+			}
+			violations = append(violations, check(fieldValue, fieldPath)...)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			violations = append(violations, check(value.Index(i), fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	case reflect.Pointer:
+		if !value.IsNil() {
+			violations = append(violations, check(value.Elem(), path)...)
+		}
+	}
+
+	return violations
+}
+
+func checkRules(value reflect.Value, path, rules string) []domain.FieldError {
+	var violations []domain.FieldError
+	for _, rule := range strings.Split(rules, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		message, ok := checkRule(value, name, param)
+		if ok {
+			continue
+		}
+		violations = append(violations, domain.FieldError{
+			Field:   path,
+			Rule:    name,
+			Message: message,
+		})
+		// The other rules make no sense for a missing value.
+		if name == "required" {
+			break
+		}
+	}
+	return violations
+}
+
+func checkRule(value reflect.Value, rule, param string) (string, bool) {
+	switch rule {
+	case "required":
+		if value.Kind() == reflect.String {
+			return "is required", strings.TrimSpace(value.String()) != ""
+		}
+		return "is required", !value.IsZero()
+	case "min":
+		return checkBound(value, param, -1)
+	case "max":
+		return checkBound(value, param, 1)
+	case "date":
+		if value.String() == "" {
+			return "", true
+		}
+		_, err := time.Parse(time.DateOnly, value.String())
+		return "must be a date in YYYY-MM-DD format", err == nil
+	case "past":
+		date, err := time.Parse(time.DateOnly, value.String())
+		if err != nil {
+			return "", true
+		}
+		return "must not be in the future", !date.After(time.Now())
+	}
+	panic(fmt.Sprintf("validate: unknown rule %q", rule))
+}
+
+// checkBound checks a min bound for a negative sign and a max bound for a
+// positive one.
+func checkBound(value reflect.Value, param string, sign int) (string, bool) {
+	limit, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		panic(fmt.Sprintf("validate: bad bound %q", param))
+	}
+
+	word := "least"
+	if sign > 0 {
+		word = "most"
+	}
+
+	var actual float64
+	var message string
+	switch value.Kind() {
+	case reflect.String:
+		actual = float64(utf8.RuneCountInString(value.String()))
+		message = fmt.Sprintf("must be at %s %s characters long", word, param)
+	case reflect.Slice, reflect.Array, reflect.Map:
+		actual = float64(value.Len())
+		message = fmt.Sprintf("must have at %s %s items", word, param)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		actual = float64(value.Int())
+		message = fmt.Sprintf("must be at %s %s", word, param)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		actual = float64(value.Uint())
+		message = fmt.Sprintf("must be at %s %s", word, param)
+	case reflect.Float32, reflect.Float64:
+		actual = value.Float()
+		message = fmt.Sprintf("must be at %s %s", word, param)
+	default:
+		panic(fmt.Sprintf("validate: no bounds for %s", value.Kind()))
+	}
+
+	if sign > 0 {
+		return message, actual <= limit
+	}
+	return message, actual >= limit
+}
+
+func jsonName(field reflect.StructField) (string, bool) {
+	if !field.IsExported() {
+		return "", false
+	}
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return "", false
+	case "":
+		return field.Name, true
+	}
+	return name, true
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
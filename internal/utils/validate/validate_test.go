@@ -0,0 +1,204 @@
+package validate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+)
+
+type role struct {
+	Character string `json:"character" validate:"required,max=10"`
+}
+
+type movie struct {
+	Title       string   `json:"title" validate:"required,min=2,max=10"`
+	ReleaseDate string   `json:"releaseDate" validate:"date,past"`
+	Rating      float64  `json:"rating" validate:"min=0,max=10"`
+	Year        int      `json:"year,omitempty" validate:"max=2100"`
+	Votes       uint64   `json:"votes" validate:"max=1000"`
+	Tags        []string `json:"tags" validate:"max=2"`
+	Roles       []role   `json:"roles"`
+	Lead        *role    `json:"lead"`
+	Untagged    string   `validate:"required"`
+	Skipped     string   `json:"-" validate:"required"`
+	hidden      string   `validate:"required"`
+}
+
+func validMovie() movie {
+	return movie{
+		Title:       "Heat",
+		ReleaseDate: "1995-12-15",
+		Rating:      8.3,
+		Untagged:    "set",
+	}
+}
+
+func TestStruct(t *testing.T) {
+	tests := []struct {
+		name               string
+		patch              func(m *movie)
+		expectedViolations []domain.FieldError
+	}{
+		{
+			name:  "OK",
+			patch: func(m *movie) {},
+		},
+		{
+			name:  "Required",
+			patch: func(m *movie) { m.Title = "" },
+			expectedViolations: []domain.FieldError{
+				{Field: "title", Rule: "required", Message: "is required"},
+			},
+		},
+		{
+			name:  "Required, whitespace only",
+			patch: func(m *movie) { m.Title = "  \t" },
+			expectedViolations: []domain.FieldError{
+				{Field: "title", Rule: "required", Message: "is required"},
+			},
+		},
+		{
+			name:  "Min, characters",
+			patch: func(m *movie) { m.Title = "Ж" },
+			expectedViolations: []domain.FieldError{
+				{Field: "title", Rule: "min", Message: "must be at least 2 characters long"},
+			},
+		},
+		{
+			name:  "Max, characters counted as runes",
+			patch: func(m *movie) { m.Title = "Жизнь прекрасна" },
+			expectedViolations: []domain.FieldError{
+				{Field: "title", Rule: "max", Message: "must be at most 10 characters long"},
+			},
+		},
+		{
+			name:  "Max, runes at the bound",
+			patch: func(m *movie) { m.Title = "Сталкер..." },
+		},
+		{
+			name:  "Min, float",
+			patch: func(m *movie) { m.Rating = -0.5 },
+			expectedViolations: []domain.FieldError{
+				{Field: "rating", Rule: "min", Message: "must be at least 0"},
+			},
+		},
+		{
+			name:  "Max, float",
+			patch: func(m *movie) { m.Rating = 10.5 },
+			expectedViolations: []domain.FieldError{
+				{Field: "rating", Rule: "max", Message: "must be at most 10"},
+			},
+		},
+		{
+			name:  "Max, int",
+			patch: func(m *movie) { m.Year = 2101 },
+			expectedViolations: []domain.FieldError{
+				{Field: "year", Rule: "max", Message: "must be at most 2100"},
+			},
+		},
+		{
+			name:  "Max, uint",
+			patch: func(m *movie) { m.Votes = 1001 },
+			expectedViolations: []domain.FieldError{
+				{Field: "votes", Rule: "max", Message: "must be at most 1000"},
+			},
+		},
+		{
+			name:  "Max, items",
+			patch: func(m *movie) { m.Tags = []string{"crime", "drama", "thriller"} },
+			expectedViolations: []domain.FieldError{
+				{Field: "tags", Rule: "max", Message: "must have at most 2 items"},
+			},
+		},
+		{
+			name:  "Date, empty is left to required",
+			patch: func(m *movie) { m.ReleaseDate = "" },
+		},
+		{
+			name:  "Date",
+			patch: func(m *movie) { m.ReleaseDate = "15.12.1995" },
+			expectedViolations: []domain.FieldError{
+				{Field: "releaseDate", Rule: "date", Message: "must be a date in YYYY-MM-DD format"},
+			},
+		},
+		{
+			name:  "Past",
+			patch: func(m *movie) { m.ReleaseDate = "2999-01-01" },
+			expectedViolations: []domain.FieldError{
+				{Field: "releaseDate", Rule: "past", Message: "must not be in the future"},
+			},
+		},
+		{
+			name:  "Slice of structs",
+			patch: func(m *movie) { m.Roles = []role{{Character: "Neil"}, {Character: ""}} },
+			expectedViolations: []domain.FieldError{
+				{Field: "roles[1].character", Rule: "required", Message: "is required"},
+			},
+		},
+		{
+			name:  "Pointer to a struct",
+			patch: func(m *movie) { m.Lead = &role{Character: "Vincent Hanna"} },
+			expectedViolations: []domain.FieldError{
+				{Field: "lead.character", Rule: "max", Message: "must be at most 10 characters long"},
+			},
+		},
+		{
+			name:  "Field without a JSON name",
+			patch: func(m *movie) { m.Untagged = "" },
+			expectedViolations: []domain.FieldError{
+				{Field: "Untagged", Rule: "required", Message: "is required"},
+			},
+		},
+		{
+			name: "Every violation at once",
+			patch: func(m *movie) {
+				m.Title = ""
+				m.Rating = 11
+				m.ReleaseDate = "1995-13-01"
+			},
+			expectedViolations: []domain.FieldError{
+				{Field: "title", Rule: "required", Message: "is required"},
+				{Field: "releaseDate", Rule: "date", Message: "must be a date in YYYY-MM-DD format"},
+				{Field: "rating", Rule: "max", Message: "must be at most 10"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := validMovie()
+			tt.patch(&m)
+
+			assert.Equal(t, tt.expectedViolations, Struct(&m))
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	m := validMovie()
+	assert.NoError(t, Check(m))
+
+	m.Title = ""
+	assert.Equal(t, domain.ValidationError{Violations: []domain.FieldError{
+		{Field: "title", Rule: "required", Message: "is required"},
+	}}, Check(m))
+}
+
+func TestStruct_BadRules(t *testing.T) {
+	assert.PanicsWithValue(t, `validate: unknown rule "email"`, func() {
+		Struct(struct {
+			Email string `validate:"email"`
+		}{})
+	})
+	assert.PanicsWithValue(t, `validate: bad bound "ten"`, func() {
+		Struct(struct {
+			Name string `validate:"max=ten"`
+		}{})
+	})
+	assert.PanicsWithValue(t, "validate: no bounds for bool", func() {
+		Struct(struct {
+			Adult bool `validate:"max=1"`
+		}{})
+	})
+}
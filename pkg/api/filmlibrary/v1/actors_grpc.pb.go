@@ -0,0 +1,281 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: filmlibrary/v1/actors.proto
+
+package filmlibraryv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ActorsService_CreateActor_FullMethodName = "/filmlibrary.v1.ActorsService/CreateActor"
+	ActorsService_GetActor_FullMethodName    = "/filmlibrary.v1.ActorsService/GetActor"
+	ActorsService_ListActors_FullMethodName  = "/filmlibrary.v1.ActorsService/ListActors"
+	ActorsService_UpdateActor_FullMethodName = "/filmlibrary.v1.ActorsService/UpdateActor"
+	ActorsService_DeleteActor_FullMethodName = "/filmlibrary.v1.ActorsService/DeleteActor"
+)
+
+// ActorsServiceClient is the client API for ActorsService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// ActorsService mirrors /api/v1/actors. Reads need a session, changes are
+// only for admins. Version fields are the expected version of the actor, as
+// sent in If-Match; zero changes any version.
+type ActorsServiceClient interface {
+	CreateActor(ctx context.Context, in *CreateActorRequest, opts ...grpc.CallOption) (*CreateActorResponse, error)
+	GetActor(ctx context.Context, in *GetActorRequest, opts ...grpc.CallOption) (*GetActorResponse, error)
+	ListActors(ctx context.Context, in *ListActorsRequest, opts ...grpc.CallOption) (*ListActorsResponse, error)
+	UpdateActor(ctx context.Context, in *UpdateActorRequest, opts ...grpc.CallOption) (*UpdateActorResponse, error)
+	DeleteActor(ctx context.Context, in *DeleteActorRequest, opts ...grpc.CallOption) (*DeleteActorResponse, error)
+}
+
+type actorsServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewActorsServiceClient(cc grpc.ClientConnInterface) ActorsServiceClient {
+	return &actorsServiceClient{cc}
+}
+
+func (c *actorsServiceClient) CreateActor(ctx context.Context, in *CreateActorRequest, opts ...grpc.CallOption) (*CreateActorResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateActorResponse)
+	err := c.cc.Invoke(ctx, ActorsService_CreateActor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *actorsServiceClient) GetActor(ctx context.Context, in *GetActorRequest, opts ...grpc.CallOption) (*GetActorResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetActorResponse)
+	err := c.cc.Invoke(ctx, ActorsService_GetActor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *actorsServiceClient) ListActors(ctx context.Context, in *ListActorsRequest, opts ...grpc.CallOption) (*ListActorsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListActorsResponse)
+	err := c.cc.Invoke(ctx, ActorsService_ListActors_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *actorsServiceClient) UpdateActor(ctx context.Context, in *UpdateActorRequest, opts ...grpc.CallOption) (*UpdateActorResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateActorResponse)
+	err := c.cc.Invoke(ctx, ActorsService_UpdateActor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *actorsServiceClient) DeleteActor(ctx context.Context, in *DeleteActorRequest, opts ...grpc.CallOption) (*DeleteActorResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteActorResponse)
+	err := c.cc.Invoke(ctx, ActorsService_DeleteActor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ActorsServiceServer is the server API for ActorsService service.
+// All implementations must embed UnimplementedActorsServiceServer
+// for forward compatibility.
+//
+// ActorsService mirrors /api/v1/actors. Reads need a session, changes are
+// only for admins. Version fields are the expected version of the actor, as
+// sent in If-Match; zero changes any version.
+type ActorsServiceServer interface {
+	CreateActor(context.Context, *CreateActorRequest) (*CreateActorResponse, error)
+	GetActor(context.Context, *GetActorRequest) (*GetActorResponse, error)
+	ListActors(context.Context, *ListActorsRequest) (*ListActorsResponse, error)
+	UpdateActor(context.Context, *UpdateActorRequest) (*UpdateActorResponse, error)
+	DeleteActor(context.Context, *DeleteActorRequest) (*DeleteActorResponse, error)
+	mustEmbedUnimplementedActorsServiceServer()
+}
+
+// UnimplementedActorsServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedActorsServiceServer struct{}
+
+func (UnimplementedActorsServiceServer) CreateActor(context.Context, *CreateActorRequest) (*CreateActorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateActor not implemented")
+}
+func (UnimplementedActorsServiceServer) GetActor(context.Context, *GetActorRequest) (*GetActorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetActor not implemented")
+}
+func (UnimplementedActorsServiceServer) ListActors(context.Context, *ListActorsRequest) (*ListActorsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListActors not implemented")
+}
+func (UnimplementedActorsServiceServer) UpdateActor(context.Context, *UpdateActorRequest) (*UpdateActorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateActor not implemented")
+}
+func (UnimplementedActorsServiceServer) DeleteActor(context.Context, *DeleteActorRequest) (*DeleteActorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteActor not implemented")
+}
+func (UnimplementedActorsServiceServer) mustEmbedUnimplementedActorsServiceServer() {}
+func (UnimplementedActorsServiceServer) testEmbeddedByValue()                       {}
+
+// UnsafeActorsServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ActorsServiceServer will
+// result in compilation errors.
+type UnsafeActorsServiceServer interface {
+	mustEmbedUnimplementedActorsServiceServer()
+}
+
+func RegisterActorsServiceServer(s grpc.ServiceRegistrar, srv ActorsServiceServer) {
+	// If the following call pancis, it indicates UnimplementedActorsServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ActorsService_ServiceDesc, srv)
+}
+
+func _ActorsService_CreateActor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateActorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ActorsServiceServer).CreateActor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ActorsService_CreateActor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ActorsServiceServer).CreateActor(ctx, req.(*CreateActorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ActorsService_GetActor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetActorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ActorsServiceServer).GetActor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ActorsService_GetActor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ActorsServiceServer).GetActor(ctx, req.(*GetActorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ActorsService_ListActors_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListActorsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ActorsServiceServer).ListActors(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ActorsService_ListActors_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ActorsServiceServer).ListActors(ctx, req.(*ListActorsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ActorsService_UpdateActor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateActorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ActorsServiceServer).UpdateActor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ActorsService_UpdateActor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ActorsServiceServer).UpdateActor(ctx, req.(*UpdateActorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ActorsService_DeleteActor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteActorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ActorsServiceServer).DeleteActor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ActorsService_DeleteActor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ActorsServiceServer).DeleteActor(ctx, req.(*DeleteActorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ActorsService_ServiceDesc is the grpc.ServiceDesc for ActorsService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ActorsService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "filmlibrary.v1.ActorsService",
+	HandlerType: (*ActorsServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateActor",
+			Handler:    _ActorsService_CreateActor_Handler,
+		},
+		{
+			MethodName: "GetActor",
+			Handler:    _ActorsService_GetActor_Handler,
+		},
+		{
+			MethodName: "ListActors",
+			Handler:    _ActorsService_ListActors_Handler,
+		},
+		{
+			MethodName: "UpdateActor",
+			Handler:    _ActorsService_UpdateActor_Handler,
+		},
+		{
+			MethodName: "DeleteActor",
+			Handler:    _ActorsService_DeleteActor_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "filmlibrary/v1/actors.proto",
+}
@@ -0,0 +1,433 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: filmlibrary/v1/movies.proto
+
+package filmlibraryv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	MoviesService_CreateMovie_FullMethodName          = "/filmlibrary.v1.MoviesService/CreateMovie"
+	MoviesService_GetMovie_FullMethodName             = "/filmlibrary.v1.MoviesService/GetMovie"
+	MoviesService_ListMovies_FullMethodName           = "/filmlibrary.v1.MoviesService/ListMovies"
+	MoviesService_UpdateMovie_FullMethodName          = "/filmlibrary.v1.MoviesService/UpdateMovie"
+	MoviesService_DeleteMovie_FullMethodName          = "/filmlibrary.v1.MoviesService/DeleteMovie"
+	MoviesService_AddActorToMovie_FullMethodName      = "/filmlibrary.v1.MoviesService/AddActorToMovie"
+	MoviesService_RemoveActorFromMovie_FullMethodName = "/filmlibrary.v1.MoviesService/RemoveActorFromMovie"
+	MoviesService_ReplaceCast_FullMethodName          = "/filmlibrary.v1.MoviesService/ReplaceCast"
+	MoviesService_BatchCast_FullMethodName            = "/filmlibrary.v1.MoviesService/BatchCast"
+)
+
+// MoviesServiceClient is the client API for MoviesService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// MoviesService mirrors /api/v1/movies. Reads need a session, changes are
+// only for admins. Version fields are the expected version of the movie, as
+// sent in If-Match; zero changes any version.
+type MoviesServiceClient interface {
+	CreateMovie(ctx context.Context, in *CreateMovieRequest, opts ...grpc.CallOption) (*CreateMovieResponse, error)
+	GetMovie(ctx context.Context, in *GetMovieRequest, opts ...grpc.CallOption) (*GetMovieResponse, error)
+	ListMovies(ctx context.Context, in *ListMoviesRequest, opts ...grpc.CallOption) (*ListMoviesResponse, error)
+	UpdateMovie(ctx context.Context, in *UpdateMovieRequest, opts ...grpc.CallOption) (*UpdateMovieResponse, error)
+	DeleteMovie(ctx context.Context, in *DeleteMovieRequest, opts ...grpc.CallOption) (*DeleteMovieResponse, error)
+	AddActorToMovie(ctx context.Context, in *AddActorToMovieRequest, opts ...grpc.CallOption) (*AddActorToMovieResponse, error)
+	RemoveActorFromMovie(ctx context.Context, in *RemoveActorFromMovieRequest, opts ...grpc.CallOption) (*RemoveActorFromMovieResponse, error)
+	ReplaceCast(ctx context.Context, in *ReplaceCastRequest, opts ...grpc.CallOption) (*ReplaceCastResponse, error)
+	BatchCast(ctx context.Context, in *BatchCastRequest, opts ...grpc.CallOption) (*BatchCastResponse, error)
+}
+
+type moviesServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMoviesServiceClient(cc grpc.ClientConnInterface) MoviesServiceClient {
+	return &moviesServiceClient{cc}
+}
+
+func (c *moviesServiceClient) CreateMovie(ctx context.Context, in *CreateMovieRequest, opts ...grpc.CallOption) (*CreateMovieResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateMovieResponse)
+	err := c.cc.Invoke(ctx, MoviesService_CreateMovie_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *moviesServiceClient) GetMovie(ctx context.Context, in *GetMovieRequest, opts ...grpc.CallOption) (*GetMovieResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetMovieResponse)
+	err := c.cc.Invoke(ctx, MoviesService_GetMovie_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *moviesServiceClient) ListMovies(ctx context.Context, in *ListMoviesRequest, opts ...grpc.CallOption) (*ListMoviesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListMoviesResponse)
+	err := c.cc.Invoke(ctx, MoviesService_ListMovies_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *moviesServiceClient) UpdateMovie(ctx context.Context, in *UpdateMovieRequest, opts ...grpc.CallOption) (*UpdateMovieResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateMovieResponse)
+	err := c.cc.Invoke(ctx, MoviesService_UpdateMovie_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *moviesServiceClient) DeleteMovie(ctx context.Context, in *DeleteMovieRequest, opts ...grpc.CallOption) (*DeleteMovieResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteMovieResponse)
+	err := c.cc.Invoke(ctx, MoviesService_DeleteMovie_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *moviesServiceClient) AddActorToMovie(ctx context.Context, in *AddActorToMovieRequest, opts ...grpc.CallOption) (*AddActorToMovieResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddActorToMovieResponse)
+	err := c.cc.Invoke(ctx, MoviesService_AddActorToMovie_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *moviesServiceClient) RemoveActorFromMovie(ctx context.Context, in *RemoveActorFromMovieRequest, opts ...grpc.CallOption) (*RemoveActorFromMovieResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RemoveActorFromMovieResponse)
+	err := c.cc.Invoke(ctx, MoviesService_RemoveActorFromMovie_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *moviesServiceClient) ReplaceCast(ctx context.Context, in *ReplaceCastRequest, opts ...grpc.CallOption) (*ReplaceCastResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReplaceCastResponse)
+	err := c.cc.Invoke(ctx, MoviesService_ReplaceCast_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *moviesServiceClient) BatchCast(ctx context.Context, in *BatchCastRequest, opts ...grpc.CallOption) (*BatchCastResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchCastResponse)
+	err := c.cc.Invoke(ctx, MoviesService_BatchCast_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MoviesServiceServer is the server API for MoviesService service.
+// All implementations must embed UnimplementedMoviesServiceServer
+// for forward compatibility.
+//
+// MoviesService mirrors /api/v1/movies. Reads need a session, changes are
+// only for admins. Version fields are the expected version of the movie, as
+// sent in If-Match; zero changes any version.
+type MoviesServiceServer interface {
+	CreateMovie(context.Context, *CreateMovieRequest) (*CreateMovieResponse, error)
+	GetMovie(context.Context, *GetMovieRequest) (*GetMovieResponse, error)
+	ListMovies(context.Context, *ListMoviesRequest) (*ListMoviesResponse, error)
+	UpdateMovie(context.Context, *UpdateMovieRequest) (*UpdateMovieResponse, error)
+	DeleteMovie(context.Context, *DeleteMovieRequest) (*DeleteMovieResponse, error)
+	AddActorToMovie(context.Context, *AddActorToMovieRequest) (*AddActorToMovieResponse, error)
+	RemoveActorFromMovie(context.Context, *RemoveActorFromMovieRequest) (*RemoveActorFromMovieResponse, error)
+	ReplaceCast(context.Context, *ReplaceCastRequest) (*ReplaceCastResponse, error)
+	BatchCast(context.Context, *BatchCastRequest) (*BatchCastResponse, error)
+	mustEmbedUnimplementedMoviesServiceServer()
+}
+
+// UnimplementedMoviesServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedMoviesServiceServer struct{}
+
+func (UnimplementedMoviesServiceServer) CreateMovie(context.Context, *CreateMovieRequest) (*CreateMovieResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateMovie not implemented")
+}
+func (UnimplementedMoviesServiceServer) GetMovie(context.Context, *GetMovieRequest) (*GetMovieResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMovie not implemented")
+}
+func (UnimplementedMoviesServiceServer) ListMovies(context.Context, *ListMoviesRequest) (*ListMoviesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMovies not implemented")
+}
+func (UnimplementedMoviesServiceServer) UpdateMovie(context.Context, *UpdateMovieRequest) (*UpdateMovieResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateMovie not implemented")
+}
+func (UnimplementedMoviesServiceServer) DeleteMovie(context.Context, *DeleteMovieRequest) (*DeleteMovieResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteMovie not implemented")
+}
+func (UnimplementedMoviesServiceServer) AddActorToMovie(context.Context, *AddActorToMovieRequest) (*AddActorToMovieResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddActorToMovie not implemented")
+}
+func (UnimplementedMoviesServiceServer) RemoveActorFromMovie(context.Context, *RemoveActorFromMovieRequest) (*RemoveActorFromMovieResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveActorFromMovie not implemented")
+}
+func (UnimplementedMoviesServiceServer) ReplaceCast(context.Context, *ReplaceCastRequest) (*ReplaceCastResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReplaceCast not implemented")
+}
+func (UnimplementedMoviesServiceServer) BatchCast(context.Context, *BatchCastRequest) (*BatchCastResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchCast not implemented")
+}
+func (UnimplementedMoviesServiceServer) mustEmbedUnimplementedMoviesServiceServer() {}
+func (UnimplementedMoviesServiceServer) testEmbeddedByValue()                       {}
+
+// UnsafeMoviesServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MoviesServiceServer will
+// result in compilation errors.
+type UnsafeMoviesServiceServer interface {
+	mustEmbedUnimplementedMoviesServiceServer()
+}
+
+func RegisterMoviesServiceServer(s grpc.ServiceRegistrar, srv MoviesServiceServer) {
+	// If the following call pancis, it indicates UnimplementedMoviesServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&MoviesService_ServiceDesc, srv)
+}
+
+func _MoviesService_CreateMovie_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateMovieRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MoviesServiceServer).CreateMovie(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MoviesService_CreateMovie_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MoviesServiceServer).CreateMovie(ctx, req.(*CreateMovieRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MoviesService_GetMovie_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMovieRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MoviesServiceServer).GetMovie(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MoviesService_GetMovie_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MoviesServiceServer).GetMovie(ctx, req.(*GetMovieRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MoviesService_ListMovies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMoviesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MoviesServiceServer).ListMovies(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MoviesService_ListMovies_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MoviesServiceServer).ListMovies(ctx, req.(*ListMoviesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MoviesService_UpdateMovie_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateMovieRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MoviesServiceServer).UpdateMovie(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MoviesService_UpdateMovie_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MoviesServiceServer).UpdateMovie(ctx, req.(*UpdateMovieRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MoviesService_DeleteMovie_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteMovieRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MoviesServiceServer).DeleteMovie(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MoviesService_DeleteMovie_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MoviesServiceServer).DeleteMovie(ctx, req.(*DeleteMovieRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MoviesService_AddActorToMovie_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddActorToMovieRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MoviesServiceServer).AddActorToMovie(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MoviesService_AddActorToMovie_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MoviesServiceServer).AddActorToMovie(ctx, req.(*AddActorToMovieRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MoviesService_RemoveActorFromMovie_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveActorFromMovieRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MoviesServiceServer).RemoveActorFromMovie(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MoviesService_RemoveActorFromMovie_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MoviesServiceServer).RemoveActorFromMovie(ctx, req.(*RemoveActorFromMovieRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MoviesService_ReplaceCast_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplaceCastRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MoviesServiceServer).ReplaceCast(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MoviesService_ReplaceCast_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MoviesServiceServer).ReplaceCast(ctx, req.(*ReplaceCastRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MoviesService_BatchCast_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchCastRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MoviesServiceServer).BatchCast(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MoviesService_BatchCast_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MoviesServiceServer).BatchCast(ctx, req.(*BatchCastRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// MoviesService_ServiceDesc is the grpc.ServiceDesc for MoviesService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var MoviesService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "filmlibrary.v1.MoviesService",
+	HandlerType: (*MoviesServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateMovie",
+			Handler:    _MoviesService_CreateMovie_Handler,
+		},
+		{
+			MethodName: "GetMovie",
+			Handler:    _MoviesService_GetMovie_Handler,
+		},
+		{
+			MethodName: "ListMovies",
+			Handler:    _MoviesService_ListMovies_Handler,
+		},
+		{
+			MethodName: "UpdateMovie",
+			Handler:    _MoviesService_UpdateMovie_Handler,
+		},
+		{
+			MethodName: "DeleteMovie",
+			Handler:    _MoviesService_DeleteMovie_Handler,
+		},
+		{
+			MethodName: "AddActorToMovie",
+			Handler:    _MoviesService_AddActorToMovie_Handler,
+		},
+		{
+			MethodName: "RemoveActorFromMovie",
+			Handler:    _MoviesService_RemoveActorFromMovie_Handler,
+		},
+		{
+			MethodName: "ReplaceCast",
+			Handler:    _MoviesService_ReplaceCast_Handler,
+		},
+		{
+			MethodName: "BatchCast",
+			Handler:    _MoviesService_BatchCast_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "filmlibrary/v1/movies.proto",
+}
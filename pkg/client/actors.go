@@ -0,0 +1,84 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+type Actor struct {
+	ID     uint64 `json:"id"`
+	Name   string `json:"name"`
+	Gender bool   `json:"gender"`
+	// BirthDate is YYYY-MM-DD.
+	BirthDate string `json:"birthDate"`
+	// Version is known for single actors, pass it to UpdateActor and
+	// DeleteActor so they fail when someone else changed the actor.
+	Version uint64 `json:"-"`
+}
+
+// ActorInput is what CreateActor and UpdateActor send.
+type ActorInput struct {
+	Name      string `json:"name"`
+	Gender    bool   `json:"gender"`
+	BirthDate string `json:"birthDate"`
+}
+
+// ActorWithMovies is an actor of ListActors with the movies they acted in,
+// without casts.
+type ActorWithMovies struct {
+	Actor  Actor   `json:"actor"`
+	Movies []Movie `json:"actedInFilms"`
+}
+
+func (c *Client) CreateActor(ctx context.Context, actor ActorInput) (uint64, error) {
+	var out idResponse
+	_, err := c.do(ctx, request{method: http.MethodPost, path: "/actors", in: actor, out: &out})
+	return out.ID, err
+}
+
+func (c *Client) GetActor(ctx context.Context, actorID uint64) (Actor, error) {
+	var actor Actor
+	resp, err := c.do(ctx, request{method: http.MethodGet, path: actorPath(actorID), out: &actor})
+	actor.Version = resp.version
+	return actor, err
+}
+
+// ListActors reads a page of actors, the first page is 1.
+func (c *Client) ListActors(ctx context.Context, page uint64) ([]ActorWithMovies, error) {
+	query := url.Values{}
+	if page > 0 {
+		query.Set("page", strconv.FormatUint(page, 10))
+	}
+
+	var actors []ActorWithMovies
+	_, err := c.do(ctx, request{method: http.MethodGet, path: "/actors", query: query, out: &actors})
+	return actors, err
+}
+
+// UpdateActor replaces the fields of an actor. A zero version updates any
+// version.
+func (c *Client) UpdateActor(ctx context.Context, actorID, version uint64, actor ActorInput) (Actor, error) {
+	var updated Actor
+	resp, err := c.do(ctx, request{
+		method:  http.MethodPut,
+		path:    actorPath(actorID),
+		version: version,
+		in:      actor,
+		out:     &updated,
+	})
+	updated.Version = resp.version
+	return updated, err
+}
+
+// DeleteActor moves an actor to the trash. A zero version deletes any
+// version.
+func (c *Client) DeleteActor(ctx context.Context, actorID, version uint64) error {
+	_, err := c.do(ctx, request{method: http.MethodDelete, path: actorPath(actorID), version: version})
+	return err
+}
+
+func actorPath(actorID uint64) string {
+	return "/actors/" + strconv.FormatUint(actorID, 10)
+}
@@ -0,0 +1,82 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+// Credentials are a username and a password.
+type Credentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// LoginResult is either the id of the logged in user or, for users with
+// two-factor authentication, the challenge to pass to LoginTwoFactor.
+type LoginResult struct {
+	UserID             uint64
+	TwoFactorChallenge string
+}
+
+type idResponse struct {
+	ID uint64 `json:"id"`
+}
+
+type loginResponse struct {
+	ID                uint64 `json:"id"`
+	TwoFactorRequired bool   `json:"twoFactorRequired"`
+	Challenge         string `json:"challenge"`
+}
+
+// SignUp registers a user and logs them in.
+func (c *Client) SignUp(ctx context.Context, credentials Credentials) (uint64, error) {
+	var out idResponse
+	_, err := c.do(ctx, request{method: http.MethodPost, path: "/signup", in: credentials, out: &out})
+	return out.ID, err
+}
+
+func (c *Client) Login(ctx context.Context, credentials Credentials) (LoginResult, error) {
+	var out loginResponse
+	if _, err := c.do(ctx, request{method: http.MethodPost, path: "/login", in: credentials, out: &out}); err != nil {
+		return LoginResult{}, err
+	}
+	if out.TwoFactorRequired {
+		return LoginResult{TwoFactorChallenge: out.Challenge}, nil
+	}
+	return LoginResult{UserID: out.ID}, nil
+}
+
+// LoginTwoFactor finishes a login with a TOTP or a recovery code.
+func (c *Client) LoginTwoFactor(ctx context.Context, challenge, code string) (uint64, error) {
+	var out idResponse
+	_, err := c.do(ctx, request{
+		method: http.MethodPost,
+		path:   "/login/2fa",
+		in:     map[string]string{"challenge": challenge, "code": code},
+		out:    &out,
+	})
+	return out.ID, err
+}
+
+func (c *Client) Logout(ctx context.Context) error {
+	_, err := c.do(ctx, request{method: http.MethodDelete, path: "/logout"})
+	return err
+}
+
+// CurrentUserID is the user of the session.
+func (c *Client) CurrentUserID(ctx context.Context) (uint64, error) {
+	var out idResponse
+	_, err := c.do(ctx, request{method: http.MethodGet, path: "/auth", out: &out})
+	return out.ID, err
+}
+
+// ChangePassword changes the password of the user of the session, their
+// other sessions end.
+func (c *Client) ChangePassword(ctx context.Context, oldPassword, newPassword string) error {
+	_, err := c.do(ctx, request{
+		method: http.MethodPut,
+		path:   "/me/password",
+		in:     map[string]string{"oldPassword": oldPassword, "newPassword": newPassword},
+	})
+	return err
+}
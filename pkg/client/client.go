@@ -0,0 +1,236 @@
+// Package client is a typed Go client of the film library REST API.
+//
+// A Client keeps the session cookie of SignUp, Login and LoginTwoFactor in
+// its cookie jar and sends it with every later call. Failed calls return an
+// *Error with the problem the server answered with. Idempotent calls (GET,
+// PUT and DELETE) are retried when the server is unavailable or asks to slow
+// down.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	basePath = "/api/v1"
+
+	defaultMaxAttempts = 3
+	defaultBackoff     = 100 * time.Millisecond
+	maxBackoff         = 5 * time.Second
+)
+
+type Client struct {
+	baseURL     *url.URL
+	httpClient  *http.Client
+	maxAttempts int
+	backoff     time.Duration
+}
+
+type Option func(c *Client)
+
+// WithHTTPClient sends requests with hc. A cookie jar is added to a copy of
+// it when it has none, the session can't be kept otherwise.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		copied := *hc
+		if copied.Jar == nil {
+			copied.Jar = c.httpClient.Jar
+		}
+		c.httpClient = &copied
+	}
+}
+
+// WithRetries sets how many times an idempotent call is tried, one turns
+// retries off. backoff is the wait before the first retry, it doubles with
+// each next one.
+func WithRetries(maxAttempts int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.maxAttempts = max(maxAttempts, 1)
+		c.backoff = backoff
+	}
+}
+
+// New makes a client of the API at baseURL, e.g. https://milchenko.online.
+func New(baseURL string, opts ...Option) (*Client, error) {
+	parsed, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, err
+	}
+	if parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("client: base url %q must be absolute", baseURL)
+	}
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Client{
+		baseURL:     parsed,
+		httpClient:  &http.Client{Jar: jar},
+		maxAttempts: defaultMaxAttempts,
+		backoff:     defaultBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// request is one call of the API. in is sent as JSON unless it is nil, out
+// is filled from a 2xx JSON answer unless it is nil.
+type request struct {
+	method  string
+	path    string
+	query   url.Values
+	version uint64
+	in      interface{}
+	out     interface{}
+}
+
+// response is what a call answered besides the body.
+type response struct {
+	status  int
+	version uint64
+}
+
+func (c *Client) do(ctx context.Context, req request) (response, error) {
+	var body []byte
+	if req.in != nil {
+		var err error
+		if body, err = json.Marshal(req.in); err != nil {
+			return response{}, err
+		}
+	}
+
+	attempts := 1
+	if idempotent(req.method) {
+		attempts = c.maxAttempts
+	}
+
+	backoff := c.backoff
+	for attempt := 1; ; attempt++ {
+		resp, err := c.send(ctx, req, body)
+		if attempt >= attempts || !retryable(resp, err) || ctx.Err() != nil {
+			return c.read(req, resp, err)
+		}
+
+		wait := backoff
+		if after, ok := retryAfter(resp); ok {
+			wait = after
+		}
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return response{}, ctx.Err()
+		case <-timer.C:
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+func (c *Client) send(ctx context.Context, req request, body []byte) (*http.Response, error) {
+	u := c.baseURL.JoinPath(basePath, req.path)
+	if len(req.query) > 0 {
+		u.RawQuery = req.query.Encode()
+	}
+
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, u.String(), bodyReader)
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	if body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	if req.version != 0 {
+		httpReq.Header.Set("If-Match", `"`+strconv.FormatUint(req.version, 10)+`"`)
+	}
+
+	return c.httpClient.Do(httpReq)
+}
+
+func (c *Client) read(req request, resp *http.Response, err error) (response, error) {
+	if err != nil {
+		return response{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return response{status: resp.StatusCode}, newError(resp)
+	}
+
+	result := response{status: resp.StatusCode, version: parseETag(resp.Header.Get("ETag"))}
+	if req.out != nil {
+		if err = json.NewDecoder(resp.Body).Decode(req.out); err != nil {
+			return result, fmt.Errorf("client: decode %s %s: %w", req.method, req.path, err)
+		}
+	}
+	return result, nil
+}
+
+func idempotent(method string) bool {
+	return method == http.MethodGet || method == http.MethodPut || method == http.MethodDelete
+}
+
+// retryable tells whether a call may succeed if it is sent again: the
+// connection failed or the server is overloaded or restarting.
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+	return min(time.Duration(seconds)*time.Second, maxBackoff), true
+}
+
+// parseETag is the version in a strong ETag, zero for anything else.
+func parseETag(tag string) uint64 {
+	unquoted, ok := strings.CutPrefix(tag, `"`)
+	if !ok {
+		return 0
+	}
+	unquoted, ok = strings.CutSuffix(unquoted, `"`)
+	if !ok {
+		return 0
+	}
+	version, _ := strconv.ParseUint(unquoted, 10, 64)
+	return version
+}
@@ -0,0 +1,243 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/docs"
+	httpActors "github.com/themilchenko/vk-tech_internship-problem_2024/internal/actors/delivery"
+	httpAuth "github.com/themilchenko/vk-tech_internship-problem_2024/internal/auth/delivery"
+	authMiddleware "github.com/themilchenko/vk-tech_internship-problem_2024/internal/auth/delivery/middleware"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/config"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/domain"
+	mockDomain "github.com/themilchenko/vk-tech_internship-problem_2024/internal/mocks/domain"
+	httpModels "github.com/themilchenko/vk-tech_internship-problem_2024/internal/models/http"
+	httpMovies "github.com/themilchenko/vk-tech_internship-problem_2024/internal/movies/delivery"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/openapi"
+	"go.uber.org/mock/gomock"
+)
+
+type mocks struct {
+	auth   *mockDomain.MockAuthUsecase
+	movies *mockDomain.MockMoviesUsecase
+	actors *mockDomain.MockActorsUsecase
+}
+
+// newServer serves the real handlers on top of mocked usecases, checked
+// against the spec. wrap can put something in front of them.
+func newServer(t *testing.T, wrap func(http.Handler) http.Handler) (*httptest.Server, mocks) {
+	cntx := gomock.NewController(t)
+
+	m := mocks{
+		auth:   mockDomain.NewMockAuthUsecase(cntx),
+		movies: mockDomain.NewMockMoviesUsecase(cntx),
+		actors: mockDomain.NewMockActorsUsecase(cntx),
+	}
+
+	cookieSettings := config.CookieSettings{HttpOnly: true}
+	cookieSettings.ExpireDate.Days = 1
+	authHandler := httpAuth.NewAuthHandler(m.auth, cookieSettings)
+	moviesHandler := httpMovies.NewActorsUsecase(m.movies)
+	actorsHandler := httpActors.NewActorsUsecase(m.actors)
+	middleware := authMiddleware.NewMiddleware(m.auth, false)
+
+	router := http.NewServeMux()
+	router.HandleFunc("POST /api/v1/login", authHandler.Login)
+	router.HandleFunc("POST /api/v1/login/2fa", authHandler.LoginTwoFactor)
+	router.HandleFunc("DELETE /api/v1/logout", middleware.LoginRequired(authHandler.Logout))
+	router.HandleFunc("GET /api/v1/movies", middleware.LoginRequired(moviesHandler.GetMovies))
+	router.HandleFunc("GET /api/v1/movies/{id}", middleware.LoginRequired(moviesHandler.GetMovie))
+	router.HandleFunc("PUT /api/v1/movies/{id}", middleware.LoginRequired(moviesHandler.UpdateMovie))
+	router.HandleFunc("POST /api/v1/movies", middleware.LoginRequired(moviesHandler.CreateMovie))
+	router.HandleFunc("POST /api/v1/movies/{id}/cast:batch", middleware.LoginRequired(moviesHandler.BatchCast))
+	router.HandleFunc("GET /api/v1/actors", middleware.LoginRequired(actorsHandler.GetActors))
+
+	// The client is only as good as the spec it follows.
+	spec, err := openapi.Load(docs.Swagger)
+	require.NoError(t, err)
+
+	handler := spec.Middleware(openapi.ModeStrict)(router)
+	if wrap != nil {
+		handler = wrap(handler)
+	}
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return server, m
+}
+
+func newClient(t *testing.T, server *httptest.Server) *Client {
+	c, err := New(server.URL, WithRetries(3, time.Millisecond))
+	require.NoError(t, err)
+	return c
+}
+
+func TestClient_Session(t *testing.T) {
+	server, m := newServer(t, nil)
+	c := newClient(t, server)
+	ctx := context.Background()
+
+	m.auth.EXPECT().
+		Login(gomock.Any(), httpModels.AuthUser{Username: "user", Password: "password"}, "127.0.0.1").
+		Return("session", uint64(1), nil)
+	m.auth.EXPECT().Auth(gomock.Any(), "session").Return(uint64(1), nil).Times(3)
+	m.movies.EXPECT().GetMovieByID(gomock.Any(), uint64(1)).Return(httpModels.MovieResponse{
+		ID:          1,
+		Title:       "The Godfather",
+		ReleaseDate: "1972-03-24",
+		Rating:      9.2,
+		CastList:    []httpModels.ActorResponse{{ID: 3, Name: "Marlon Brando", Gender: true, BirthDate: "1924-04-03"}},
+		Version:     2,
+	}, nil)
+	m.movies.EXPECT().
+		GetMovies(gomock.Any(), "god", "", httpModels.SortBy("releaseDate"), false).
+		Return([]httpModels.MovieResponse{{ID: 1, Title: "The Godfather"}}, nil)
+	m.auth.EXPECT().Logout(gomock.Any(), "session").Return(nil)
+
+	_, err := c.GetMovie(ctx, 1)
+	assert.True(t, HasCode(err, CodeNoSession), err)
+
+	login, err := c.Login(ctx, Credentials{Username: "user", Password: "password"})
+	require.NoError(t, err)
+	assert.Equal(t, LoginResult{UserID: 1}, login)
+
+	movie, err := c.GetMovie(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, Movie{
+		ID:          1,
+		Title:       "The Godfather",
+		ReleaseDate: "1972-03-24",
+		Rating:      9.2,
+		Cast:        []Actor{{ID: 3, Name: "Marlon Brando", Gender: true, BirthDate: "1924-04-03"}},
+		Version:     2,
+	}, movie)
+
+	movies, err := c.ListMovies(ctx, MoviesFilter{Title: "god", SortBy: SortByReleaseDate, Descending: true})
+	require.NoError(t, err)
+	assert.Equal(t, []Movie{{ID: 1, Title: "The Godfather"}}, movies)
+
+	require.NoError(t, c.Logout(ctx))
+	_, err = c.ListMovies(ctx, MoviesFilter{})
+	assert.True(t, HasCode(err, CodeNoSession), err)
+}
+
+func TestClient_LoginTwoFactor(t *testing.T) {
+	server, m := newServer(t, nil)
+	c := newClient(t, server)
+	ctx := context.Background()
+
+	m.auth.EXPECT().
+		Login(gomock.Any(), httpModels.AuthUser{Username: "admin", Password: "password"}, "127.0.0.1").
+		Return("", uint64(0), domain.TwoFactorRequiredError{Challenge: "challenge"})
+	m.auth.EXPECT().
+		VerifyTwoFactor(gomock.Any(), httpModels.TwoFactorLogin{Challenge: "challenge", Code: "123456"}).
+		Return("session", uint64(1), nil)
+	m.auth.EXPECT().Auth(gomock.Any(), "session").Return(uint64(1), nil)
+	m.actors.EXPECT().GetActors(gomock.Any(), uint64(2)).Return([]httpModels.GetActorsResponse{{
+		Actor:        httpModels.ActorResponse{ID: 3, Name: "Marlon Brando", Gender: true, BirthDate: "1924-04-03"},
+		ActedInFilms: []httpModels.MovieWithoutCastList{{ID: 1, Title: "The Godfather", ReleaseDate: "1972-03-24"}},
+	}}, nil)
+
+	login, err := c.Login(ctx, Credentials{Username: "admin", Password: "password"})
+	require.NoError(t, err)
+	assert.Equal(t, LoginResult{TwoFactorChallenge: "challenge"}, login)
+
+	userID, err := c.LoginTwoFactor(ctx, login.TwoFactorChallenge, "123456")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), userID)
+
+	actors, err := c.ListActors(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []ActorWithMovies{{
+		Actor:  Actor{ID: 3, Name: "Marlon Brando", Gender: true, BirthDate: "1924-04-03"},
+		Movies: []Movie{{ID: 1, Title: "The Godfather", ReleaseDate: "1972-03-24"}},
+	}}, actors)
+}
+
+func TestClient_Errors(t *testing.T) {
+	server, m := newServer(t, nil)
+	c := newClient(t, server)
+	ctx := context.Background()
+
+	m.auth.EXPECT().Login(gomock.Any(), gomock.Any(), gomock.Any()).Return("session", uint64(1), nil)
+	m.auth.EXPECT().Auth(gomock.Any(), "session").Return(uint64(1), nil).AnyTimes()
+	m.movies.EXPECT().
+		UpdateMovie(gomock.Any(), gomock.Any(), uint64(1), uint64(2)).
+		Return(httpModels.MovieResponse{}, domain.ErrVersionMismatch)
+	m.movies.EXPECT().
+		BatchCast(gomock.Any(), uint64(1), uint64(3), httpModels.CastBatch{Add: []uint64{4}}).
+		Return(httpModels.CastDiff{Added: []uint64{4}, Unchanged: []uint64{3}, Version: 4}, nil)
+
+	_, err := c.Login(ctx, Credentials{Username: "admin", Password: "password"})
+	require.NoError(t, err)
+
+	_, err = c.UpdateMovie(ctx, 1, 2, MovieInput{Title: "The Godfather", ReleaseDate: "1972-03-24"})
+	assert.Equal(t, &Error{
+		StatusCode: http.StatusPreconditionFailed,
+		Type:       "about:blank",
+		Title:      "Precondition Failed",
+		Detail:     "item was changed since it was read",
+		Code:       CodeVersionMismatch,
+	}, err)
+
+	_, err = c.CreateMovie(ctx, MovieInput{ReleaseDate: "1972-03-24", Rating: 11}, 3)
+	assert.Equal(t, &Error{
+		StatusCode: http.StatusUnprocessableEntity,
+		Type:       "about:blank",
+		Title:      "Unprocessable Entity",
+		Detail:     "validation failed",
+		Code:       CodeValidationFailed,
+		Violations: []Violation{
+			{Field: "title", Rule: "required", Message: "is required"},
+			{Field: "rating", Rule: "max", Message: "must be at most 10"},
+		},
+	}, err)
+	assert.EqualError(t, err, "client: 422 Unprocessable Entity (validation_failed): validation failed; "+
+		"title is required; rating must be at most 10")
+
+	diff, err := c.BatchCast(ctx, 1, 3, []uint64{4}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, CastDiff{Added: []uint64{4}, Removed: []uint64{}, Unchanged: []uint64{3}, Version: 4}, diff)
+}
+
+func TestClient_Retries(t *testing.T) {
+	// unavailable answers 503 to the first two requests of each method.
+	failures := map[string]int{}
+	unavailable := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if failures[r.Method] < 2 {
+				failures[r.Method]++
+				http.Error(w, "try later", http.StatusServiceUnavailable)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+
+	server, m := newServer(t, unavailable)
+	c := newClient(t, server)
+	ctx := context.Background()
+
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	c.httpClient.Jar.SetCookies(serverURL, []*http.Cookie{{Name: httpAuth.CookieName, Value: "session", Path: "/"}})
+
+	m.auth.EXPECT().Auth(gomock.Any(), "session").Return(uint64(1), nil)
+	m.movies.EXPECT().
+		GetMovies(gomock.Any(), "", "", httpModels.SortBy("rating"), true).
+		Return([]httpModels.MovieResponse{{ID: 1}}, nil)
+
+	movies, err := c.ListMovies(ctx, MoviesFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, []Movie{{ID: 1}}, movies)
+	assert.Equal(t, 2, failures[http.MethodGet])
+
+	_, err = c.Login(ctx, Credentials{Username: "user", Password: "password"})
+	assert.Equal(t, &Error{StatusCode: http.StatusServiceUnavailable, Detail: "try later"}, err)
+	assert.Equal(t, 1, failures[http.MethodPost])
+}
@@ -0,0 +1,93 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Codes of the errors clients usually handle. Codes are stable, messages
+// are not, so match on these.
+const (
+	CodeBadRequest          = "bad_request"
+	CodeValidationFailed    = "validation_failed"
+	CodeInvalidJSON         = "invalid_json"
+	CodeNoSession           = "no_session"
+	CodeBadSession          = "bad_session"
+	CodeInvalidCredentials  = "invalid_credentials"
+	CodeTooManyAttempts     = "too_many_attempts"
+	CodeForbidden           = "forbidden"
+	CodeNotFound            = "not_found"
+	CodeConflict            = "conflict"
+	CodeAlreadyExists       = "already_exists"
+	CodeReferenceViolation  = "reference_violation"
+	CodeUserExists          = "user_exists"
+	CodeWrongPassword       = "wrong_password"
+	CodeVersionMismatch     = "version_mismatch"
+	CodeIfMatchRequired     = "if_match_required"
+	CodeTwoFactorEnrollment = "two_factor_enrollment_required"
+	CodeInvalidTwoFactor    = "invalid_two_factor_code"
+	CodeInternal            = "internal_error"
+)
+
+// Error is an RFC 7807 problem the API answered with. Answers that are not
+// problems, e.g. from a proxy, only have StatusCode, Detail and the
+// RequestID of the response header, if any.
+type Error struct {
+	StatusCode int         `json:"status"`
+	Type       string      `json:"type"`
+	Title      string      `json:"title"`
+	Detail     string      `json:"detail"`
+	Code       string      `json:"code"`
+	Violations []Violation `json:"violations"`
+	RequestID  string      `json:"requestId"`
+}
+
+// Violation is a broken rule of a failed validation.
+type Violation struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	message := fmt.Sprintf("client: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	if e.Code != "" {
+		message += " (" + e.Code + ")"
+	}
+	if e.Detail != "" {
+		message += ": " + e.Detail
+	}
+	for _, v := range e.Violations {
+		message += fmt.Sprintf("; %s %s", v.Field, v.Message)
+	}
+	return message
+}
+
+// HasCode tells whether err is an *Error with the code.
+func HasCode(err error, code string) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.Code == code
+}
+
+func newError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+
+	apiErr := &Error{}
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "application/problem+json") &&
+		json.Unmarshal(body, apiErr) == nil {
+		apiErr.StatusCode = resp.StatusCode
+		if apiErr.RequestID == "" {
+			apiErr.RequestID = resp.Header.Get("X-Request-ID")
+		}
+		return apiErr
+	}
+	return &Error{
+		StatusCode: resp.StatusCode,
+		Detail:     strings.TrimSpace(string(body)),
+		RequestID:  resp.Header.Get("X-Request-ID"),
+	}
+}
@@ -0,0 +1,170 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Sort orders of ListMovies.
+const (
+	SortByRating      = "rating"
+	SortByTitle       = "title"
+	SortByReleaseDate = "releaseDate"
+)
+
+type Movie struct {
+	ID          uint64 `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	// ReleaseDate is YYYY-MM-DD.
+	ReleaseDate string  `json:"releaseDate"`
+	Rating      float32 `json:"rating"`
+	Cast        []Actor `json:"castList"`
+	// Version is known for single movies, pass it to the calls that change
+	// the movie so they fail when someone else changed it.
+	Version uint64 `json:"-"`
+}
+
+// MovieInput is what CreateMovie and UpdateMovie send.
+type MovieInput struct {
+	Title       string  `json:"title"`
+	Description string  `json:"description"`
+	ReleaseDate string  `json:"releaseDate"`
+	Rating      float32 `json:"rating"`
+}
+
+// MoviesFilter picks movies for ListMovies. Movies are sorted by rating in
+// ascending order unless SortBy and Descending say otherwise.
+type MoviesFilter struct {
+	Title      string
+	Actor      string
+	SortBy     string
+	Descending bool
+}
+
+type castBatch struct {
+	Add    []uint64 `json:"add,omitempty"`
+	Remove []uint64 `json:"remove,omitempty"`
+}
+
+// CastDiff tells how the cast of a movie has changed, Version is the new
+// version of the movie.
+type CastDiff struct {
+	Added     []uint64 `json:"added"`
+	Removed   []uint64 `json:"removed"`
+	Unchanged []uint64 `json:"unchanged"`
+	Version   uint64   `json:"-"`
+}
+
+type newMovie struct {
+	MovieInput
+	CastIDs []uint64 `json:"castIDList"`
+}
+
+func (c *Client) CreateMovie(ctx context.Context, movie MovieInput, castIDs ...uint64) (uint64, error) {
+	var out idResponse
+	_, err := c.do(ctx, request{
+		method: http.MethodPost,
+		path:   "/movies",
+		in:     newMovie{MovieInput: movie, CastIDs: castIDs},
+		out:    &out,
+	})
+	return out.ID, err
+}
+
+func (c *Client) GetMovie(ctx context.Context, movieID uint64) (Movie, error) {
+	var movie Movie
+	resp, err := c.do(ctx, request{method: http.MethodGet, path: moviePath(movieID), out: &movie})
+	movie.Version = resp.version
+	return movie, err
+}
+
+func (c *Client) ListMovies(ctx context.Context, filter MoviesFilter) ([]Movie, error) {
+	query := url.Values{}
+	if filter.Title != "" {
+		query.Set("title", filter.Title)
+	}
+	if filter.Actor != "" {
+		query.Set("actor", filter.Actor)
+	}
+	if filter.SortBy != "" {
+		query.Set("filter", filter.SortBy)
+	}
+	if filter.Descending {
+		query.Set("order", "false")
+	}
+
+	var movies []Movie
+	_, err := c.do(ctx, request{method: http.MethodGet, path: "/movies", query: query, out: &movies})
+	return movies, err
+}
+
+// UpdateMovie replaces the fields of a movie, the cast stays. A zero version
+// updates any version.
+func (c *Client) UpdateMovie(ctx context.Context, movieID, version uint64, movie MovieInput) (Movie, error) {
+	var updated Movie
+	resp, err := c.do(ctx, request{
+		method:  http.MethodPut,
+		path:    moviePath(movieID),
+		version: version,
+		in:      movie,
+		out:     &updated,
+	})
+	updated.Version = resp.version
+	return updated, err
+}
+
+// DeleteMovie moves a movie to the trash. A zero version deletes any
+// version.
+func (c *Client) DeleteMovie(ctx context.Context, movieID, version uint64) error {
+	_, err := c.do(ctx, request{method: http.MethodDelete, path: moviePath(movieID), version: version})
+	return err
+}
+
+// ReplaceCast makes actorIDs the whole cast of a movie.
+func (c *Client) ReplaceCast(ctx context.Context, movieID, version uint64, actorIDs []uint64) (CastDiff, error) {
+	var diff CastDiff
+	resp, err := c.do(ctx, request{
+		method:  http.MethodPut,
+		path:    moviePath(movieID) + "/cast",
+		version: version,
+		in:      map[string][]uint64{"actorIDs": actorIDs},
+		out:     &diff,
+	})
+	diff.Version = resp.version
+	return diff, err
+}
+
+// BatchCast adds and removes actors of the cast of a movie at once.
+func (c *Client) BatchCast(ctx context.Context, movieID, version uint64, add, remove []uint64) (CastDiff, error) {
+	var diff CastDiff
+	resp, err := c.do(ctx, request{
+		method:  http.MethodPost,
+		path:    moviePath(movieID) + "/cast:batch",
+		version: version,
+		in:      castBatch{Add: add, Remove: remove},
+		out:     &diff,
+	})
+	diff.Version = resp.version
+	return diff, err
+}
+
+func (c *Client) AddActorToMovie(ctx context.Context, movieID, actorID uint64) error {
+	_, err := c.do(ctx, request{method: http.MethodPost, path: castPath(movieID, actorID)})
+	return err
+}
+
+func (c *Client) RemoveActorFromMovie(ctx context.Context, movieID, actorID uint64) error {
+	_, err := c.do(ctx, request{method: http.MethodDelete, path: castPath(movieID, actorID)})
+	return err
+}
+
+func moviePath(movieID uint64) string {
+	return "/movies/" + strconv.FormatUint(movieID, 10)
+}
+
+func castPath(movieID, actorID uint64) string {
+	return moviePath(movieID) + "/actors/" + strconv.FormatUint(actorID, 10)
+}
@@ -1,11 +1,73 @@
 package pkg
 
 import (
-	"log"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
 	"time"
+
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/requestctx"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// NewLogger makes a logger writing to w. level is one of debug, info, warn
+// and error, format is json or text. Records logged with a request context
+// carry its request id, user id and route, and the ids of the trace and span
+// if it is traced.
+func NewLogger(w io.Writer, level, format string) (*slog.Logger, error) {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("logger level: %w", err)
+	}
+
+	options := &slog.HandlerOptions{Level: lvl}
+	var handler slog.Handler
+	switch format {
+	case "json":
+		handler = slog.NewJSONHandler(w, options)
+	case "text":
+		handler = slog.NewTextHandler(w, options)
+	default:
+		return nil, fmt.Errorf("logger format %q is neither json nor text", format)
+	}
+
+	return slog.New(contextHandler{handler}), nil
+}
+
+// contextHandler adds what the context knows about the request to records.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if requestID := requestctx.RequestID(ctx); requestID != "" {
+		record.AddAttrs(slog.String("request_id", requestID))
+	}
+	if userID := requestctx.UserID(ctx); userID != 0 {
+		record.AddAttrs(slog.Uint64("user_id", userID))
+	}
+	if route := requestctx.Route(ctx); route != "" {
+		record.AddAttrs(slog.String("route", route))
+	}
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		record.AddAttrs(
+			slog.String("trace_id", span.TraceID().String()),
+			slog.String("span_id", span.SpanID().String()),
+		)
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
 type customResponseWriter struct {
 	http.ResponseWriter
 	status int
@@ -26,6 +88,15 @@ func (crw *customResponseWriter) Write(data []byte) (int, error) {
 	return size, err
 }
 
+func (crw *customResponseWriter) Unwrap() http.ResponseWriter {
+	return crw.ResponseWriter
+}
+
+// Middleware writes the access log through slog.Default. It goes behind
+// requestctx.Middleware, so that the records carry the request id. Panics
+// are answered with 500, except http.ErrAbortHandler: the handler wants the
+// connection cut, e.g. in the middle of an export, so it is panicked again
+// for the server to do that.
 func Middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
@@ -33,31 +104,38 @@ func Middleware(next http.Handler) http.Handler {
 		crw := &customResponseWriter{w, http.StatusOK, 0}
 
 		defer func() {
-			if err := recover(); err != nil {
+			level := slog.LevelInfo
+			err := recover()
+			aborted := err == http.ErrAbortHandler
+			switch {
+			case aborted:
+				level = slog.LevelError
+			case err != nil:
 				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-				log.Printf("Panic: %v", err)
+				crw.status = http.StatusInternalServerError
+				level = slog.LevelError
+				slog.ErrorContext(r.Context(), "panic", "error", err)
+			case crw.status >= http.StatusInternalServerError:
+				level = slog.LevelError
 			}
 
-			remoteIP := r.RemoteAddr
-			host := r.Host
-			uri := r.RequestURI
-			method := r.Method
-
-			duration := time.Since(start)
-
-			log.Printf(
-				"\n{\n\tMethod: %s,\n\tRequestURI: %s,\n\tStatus: %d,\n\tTime: %s,\n\tremote_ip: %s,\n\thost: %s,\n\turi: %s,\n\tmethod: %s,\n\tuser_agent: %s,\n\tbytes_out: %d,\n}\n",
-				method,
-				uri,
-				crw.status,
-				duration,
-				remoteIP,
-				host,
-				uri,
-				method,
-				r.UserAgent(),
-				crw.size,
+			slog.Log(
+				r.Context(),
+				level,
+				"request",
+				"method", r.Method,
+				"uri", r.RequestURI,
+				"status", crw.status,
+				"duration", time.Since(start),
+				"remote_ip", r.RemoteAddr,
+				"host", r.Host,
+				"user_agent", r.UserAgent(),
+				"bytes_out", crw.size,
+				"aborted", aborted,
 			)
+			if aborted {
+				panic(err)
+			}
 		}()
 
 		next.ServeHTTP(crw, r)
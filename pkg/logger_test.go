@@ -0,0 +1,63 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/themilchenko/vk-tech_internship-problem_2024/internal/utils/requestctx"
+)
+
+func TestMiddleware(t *testing.T) {
+	var out bytes.Buffer
+	logger, err := NewLogger(&out, "info", "json")
+	require.NoError(t, err)
+
+	defaultLogger := slog.Default()
+	slog.SetDefault(logger)
+	defer slog.SetDefault(defaultLogger)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/movies/{id}", func(w http.ResponseWriter, r *http.Request) {
+		ctx := requestctx.WithRoute(r.Context(), "GET /api/v1/movies/{id}")
+		ctx = requestctx.WithUserID(ctx, 7)
+		slog.DebugContext(ctx, "not logged")
+		slog.InfoContext(ctx, "movie read")
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/movies/1", nil)
+	req.Header.Set(requestctx.RequestIDHeader, "request")
+	requestctx.Middleware(Middleware(mux)).ServeHTTP(httptest.NewRecorder(), req)
+
+	var records []map[string]interface{}
+	decoder := json.NewDecoder(&out)
+	for decoder.More() {
+		var record map[string]interface{}
+		require.NoError(t, decoder.Decode(&record))
+		records = append(records, record)
+	}
+	require.Len(t, records, 2)
+
+	for _, record := range records {
+		assert.Equal(t, "request", record["request_id"])
+		assert.Equal(t, float64(7), record["user_id"])
+		assert.Equal(t, "GET /api/v1/movies/{id}", record["route"])
+	}
+	assert.Equal(t, "movie read", records[0]["msg"])
+	assert.Equal(t, "request", records[1]["msg"])
+	assert.Equal(t, float64(http.StatusNotFound), records[1]["status"])
+}
+
+func TestNewLogger(t *testing.T) {
+	_, err := NewLogger(&bytes.Buffer{}, "loud", "json")
+	assert.Error(t, err)
+
+	_, err = NewLogger(&bytes.Buffer{}, "warn", "xml")
+	assert.Error(t, err)
+}